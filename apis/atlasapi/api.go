@@ -17,6 +17,7 @@
 package atlasapi
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -43,11 +44,13 @@ import (
 	"github.com/mapprotocol/atlas/chains"
 	"github.com/mapprotocol/atlas/chains/interfaces"
 	"github.com/mapprotocol/atlas/consensus/misc"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/chain"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
 	"github.com/mapprotocol/atlas/p2p"
 	"github.com/mapprotocol/atlas/params"
 )
@@ -133,13 +136,20 @@ func (s *PublicEthereumAPI) Syncing() (interface{}, error) {
 		return false, nil
 	}
 	// Otherwise gather the block sync stats
-	return map[string]interface{}{
+	fields := map[string]interface{}{
 		"startingBlock": hexutil.Uint64(progress.StartingBlock),
 		"currentBlock":  hexutil.Uint64(progress.CurrentBlock),
 		"highestBlock":  hexutil.Uint64(progress.HighestBlock),
 		"pulledStates":  hexutil.Uint64(progress.PulledStates),
 		"knownStates":   hexutil.Uint64(progress.KnownStates),
-	}, nil
+	}
+	if anchor := s.b.SyncAnchor(); anchor != nil {
+		fields["checkpoint"] = map[string]interface{}{
+			"height": hexutil.Uint64(anchor.Height),
+			"root":   anchor.Root,
+		}
+	}
+	return fields, nil
 }
 
 // PublicTxPoolAPI offers and API for the transaction pool. It only operates on data that is non confidential.
@@ -599,6 +609,9 @@ func (s *PrivateAccountAPI) Unpair(ctx context.Context, url string, pin string)
 	}
 }
 
+// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+const chainHeadChanSize = 10
+
 // PublicBlockChainAPI provides an API to access the Ethereum blockchain.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicBlockChainAPI struct {
@@ -760,6 +773,97 @@ func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Ha
 	return nil, err
 }
 
+// GetBlockReceipts returns the receipts of all the transactions of the requested block, followed
+// by the system receipt of the block if it has one. The system receipt is marked as such and has
+// no transaction fields.
+func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	block, err := s.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	baseFees, err := feeCurrencyBaseFees(ctx, s.b, block.Header(), block.Transactions())
+	if err != nil {
+		return nil, err
+	}
+	return marshalBlockReceipts(block, receipts, baseFees, s.b.ChainConfig())
+}
+
+// Receipts sends a notification with the receipts of the block, as returned by GetBlockReceipts,
+// each time a new block becomes the head of the chain.
+func (s *PublicBlockChainAPI) Receipts(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+		headsSub := s.b.SubscribeChainHeadEvent(heads)
+		defer headsSub.Unsubscribe()
+
+		for {
+			select {
+			case head := <-heads:
+				receipts, err := s.b.GetReceipts(context.Background(), head.Block.Hash())
+				if err != nil {
+					log.Warn("Failed to retrieve block receipts", "number", head.Block.Number(), "hash", head.Block.Hash(), "err", err)
+					continue
+				}
+				baseFees, err := feeCurrencyBaseFees(context.Background(), s.b, head.Block.Header(), head.Block.Transactions())
+				if err != nil {
+					log.Warn("Failed to convert the block base fee", "number", head.Block.Number(), "hash", head.Block.Hash(), "err", err)
+					continue
+				}
+				fields, err := marshalBlockReceipts(head.Block, receipts, baseFees, s.b.ChainConfig())
+				if err != nil {
+					log.Warn("Failed to marshal block receipts", "number", head.Block.Number(), "hash", head.Block.Hash(), "err", err)
+					continue
+				}
+				notifier.Notify(rpcSub.ID, fields)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-headsSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// marshalBlockReceipts converts the receipts of the block into a JSON RPC compatible format,
+// the system receipt of the block coming after the transaction receipts. The base fees of the
+// transactions paying in a fee currency are looked up in baseFees.
+func marshalBlockReceipts(block *types.Block, receipts types.Receipts, baseFees map[common.Address]*big.Int, config *params.ChainConfig) ([]map[string]interface{}, error) {
+	txs := block.Transactions()
+	if len(receipts) != len(txs) && len(receipts) != len(txs)+1 {
+		return nil, fmt.Errorf("receipts length mismatch: %d transactions, %d receipts", len(txs), len(receipts))
+	}
+	var (
+		signer = types.MakeSigner(config, block.Number())
+		fields = make([]map[string]interface{}, 0, len(receipts))
+	)
+	for i, receipt := range receipts.TransactionReceipts(len(txs)) {
+		baseFee := block.BaseFee()
+		if feeCurrency := txs[i].FeeCurrency(); feeCurrency != nil {
+			baseFee = baseFees[*feeCurrency]
+		}
+		fields = append(fields, marshalReceipt(receipt, block.Hash(), block.NumberU64(), signer, txs[i], uint64(i), config, baseFee))
+	}
+	if receipt := receipts.SystemReceipt(len(txs)); receipt != nil {
+		fields = append(fields, marshalSystemReceipt(receipt, block))
+	}
+	return fields, nil
+}
+
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -973,8 +1077,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	} else {
 		feeCap = common.Big0
 	}
-	// Recap the highest gas limit with account's available balance.
-	if feeCap.BitLen() != 0 {
+	// Recap the highest gas limit with account's available balance, the fee
+	// currency balance is checked by the execution.
+	if feeCap.BitLen() != 0 && args.FeeCurrency == nil {
 		state, _, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 		if err != nil {
 			return 0, err
@@ -1153,6 +1258,9 @@ func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 	if head.BaseFee != nil {
 		result["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
 	}
+	if head.MmrRoot != nil {
+		result["mmrRoot"] = head.MmrRoot
+	}
 
 	return result
 }
@@ -1248,6 +1356,7 @@ type RPCTransaction struct {
 	Type             hexutil.Uint64    `json:"type"`
 	Accesses         *types.AccessList `json:"accessList,omitempty"`
 	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	FeeCurrency      *common.Address   `json:"feeCurrency,omitempty"`
 	V                *hexutil.Big      `json:"v"`
 	R                *hexutil.Big      `json:"r"`
 	S                *hexutil.Big      `json:"s"`
@@ -1297,6 +1406,15 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		} else {
 			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
 		}
+	case types.FeeCurrencyTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.FeeCurrency = tx.FeeCurrency()
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// the base fee converted to the fee currency is not known here
+		result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
 	}
 	return result
 }
@@ -1576,15 +1694,77 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
-	// Derive the sender.
 	bigblock := new(big.Int).SetUint64(blockNumber)
+	var baseFee *big.Int
+	if s.b.ChainConfig().IsLondon(bigblock) {
+		header, err := s.b.HeaderByHash(ctx, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		baseFee = header.BaseFee
+		if feeCurrency := tx.FeeCurrency(); feeCurrency != nil {
+			baseFees, err := feeCurrencyBaseFees(ctx, s.b, header, types.Transactions{tx})
+			if err != nil {
+				return nil, err
+			}
+			baseFee = baseFees[*feeCurrency]
+		}
+	}
 	signer := types.MakeSigner(s.b.ChainConfig(), bigblock)
+	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, index, s.b.ChainConfig(), baseFee), nil
+}
+
+// feeCurrencyBaseFees converts the base fee of the block to the fee currencies of the given
+// transactions, at the exchange rates of the parent state like the state transition does.
+func feeCurrencyBaseFees(ctx context.Context, b Backend, header *types.Header, txs types.Transactions) (map[common.Address]*big.Int, error) {
+	if !b.ChainConfig().IsLondon(header.Number) {
+		return nil, nil
+	}
+	baseFee := header.BaseFee
+	if baseFee == nil {
+		baseFee = params.MinBaseFee
+	}
+	var (
+		baseFees = make(map[common.Address]*big.Int)
+		vmRunner vm.EVMRunner
+	)
+	for _, tx := range txs {
+		feeCurrency := tx.FeeCurrency()
+		if feeCurrency == nil || baseFees[*feeCurrency] != nil {
+			continue
+		}
+		if vmRunner == nil {
+			state, _, err := b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithHash(header.ParentHash, false))
+			if state == nil || err != nil {
+				return nil, err
+			}
+			msg := types.NewMessage(common.Address{}, nil, 0, common.Big0, 0, common.Big0, common.Big0, common.Big0, nil, nil, nil, true)
+			evm, _, err := b.GetEVM(ctx, msg, state, header, nil)
+			if err != nil {
+				return nil, err
+			}
+			vmRunner = vmcontext.NewEVMRunnerFromEVM(evm)
+		}
+		rate, err := currency.GetExchangeRate(vmRunner, feeCurrency)
+		if err != nil {
+			return nil, err
+		}
+		baseFees[*feeCurrency] = rate.FromBase(baseFee)
+	}
+	return baseFees, nil
+}
+
+// marshalReceipt converts the receipt of the transaction at the given index of the block
+// into a JSON RPC compatible format. The base fee of a transaction paying in a fee currency is
+// given in that currency.
+func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, signer types.Signer, tx *types.Transaction, index uint64, config *params.ChainConfig, baseFee *big.Int) map[string]interface{} {
+	// Derive the sender.
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
 		"blockHash":         blockHash,
 		"blockNumber":       hexutil.Uint64(blockNumber),
-		"transactionHash":   hash,
+		"transactionHash":   tx.Hash(),
 		"transactionIndex":  hexutil.Uint64(index),
 		"from":              from,
 		"to":                tx.To(),
@@ -1595,18 +1775,17 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		"logsBloom":         receipt.Bloom,
 		"type":              hexutil.Uint(tx.Type()),
 	}
+	if feeCurrency := tx.FeeCurrency(); feeCurrency != nil {
+		fields["feeCurrency"] = feeCurrency
+	}
 	// Assign the effective gas price paid
-	if !s.b.ChainConfig().IsLondon(bigblock) {
+	if !config.IsLondon(new(big.Int).SetUint64(blockNumber)) {
 		fields["effectiveGasPrice"] = hexutil.Uint64(tx.GasPrice().Uint64())
 	} else {
-		header, err := s.b.HeaderByHash(ctx, blockHash)
-		if err != nil {
-			return nil, err
-		}
-		if header.BaseFee == nil {
-			header.BaseFee = params.MinBaseFee
+		if baseFee == nil {
+			baseFee = params.MinBaseFee
 		}
-		gasPrice := new(big.Int).Add(header.BaseFee, tx.EffectiveGasTipValue(header.BaseFee))
+		gasPrice := new(big.Int).Add(baseFee, tx.EffectiveGasTipValue(baseFee))
 		fields["effectiveGasPrice"] = hexutil.Uint64(gasPrice.Uint64())
 	}
 	// Assign receipt status or post state.
@@ -1622,7 +1801,81 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
-	return fields, nil
+	return fields
+}
+
+// MerkleProofResult is the merkle proof of a transaction or a receipt against the
+// transactions or receipts root of its block
+type MerkleProofResult struct {
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	Root             common.Hash     `json:"root"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	Key              hexutil.Bytes   `json:"key"`
+	Value            hexutil.Bytes   `json:"value"`
+	Proof            []hexutil.Bytes `json:"proof"`
+}
+
+// newMerkleProofResult rebuilds the trie of the list like DeriveSha and proves the
+// item at index against the root of the block.
+func newMerkleProofResult(list types.DerivableList, index uint64, root, blockHash common.Hash, blockNumber uint64) (*MerkleProofResult, error) {
+	hash, key, nodes, err := types.DeriveProof(list, int(index))
+	if err != nil {
+		return nil, err
+	}
+	if hash != root {
+		return nil, fmt.Errorf("trie root mismatch: have %x, want %x", hash, root)
+	}
+	var value bytes.Buffer
+	list.EncodeIndex(int(index), &value)
+	proof := make([]hexutil.Bytes, 0, len(nodes))
+	for _, node := range nodes {
+		proof = append(proof, node)
+	}
+	return &MerkleProofResult{
+		BlockHash:        blockHash,
+		BlockNumber:      hexutil.Uint64(blockNumber),
+		Root:             root,
+		TransactionIndex: hexutil.Uint64(index),
+		Key:              key,
+		Value:            value.Bytes(),
+		Proof:            proof,
+	}, nil
+}
+
+// GetTransactionProof returns the merkle proof of the transaction against the
+// transactions root of its block.
+func (s *PublicTransactionPoolAPI) GetTransactionProof(ctx context.Context, hash common.Hash) (*MerkleProofResult, error) {
+	tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		return nil, nil
+	}
+	block, err := s.b.BlockByHash(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return newMerkleProofResult(block.Transactions(), index, block.TxHash(), blockHash, blockNumber)
+}
+
+// GetReceiptProof returns the merkle proof of the receipt of the transaction against
+// the receipts root of its block.
+func (s *PublicTransactionPoolAPI) GetReceiptProof(ctx context.Context, hash common.Hash) (*MerkleProofResult, error) {
+	tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		return nil, nil
+	}
+	header, err := s.b.HeaderByHash(ctx, blockHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) <= int(index) {
+		return nil, nil
+	}
+	return newMerkleProofResult(receipts, index, header.ReceiptHash, blockHash, blockNumber)
 }
 
 // sign is a helper function that signs a transaction with the private key of the given address.
@@ -2070,11 +2323,11 @@ func (p *PublicHeaderStoreAPI) LatestState() (*state.StateDB, error) {
 
 func (p *PublicHeaderStoreAPI) CurrentHeaderNumber(chainID uint64) (uint64, error) {
 	//return new(ethereum.Validate).GetCurrentHeaderNumber(chains.ChainType(chainID))
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID))
+	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID), p.b.ChainConfig(), p.b.CurrentHeader().Number)
 	if err != nil {
 		return 0, err
 	}
-	hs, err := interfaces.HeaderStoreFactory(group)
+	hs, err := interfaces.HeaderStoreFactory(group, chains.ChainType(chainID))
 	if err != nil {
 		return 0, err
 	}
@@ -2092,11 +2345,11 @@ func (p *PublicHeaderStoreAPI) CurrentHeaderNumber(chainID uint64) (uint64, erro
 
 func (p *PublicHeaderStoreAPI) GetHashByNumber(chainID uint64, number uint64) (common.Hash, error) {
 	//return new(ethereum.Validate).GetHashByNumber(chains.ChainType(chainID), number)
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID))
+	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID), p.b.ChainConfig(), p.b.CurrentHeader().Number)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	hs, err := interfaces.HeaderStoreFactory(group)
+	hs, err := interfaces.HeaderStoreFactory(group, chains.ChainType(chainID))
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -2109,11 +2362,11 @@ func (p *PublicHeaderStoreAPI) GetHashByNumber(chainID uint64, number uint64) (c
 }
 
 func (p *PublicHeaderStoreAPI) CurrentNumberAndHash(chainID uint64) (map[string]interface{}, error) {
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID))
+	group, err := chains.ChainType2ChainGroup(chains.ChainType(chainID), p.b.ChainConfig(), p.b.CurrentHeader().Number)
 	if err != nil {
 		return nil, err
 	}
-	hs, err := interfaces.HeaderStoreFactory(group)
+	hs, err := interfaces.HeaderStoreFactory(group, chains.ChainType(chainID))
 	if err != nil {
 		return nil, err
 	}
@@ -2133,3 +2386,97 @@ func (p *PublicHeaderStoreAPI) CurrentNumberAndHash(chainID uint64) (map[string]
 	}
 	return nh, nil
 }
+
+// GetRelayerReward returns the pending and claimed header submission rewards of the relayer
+func (p *PublicHeaderStoreAPI) GetRelayerReward(relayer common.Address) (map[string]interface{}, error) {
+	statedb, err := p.LatestState()
+	if err != nil {
+		return nil, err
+	}
+	reward, err := vm.GetRelayerReward(statedb, relayer)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"pending": (*hexutil.Big)(reward.Pending),
+		"claimed": (*hexutil.Big)(reward.Claimed),
+	}, nil
+}
+
+// PublicAtlasAPI provides the atlas specific chain APIs
+type PublicAtlasAPI struct {
+	b Backend
+}
+
+func NewPublicAtlasAPI(b Backend) *PublicAtlasAPI {
+	return &PublicAtlasAPI{b: b}
+}
+
+// GetMmrProof returns the merkle mountain range proof of the block against the mmr root
+// committed by the tip header, the range of the tip holds all the blocks before it.
+func (p *PublicAtlasAPI) GetMmrProof(ctx context.Context, block rpc.BlockNumber, tip rpc.BlockNumber) (map[string]interface{}, error) {
+	header, err := p.b.HeaderByNumber(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	tipHeader, err := p.b.HeaderByNumber(ctx, tip)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil || tipHeader == nil {
+		return nil, errors.New("header not found")
+	}
+	proof, err := chain.GetMmrProof(p.b.ChainDb(), header.Number.Uint64(), tipHeader.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	if tipHeader.MmrRoot != nil && *tipHeader.MmrRoot != proof.RootHash {
+		return nil, fmt.Errorf("mmr root mismatch: header %x, proof %x", *tipHeader.MmrRoot, proof.RootHash)
+	}
+	data, err := rlp.EncodeToBytes(proof)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"blockHash":  header.Hash(),
+		"tipHash":    tipHeader.Hash(),
+		"root":       proof.RootHash,
+		"leafNumber": hexutil.Uint64(proof.LeafNumber),
+		"proof":      hexutil.Bytes(data),
+	}, nil
+}
+
+// GetBlockReceipt returns the system receipt of the block, holding the logs emitted by the
+// core contract calls made during the block processing outside of transactions. Blocks
+// without such logs have a system receipt without logs.
+func (p *PublicAtlasAPI) GetBlockReceipt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[string]interface{}, error) {
+	block, err := p.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := p.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return marshalSystemReceipt(receipts.SystemReceipt(len(block.Transactions())), block), nil
+}
+
+// marshalSystemReceipt converts the system receipt of the block, nil if it has none, into
+// a JSON RPC compatible format. It has no transaction fields so that it is never mistaken
+// for a transaction receipt.
+func marshalSystemReceipt(receipt *types.Receipt, block *types.Block) map[string]interface{} {
+	fields := map[string]interface{}{
+		"blockHash":   block.Hash(),
+		"blockNumber": hexutil.Uint64(block.NumberU64()),
+		"logs":        []*types.Log{},
+		"logsBloom":   types.Bloom{},
+		"system":      true,
+	}
+	if receipt != nil {
+		if receipt.Logs != nil {
+			fields["logs"] = receipt.Logs
+		}
+		fields["logsBloom"] = receipt.Bloom
+	}
+	return fields
+}
@@ -7,6 +7,22 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 	"testing"
 	"github.com/mapprotocol/atlas/core/types"
+
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/ethereum"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
 )
 
 
@@ -16,4 +32,209 @@ func Test01(t *testing.T) {
 	fmt.Println(EmptyRootHash0)
 }
 
+func TestReceiptProof(t *testing.T) {
+	var receipts types.Receipts
+	for i := 0; i < 150; i++ {
+		receipt := &types.Receipt{
+			Type:              types.LegacyTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*types.Log{{
+				Address: common.BigToAddress(big.NewInt(int64(i))),
+				Topics:  []common.Hash{common.BigToHash(big.NewInt(int64(i)))},
+				Data:    []byte{byte(i)},
+			}},
+		}
+		if i%2 == 1 {
+			receipt.Type = types.DynamicFeeTxType
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts = append(receipts, receipt)
+	}
+	root := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+
+	// Store a header committing the receipts in an ethereum header store, so the proofs
+	// go through the verification of the cross chain transactions
+	header := &ethereum.Header{
+		ReceiptHash: root,
+		Difficulty:  big.NewInt(1),
+		Number:      big.NewInt(100),
+		Extra:       []byte{},
+	}
+	enc, _ := rlp.EncodeToBytes(header)
+	db, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err := ethereum.NewHeaderStore(uint64(chains.ChainTypeETH)).ResetHeaderStore(db, enc, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	verify := new(ethereum.Verify)
+
+	for _, index := range []uint64{0, 1, 0x7f, 0x80, 149} {
+		result, err := newMerkleProofResult(receipts, index, root, common.Hash{}, 100)
+		if err != nil {
+			t.Fatalf("receipt %d: %v", index, err)
+		}
+		receipt := new(ethtypes.Receipt)
+		if err := receipt.UnmarshalBinary(result.Value); err != nil {
+			t.Fatalf("receipt %d: %v", index, err)
+		}
+		prove := ethereum.TxProve{
+			Receipt:     receipt,
+			BlockNumber: 100,
+			TxIndex:     uint(index),
+		}
+		for _, node := range result.Proof {
+			prove.Prove = append(prove.Prove, rlp.RawValue(node))
+		}
+		data, _ := rlp.EncodeToBytes(&prove)
+		if _, err := verify.Verify(db, common.Address{}, data); err != nil {
+			t.Fatalf("receipt %d: %v", index, err)
+		}
+
+		// the proof of a receipt doesn't prove another one
+		prove.TxIndex = uint(index+1) % uint(len(receipts))
+		data, _ = rlp.EncodeToBytes(&prove)
+		if _, err := verify.Verify(db, common.Address{}, data); err == nil {
+			t.Fatalf("receipt %d verified at index %d", index, prove.TxIndex)
+		}
+	}
+	if _, err := newMerkleProofResult(receipts, 0, common.Hash{1}, common.Hash{}, 100); err == nil {
+		t.Fatal("proof against a wrong root succeeded")
+	}
+}
+
+func TestTransactionProof(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	var txs types.Transactions
+	for i := uint64(0); i < 150; i++ {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     i,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(1),
+			Gas:       21000,
+			To:        &common.Address{},
+			Value:     big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+	root := types.DeriveSha(txs, trie.NewStackTrie(nil))
+
+	for _, index := range []uint64{0, 1, 0x7f, 0x80, 149} {
+		result, err := newMerkleProofResult(txs, index, root, common.Hash{}, 100)
+		if err != nil {
+			t.Fatalf("tx %d: %v", index, err)
+		}
+		proof := light.NodeList{}
+		for _, node := range result.Proof {
+			proof = append(proof, rlp.RawValue(node))
+		}
+		value, err := trie.VerifyProof(root, result.Key, proof.NodeSet())
+		if err != nil {
+			t.Fatalf("tx %d: %v", index, err)
+		}
+		want, _ := txs[index].MarshalBinary()
+		if !bytes.Equal(value, want) || !bytes.Equal(result.Value, want) {
+			t.Fatalf("tx %d: proved %x, want %x", index, value, want)
+		}
+	}
+	if _, err := newMerkleProofResult(txs, 150, root, common.Hash{}, 100); err == nil {
+		t.Fatal("proof of a missing tx succeeded")
+	}
+}
+
+func TestMarshalBlockReceipts(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	config := params.TestChainConfig
+	header := &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1)}
+	signer := types.MakeSigner(config, header.Number)
+
+	to := common.HexToAddress("0x1")
+	var (
+		txs      types.Transactions
+		receipts types.Receipts
+	)
+	for i := 0; i < 2; i++ {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(i), To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(21000 * (i + 1))})
+	}
+	block := types.NewBlock(header, txs, nil, nil)
+	systemLogs := []*types.Log{{Address: common.HexToAddress("0x2")}}
+	receipts = append(receipts, types.NewSystemReceipt(systemLogs, block.Hash(), len(txs)))
+	if err := receipts.DeriveFields(config, block.Hash(), block.NumberU64(), txs); err != nil {
+		t.Fatal(err)
+	}
 
+	fields, err := marshalBlockReceipts(block, receipts, nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != len(receipts) {
+		t.Fatalf("receipt count mismatch: have %d, want %d", len(fields), len(receipts))
+	}
+	for i, tx := range txs {
+		if fields[i]["transactionHash"] != tx.Hash() {
+			t.Errorf("receipt %d: transaction hash mismatch: have %v, want %v", i, fields[i]["transactionHash"], tx.Hash())
+		}
+		if _, ok := fields[i]["system"]; ok {
+			t.Errorf("receipt %d: transaction receipt marked as system receipt", i)
+		}
+	}
+	system := fields[len(txs)]
+	if system["system"] != true {
+		t.Error("system receipt not marked as such")
+	}
+	if _, ok := system["transactionHash"]; ok {
+		t.Error("system receipt has a transaction hash")
+	}
+	if logs := system["logs"].([]*types.Log); len(logs) != 1 || !logs[0].System {
+		t.Errorf("system logs mismatch: %v", logs)
+	}
+
+	// Blocks without system logs only have transaction receipts
+	if fields, err := marshalBlockReceipts(block, receipts[:len(txs)], nil, config); err != nil || len(fields) != len(txs) {
+		t.Errorf("transaction receipts mismatch: have %d, %v, want %d", len(fields), err, len(txs))
+	}
+	if _, err := marshalBlockReceipts(block, receipts[:1], nil, config); err == nil {
+		t.Error("missing receipts not detected")
+	}
+}
+
+func TestMarshalBlockReceiptsFeeCurrency(t *testing.T) {
+	config := params.TestChainConfig
+	header := &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1)}
+
+	to := common.HexToAddress("0x1")
+	feeCurrency := common.HexToAddress("0x2")
+	tx := types.NewTx(&types.FeeCurrencyTx{
+		ChainID:     config.ChainID,
+		GasTipCap:   big.NewInt(2),
+		GasFeeCap:   big.NewInt(100),
+		Gas:         21000,
+		FeeCurrency: feeCurrency,
+		To:          &to,
+		Value:       big.NewInt(0),
+	})
+	block := types.NewBlock(header, types.Transactions{tx}, nil, nil)
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000}}
+
+	// the effective gas price is paid in the fee currency, from the converted base fee
+	baseFees := map[common.Address]*big.Int{feeCurrency: big.NewInt(10)}
+	fields, err := marshalBlockReceipts(block, receipts, baseFees, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price := fields[0]["effectiveGasPrice"]; price != hexutil.Uint64(12) {
+		t.Errorf("effective gas price mismatch: have %v, want %v", price, hexutil.Uint64(12))
+	}
+	if currency, ok := fields[0]["feeCurrency"].(*common.Address); !ok || *currency != feeCurrency {
+		t.Errorf("fee currency mismatch: have %v, want %v", fields[0]["feeCurrency"], feeCurrency)
+	}
+}
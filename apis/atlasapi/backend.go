@@ -44,6 +44,7 @@ import (
 type Backend interface {
 	// General Ethereum API
 	SyncProgress() ethereum.SyncProgress
+	SyncAnchor() *chain.CheckPoint // bitcoin anchored checkpoint trusted by the sync, if any
 
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
@@ -144,6 +145,11 @@ func GetAPIs(apiBackend Backend, engine consensus.Engine) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicHeaderStoreAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "atlas",
+			Version:   "1.0",
+			Service:   NewPublicAtlasAPI(apiBackend),
+			Public:    true,
 		},
 	}
 }
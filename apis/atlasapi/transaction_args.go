@@ -52,6 +52,9 @@ type TransactionArgs struct {
 	// Introduced by AccessListTxType transaction.
 	AccessList *types.AccessList `json:"accessList,omitempty"`
 	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+
+	// Introduced by FeeCurrencyTxType transaction.
+	FeeCurrency *common.Address `json:"feeCurrency,omitempty"`
 }
 
 // from retrieves the transaction sender address.
@@ -78,6 +81,10 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
 		return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
 	}
+	// The suggested fees are in the native token, they can't be defaulted for a fee currency
+	if args.FeeCurrency != nil && (args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil) {
+		return errors.New("feeCurrency specified without maxFeePerGas and maxPriorityFeePerGas")
+	}
 	// After london, default to 1559 unless gasPrice is set
 	head := b.CurrentHeader()
 	// If user specifies both maxPriorityfee and maxFee, then we do not
@@ -156,6 +163,7 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 			Value:                args.Value,
 			Data:                 (*hexutil.Bytes)(&data),
 			AccessList:           args.AccessList,
+			FeeCurrency:          args.FeeCurrency,
 		}
 		pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 		estimated, err := DoEstimateGas(ctx, b, callArgs, pendingBlockNr, b.RPCGasCap())
@@ -223,9 +231,12 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 			if args.MaxPriorityFeePerGas != nil {
 				gasTipCap = args.MaxPriorityFeePerGas.ToInt()
 			}
-			// Backfill the legacy gasPrice for EVM execution, unless we're all zeroes
+			// Backfill the legacy gasPrice for EVM execution, unless we're all zeroes.
+			// The base fee is converted to the fee currency by the state transition.
 			gasPrice = new(big.Int)
-			if gasFeeCap.BitLen() > 0 || gasTipCap.BitLen() > 0 {
+			if args.FeeCurrency != nil {
+				gasPrice = gasFeeCap
+			} else if gasFeeCap.BitLen() > 0 || gasTipCap.BitLen() > 0 {
 				gasPrice = math.BigMin(new(big.Int).Add(gasTipCap, baseFee), gasFeeCap)
 			}
 		}
@@ -239,7 +250,7 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 	if args.AccessList != nil {
 		accessList = *args.AccessList
 	}
-	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, gasFeeCap, gasTipCap, data, accessList, true)
+	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, gasFeeCap, gasTipCap, args.FeeCurrency, data, accessList, true)
 	return msg, nil
 }
 
@@ -248,6 +259,23 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 func (args *TransactionArgs) toTransaction() *types.Transaction {
 	var data types.TxData
 	switch {
+	case args.FeeCurrency != nil:
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		data = &types.FeeCurrencyTx{
+			To:          args.To,
+			ChainID:     (*big.Int)(args.ChainID),
+			Nonce:       uint64(*args.Nonce),
+			Gas:         uint64(*args.Gas),
+			GasFeeCap:   (*big.Int)(args.MaxFeePerGas),
+			GasTipCap:   (*big.Int)(args.MaxPriorityFeePerGas),
+			FeeCurrency: *args.FeeCurrency,
+			Value:       (*big.Int)(args.Value),
+			Data:        args.data(),
+			AccessList:  al,
+		}
 	case args.MaxFeePerGas != nil:
 		al := types.AccessList{}
 		if args.AccessList != nil {
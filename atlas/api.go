@@ -161,6 +161,12 @@ func NewPrivateAdminAPI(eth *Ethereum) *PrivateAdminAPI {
 	return &PrivateAdminAPI{eth: eth}
 }
 
+// PublishedCheckPoints returns the checkpoints this node anchored on bitcoin, for
+// auditing the anchoring history.
+func (api *PrivateAdminAPI) PublishedCheckPoints() []*rawdb.PublishedCheckPoint {
+	return rawdb.ReadPublishedCheckPoints(api.eth.chainDb)
+}
+
 // ExportChain exports the current blockchain into a local file,
 // or a range of blocks if first and last are non-nil
 func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64) (bool, error) {
@@ -290,6 +290,14 @@ func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 	return b.eth.Downloader().Progress()
 }
 
+func (b *EthAPIBackend) SyncAnchor() *chain.CheckPoint {
+	anchor := b.eth.Downloader().Anchor()
+	if anchor == nil {
+		return nil
+	}
+	return &chain.CheckPoint{Height: anchor.Number, Root: anchor.Root.Hex()}
+}
+
 func (b *EthAPIBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestTipCap(ctx)
 }
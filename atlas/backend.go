@@ -36,6 +36,7 @@ import (
 	ethparams "github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mapprotocol/atlas/chains/eth2"
 	"github.com/mapprotocol/atlas/chains/ethereum"
 
 	"github.com/mapprotocol/atlas/accounts"
@@ -145,6 +146,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	if err := eth2.RegisterChainConfigNetworks(chainConfig); err != nil {
+		return nil, err
+	}
+
 	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
 		log.Error("Failed to recover state", "error", err)
 	}
@@ -184,6 +189,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	var (
 		vmConfig = vm.Config{
 			EnablePreimageRecording: config.EnablePreimageRecording,
+			ParallelExecution:       config.ParallelTxExecution,
+			ParallelWorkers:         config.ParallelTxWorkers,
 		}
 		cacheConfig = &chain.CacheConfig{
 			TrieCleanLimit:      config.TrieCleanCache,
@@ -275,18 +282,51 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	// Start the RPC service
 	eth.netRPCService = atlasapi.NewPublicNetAPI(eth.p2pServer, config.NetworkId)
+	syncing := true
 	if config.VerifyCheckPoint {
-		log.Info("[atlas start on verify the check point]")
-		err := chain.VerifyCheckPoint(true, eth.blockchain)
+		log.Info("[atlas start on verify the check point]", "source", config.CheckPoint.Source, "policy", config.CheckPoint.Policy)
+		source, err := chain.NewCheckpointSource(&config.CheckPoint)
 		if err != nil {
 			return nil, err
 		}
-		log.Info("[atlas start on verify the check point. pass....]")
+		checkpoint, err := chain.VerifyCheckPoint(source, eth.blockchain)
+		switch {
+		case errors.Is(err, chain.ErrCheckPointMismatch):
+			switch config.CheckPoint.Policy {
+			case chain.CheckPointPolicyWarn:
+				log.Warn("Verify the check point failed, keep syncing", "err", err)
+			case chain.CheckPointPolicyRefuse:
+				log.Error("Verify the check point failed, refuse to sync with peers", "err", err)
+				syncing = false
+			default:
+				return nil, err
+			}
+		case err != nil:
+			// the policy only applies to a disagreement, a node that can't reach the source
+			// starts unverified as before
+			log.Error("Verify the check point skipped", "err", err)
+		default:
+			log.Info("[atlas start on verify the check point. pass....]")
+			// New nodes trust the anchored state root as the fast sync pivot
+			if (config.SyncMode == downloader.FastSync || config.SyncMode == downloader.SnapSync) && checkpoint.Height > eth.blockchain.CurrentBlock().NumberU64() {
+				log.Info("Fast sync anchored on the checkpoint", "checkpoint", checkpoint)
+				eth.handler.downloader.SetAnchor(&downloader.Anchor{Number: checkpoint.Height, Root: common.HexToHash(checkpoint.Root)})
+			}
+		}
 	}
 	// Register the backend on the node
 	stack.RegisterAPIs(eth.APIs())
-	stack.RegisterProtocols(eth.Protocols())
+	if syncing {
+		stack.RegisterProtocols(eth.Protocols())
+	}
 	stack.RegisterLifecycle(eth)
+	if config.CheckPoint.Publish {
+		publisher, err := chain.NewCheckpointPublisher(&config.CheckPoint, eth.blockchain, chainDb)
+		if err != nil {
+			return nil, err
+		}
+		stack.RegisterLifecycle(publisher)
+	}
 	// Check for unclean shutdown
 	if uncleanShutdowns, discards, err := rawdb.PushUncleanShutdownMarker(chainDb); err != nil {
 		log.Error("Could not update unclean-shutdown-marker list", "error", err)
@@ -137,6 +137,9 @@ type Downloader struct {
 	pivotHeader *types.Header // Pivot block header to dynamically push the syncing state root
 	pivotLock   sync.RWMutex  // Lock protecting pivot header reads from updates
 
+	anchor     *Anchor      // Checkpoint anchored on bitcoin, trusted as the fast sync pivot
+	anchorLock sync.RWMutex // Lock protecting the anchor
+
 	snapSync       bool         // Whether to run state sync over the snap protocol
 	SnapSyncer     *snap.Syncer // TODO(karalabe): make private! hack for now
 	stateSyncStart chan *stateSync
@@ -303,6 +306,62 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	}
 }
 
+// Anchor is a checkpoint anchored on bitcoin, the state root of the block at Number is
+// trusted without asking the peers.
+type Anchor struct {
+	Number uint64      `json:"number"`
+	Root   common.Hash `json:"root"`
+}
+
+// SetAnchor sets the checkpoint used as the fast sync pivot, the headers retrieved at its
+// height must carry its state root.
+func (d *Downloader) SetAnchor(anchor *Anchor) {
+	d.anchorLock.Lock()
+	defer d.anchorLock.Unlock()
+
+	d.anchor = anchor
+}
+
+// Anchor returns the checkpoint used as the fast sync pivot, if any.
+func (d *Downloader) Anchor() *Anchor {
+	d.anchorLock.RLock()
+	defer d.anchorLock.RUnlock()
+
+	return d.anchor
+}
+
+// anchoredPivot returns the fast sync pivot of the anchored checkpoint: the block
+// calcPivot picks for a head fsMinFullBlocks blocks past the anchor, which is the
+// first block of the anchor epoch. The anchor itself is verified when its header is
+// retrieved along the chain after the pivot.
+func (d *Downloader) anchoredPivot(anchor *Anchor) uint64 {
+	return d.calcPivot(anchor.Number + uint64(fsMinFullBlocks))
+}
+
+// stalePivot returns whether the head is so far past the pivot that the peers may have
+// garbage collected its state.
+func (d *Downloader) stalePivot(pivot, height uint64) bool {
+	return height > pivot+2*max(d.epoch, uint64(fsMinFullBlocks))
+}
+
+// verifyAnchor checks that the headers don't contradict the anchored checkpoint.
+func (d *Downloader) verifyAnchor(headers []*types.Header) error {
+	anchor := d.Anchor()
+	if anchor == nil || len(headers) == 0 {
+		return nil
+	}
+	first, last := headers[0].Number.Uint64(), headers[len(headers)-1].Number.Uint64()
+	if anchor.Number < first || anchor.Number > last {
+		return nil
+	}
+	for _, header := range headers {
+		if header.Number.Uint64() == anchor.Number && header.Root != anchor.Root {
+			return fmt.Errorf("%w: block %d root %x contradicts the anchored checkpoint %x", errInvalidChain, anchor.Number, header.Root, anchor.Root)
+		}
+	}
+	return nil
+}
+
 // Synchronising returns whether the downloader is currently retrieving blocks.
 func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
@@ -680,6 +739,18 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 	}
 	height := td.Uint64() - 1 // height == TD - 1
 	beginningEpochBlockNumber := d.calcPivot(height)
+	// Prefer the pivot of the anchored checkpoint if the head is far enough past the
+	// anchor, unless we already have the block or its state may be gone. The anchor is
+	// still checked against the headers when falling back to the latest pivot.
+	if anchor := d.Anchor(); mode == FastSync && anchor != nil && anchor.Number+uint64(fsMinFullBlocks) <= height {
+		pivot := d.anchoredPivot(anchor)
+		switch {
+		case d.stalePivot(pivot, height):
+			log.Warn("Anchored pivot is stale, using the latest pivot", "anchor", anchor.Number, "pivot", beginningEpochBlockNumber)
+		case pivot > d.blockchain.CurrentBlock().NumberU64():
+			beginningEpochBlockNumber = pivot
+		}
+	}
 	// NOTE: the beginningEpochBlockNumber is subtracting fsMinFullBlocks to the height,
 	// so, height and beginningEpochBlockNumber will be the same ONLY if the head is the genesis block
 	blocksFromHeightToEpochBlock := height - beginningEpochBlockNumber
@@ -724,6 +795,9 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 			if pivot.Number.Uint64() != beginningEpochBlockNumber {
 				return nil, nil, fmt.Errorf("%w: remote pivot %d != requested %d", errInvalidChain, pivot.Number, beginningEpochBlockNumber)
 			}
+			if err := d.verifyAnchor([]*types.Header{pivot}); err != nil {
+				return nil, nil, err
+			}
 			return head, pivot, nil
 
 		case <-timeout:
@@ -1655,6 +1729,10 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 				}
 				chunk := headers[:limit]
 
+				if err := d.verifyAnchor(chunk); err != nil {
+					rollbackErr = err
+					return err
+				}
 				// In case of header only syncing, validate the chunk immediately
 				if mode == FastSync || mode == LightSync {
 					// If we're importing pure headers, verify based on their recentness
@@ -1904,9 +1982,11 @@ func (d *Downloader) processFastSyncContent() error {
 			//	rawdb.WriteLastPivotNumber(d.stateDB, pivot.Number.Uint64())
 			//}
 
-			if height := latest.Number.Uint64(); height > pivot.Number.Uint64()+2*max(d.epoch, uint64(fsMinFullBlocks)) {
+			// An anchored pivot is moved as well, the anchor is still checked against the
+			// headers
+			if height := latest.Number.Uint64(); d.stalePivot(pivot.Number.Uint64(), height) {
 				newPivot := d.calcPivot(height)
-				log.Warn("Pivot became stale, moving", "old", pivot, "new", newPivot)
+				log.Warn("Pivot became stale, moving", "old", pivot.Number, "new", newPivot)
 
 				pivot = d.blockchain.GetHeaderByNumber(newPivot)
 				d.pivotLock.Lock()
@@ -548,6 +548,105 @@ func testCanonSync(t *testing.T, protocol uint, mode SyncMode) {
 	assertOwnChain(t, tester, chain.len())
 }
 
+// Tests that headers contradicting the checkpoint anchored on bitcoin are refused.
+func TestVerifyAnchor(t *testing.T) {
+	chain := testChainBase.shorten(64)
+	headers := make([]*types.Header, 0, chain.len())
+	for _, hash := range chain.chain {
+		headers = append(headers, chain.headerm[hash])
+	}
+	anchored := headers[32]
+
+	tester := newTester()
+	defer tester.terminate()
+
+	if err := tester.downloader.verifyAnchor(headers); err != nil {
+		t.Fatalf("failed to verify without anchor: %v", err)
+	}
+	tester.downloader.SetAnchor(&Anchor{Number: anchored.Number.Uint64(), Root: anchored.Root})
+	if err := tester.downloader.verifyAnchor(headers); err != nil {
+		t.Fatalf("failed to verify the anchored chain: %v", err)
+	}
+	tester.downloader.SetAnchor(&Anchor{Number: anchored.Number.Uint64(), Root: common.Hash{0xff}})
+	if err := tester.downloader.verifyAnchor(headers); !errors.Is(err, errInvalidChain) {
+		t.Fatalf("anchor mismatch error: have %v, want %v", err, errInvalidChain)
+	}
+	// Headers not reaching the anchored height can't contradict it
+	if err := tester.downloader.verifyAnchor(headers[:32]); err != nil {
+		t.Fatalf("failed to verify headers below the anchor: %v", err)
+	}
+}
+
+// ibftTesterPeer is a download tester peer advertising the total difficulty of an
+// IBFT chain, where it is the head number plus one.
+type ibftTesterPeer struct {
+	*downloadTesterPeer
+}
+
+func (p ibftTesterPeer) Head() (common.Hash, *big.Int) {
+	head := p.chain.headBlock()
+	return head.Hash(), head.TotalDifficulty()
+}
+
+// Tests that fast sync pivots on the first block of the epoch of the anchored
+// checkpoint, like calcPivot, and only if the head is far enough past the anchor but
+// not so far that the state of the pivot may be gone.
+func TestFetchHeadAnchor(t *testing.T) {
+	const epoch = 32
+	chain := testChainBase.shorten(300)
+	height := uint64(chain.len() - 1)
+	near := height - uint64(fsMinFullBlocks) + 1
+
+	tester := newTester()
+	defer tester.terminate()
+	tester.downloader.epoch = epoch
+	tester.downloader.cancelCh = make(chan struct{})
+	atomic.StoreUint32(&tester.downloader.mode, uint32(FastSync))
+
+	peer := ibftTesterPeer{&downloadTesterPeer{dl: tester, id: "peer", chain: chain}}
+	if err := tester.downloader.RegisterPeer("peer", eth.ETH66, peer); err != nil {
+		t.Fatalf("failed to register peer: %v", err)
+	}
+	fetchPivot := func(anchor *Anchor) (uint64, error) {
+		tester.downloader.SetAnchor(anchor)
+		_, pivot, err := tester.downloader.fetchHead(tester.downloader.peers.Peer("peer"))
+		if err != nil {
+			return 0, err
+		}
+		return pivot.Number.Uint64(), nil
+	}
+	root := func(number uint64) common.Hash {
+		return chain.headerm[chain.chain[number]].Root
+	}
+	tests := []struct {
+		anchor *Anchor
+		pivot  uint64
+	}{
+		// Without anchor the pivot is the first block of the epoch fsMinFullBlocks before the head
+		{nil, computePivot(height, epoch)},
+		// A mid-epoch anchor is snapped to the first block of its epoch
+		{&Anchor{Number: 200, Root: root(200)}, 193},
+		{&Anchor{Number: 193, Root: root(193)}, 193},
+		// An anchor closer than fsMinFullBlocks to the head is ignored
+		{&Anchor{Number: near, Root: root(near)}, computePivot(height, epoch)},
+		// A stale anchor falls back to the latest pivot
+		{&Anchor{Number: 100, Root: root(100)}, computePivot(height, epoch)},
+	}
+	for i, tt := range tests {
+		pivot, err := fetchPivot(tt.anchor)
+		if err != nil {
+			t.Fatalf("test %d: failed to fetch head: %v", i, err)
+		}
+		if pivot != tt.pivot {
+			t.Errorf("test %d: pivot mismatch: have %d, want %d", i, pivot, tt.pivot)
+		}
+	}
+	// A pivot contradicting the anchored root is refused
+	if _, err := fetchPivot(&Anchor{Number: 193, Root: common.Hash{0xff}}); !errors.Is(err, errInvalidChain) {
+		t.Fatalf("anchor mismatch error: have %v, want %v", err, errInvalidChain)
+	}
+}
+
 // Tests that if a large batch of blocks are being downloaded, it is throttled
 // until the cached blocks are retrieved.
 func TestThrottling66Full(t *testing.T) { testThrottling(t, eth.ETH66, FullSync) }
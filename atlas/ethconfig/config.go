@@ -57,6 +57,7 @@ var Defaults = Config{
 	SyncMode:                downloader.FullSync,
 	NetworkId:               params.MainnetNetWorkID,
 	TxLookupLimit:           2350000,
+	CheckPoint:              chain.DefaultCheckPointConfig,
 	LightPeers:              100,
 	LightServ:               0,
 	UltraLightFraction:      75,
@@ -125,6 +126,9 @@ type Config struct {
 	NoPrefetch       bool // Whether to disable prefetching and only load state on demand
 	VerifyCheckPoint bool `toml:",omitempty"`
 
+	// CheckPoint selects the source of the checkpoints anchored on bitcoin and the policy on a mismatch
+	CheckPoint chain.CheckPointConfig `toml:",omitempty"`
+
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
 	// Whitelist of required block number -> hash values to accept
@@ -181,6 +185,10 @@ type Config struct {
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// Enables the speculative parallel execution of block transactions
+	ParallelTxExecution bool
+	ParallelTxWorkers   int
+
 	// Istanbul options
 	Istanbul istanbul.Config
 
@@ -26,6 +26,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPruning               bool
 		NoPrefetch              bool
 		VerifyCheckPoint 		bool 				`toml:",omitempty"`
+		CheckPoint              chain.CheckPointConfig `toml:",omitempty"`
 		TxLookupLimit           uint64                 `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               int                    `toml:",omitempty"`
@@ -54,6 +55,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxPool                  chain.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
+		ParallelTxExecution     bool
+		ParallelTxWorkers       int
 		Istanbul                istanbul.Config
 		DocRoot                 string `toml:"-"`
 		RPCGasCap               uint64
@@ -73,6 +76,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SnapDiscoveryURLs = c.SnapDiscoveryURLs
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
+	enc.CheckPoint = c.CheckPoint
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.Whitelist = c.Whitelist
 	enc.LightServ = c.LightServ
@@ -101,6 +105,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.ParallelTxExecution = c.ParallelTxExecution
+	enc.ParallelTxWorkers = c.ParallelTxWorkers
 	enc.Istanbul = c.Istanbul
 	enc.DocRoot = c.DocRoot
 	enc.RPCGasCap = c.RPCGasCap
@@ -124,6 +130,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SnapDiscoveryURLs       []string
 		NoPruning               *bool
 		NoPrefetch              *bool
+		CheckPoint              *chain.CheckPointConfig `toml:",omitempty"`
 		TxLookupLimit           *uint64                `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               *int                   `toml:",omitempty"`
@@ -152,6 +159,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TxPool                  *chain.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
+		ParallelTxExecution     *bool
+		ParallelTxWorkers       *int
 		Istanbul                *istanbul.Config
 		DocRoot                 *string `toml:"-"`
 		RPCGasCap               *uint64
@@ -188,6 +197,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPrefetch != nil {
 		c.NoPrefetch = *dec.NoPrefetch
 	}
+	if dec.CheckPoint != nil {
+		c.CheckPoint = *dec.CheckPoint
+	}
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
@@ -272,6 +284,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.ParallelTxExecution != nil {
+		c.ParallelTxExecution = *dec.ParallelTxExecution
+	}
+	if dec.ParallelTxWorkers != nil {
+		c.ParallelTxWorkers = *dec.ParallelTxWorkers
+	}
 	if dec.Istanbul != nil {
 		c.Istanbul = *dec.Istanbul
 	}
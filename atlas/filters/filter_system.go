@@ -418,7 +418,7 @@ func (es *EventSystem) lightFilterLogs(header *types.Header, addresses []common.
 			}
 		}
 		logs := filterLogs(unfiltered, nil, nil, addresses, topics)
-		if len(logs) > 0 && logs[0].TxHash == (common.Hash{}) {
+		if len(logs) > 0 && logs[0].TxHash == (common.Hash{}) && !logs[0].System {
 			// We have matching but non-derived logs
 			receipts, err := es.backend.GetReceipts(ctx, header.Hash())
 			if err != nil {
@@ -108,6 +108,34 @@ func (context *chainContext) GetHeader(hash common.Hash, number uint64) *types.H
 	return header
 }
 
+func (context *chainContext) GetHeaderByNumber(number uint64) *types.Header {
+	header, err := context.api.backend.HeaderByNumber(context.ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (context *chainContext) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, err := context.api.backend.HeaderByHash(context.ctx, hash)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (context *chainContext) CurrentHeader() *types.Header {
+	header, err := context.api.backend.HeaderByNumber(context.ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (context *chainContext) Config() *params.ChainConfig {
+	return context.api.backend.ChainConfig()
+}
+
 // chainContext construts the context reader which is used by the evm for reading
 // the necessary chain context.
 func (api *API) chainContext(ctx context.Context) abstract.ChainContext {
@@ -162,6 +190,8 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// SystemCalls appends the trace of the block system calls to the block traces
+	SystemCalls *bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -625,6 +655,15 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if failed != nil {
 		return nil, failed
 	}
+	// The system calls are traced as a last result, like the block receipt
+	if config != nil && config.SystemCalls != nil && *config.SystemCalls {
+		res, err := api.traceSystemCalls(ctx, block, config)
+		if err != nil {
+			results = append(results, &txTraceResult{Error: err.Error()})
+		} else {
+			results = append(results, &txTraceResult{Result: res})
+		}
+	}
 	return results, nil
 }
 
@@ -842,15 +881,12 @@ func (api *API) TraceCall(ctx context.Context, args atlasapi.TransactionArgs, bl
 	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
 }
 
-// traceTx configures a new tracer according to the provided configuration, and
-// executes the given message in the provided environment. The return value will
-// be tracer dependent.
-func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
+// newTracer assembles the structured logger or the JavaScript tracer of the configuration,
+// the returned cancel function must be called once the tracing is over.
+func (api *API) newTracer(ctx context.Context, txctx *Context, config *TraceConfig) (vm.Tracer, context.CancelFunc, error) {
 	var (
-		tracer    vm.Tracer
-		err       error
-		txContext = chain.NewEVMTxContext(message)
+		tracer vm.Tracer
+		err    error
 	)
 	switch {
 	case config != nil && config.Tracer != nil:
@@ -858,12 +894,12 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 		timeout := defaultTraceTimeout
 		if config.Timeout != nil {
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 		// Constuct the JavaScript tracer to execute with
 		if tracer, err = New(*config.Tracer, txctx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -873,7 +909,7 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 				tracer.(*Tracer).Stop(errors.New("execution timeout"))
 			}
 		}()
-		defer cancel()
+		return tracer, cancel, nil
 
 	case config == nil:
 		tracer = vm.NewStructLogger(nil)
@@ -881,6 +917,20 @@ func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Conte
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
+	return tracer, func() {}, nil
+}
+
+// traceTx configures a new tracer according to the provided configuration, and
+// executes the given message in the provided environment. The return value will
+// be tracer dependent.
+func (api *API) traceTx(ctx context.Context, message chain.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	txContext := chain.NewEVMTxContext(message)
+	tracer, cancel, err := api.newTracer(ctx, txctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
 	// Run the transaction with tracing enabled.
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: true, Tracer: tracer, NoBaseFee: true})
 
@@ -0,0 +1,209 @@
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/apis/atlasapi"
+	"github.com/mapprotocol/atlas/consensus"
+	"github.com/mapprotocol/atlas/contracts/random"
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/chain"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
+)
+
+const (
+	// systemCallRandomness is the random beacon reveal made before the block transactions
+	systemCallRandomness = "randomness"
+	// systemCallFinalize is a call of the block finalization made after the block transactions
+	systemCallFinalize = "finalize"
+)
+
+var errNoSystemCalls = errors.New("system calls are only traced with the istanbul engine")
+
+// systemCallTraceResult is the trace of a state changing system contract call of the
+// consensus engine
+type systemCallTraceResult struct {
+	Type   string         `json:"type"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Input  hexutil.Bytes  `json:"input"`
+	Result interface{}    `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// systemCallRunner is a vm.EVMRunner tracing each state changing call with a new tracer,
+// the queries are not traced
+type systemCallRunner struct {
+	api       *API
+	ctx       context.Context
+	chainCtx  *chainContext
+	header    *types.Header
+	blockHash common.Hash
+	statedb   *state.StateDB
+	config    *TraceConfig
+
+	callType     string
+	results      []*systemCallTraceResult
+	dontMeterGas bool
+}
+
+func (r *systemCallRunner) newEVM(from common.Address, vmConfig vm.Config) *vm.EVM {
+	blockCtx := vmcontext.New(from, common.Big0, r.header, r.chainCtx, nil)
+	evm := vm.NewEVM(blockCtx, vm.TxContext{}, r.statedb, r.api.backend.ChainConfig(), vmConfig)
+	if r.dontMeterGas {
+		evm.StopGasMetering()
+	}
+	return evm
+}
+
+func (r *systemCallRunner) Execute(recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return r.traceCall(vmcontext.VMAddress, recipient, input, gas, value)
+}
+
+func (r *systemCallRunner) ExecuteFrom(sender, recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	return r.traceCall(sender, recipient, input, gas, value)
+}
+
+func (r *systemCallRunner) Query(recipient common.Address, input []byte, gas uint64) ([]byte, error) {
+	evm := r.newEVM(vmcontext.VMAddress, vm.Config{})
+	ret, _, err := evm.StaticCall(vm.AccountRef(evm.Origin), recipient, input, gas)
+	return ret, err
+}
+
+func (r *systemCallRunner) StopGasMetering() {
+	r.dontMeterGas = true
+}
+
+func (r *systemCallRunner) StartGasMetering() {
+	r.dontMeterGas = false
+}
+
+// traceCall runs the call with a new tracer and records its trace
+func (r *systemCallRunner) traceCall(sender, recipient common.Address, input []byte, gas uint64, value *big.Int) ([]byte, error) {
+	result := &systemCallTraceResult{Type: r.callType, From: sender, To: recipient, Input: common.CopyBytes(input)}
+	r.results = append(r.results, result)
+
+	tracer, cancel, err := r.api.newTracer(r.ctx, &Context{BlockHash: r.blockHash}, r.config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	evm := r.newEVM(sender, vm.Config{Debug: true, Tracer: tracer})
+	ret, leftOverGas, err := evm.Call(vm.AccountRef(sender), recipient, input, gas, value)
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		result.Result = &atlasapi.ExecutionResult{
+			Gas:         gas - leftOverGas,
+			Failed:      err != nil,
+			ReturnValue: fmt.Sprintf("%x", ret),
+			StructLogs:  atlasapi.FormatLogs(tracer.StructLogs()),
+		}
+	case *Tracer:
+		if res, traceErr := tracer.GetResult(); traceErr != nil {
+			result.Error = traceErr.Error()
+		} else {
+			result.Result = res
+		}
+	}
+	return ret, err
+}
+
+// TraceBlockSystemCalls replays the system contract calls of the consensus engine in the
+// block, the random beacon reveal before the transactions and the block finalization (epoch
+// rewards, validator scores, deregistration and activation of the pending votes) after them,
+// and traces each state changing call with the requested tracer.
+func (api *API) TraceBlockSystemCalls(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*systemCallTraceResult, error) {
+	block, err := api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceSystemCalls(ctx, block, config)
+}
+
+// traceSystemCalls executes the block on top of its parent state, tracing the system calls
+// made around the transactions.
+func (api *API) traceSystemCalls(ctx context.Context, block *types.Block, config *TraceConfig) ([]*systemCallTraceResult, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	engine, ok := api.backend.Engine().(consensus.Istanbul)
+	if !ok {
+		return nil, errNoSystemCalls
+	}
+	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finalize updates the header root, trace on a copy
+	header := types.CopyHeader(block.Header())
+	chainCtx := &chainContext{api: api, ctx: ctx}
+	runner := &systemCallRunner{
+		api:       api,
+		ctx:       ctx,
+		chainCtx:  chainCtx,
+		header:    header,
+		blockHash: block.Hash(),
+		statedb:   statedb,
+		config:    config,
+		callType:  systemCallRandomness,
+	}
+	if random.IsRunning(runner) {
+		author, err := engine.Author(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := random.RevealAndCommit(runner, block.Randomness().Revealed, block.Randomness().Committed, author); err != nil {
+			return nil, err
+		}
+		statedb.IntermediateRoot(true)
+	}
+
+	// Apply the transactions without tracing
+	var (
+		signer   = types.MakeSigner(api.backend.ChainConfig(), block.Number())
+		blockCtx = chain.NewEVMBlockContext(header, chainCtx, nil)
+	)
+	for i, tx := range block.Transactions() {
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		statedb.Prepare(tx.Hash(), i)
+		vmenv := vm.NewEVM(blockCtx, chain.NewEVMTxContext(msg), statedb, api.backend.ChainConfig(), vm.Config{})
+		if _, err := chain.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+	}
+
+	runner.callType = systemCallFinalize
+	engine.FinalizeWithEVMRunner(chainCtx, header, statedb, block.Transactions(), runner)
+	if header.Root != block.Root() {
+		return nil, fmt.Errorf("system calls replay diverged from the block state (remote: %x local: %x)", block.Root(), header.Root)
+	}
+	return runner.results, nil
+}
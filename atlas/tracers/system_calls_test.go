@@ -0,0 +1,228 @@
+package tracers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/apis/atlasapi"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/backend"
+	"github.com/mapprotocol/atlas/contracts/random"
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/chain"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func TestSystemCallRunner(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0xc0de")
+		view     = common.HexToAddress("0xc0df")
+	)
+	genesis := &chain.Genesis{Alloc: chain.GenesisAlloc{
+		// PUSH1 1 PUSH1 0 MSTORE STOP
+		contract: {Balance: big.NewInt(0), Code: common.FromHex("0x600160005200")},
+		// STOP
+		view: {Balance: big.NewInt(0), Code: common.FromHex("0x00")},
+	}}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *chain.BlockGen) {})
+	api := NewAPI(backend)
+
+	block := backend.chain.GetBlockByNumber(1)
+	statedb, err := backend.chain.StateAt(block.Root())
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	ctx := context.Background()
+	runner := &systemCallRunner{
+		api:       api,
+		ctx:       ctx,
+		chainCtx:  &chainContext{api: api, ctx: ctx},
+		header:    block.Header(),
+		blockHash: block.Hash(),
+		statedb:   statedb,
+		callType:  systemCallFinalize,
+	}
+
+	if _, err := runner.Query(view, nil, 100000); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(runner.results) != 0 {
+		t.Fatalf("query traced: have %d results, want 0", len(runner.results))
+	}
+	if _, err := runner.Execute(contract, []byte{0x01}, 100000, big.NewInt(0)); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if len(runner.results) != 1 {
+		t.Fatalf("results mismatch: have %d, want 1", len(runner.results))
+	}
+	res := runner.results[0]
+	if res.Type != systemCallFinalize || res.From != vmcontext.VMAddress || res.To != contract || res.Error != "" {
+		t.Errorf("trace mismatch: have %+v", res)
+	}
+	exec, ok := res.Result.(*atlasapi.ExecutionResult)
+	if !ok {
+		t.Fatalf("result type mismatch: have %T", res.Result)
+	}
+	if exec.Failed || len(exec.StructLogs) != 4 {
+		t.Errorf("execution mismatch: failed %v, %d struct logs, want 4", exec.Failed, len(exec.StructLogs))
+	}
+
+	// the faker is not an istanbul engine
+	if _, err := api.TraceBlockSystemCalls(ctx, 1, nil); err != errNoSystemCalls {
+		t.Errorf("error mismatch: have %v, want %v", err, errNoSystemCalls)
+	}
+}
+
+// TestTraceBlockSystemCallsReplay replays the system calls of a block made by the istanbul
+// engine and checks that the replay reaches the state root of the block.
+func TestTraceBlockSystemCallsReplay(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	blsPrivateKey, _ := blscrypto.CryptoType().ECDSAToBLS(key)
+	blsPublicKey, _ := blscrypto.CryptoType().PrivateToPublic(blsPrivateKey)
+	blsG1PublicKey, _ := blscrypto.CryptoType().PrivateToG1Public(blsPrivateKey)
+	extra, err := rlp.EncodeToBytes(&types.IstanbulExtra{
+		AddedValidators:             []common.Address{addr},
+		AddedValidatorsPublicKeys:   []blscrypto.SerializedPublicKey{blsPublicKey},
+		AddedValidatorsG1PublicKeys: []blscrypto.SerializedG1PublicKey{blsG1PublicKey},
+		RemovedValidators:           big.NewInt(0),
+		Seal:                        []byte{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := *params.IstanbulTestChainConfig
+	config.Istanbul = &params.IstanbulConfig{Epoch: 10, LookbackWindow: 3}
+	// The registry resolves the gold token and the random contracts to a contract returning
+	// zeros, the other contracts are not deployed
+	token := common.HexToAddress("0x70c3")
+	registry := "0x600435" + // PUSH1 4 CALLDATALOAD
+		"807f" + common.Bytes2Hex(params.GoldTokenRegistryId[:]) + "14605257" + // DUP1 PUSH32 id EQ PUSH1 82 JUMPI
+		"7f" + common.Bytes2Hex(params.RandomRegistryId[:]) + "14605257" + // PUSH32 id EQ PUSH1 82 JUMPI
+		"600080fd" + // PUSH1 0 DUP1 REVERT
+		"5b73" + common.Bytes2Hex(token.Bytes()) + "60005260206000f3" // JUMPDEST PUSH20 token PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	genesis := &chain.Genesis{
+		Config:    &config,
+		GasLimit:  ethparams.GenesisGasLimit,
+		ExtraData: append(make([]byte, types.IstanbulExtraVanity), extra...),
+		Alloc: chain.GenesisAlloc{
+			addr:                                {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(ethparams.Ether))},
+			params.RegistrySmartContractAddress: {Balance: big.NewInt(0), Code: common.FromHex(registry)},
+			// the token has no supply yet, so the finalization increases it: PUSH1 32 PUSH1 0 RETURN
+			token: {Balance: big.NewInt(0), Code: common.FromHex("0x60206000f3")},
+		},
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	istanbulConfig := *istanbul.DefaultConfig
+	istanbulConfig.ReplicaStateDBPath = ""
+	istanbulConfig.ValidatorEnodeDBPath = ""
+	istanbulConfig.VersionCertificateDBPath = ""
+	istanbulConfig.RoundStateDBPath = ""
+	istanbul.ApplyParamsChainConfigToConfig(&config, &istanbulConfig)
+	engine := backend.New(&istanbulConfig, db).(*backend.Backend)
+	genesis.MustCommit(db)
+	blockchain, err := chain.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer blockchain.Stop()
+	engine.SetChain(blockchain, blockchain.CurrentBlock, func(hash common.Hash) (*state.StateDB, error) {
+		return blockchain.StateAt(blockchain.GetHeaderByHash(hash).Root)
+	})
+
+	// Make a block transferring some value, finalized by the engine
+	parent := blockchain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit(),
+		Coinbase:   addr,
+	}
+	if err := engine.Prepare(blockchain, header); err != nil {
+		t.Fatal(err)
+	}
+	statedb, err := blockchain.StateAt(parent.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := random.RevealAndCommit(blockchain.NewEVMRunner(header, statedb), common.Hash{}, common.Hash{}, addr); err != nil {
+		t.Fatal(err)
+	}
+	// the miner commits the randomness before applying the transactions
+	statedb.IntermediateRoot(true)
+	signer := types.MakeSigner(&config, header.Number)
+	tx, _ := types.SignTx(types.NewTransaction(0, common.HexToAddress("0xdead"), big.NewInt(1000), ethparams.TxGas, big.NewInt(1000*ethparams.GWei), nil), signer, key)
+	statedb.Prepare(tx.Hash(), 0)
+	receipt, err := chain.ApplyTransaction(&config, blockchain, &addr, new(core.GasPool).AddGas(header.GasLimit), statedb, header, tx, &header.GasUsed, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := engine.FinalizeAndAssemble(blockchain, header, statedb, types.Transactions{tx}, types.Receipts{receipt}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seal the block, the fees of the replay are paid to the signer
+	sealed := block.Header()
+	sealHash := crypto.Keccak256(rlpHash(t, types.IstanbulFilteredHeader(sealed, false)).Bytes())
+	seal, err := crypto.Sign(sealHash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	istanbulExtra, err := types.ExtractIstanbulExtra(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	istanbulExtra.Seal = seal
+	payload, err := rlp.EncodeToBytes(istanbulExtra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed.Extra = append(sealed.Extra[:types.IstanbulExtraVanity], payload...)
+	block = block.WithHeader(sealed)
+	if err := blockchain.WriteBlockWithState(block, types.Receipts{receipt}, receipt.Logs, statedb, true); err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewAPI(&testBackend{chainConfig: &config, engine: engine, chaindb: db, chain: blockchain})
+	ctx := context.Background()
+	results, err := api.TraceBlockSystemCalls(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to trace the system calls: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results mismatch: have %d, want 2", len(results))
+	}
+	for i, want := range []string{systemCallRandomness, systemCallFinalize} {
+		if res := results[i]; res.Type != want || res.From != vmcontext.VMAddress || res.To != token || res.Error != "" {
+			t.Errorf("trace %d mismatch: have %+v", i, res)
+		}
+	}
+
+	// a replay diverging from the block is rejected
+	diverged := types.CopyHeader(block.Header())
+	diverged.Root = common.Hash{1}
+	if _, err := api.traceSystemCalls(ctx, block.WithHeader(diverged), nil); err == nil {
+		t.Fatal("replay of a block with another state root succeeded")
+	}
+}
+
+func rlpHash(t *testing.T, x interface{}) common.Hash {
+	data, err := rlp.EncodeToBytes(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.Keccak256Hash(data)
+}
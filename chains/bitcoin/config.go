@@ -0,0 +1,48 @@
+package bitcoin
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+const (
+	// bitcoin has no chain id, these identify it in cross-chain messages
+	MainnetChainID = 1360095883558913
+	TestnetChainID = 1360095883558914
+
+	defaultConfirmations = 6
+)
+
+type ChainConfig struct {
+	ChainID uint64
+	Params  *chaincfg.Params
+	// Confirmations is the number of blocks, including its own, on top of which a transaction
+	// is considered final
+	Confirmations uint64
+}
+
+var chainConfigs = map[uint64]*ChainConfig{
+	MainnetChainID: {
+		ChainID:       MainnetChainID,
+		Params:        &chaincfg.MainNetParams,
+		Confirmations: defaultConfirmations,
+	},
+	TestnetChainID: {
+		ChainID:       TestnetChainID,
+		Params:        &chaincfg.TestNet3Params,
+		Confirmations: defaultConfirmations,
+	},
+}
+
+func GetChainConfig(chainID uint64) (*ChainConfig, error) {
+	config, ok := chainConfigs[chainID]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bitcoin chain id: %d", chainID)
+	}
+	return config, nil
+}
+
+func (c *ChainConfig) blocksPerRetarget() uint64 {
+	return uint64(c.Params.TargetTimespan / c.Params.TargetTimePerBlock)
+}
@@ -0,0 +1,14 @@
+package bitcoin
+
+import "errors"
+
+var (
+	errNotInitialized          = errors.New("please initialize header store")
+	errUnknownAncestor         = errors.New("unknown ancestor")
+	errInvalidCheckpoint       = errors.New("invalid checkpoint headers")
+	errInvalidHeader           = errors.New("invalid bitcoin header")
+	errInsufficientConfirms    = errors.New("insufficient confirmations")
+	errInvalidMerkleProof      = errors.New("invalid merkle proof")
+	errAmbiguousTransaction    = errors.New("64 byte transactions can't be proven")
+	errTransactionNotCanonical = errors.New("block is not in the canonical chain")
+)
@@ -0,0 +1,113 @@
+package bitcoin
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StoredHeader is a bitcoin header with its position in the chain
+type StoredHeader struct {
+	Header []byte // the 80 byte serialized header
+	Height uint64
+	// Work is the total work of the chain ending at the header
+	Work *big.Int
+
+	header *wire.BlockHeader
+}
+
+func decodeHeader(data []byte) (*wire.BlockHeader, error) {
+	if len(data) != wire.MaxBlockHeaderPayload {
+		return nil, errInvalidHeader
+	}
+	var header wire.BlockHeader
+	if err := header.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+func (h *StoredHeader) BlockHeader() *wire.BlockHeader {
+	if h.header == nil {
+		h.header, _ = decodeHeader(h.Header)
+	}
+	return h.header
+}
+
+// Hash returns the block hash in its internal byte order, which is the reverse of the
+// hex string that bitcoin displays
+func (h *StoredHeader) Hash() common.Hash {
+	return common.Hash(h.BlockHeader().BlockHash())
+}
+
+// headerCtx gives btcd the context of a header stored in, or being added to, the store
+type headerCtx struct {
+	*StoredHeader
+	chain *headerChain
+}
+
+func (h *headerCtx) Height() int32 {
+	return int32(h.StoredHeader.Height)
+}
+
+func (h *headerCtx) Bits() uint32 {
+	return h.BlockHeader().Bits
+}
+
+func (h *headerCtx) Timestamp() int64 {
+	return h.BlockHeader().Timestamp.Unix()
+}
+
+func (h *headerCtx) Parent() blockchain.HeaderCtx {
+	parent := h.chain.header(common.Hash(h.BlockHeader().PrevBlock))
+	if parent == nil {
+		return nil
+	}
+	return parent
+}
+
+func (h *headerCtx) RelativeAncestorCtx(distance int32) blockchain.HeaderCtx {
+	if distance < 0 || int64(distance) > int64(h.StoredHeader.Height) {
+		return nil
+	}
+	ancestor := h.chain.ancestor(h, h.StoredHeader.Height-uint64(distance))
+	if ancestor == nil {
+		return nil
+	}
+	return ancestor
+}
+
+// chainCtx gives btcd the difficulty parameters of the network, the store doesn't use
+// btcd checkpoints
+type chainCtx struct {
+	params *chaincfg.Params
+}
+
+func (c *chainCtx) ChainParams() *chaincfg.Params {
+	return c.params
+}
+
+func (c *chainCtx) BlocksPerRetarget() int32 {
+	return int32(c.params.TargetTimespan / c.params.TargetTimePerBlock)
+}
+
+func (c *chainCtx) MinRetargetTimespan() int64 {
+	return int64(c.params.TargetTimespan.Seconds()) / c.params.RetargetAdjustmentFactor
+}
+
+func (c *chainCtx) MaxRetargetTimespan() int64 {
+	return int64(c.params.TargetTimespan.Seconds()) * c.params.RetargetAdjustmentFactor
+}
+
+func (c *chainCtx) VerifyCheckpoint(int32, *chainhash.Hash) bool {
+	return true
+}
+
+func (c *chainCtx) FindPreviousCheckpoint() (blockchain.HeaderCtx, error) {
+	return nil, nil
+}
@@ -0,0 +1,439 @@
+package bitcoin
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	MaxHeaderLimit = 100000
+	// medianTimeBlocks is the number of headers a checkpoint must carry so that the
+	// median time past of the next header can be computed
+	medianTimeBlocks = 11
+)
+
+// HeaderStore follows the bitcoin chain with the most work, headers of side chains are kept
+// so that a heavier fork can be built in several inserts
+type HeaderStore struct {
+	ChainID   uint64
+	CurNumber uint64
+	CurHash   common.Hash
+	CurWork   *big.Int
+}
+
+// Checkpoint is the trusted start of the store: the last medianTimeBlocks headers ending at
+// a difficulty retarget boundary
+type Checkpoint struct {
+	Height  uint64
+	Headers [][]byte
+}
+
+func NewHeaderStore(chainID uint64) *HeaderStore {
+	return &HeaderStore{ChainID: chainID}
+}
+
+func (hs *HeaderStore) storeDbKey() common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("btc%d", hs.ChainID)))
+}
+
+func (hs *HeaderStore) headerDbKey(hash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("btc%d-h", hs.ChainID)), hash[:])
+}
+
+func (hs *HeaderStore) canonicalDbKey(number uint64) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("btc%d-c%d", hs.ChainID, number%MaxHeaderLimit)))
+}
+
+func (hs *HeaderStore) earliestDbKey() common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("btc%d-earliest", hs.ChainID)))
+}
+
+func (hs *HeaderStore) Store(db types.StateDB) error {
+	data, err := rlp.EncodeToBytes(hs)
+	if err != nil {
+		log.Error("Failed to RLP encode HeaderStore", "err", err)
+		return err
+	}
+	db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.storeDbKey(), data)
+	return nil
+}
+
+func (hs *HeaderStore) Load(db types.StateDB) error {
+	data := db.GetPOWState(chains.BitcoinHeaderStoreAddress, hs.storeDbKey())
+	if len(data) == 0 {
+		return errNotInitialized
+	}
+	var h HeaderStore
+	if err := rlp.DecodeBytes(data, &h); err != nil {
+		log.Error("HeaderStore RLP decode failed", "err", err)
+		return fmt.Errorf("HeaderStore RLP decode failed, error: %s", err.Error())
+	}
+	hs.CurNumber, hs.CurHash, hs.CurWork = h.CurNumber, h.CurHash, h.CurWork
+	return nil
+}
+
+func (hs *HeaderStore) storeHeader(db types.StateDB, header *StoredHeader) error {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.headerDbKey(header.Hash()), data)
+	return nil
+}
+
+func (hs *HeaderStore) loadHeader(db types.StateDB, hash common.Hash) *StoredHeader {
+	data := db.GetPOWState(chains.BitcoinHeaderStoreAddress, hs.headerDbKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var header StoredHeader
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		log.Error("Invalid bitcoin header RLP", "hash", hash, "err", err)
+		return nil
+	}
+	if header.BlockHeader() == nil {
+		return nil
+	}
+	return &header
+}
+
+func (hs *HeaderStore) writeCanonicalHash(db types.StateDB, number uint64, hash common.Hash) {
+	db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.canonicalDbKey(number), hash[:])
+}
+
+func (hs *HeaderStore) readCanonicalHash(db types.StateDB, number uint64) common.Hash {
+	if number > hs.CurNumber {
+		return common.Hash{}
+	}
+	return common.BytesToHash(db.GetPOWState(chains.BitcoinHeaderStoreAddress, hs.canonicalDbKey(number)))
+}
+
+// getCanonicalHeader returns the header at number of the chain with the most work
+func (hs *HeaderStore) getCanonicalHeader(db types.StateDB, number uint64) *StoredHeader {
+	hash := hs.readCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	header := hs.loadHeader(db, hash)
+	if header == nil || header.Height != number {
+		return nil
+	}
+	return header
+}
+
+// retention returns the governance configured number of recent headers to keep, zero if
+// it was never configured
+func (hs *HeaderStore) retention(db types.StateDB) uint64 {
+	retention := chains.GetHeaderRetention(db, chains.ChainType(hs.ChainID))
+	if retention > MaxHeaderLimit {
+		retention = MaxHeaderLimit
+	}
+	return retention
+}
+
+// earliestNumber returns the lowest block number whose canonical header may still be stored
+func (hs *HeaderStore) earliestNumber(db types.StateDB) uint64 {
+	var earliest uint64
+	if data := db.GetPOWState(chains.BitcoinHeaderStoreAddress, hs.earliestDbKey()); len(data) != 0 {
+		if err := rlp.DecodeBytes(data, &earliest); err != nil {
+			log.Error("decode earliest stored number failed", "err", err)
+		}
+	}
+	// the ring of canonical slots only holds the last MaxHeaderLimit numbers
+	if hs.CurNumber >= MaxHeaderLimit && earliest < hs.CurNumber-MaxHeaderLimit+1 {
+		earliest = hs.CurNumber - MaxHeaderLimit + 1
+	}
+	return earliest
+}
+
+func (hs *HeaderStore) storeEarliestNumber(db types.StateDB, number uint64) error {
+	data, err := rlp.EncodeToBytes(number)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.earliestDbKey(), data)
+	return nil
+}
+
+// delOldHeaders deletes the canonical headers evicted from the configured retention window,
+// at most limit block numbers of them. The headers of side chains aren't indexed by number
+// and stay stored.
+func (hs *HeaderStore) delOldHeaders(db types.StateDB, limit uint64) error {
+	retention := hs.retention(db)
+	if retention == 0 || hs.CurNumber < retention {
+		return nil
+	}
+	keepFrom := hs.CurNumber - retention + 1
+	earliest := hs.earliestNumber(db)
+	if earliest >= keepFrom {
+		return nil
+	}
+	end := keepFrom
+	if end-earliest > limit {
+		end = earliest + limit
+	}
+	for number := earliest; number < end; number++ {
+		if header := hs.getCanonicalHeader(db, number); header != nil {
+			db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.headerDbKey(header.Hash()), nil)
+			db.SetPOWState(chains.BitcoinHeaderStoreAddress, hs.canonicalDbKey(number), nil)
+		}
+	}
+	log.Debug("pruned bitcoin headers", "from", earliest, "to", end)
+	return hs.storeEarliestNumber(db, end)
+}
+
+// EarliestStoredNumber returns the lowest block number whose header may still be used
+// to verify transactions
+func (hs *HeaderStore) EarliestStoredNumber(db types.StateDB) (uint64, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	return hs.earliestNumber(db), nil
+}
+
+// MinRetention returns the lower bound of the retention setting, new headers need the
+// ancestors of a whole difficulty retarget period
+func (hs *HeaderStore) MinRetention() uint64 {
+	config, err := GetChainConfig(hs.ChainID)
+	if err != nil {
+		return chains.MinHeaderRetention
+	}
+	return config.blocksPerRetarget() + chains.MinHeaderRetention
+}
+
+// MaxRetention returns the upper bound of the retention setting
+func (hs *HeaderStore) MaxRetention() uint64 {
+	return MaxHeaderLimit
+}
+
+// SetRetention sets the number of recent headers of the chain the store keeps
+func (hs *HeaderStore) SetRetention(db types.StateDB, retention uint64) error {
+	return chains.SetHeaderRetention(db, chains.ChainType(hs.ChainID), retention)
+}
+
+func (hs *HeaderStore) ResetHeaderStore(db types.StateDB, checkpoint []byte, td *big.Int) error {
+	var cp Checkpoint
+	if err := rlp.DecodeBytes(checkpoint, &cp); err != nil {
+		log.Error("rlp decode bitcoin checkpoint failed.", "err", err)
+		return chains.ErrRLPDecode
+	}
+	config, err := GetChainConfig(hs.ChainID)
+	if err != nil {
+		return err
+	}
+	if len(cp.Headers) == 0 || len(cp.Headers) > medianTimeBlocks || uint64(len(cp.Headers)) > cp.Height+1 {
+		return errInvalidCheckpoint
+	}
+	if len(cp.Headers) < medianTimeBlocks && uint64(len(cp.Headers)) != cp.Height+1 {
+		return errInvalidCheckpoint
+	}
+	if !config.Params.PoWNoRetargeting && cp.Height%config.blocksPerRetarget() != 0 {
+		return errInvalidCheckpoint
+	}
+
+	headers := make([]*StoredHeader, len(cp.Headers))
+	for i, data := range cp.Headers {
+		header, err := decodeHeader(data)
+		if err != nil {
+			return err
+		}
+		if i > 0 && header.PrevBlock != headers[i-1].BlockHeader().BlockHash() {
+			return errInvalidCheckpoint
+		}
+		headers[i] = &StoredHeader{Header: data, Height: cp.Height - uint64(len(cp.Headers)-1-i), header: header}
+	}
+
+	// td is the total work of the chain ending at the checkpoint
+	tip := headers[len(headers)-1]
+	if td == nil {
+		td = blockchain.CalcWork(tip.BlockHeader().Bits)
+	}
+	work := new(big.Int).Set(td)
+	for i := len(headers) - 1; i >= 0; i-- {
+		headers[i].Work = new(big.Int).Set(work)
+		work.Sub(work, blockchain.CalcWork(headers[i].BlockHeader().Bits))
+	}
+
+	hs.CurNumber, hs.CurHash, hs.CurWork = tip.Height, tip.Hash(), tip.Work
+	for _, header := range headers {
+		if err := hs.storeHeader(db, header); err != nil {
+			return err
+		}
+		hs.writeCanonicalHash(db, header.Height, header.Hash())
+	}
+	if hs.retention(db) != 0 {
+		if err := hs.storeEarliestNumber(db, headers[0].Height); err != nil {
+			return err
+		}
+	}
+	return hs.Store(db)
+}
+
+// headerChain looks headers up in the store and in the batch being added to it
+type headerChain struct {
+	hs      *HeaderStore
+	db      types.StateDB
+	pending map[common.Hash]*StoredHeader
+}
+
+func (c *headerChain) header(hash common.Hash) *headerCtx {
+	if header, ok := c.pending[hash]; ok {
+		return &headerCtx{StoredHeader: header, chain: c}
+	}
+	if header := c.hs.loadHeader(c.db, hash); header != nil {
+		return &headerCtx{StoredHeader: header, chain: c}
+	}
+	return nil
+}
+
+// ancestor returns the ancestor of the header at number, it walks back the parents until it
+// meets the canonical chain
+func (c *headerChain) ancestor(h *headerCtx, number uint64) *headerCtx {
+	for h != nil && h.StoredHeader.Height > number {
+		if c.hs.readCanonicalHash(c.db, h.StoredHeader.Height) == h.Hash() {
+			header := c.hs.getCanonicalHeader(c.db, number)
+			if header == nil {
+				return nil
+			}
+			return &headerCtx{StoredHeader: header, chain: c}
+		}
+		h = c.header(common.Hash(h.BlockHeader().PrevBlock))
+	}
+	return h
+}
+
+// applyHeaders verifies the headers on top of their stored parent, it returns the stored
+// form of the headers, or the index of the first invalid header
+func (hs *HeaderStore) applyHeaders(db types.StateDB, headers []*wire.BlockHeader) ([]*StoredHeader, int, error) {
+	config, err := GetChainConfig(hs.ChainID)
+	if err != nil {
+		return nil, 0, err
+	}
+	chain := &headerChain{hs: hs, db: db, pending: make(map[common.Hash]*StoredHeader)}
+	ctx := &chainCtx{params: config.Params}
+
+	parent := chain.header(common.Hash(headers[0].PrevBlock))
+	if parent == nil {
+		return nil, 0, errUnknownAncestor
+	}
+	stored := make([]*StoredHeader, 0, len(headers))
+	for i, header := range headers {
+		if header.PrevBlock != parent.BlockHeader().BlockHash() {
+			return nil, i, errUnknownAncestor
+		}
+		if err := blockchain.CheckProofOfWork(btcutil.NewBlock(&wire.MsgBlock{Header: *header}), config.Params.PowLimit); err != nil {
+			return nil, i, err
+		}
+		if err := blockchain.CheckBlockHeaderContext(header, parent, blockchain.BFNone, ctx, true); err != nil {
+			return nil, i, err
+		}
+
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			return nil, i, err
+		}
+		current := &StoredHeader{
+			Header: buf.Bytes(),
+			Height: parent.StoredHeader.Height + 1,
+			Work:   new(big.Int).Add(parent.Work, blockchain.CalcWork(header.Bits)),
+			header: header,
+		}
+		chain.pending[current.Hash()] = current
+		stored = append(stored, current)
+		parent = &headerCtx{StoredHeader: current, chain: chain}
+	}
+	return stored, 0, nil
+}
+
+func decodeHeaders(data []byte) ([]*wire.BlockHeader, error) {
+	var raw [][]byte
+	if err := rlp.DecodeBytes(data, &raw); err != nil {
+		log.Error("rlp decode bitcoin headers failed.", "err", err)
+		return nil, chains.ErrRLPDecode
+	}
+	headers := make([]*wire.BlockHeader, len(raw))
+	for i, data := range raw {
+		header, err := decodeHeader(data)
+		if err != nil {
+			return nil, err
+		}
+		headers[i] = header
+	}
+	return headers, nil
+}
+
+func (hs *HeaderStore) InsertHeaders(db types.StateDB, data []byte) ([]*params.NumberHash, error) {
+	headers, err := decodeHeaders(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	if err := hs.Load(db); err != nil {
+		return nil, err
+	}
+
+	stored, _, err := hs.applyHeaders(db, headers)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range stored {
+		if err := hs.storeHeader(db, header); err != nil {
+			return nil, err
+		}
+	}
+
+	tip := stored[len(stored)-1]
+	if tip.Work.Cmp(hs.CurWork) <= 0 {
+		log.Info("stored bitcoin side chain headers", "count", len(stored), "number", tip.Height, "hash", tip.Hash())
+		return nil, nil
+	}
+
+	// the chain with the most work becomes canonical
+	var imported []*params.NumberHash
+	chain := &headerChain{hs: hs, db: db}
+	for h := (&headerCtx{StoredHeader: tip, chain: chain}); h != nil; h = chain.header(common.Hash(h.BlockHeader().PrevBlock)) {
+		if h.StoredHeader.Height <= hs.CurNumber && hs.readCanonicalHash(db, h.StoredHeader.Height) == h.Hash() {
+			break
+		}
+		hs.writeCanonicalHash(db, h.StoredHeader.Height, h.Hash())
+		imported = append([]*params.NumberHash{{Number: h.StoredHeader.Height, Hash: h.Hash()}}, imported...)
+	}
+	hs.CurNumber, hs.CurHash, hs.CurWork = tip.Height, tip.Hash(), tip.Work
+	if err := hs.Store(db); err != nil {
+		return nil, err
+	}
+	if err := hs.delOldHeaders(db, uint64(len(imported))*chains.PrunePerHeader); err != nil {
+		return nil, err
+	}
+	log.Info("stored new bitcoin block headers", "count", len(imported), "number", tip.Height, "hash", tip.Hash())
+	return imported, nil
+}
+
+func (hs *HeaderStore) GetCurrentNumberAndHash(db types.StateDB) (uint64, common.Hash, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, common.Hash{}, err
+	}
+	return hs.CurNumber, hs.CurHash, nil
+}
+
+func (hs *HeaderStore) GetHashByNumber(db types.StateDB, number uint64) (common.Hash, error) {
+	if err := hs.Load(db); err != nil {
+		return common.Hash{}, err
+	}
+	return hs.readCanonicalHash(db, number), nil
+}
@@ -0,0 +1,212 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// the first bitcoin mainnet headers
+var mainnetHeaders = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c",
+	"010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299",
+	"010000004860eb18bf1b1620e37e9490fc8a427514416fd75159ab86688e9a8300000000d5fdcc541e25de1c7a5addedf24858b8bb665c9f36ef744ee42c316022c90f9bb0bc6649ffff001d08d2bd61",
+}
+
+func decodeMainnetHeaders(t *testing.T) []*wire.BlockHeader {
+	headers := make([]*wire.BlockHeader, len(mainnetHeaders))
+	for i, h := range mainnetHeaders {
+		data, err := hex.DecodeString(h)
+		assert.Nil(t, err)
+		var header wire.BlockHeader
+		assert.Nil(t, header.Deserialize(bytes.NewReader(data)))
+		headers[i] = &header
+	}
+	return headers
+}
+
+func TestMainnetHeaders(t *testing.T) {
+	headers := decodeMainnetHeaders(t)
+	assert.Equal(t, "00000000839a8e6886ab5951d76f411475428afc90947ee320161bbf18eb6048", headers[1].BlockHash().String())
+
+	db := newTestStateDB()
+	hs := NewHeaderStore(MainnetChainID)
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, headers[0]), nil))
+
+	i, err := new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers[1:]...), MainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	imported, err := hs.InsertHeaders(db, encodeHeaders(t, headers[1:]...))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(imported))
+
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), number)
+	assert.Equal(t, "000000006a625f06636b8bb6ac7b960a8d03705d1ace08b1a19da3fdcc99ddbd", chainhash.Hash(hash).String())
+	hash, err = hs.GetHashByNumber(db, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, common.Hash(headers[1].BlockHash()), hash)
+
+	// the proof of work must match the bits
+	tampered := *headers[2]
+	tampered.Nonce++
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers[1], &tampered), MainnetChainID)
+	assert.NotNil(t, err)
+
+	// an easier target than the required one
+	tampered = *headers[2]
+	tampered.Bits = 0x1d01ffff
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers[1], &tampered), MainnetChainID)
+	assert.NotNil(t, err)
+
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers[1]), TestnetChainID)
+	assert.Equal(t, errNotInitialized, err)
+}
+
+func TestResetHeaderStore(t *testing.T) {
+	genesis := testGenesis()
+	headers := append([]*wire.BlockHeader{genesis}, makeHeaderChain(genesis, 12, testParams.TargetTimePerBlock, chainhash.Hash{})...)
+	hs := NewHeaderStore(testChainID)
+	db := newTestStateDB()
+
+	// the checkpoint must be at a retarget boundary
+	assert.Equal(t, errInvalidCheckpoint, hs.ResetHeaderStore(db, encodeCheckpoint(t, 11, headers[1:12]...), nil))
+	// with enough headers to compute the median time past
+	assert.Equal(t, errInvalidCheckpoint, hs.ResetHeaderStore(db, encodeCheckpoint(t, 10, headers[5:11]...), nil))
+	// that are linked
+	assert.Equal(t, errInvalidCheckpoint, hs.ResetHeaderStore(db, encodeCheckpoint(t, 10, append(append([]*wire.BlockHeader{}, headers[0:5]...), headers[6:12]...)...), nil))
+
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 10, headers[0:11]...), big.NewInt(1000)))
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), number)
+	assert.Equal(t, common.Hash(headers[10].BlockHash()), hash)
+	assert.Equal(t, big.NewInt(1000), hs.CurWork)
+}
+
+func TestDifficultyRetarget(t *testing.T) {
+	genesis := testGenesis()
+	hs := NewHeaderStore(testChainID)
+	db := newTestStateDB()
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, genesis), nil))
+
+	// blocks come twice as fast as the target
+	spacing := testParams.TargetTimePerBlock / 2
+	headers := makeHeaderChain(genesis, 9, spacing, chainhash.Hash{})
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	// the first block of the next period must double the difficulty
+	bits := retargetBits(genesis.Bits, 9*spacing, testParams.TargetTimespan)
+	assert.NotEqual(t, genesis.Bits, bits)
+
+	next := makeHeaderChain(headers[8], 1, spacing, chainhash.Hash{})[0]
+	i, err := new(Validate).ValidateHeaderChain(db, encodeHeaders(t, next), testChainID)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, i)
+
+	next = mine(&wire.BlockHeader{Version: 4, PrevBlock: headers[8].BlockHash(), Timestamp: headers[8].Timestamp.Add(spacing), Bits: bits})
+	following := makeHeaderChain(next, 2, spacing, chainhash.Hash{})
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, append([]*wire.BlockHeader{next}, following...)...))
+	assert.Nil(t, err)
+	number, _, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(12), number)
+
+	// within a period the difficulty can't change
+	easier := mine(&wire.BlockHeader{Version: 4, PrevBlock: following[1].BlockHash(), Timestamp: following[1].Timestamp.Add(spacing), Bits: genesis.Bits})
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, easier), testChainID)
+	assert.NotNil(t, err)
+}
+
+func TestHeaderRetention(t *testing.T) {
+	genesis := testGenesis()
+	hs := NewHeaderStore(testChainID)
+	db := newTestStateDB()
+	assert.Nil(t, hs.SetRetention(db, 5))
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, genesis), nil))
+
+	headers := makeHeaderChain(genesis, 9, testParams.TargetTimePerBlock, chainhash.Hash{})
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	earliest, err := hs.EarliestStoredNumber(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), earliest)
+	for number := uint64(0); number < 5; number++ {
+		assert.Nil(t, hs.getCanonicalHeader(db, number))
+	}
+	assert.Nil(t, hs.loadHeader(db, common.Hash(genesis.BlockHash())))
+	for number := uint64(5); number <= 9; number++ {
+		assert.NotNil(t, hs.getCanonicalHeader(db, number))
+	}
+}
+
+func TestMedianTimePast(t *testing.T) {
+	genesis := testGenesis()
+	hs := NewHeaderStore(testChainID)
+	db := newTestStateDB()
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, genesis), nil))
+
+	headers := makeHeaderChain(genesis, 5, testParams.TargetTimePerBlock, chainhash.Hash{})
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	// the median of the last 6 timestamps is the one of block 3
+	late := mine(&wire.BlockHeader{Version: 4, PrevBlock: headers[4].BlockHash(), Timestamp: headers[2].Timestamp, Bits: genesis.Bits})
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, late), testChainID)
+	assert.NotNil(t, err)
+
+	late = mine(&wire.BlockHeader{Version: 4, PrevBlock: headers[4].BlockHash(), Timestamp: headers[2].Timestamp.Add(time.Second), Bits: genesis.Bits})
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, late), testChainID)
+	assert.Nil(t, err)
+}
+
+func TestMostWorkForkChoice(t *testing.T) {
+	genesis := testGenesis()
+	hs := NewHeaderStore(testChainID)
+	db := newTestStateDB()
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, genesis), nil))
+
+	canonical := makeHeaderChain(genesis, 4, testParams.TargetTimePerBlock, chainhash.Hash{})
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, canonical...))
+	assert.Nil(t, err)
+
+	// a fork with as much work is kept aside
+	fork := makeHeaderChain(canonical[0], 3, testParams.TargetTimePerBlock, chainhash.Hash{1})
+	imported, err := hs.InsertHeaders(db, encodeHeaders(t, fork...))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(imported))
+	_, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, common.Hash(canonical[3].BlockHash()), hash)
+
+	// and becomes canonical once it has more work
+	more := makeHeaderChain(fork[2], 1, testParams.TargetTimePerBlock, chainhash.Hash{1})
+	imported, err = hs.InsertHeaders(db, encodeHeaders(t, more...))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(imported))
+	assert.Equal(t, uint64(2), imported[0].Number)
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), number)
+	assert.Equal(t, common.Hash(more[0].BlockHash()), hash)
+	for i, header := range fork {
+		hash, err := hs.GetHashByNumber(db, uint64(i+2))
+		assert.Nil(t, err)
+		assert.Equal(t, common.Hash(header.BlockHash()), hash)
+	}
+	assert.Equal(t, new(big.Int).Mul(blockchain.CalcWork(genesis.Bits), big.NewInt(6)), hs.CurWork)
+
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, makeHeaderChain(more[0], 2, testParams.TargetTimePerBlock, chainhash.Hash{})[1]))
+	assert.Equal(t, errUnknownAncestor, err)
+}
@@ -0,0 +1,119 @@
+package bitcoin
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+)
+
+const (
+	// testChainID follows a regtest network that retargets every 10 blocks
+	testChainID = 1
+	// testMainnetChainID follows bitcoin mainnet with fewer confirmations, so that the first
+	// mainnet blocks can be proven
+	testMainnetChainID    = 2
+	testBlocksPerRetarget = 10
+)
+
+var testParams = func() chaincfg.Params {
+	params := chaincfg.RegressionNetParams
+	params.PoWNoRetargeting = false
+	params.ReduceMinDifficulty = false
+	params.TargetTimespan = testBlocksPerRetarget * params.TargetTimePerBlock
+	return params
+}()
+
+func init() {
+	chainConfigs[testChainID] = &ChainConfig{ChainID: testChainID, Params: &testParams, Confirmations: 3}
+	chainConfigs[testMainnetChainID] = &ChainConfig{ChainID: testMainnetChainID, Params: &chaincfg.MainNetParams, Confirmations: 2}
+}
+
+func newTestStateDB() *state.StateDB {
+	db, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return db
+}
+
+func serializeHeader(t *testing.T, header *wire.BlockHeader) []byte {
+	var buf bytes.Buffer
+	assert.Nil(t, header.Serialize(&buf))
+	return buf.Bytes()
+}
+
+func encodeHeaders(t *testing.T, headers ...*wire.BlockHeader) []byte {
+	raw := make([][]byte, len(headers))
+	for i, header := range headers {
+		raw[i] = serializeHeader(t, header)
+	}
+	data, err := rlp.EncodeToBytes(raw)
+	assert.Nil(t, err)
+	return data
+}
+
+func encodeCheckpoint(t *testing.T, height uint64, headers ...*wire.BlockHeader) []byte {
+	cp := Checkpoint{Height: height}
+	for _, header := range headers {
+		cp.Headers = append(cp.Headers, serializeHeader(t, header))
+	}
+	data, err := rlp.EncodeToBytes(cp)
+	assert.Nil(t, err)
+	return data
+}
+
+// mine solves the proof of work of the header
+func mine(header *wire.BlockHeader) *wire.BlockHeader {
+	target := blockchain.CompactToBig(header.Bits)
+	for {
+		hash := header.BlockHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			return header
+		}
+		header.Nonce++
+	}
+}
+
+// makeHeaderChain mines n headers after parent, spacing seconds apart. Bits are set by the
+// caller for the headers that start a retarget period.
+func makeHeaderChain(parent *wire.BlockHeader, n int, spacing time.Duration, merkleRoot chainhash.Hash) []*wire.BlockHeader {
+	headers := make([]*wire.BlockHeader, n)
+	for i := range headers {
+		headers[i] = mine(&wire.BlockHeader{
+			Version:    4,
+			PrevBlock:  parent.BlockHash(),
+			MerkleRoot: merkleRoot,
+			Timestamp:  parent.Timestamp.Add(spacing),
+			Bits:       parent.Bits,
+		})
+		parent = headers[i]
+	}
+	return headers
+}
+
+// testGenesis is the first header of the regtest chain, at a retarget boundary
+func testGenesis() *wire.BlockHeader {
+	return mine(&wire.BlockHeader{
+		Version:   4,
+		Timestamp: time.Unix(1700000000, 0),
+		Bits:      testParams.PowLimitBits,
+	})
+}
+
+func retargetBits(bits uint32, actual, target time.Duration) uint32 {
+	newTarget := new(big.Int).Mul(blockchain.CompactToBig(bits), big.NewInt(int64(actual/time.Second)))
+	newTarget.Div(newTarget, big.NewInt(int64(target/time.Second)))
+	if newTarget.Cmp(testParams.PowLimit) > 0 {
+		newTarget.Set(testParams.PowLimit)
+	}
+	return blockchain.BigToCompact(newTarget)
+}
@@ -0,0 +1,27 @@
+package bitcoin
+
+import (
+	"errors"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+type Validate struct{}
+
+func (v *Validate) ValidateHeaderChain(db types.StateDB, data []byte, chainType chains.ChainType) (int, error) {
+	headers, err := decodeHeaders(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(headers) == 0 {
+		return 0, errors.New("headers cannot be empty")
+	}
+
+	hs := NewHeaderStore(uint64(chainType))
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	_, i, err := hs.applyHeaders(db, headers)
+	return i, err
+}
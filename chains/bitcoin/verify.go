@@ -0,0 +1,96 @@
+package bitcoin
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// TxProve proves that a transaction is included in a canonical bitcoin block, Proof holds
+// the merkle branch of the txid from the leaf up
+type TxProve struct {
+	Tx          []byte
+	BlockNumber uint64
+	TxIndex     uint64
+	Proof       []common.Hash
+}
+
+type Verify struct {
+	chainID uint64
+}
+
+func NewVerify(chainID uint64) *Verify {
+	return &Verify{chainID: chainID}
+}
+
+// Verify returns the proven transaction serialized without witness data, bitcoin
+// transactions have no logs
+func (v *Verify) Verify(db types.StateDB, routerContractAddr common.Address, txProveBytes []byte) (logs []byte, err error) {
+	var txProve TxProve
+	if err := rlp.DecodeBytes(txProveBytes, &txProve); err != nil {
+		return nil, err
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(txProve.Tx)); err != nil {
+		return nil, err
+	}
+	// a 64 byte transaction could be mistaken for an inner node of the merkle tree
+	if tx.SerializeSizeStripped() == 64 {
+		return nil, errAmbiguousTransaction
+	}
+
+	config, err := GetChainConfig(v.chainID)
+	if err != nil {
+		return nil, err
+	}
+	hs := NewHeaderStore(v.chainID)
+	if err := hs.Load(db); err != nil {
+		return nil, err
+	}
+	header := hs.getCanonicalHeader(db, txProve.BlockNumber)
+	if header == nil && txProve.BlockNumber < hs.earliestNumber(db) {
+		return nil, fmt.Errorf("header was pruned, number: %d, earliest stored number: %d", txProve.BlockNumber, hs.earliestNumber(db))
+	}
+	if header == nil {
+		return nil, errTransactionNotCanonical
+	}
+	if confirmations := hs.CurNumber - header.Height + 1; confirmations < config.Confirmations {
+		return nil, fmt.Errorf("%w, exp: %d, got: %d", errInsufficientConfirms, config.Confirmations, confirmations)
+	}
+
+	if computeMerkleRoot(tx.TxHash(), txProve.TxIndex, txProve.Proof) != header.BlockHeader().MerkleRoot {
+		return nil, errInvalidMerkleProof
+	}
+
+	var buf bytes.Buffer
+	if err := tx.SerializeNoWitness(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func computeMerkleRoot(txid chainhash.Hash, index uint64, proof []common.Hash) chainhash.Hash {
+	hash := txid
+	for _, sibling := range proof {
+		var node [chainhash.HashSize * 2]byte
+		if index&1 == 0 {
+			copy(node[:chainhash.HashSize], hash[:])
+			copy(node[chainhash.HashSize:], sibling[:])
+		} else {
+			copy(node[:chainhash.HashSize], sibling[:])
+			copy(node[chainhash.HashSize:], hash[:])
+		}
+		hash = chainhash.DoubleHashH(node[:])
+		index >>= 1
+	}
+	if index != 0 {
+		return chainhash.Hash{}
+	}
+	return hash
+}
@@ -0,0 +1,115 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+// the coinbase transaction of mainnet block 1
+const mainnetBlock1Coinbase = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff0704ffff001d0104ffffffff0100f2052a0100000043410496b538e853519c726a2c91e61ec11600ae1390813a627c66fb8be7947be63c52da7589379515d4e0a604f8141781e62294721166bf621e73a82cbf2342c858eeac00000000"
+
+func encodeTxProve(t *testing.T, tx []byte, number, index uint64, proof []common.Hash) []byte {
+	data, err := rlp.EncodeToBytes(TxProve{Tx: tx, BlockNumber: number, TxIndex: index, Proof: proof})
+	assert.Nil(t, err)
+	return data
+}
+
+func newTestTxs(t *testing.T, n int) ([][]byte, []chainhash.Hash) {
+	var (
+		txs    [][]byte
+		txids  []chainhash.Hash
+		script = []byte{0x51}
+	)
+	for i := 0; i < n; i++ {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{byte(i + 1)}}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(1000*(i+1)), script))
+		var buf bytes.Buffer
+		assert.Nil(t, tx.Serialize(&buf))
+		txs = append(txs, buf.Bytes())
+		txids = append(txids, tx.TxHash())
+	}
+	return txs, txids
+}
+
+// merkleProof returns the merkle root of the txids and the branch of the one at index
+func merkleProof(txids []chainhash.Hash, index int) (chainhash.Hash, []common.Hash) {
+	var proof []common.Hash
+	level := append([]chainhash.Hash{}, txids...)
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		proof = append(proof, common.Hash(level[index^1]))
+		next := make([]chainhash.Hash, len(level)/2)
+		for i := range next {
+			var node [chainhash.HashSize * 2]byte
+			copy(node[:chainhash.HashSize], level[2*i][:])
+			copy(node[chainhash.HashSize:], level[2*i+1][:])
+			next[i] = chainhash.DoubleHashH(node[:])
+		}
+		level, index = next, index/2
+	}
+	return level[0], proof
+}
+
+func TestVerifyMainnetCoinbase(t *testing.T) {
+	headers := decodeMainnetHeaders(t)
+	db := newTestStateDB()
+	hs := NewHeaderStore(testMainnetChainID)
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, headers[0]), nil))
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers[1]))
+	assert.Nil(t, err)
+
+	tx, err := hex.DecodeString(mainnetBlock1Coinbase)
+	assert.Nil(t, err)
+	router := common.Address{}
+	_, err = NewVerify(testMainnetChainID).Verify(db, router, encodeTxProve(t, tx, 1, 0, nil))
+	assert.ErrorIs(t, err, errInsufficientConfirms)
+
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, headers[2]))
+	assert.Nil(t, err)
+	ret, err := NewVerify(testMainnetChainID).Verify(db, router, encodeTxProve(t, tx, 1, 0, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, tx, ret)
+
+	// the transaction is not in the genesis block
+	_, err = NewVerify(testMainnetChainID).Verify(db, router, encodeTxProve(t, tx, 0, 0, nil))
+	assert.Equal(t, errInvalidMerkleProof, err)
+}
+
+func TestVerify(t *testing.T) {
+	txs, txids := newTestTxs(t, 5)
+	root, proof := merkleProof(txids, 3)
+
+	genesis := testGenesis()
+	db := newTestStateDB()
+	hs := NewHeaderStore(testChainID)
+	assert.Nil(t, hs.ResetHeaderStore(db, encodeCheckpoint(t, 0, genesis), nil))
+	headers := makeHeaderChain(genesis, 1, testParams.TargetTimePerBlock, root)
+	headers = append(headers, makeHeaderChain(headers[0], 2, testParams.TargetTimePerBlock, chainhash.Hash{})...)
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	v := NewVerify(testChainID)
+	ret, err := v.Verify(db, common.Address{}, encodeTxProve(t, txs[3], 1, 3, proof))
+	assert.Nil(t, err)
+	assert.Equal(t, txs[3], ret)
+
+	_, err = v.Verify(db, common.Address{}, encodeTxProve(t, txs[2], 1, 3, proof))
+	assert.Equal(t, errInvalidMerkleProof, err)
+	_, err = v.Verify(db, common.Address{}, encodeTxProve(t, txs[3], 1, 2, proof))
+	assert.Equal(t, errInvalidMerkleProof, err)
+	// the index can't point above the tree
+	_, err = v.Verify(db, common.Address{}, encodeTxProve(t, txs[3], 1, 3+1<<uint(len(proof)), proof))
+	assert.Equal(t, errInvalidMerkleProof, err)
+	_, err = v.Verify(db, common.Address{}, encodeTxProve(t, txs[3], 4, 3, proof))
+	assert.Equal(t, errTransactionNotCanonical, err)
+}
@@ -0,0 +1,85 @@
+package bsc
+
+import (
+	"fmt"
+)
+
+const (
+	MainnetChainID = 56
+	TestnetChainID = 97
+
+	defaultEpochLength = uint64(200)
+	defaultTurnLength  = uint64(1)
+)
+
+// EpochFork raises the epoch length once the fork time has passed, the new length takes effect
+// from the first block that is a multiple of it
+type EpochFork struct {
+	Time  uint64
+	Epoch uint64
+}
+
+type ChainConfig struct {
+	ChainID uint64
+	Epoch   uint64
+	// BohrTime is the fork after which epoch blocks carry the turn length of the next validator set
+	BohrTime   *uint64
+	EpochForks []EpochFork
+}
+
+func newUint64(v uint64) *uint64 { return &v }
+
+var chainConfigs = map[uint64]*ChainConfig{
+	MainnetChainID: {
+		ChainID:  MainnetChainID,
+		Epoch:    defaultEpochLength,
+		BohrTime: newUint64(1727317200),
+		EpochForks: []EpochFork{
+			{Time: 1745903100, Epoch: 500},  // lorentz
+			{Time: 1751250600, Epoch: 1000}, // maxwell
+		},
+	},
+	TestnetChainID: {
+		ChainID:  TestnetChainID,
+		Epoch:    defaultEpochLength,
+		BohrTime: newUint64(1724116996),
+		EpochForks: []EpochFork{
+			{Time: 1744097580, Epoch: 500},  // lorentz
+			{Time: 1748243100, Epoch: 1000}, // maxwell
+		},
+	},
+}
+
+func GetChainConfig(chainID uint64) (*ChainConfig, error) {
+	config, ok := chainConfigs[chainID]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bsc chain id: %d", chainID)
+	}
+	return config, nil
+}
+
+func (c *ChainConfig) IsBohr(time uint64) bool {
+	return c.BohrTime != nil && time >= *c.BohrTime
+}
+
+// epochLength returns the epoch length in force for a header at the given time
+func (c *ChainConfig) epochLength(time uint64) uint64 {
+	epoch := c.Epoch
+	for _, fork := range c.EpochForks {
+		if time >= fork.Time && fork.Epoch > epoch {
+			epoch = fork.Epoch
+		}
+	}
+	return epoch
+}
+
+// nextEpochLength returns the epoch length that the snapshot switches to after the header at
+// number, the switch only happens right before a block that starts an epoch of the new length
+func (c *ChainConfig) nextEpochLength(current, number, time uint64) uint64 {
+	for _, fork := range c.EpochForks {
+		if time >= fork.Time && fork.Epoch > current && (number+1)%fork.Epoch == 0 {
+			current = fork.Epoch
+		}
+	}
+	return current
+}
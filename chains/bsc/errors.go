@@ -0,0 +1,26 @@
+package bsc
+
+import "errors"
+
+var (
+	errMissingVanity            = errors.New("extra-data 32 byte vanity prefix missing")
+	errMissingSignature         = errors.New("extra-data 65 byte signature suffix missing")
+	errInvalidSpanValidators    = errors.New("invalid validator list on sprint end block")
+	errInvalidTurnLength        = errors.New("invalid turn length")
+	errInvalidDifficulty        = errors.New("invalid difficulty")
+	errWrongDifficulty          = errors.New("wrong difficulty")
+	errInvalidUncleHash         = errors.New("non empty uncle hash")
+	errOlderBlockTime           = errors.New("timestamp older than parent")
+	errInvalidGasUsed           = errors.New("invalid gasUsed: have gasUsed > gasLimit")
+	errCoinBaseMisMatch         = errors.New("coinbase do not match with signature")
+	errUnauthorizedValidator    = errors.New("unauthorized validator")
+	errRecentlySigned           = errors.New("recently signed")
+	errUnknownAncestor          = errors.New("unknown ancestor")
+	errInvalidAttestation       = errors.New("invalid vote attestation")
+	errInsufficientVotes        = errors.New("vote attestation does not reach the 2/3 quorum")
+	errAggregateVerifyFailed    = errors.New("vote attestation aggregate signature verify failed")
+	errReorgBelowFinalized      = errors.New("reorg below the finalized header")
+	errNotInitialized           = errors.New("please initialize header store")
+	errHeaderNotFinalized       = errors.New("header is not finalized")
+	errCheckpointHeaderRequired = errors.New("header store must be reset with an epoch checkpoint header")
+)
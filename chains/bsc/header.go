@@ -0,0 +1,220 @@
+package bsc
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	extraVanity          = 32 // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal            = 65 // Fixed number of extra-data suffix bytes reserved for signer seal
+	validatorNumberSize  = 1  // Fixed number of extra prefix bytes reserved for validator number after Luban
+	turnLengthSize       = 1  // Fixed number of extra-data suffix bytes reserved for turnLength
+	validatorBytesLength = common.AddressLength + BLSPublicKeyLength
+
+	BLSPublicKeyLength = 48
+	BLSSignatureLength = 96
+
+	maxAttestationExtraLength = 256
+	diffInTurn                = 2
+	diffNoTurn                = 1
+)
+
+// Header is a BNB Smart Chain block header, the optional fields follow the ethereum
+// forks adopted by BSC
+type Header struct {
+	ParentHash  common.Hash      `json:"parentHash"       gencodec:"required"`
+	UncleHash   common.Hash      `json:"sha3Uncles"       gencodec:"required"`
+	Coinbase    common.Address   `json:"miner"            gencodec:"required"`
+	Root        common.Hash      `json:"stateRoot"        gencodec:"required"`
+	TxHash      common.Hash      `json:"transactionsRoot" gencodec:"required"`
+	ReceiptHash common.Hash      `json:"receiptsRoot"     gencodec:"required"`
+	Bloom       types.Bloom      `json:"logsBloom"        gencodec:"required"`
+	Difficulty  *big.Int         `json:"difficulty"       gencodec:"required"`
+	Number      *big.Int         `json:"number"           gencodec:"required"`
+	GasLimit    uint64           `json:"gasLimit"         gencodec:"required"`
+	GasUsed     uint64           `json:"gasUsed"          gencodec:"required"`
+	Time        uint64           `json:"timestamp"        gencodec:"required"`
+	Extra       []byte           `json:"extraData"        gencodec:"required"`
+	MixDigest   common.Hash      `json:"mixHash"`
+	Nonce       types.BlockNonce `json:"nonce"`
+
+	BaseFee          *big.Int     `json:"baseFeePerGas"    rlp:"optional"`
+	WithdrawalsHash  *common.Hash `json:"withdrawalsRoot"  rlp:"optional"`
+	BlobGasUsed      *uint64      `json:"blobGasUsed"      rlp:"optional"`
+	ExcessBlobGas    *uint64      `json:"excessBlobGas"    rlp:"optional"`
+	ParentBeaconRoot *common.Hash `json:"parentBeaconRoot" rlp:"optional"`
+	RequestsHash     *common.Hash `json:"requestsHash"     rlp:"optional"`
+}
+
+func (h *Header) Hash() common.Hash {
+	return rlpHash(h)
+}
+
+// SealHash returns the hash of a block prior to it being sealed.
+func SealHash(header *Header, chainID *big.Int) (hash common.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	encodeSigHeader(hasher, header, chainID)
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func encodeSigHeader(w io.Writer, header *Header, chainID *big.Int) {
+	enc := []interface{}{
+		chainID,
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra[:len(header.Extra)-extraSeal], // this will panic if extra is too short, should check before calling encodeSigHeader
+		header.MixDigest,
+		header.Nonce,
+	}
+	if header.ParentBeaconRoot != nil && *header.ParentBeaconRoot == (common.Hash{}) {
+		enc = append(enc, header.BaseFee, header.WithdrawalsHash, header.BlobGasUsed, header.ExcessBlobGas, header.ParentBeaconRoot)
+	}
+	if header.RequestsHash != nil {
+		enc = append(enc, header.RequestsHash)
+	}
+	if err := rlp.Encode(w, enc); err != nil {
+		panic("can't encode: " + err.Error())
+	}
+}
+
+// ecrecover extracts the validator address from a signed header.
+func ecrecover(header *Header, chainID *big.Int) (common.Address, error) {
+	if len(header.Extra) < extraSeal {
+		return common.Address{}, errMissingSignature
+	}
+	signature := header.Extra[len(header.Extra)-extraSeal:]
+
+	pubkey, err := crypto.Ecrecover(SealHash(header, chainID).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return signer, nil
+}
+
+// VoteData is the source and target checkpoints that validators vote for with fast finality
+type VoteData struct {
+	SourceNumber uint64
+	SourceHash   common.Hash
+	TargetNumber uint64
+	TargetHash   common.Hash
+}
+
+func (d *VoteData) Hash() common.Hash {
+	return rlpHash(d)
+}
+
+// VoteAttestation is the aggregated vote of the validators set in VoteAddressSet, indexed by
+// their position in the address-sorted validator set
+type VoteAttestation struct {
+	VoteAddressSet uint64
+	AggSignature   [BLSSignatureLength]byte
+	Data           *VoteData
+	Extra          []byte
+}
+
+// parseValidators returns the validators and their vote addresses listed in an epoch header,
+// in the order of the extra data
+func parseValidators(header *Header, config *ChainConfig) ([]*ValidatorInfo, error) {
+	extra := header.Extra
+	if len(extra) <= extraVanity+extraSeal {
+		return nil, errInvalidSpanValidators
+	}
+	num := int(extra[extraVanity])
+	start := extraVanity + validatorNumberSize
+	end := start + num*validatorBytesLength
+	extraMinLen := end + extraSeal
+	if config.IsBohr(header.Time) {
+		extraMinLen += turnLengthSize
+	}
+	if num == 0 || len(extra) < extraMinLen {
+		return nil, errInvalidSpanValidators
+	}
+
+	validators := make([]*ValidatorInfo, 0, num)
+	for i := 0; i < num; i++ {
+		data := extra[start+i*validatorBytesLength : start+(i+1)*validatorBytesLength]
+		validators = append(validators, &ValidatorInfo{
+			Address:     common.BytesToAddress(data[:common.AddressLength]),
+			VoteAddress: common.CopyBytes(data[common.AddressLength:]),
+		})
+	}
+	return validators, nil
+}
+
+// parseTurnLength returns the number of consecutive blocks a validator produces, it's only
+// carried by epoch headers after bohr
+func parseTurnLength(header *Header, config *ChainConfig) (uint64, error) {
+	if !config.IsBohr(header.Time) {
+		return defaultTurnLength, nil
+	}
+	num := int(header.Extra[extraVanity])
+	pos := extraVanity + validatorNumberSize + num*validatorBytesLength
+	turnLength := uint64(header.Extra[pos])
+	if turnLength == 0 || turnLength > 64 {
+		return 0, errInvalidTurnLength
+	}
+	return turnLength, nil
+}
+
+// getVoteAttestation returns the vote attestation carried by a header, or nil if it has none
+func getVoteAttestation(header *Header, config *ChainConfig, epochLength uint64) (*VoteAttestation, error) {
+	if len(header.Extra) <= extraVanity+extraSeal {
+		return nil, nil
+	}
+
+	var data []byte
+	if header.Number.Uint64()%epochLength != 0 {
+		data = header.Extra[extraVanity : len(header.Extra)-extraSeal]
+	} else {
+		num := int(header.Extra[extraVanity])
+		start := extraVanity + validatorNumberSize + num*validatorBytesLength
+		if config.IsBohr(header.Time) {
+			start += turnLengthSize
+		}
+		end := len(header.Extra) - extraSeal
+		if end <= start {
+			return nil, nil
+		}
+		data = header.Extra[start:end]
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var attestation VoteAttestation
+	if err := rlp.Decode(bytes.NewReader(data), &attestation); err != nil {
+		return nil, err
+	}
+	if attestation.Data == nil || len(attestation.Extra) > maxAttestationExtraLength {
+		return nil, errInvalidAttestation
+	}
+	return &attestation, nil
+}
+
+func rlpHash(x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	_ = rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
@@ -0,0 +1,441 @@
+package bsc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	MaxHeaderLimit = 100000
+	// MaxSnapshotLimit bounds how deep a reorg can be, reorgs are also never allowed below
+	// the finalized header
+	MaxSnapshotLimit = 1024
+)
+
+// HeaderStore follows the canonical chain of a BNB Smart Chain network, a snapshot of the
+// parlia validator set is kept for every recent header so that reorgs can be verified
+type HeaderStore struct {
+	ChainID   uint64
+	CurNumber uint64
+	CurHash   common.Hash
+}
+
+func NewHeaderStore(chainID uint64) *HeaderStore {
+	return &HeaderStore{ChainID: chainID}
+}
+
+func (hs *HeaderStore) storeDbKey() common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("bsc%d", hs.ChainID)))
+}
+
+func (hs *HeaderStore) headerDbKey(number uint64) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("bsc%d-h%d", hs.ChainID, number%MaxHeaderLimit)))
+}
+
+func (hs *HeaderStore) snapshotDbKey(number uint64) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("bsc%d-s%d", hs.ChainID, number%MaxSnapshotLimit)))
+}
+
+func (hs *HeaderStore) earliestDbKey() common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("bsc%d-earliest", hs.ChainID)))
+}
+
+// retention returns the governance configured number of recent headers to keep, zero if
+// it was never configured
+func (hs *HeaderStore) retention(db types.StateDB) uint64 {
+	retention := chains.GetHeaderRetention(db, chains.ChainType(hs.ChainID))
+	if retention > MaxHeaderLimit {
+		retention = MaxHeaderLimit
+	}
+	return retention
+}
+
+// earliestNumber returns the lowest block number whose header may still be stored
+func (hs *HeaderStore) earliestNumber(db types.StateDB) uint64 {
+	var earliest uint64
+	if data := db.GetPOWState(chains.BSCHeaderStoreAddress, hs.earliestDbKey()); len(data) != 0 {
+		if err := rlp.DecodeBytes(data, &earliest); err != nil {
+			log.Error("decode earliest stored number failed", "err", err)
+		}
+	}
+	// the ring of slots only holds the last MaxHeaderLimit numbers
+	if hs.CurNumber >= MaxHeaderLimit && earliest < hs.CurNumber-MaxHeaderLimit+1 {
+		earliest = hs.CurNumber - MaxHeaderLimit + 1
+	}
+	return earliest
+}
+
+func (hs *HeaderStore) storeEarliestNumber(db types.StateDB, number uint64) error {
+	data, err := rlp.EncodeToBytes(number)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.BSCHeaderStoreAddress, hs.earliestDbKey(), data)
+	return nil
+}
+
+// delOldHeaders deletes the headers evicted from the configured retention window, at most
+// limit block numbers of them. Stores without a retention setting keep overwriting the
+// ring of MaxHeaderLimit slots.
+func (hs *HeaderStore) delOldHeaders(db types.StateDB, limit uint64) error {
+	retention := hs.retention(db)
+	if retention == 0 || hs.CurNumber < retention {
+		return nil
+	}
+	keepFrom := hs.CurNumber - retention + 1
+	earliest := hs.earliestNumber(db)
+	if earliest >= keepFrom {
+		return nil
+	}
+	end := keepFrom
+	if end-earliest > limit {
+		end = earliest + limit
+	}
+	for number := earliest; number < end; number++ {
+		if hs.loadHeader(db, number) != nil {
+			db.SetPOWState(chains.BSCHeaderStoreAddress, hs.headerDbKey(number), nil)
+		}
+	}
+	log.Debug("pruned bsc headers", "from", earliest, "to", end)
+	return hs.storeEarliestNumber(db, end)
+}
+
+// EarliestStoredNumber returns the lowest block number whose header may still be used
+// to verify receipts
+func (hs *HeaderStore) EarliestStoredNumber(db types.StateDB) (uint64, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	return hs.earliestNumber(db), nil
+}
+
+// MinRetention returns the lower bound of the retention setting, it covers the snapshots
+// kept for reorgs
+func (hs *HeaderStore) MinRetention() uint64 {
+	return chains.MinHeaderRetention
+}
+
+// MaxRetention returns the upper bound of the retention setting
+func (hs *HeaderStore) MaxRetention() uint64 {
+	return MaxHeaderLimit
+}
+
+// SetRetention sets the number of recent headers of the chain the store keeps
+func (hs *HeaderStore) SetRetention(db types.StateDB, retention uint64) error {
+	return chains.SetHeaderRetention(db, chains.ChainType(hs.ChainID), retention)
+}
+
+func (hs *HeaderStore) Store(db types.StateDB) error {
+	data, err := rlp.EncodeToBytes(hs)
+	if err != nil {
+		log.Error("Failed to RLP encode HeaderStore", "err", err)
+		return err
+	}
+	db.SetPOWState(chains.BSCHeaderStoreAddress, hs.storeDbKey(), data)
+	return nil
+}
+
+func (hs *HeaderStore) Load(db types.StateDB) error {
+	data := db.GetPOWState(chains.BSCHeaderStoreAddress, hs.storeDbKey())
+	if len(data) == 0 {
+		return errNotInitialized
+	}
+	var h HeaderStore
+	if err := rlp.DecodeBytes(data, &h); err != nil {
+		log.Error("HeaderStore RLP decode failed", "err", err)
+		return fmt.Errorf("HeaderStore RLP decode failed, error: %s", err.Error())
+	}
+	hs.CurNumber, hs.CurHash = h.CurNumber, h.CurHash
+	return nil
+}
+
+func (hs *HeaderStore) storeHeader(db types.StateDB, header *Header) error {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.BSCHeaderStoreAddress, hs.headerDbKey(header.Number.Uint64()), data)
+	return nil
+}
+
+// loadHeader returns the canonical header at number, or nil if it's not stored
+func (hs *HeaderStore) loadHeader(db types.StateDB, number uint64) *Header {
+	data := db.GetPOWState(chains.BSCHeaderStoreAddress, hs.headerDbKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	var header Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		log.Error("Invalid bsc header RLP", "number", number, "err", err)
+		return nil
+	}
+	if header.Number.Uint64() != number {
+		return nil
+	}
+	return &header
+}
+
+func (hs *HeaderStore) storeSnapshot(db types.StateDB, snap *Snapshot) error {
+	data, err := rlp.EncodeToBytes(snap)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.BSCHeaderStoreAddress, hs.snapshotDbKey(snap.Number), data)
+	return nil
+}
+
+// loadSnapshot returns the snapshot after the canonical header number, or nil if the header
+// is not the one with the given hash
+func (hs *HeaderStore) loadSnapshot(db types.StateDB, number uint64, hash common.Hash) *Snapshot {
+	data := db.GetPOWState(chains.BSCHeaderStoreAddress, hs.snapshotDbKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	var snap Snapshot
+	if err := rlp.DecodeBytes(data, &snap); err != nil {
+		log.Error("Invalid bsc snapshot RLP", "number", number, "err", err)
+		return nil
+	}
+	if snap.Number != number || snap.Hash != hash {
+		return nil
+	}
+	return &snap
+}
+
+// ResetHeaderStore initializes the store with the RLP encoded list of two epoch checkpoint
+// headers: the previous checkpoint, whose validators seal the blocks after the latest
+// checkpoint, and the latest checkpoint, whose validators take over later in the epoch.
+// The latest checkpoint is trusted as finalized.
+func (hs *HeaderStore) ResetHeaderStore(db types.StateDB, headers []byte, td *big.Int) error {
+	var checkpoints []*Header
+	if err := rlp.DecodeBytes(headers, &checkpoints); err != nil {
+		log.Error("rlp decode bsc headers failed.", "err", err)
+		return chains.ErrRLPDecode
+	}
+	if len(checkpoints) != 2 || checkpoints[0].Number == nil || checkpoints[1].Number == nil {
+		return errCheckpointHeaderRequired
+	}
+	config, err := GetChainConfig(hs.ChainID)
+	if err != nil {
+		return err
+	}
+
+	prev, cur := checkpoints[0], checkpoints[1]
+	epochLength := config.epochLength(cur.Time)
+	if cur.Number.Uint64()%epochLength != 0 || prev.Number.Cmp(cur.Number) >= 0 {
+		return errCheckpointHeaderRequired
+	}
+	validators, err := parseValidators(prev, config)
+	if err != nil {
+		return err
+	}
+	turnLength, err := parseTurnLength(prev, config)
+	if err != nil {
+		return err
+	}
+	pending, err := parseValidators(cur, config)
+	if err != nil {
+		return err
+	}
+	pendingTurnLength, err := parseTurnLength(cur, config)
+	if err != nil {
+		return err
+	}
+
+	number, hash := cur.Number.Uint64(), cur.Hash()
+	justified := &VoteData{SourceNumber: number, SourceHash: hash, TargetNumber: number, TargetHash: hash}
+	attestation, err := getVoteAttestation(cur, config, epochLength)
+	if err != nil {
+		return err
+	}
+	if attestation != nil {
+		justified = attestation.Data
+	}
+	if td == nil {
+		td = cur.Difficulty
+	}
+
+	snap := &Snapshot{
+		Number:            number,
+		Hash:              hash,
+		Time:              cur.Time,
+		TD:                new(big.Int).Set(td),
+		EpochLength:       epochLength,
+		TurnLength:        turnLength,
+		Validators:        sortValidators(validators),
+		VoteValidators:    copyValidators(validators),
+		PendingValidators: sortValidators(pending),
+		PendingTurnLength: pendingTurnLength,
+		Attestation:       justified,
+		FinalizedNumber:   number,
+		FinalizedHash:     hash,
+	}
+	if err := hs.storeHeader(db, cur); err != nil {
+		return err
+	}
+	if err := hs.storeSnapshot(db, snap); err != nil {
+		return err
+	}
+	if hs.retention(db) != 0 {
+		if err := hs.storeEarliestNumber(db, number); err != nil {
+			return err
+		}
+	}
+	hs.CurNumber, hs.CurHash = number, hash
+	return hs.Store(db)
+}
+
+// applyHeaders verifies the headers on top of their stored parent, it returns the snapshots
+// after each header, or the index of the first invalid header
+func (hs *HeaderStore) applyHeaders(db types.StateDB, headers []*Header) ([]*Snapshot, int, error) {
+	config, err := GetChainConfig(hs.ChainID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if headers[0].Number == nil || headers[0].Number.Sign() == 0 {
+		return nil, 0, errUnknownAncestor
+	}
+	parentNumber := headers[0].Number.Uint64() - 1
+	if parentNumber > hs.CurNumber {
+		return nil, 0, errUnknownAncestor
+	}
+	parent := hs.loadSnapshot(db, parentNumber, headers[0].ParentHash)
+	if parent == nil {
+		return nil, 0, errUnknownAncestor
+	}
+	if parent.Hash != hs.CurHash {
+		head := hs.loadSnapshot(db, hs.CurNumber, hs.CurHash)
+		if head == nil {
+			return nil, 0, errUnknownAncestor
+		}
+		if parentNumber < head.FinalizedNumber {
+			return nil, 0, errReorgBelowFinalized
+		}
+	}
+
+	snaps := make([]*Snapshot, 0, len(headers))
+	for i, header := range headers {
+		snap, err := parent.apply(header, config)
+		if err != nil {
+			return nil, i, err
+		}
+		snaps = append(snaps, snap)
+		parent = snap
+	}
+	return snaps, 0, nil
+}
+
+// reorgNeeded implements the fast finality fork choice: the chain with the higher justified
+// block wins, then the one with the higher total difficulty
+func reorgNeeded(head, tip *Snapshot) bool {
+	if tip.Attestation.TargetNumber != head.Attestation.TargetNumber {
+		return tip.Attestation.TargetNumber > head.Attestation.TargetNumber
+	}
+	return tip.TD.Cmp(head.TD) > 0
+}
+
+func (hs *HeaderStore) InsertHeaders(db types.StateDB, headers []byte) ([]*params.NumberHash, error) {
+	var chain []*Header
+	if err := rlp.DecodeBytes(headers, &chain); err != nil {
+		log.Error("rlp decode bsc headers failed.", "err", err)
+		return nil, chains.ErrRLPDecode
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	if err := hs.Load(db); err != nil {
+		return nil, err
+	}
+
+	snaps, _, err := hs.applyHeaders(db, chain)
+	if err != nil {
+		return nil, err
+	}
+	tip := snaps[len(snaps)-1]
+	if chain[0].ParentHash != hs.CurHash {
+		head := hs.loadSnapshot(db, hs.CurNumber, hs.CurHash)
+		if head == nil {
+			return nil, errUnknownAncestor
+		}
+		if !reorgNeeded(head, tip) {
+			log.Info("ignored bsc side chain", "number", tip.Number, "hash", tip.Hash)
+			return nil, nil
+		}
+	}
+
+	imported := make([]*params.NumberHash, 0, len(chain))
+	for i, header := range chain {
+		if err := hs.storeHeader(db, header); err != nil {
+			return nil, err
+		}
+		if err := hs.storeSnapshot(db, snaps[i]); err != nil {
+			return nil, err
+		}
+		imported = append(imported, &params.NumberHash{Number: snaps[i].Number, Hash: snaps[i].Hash})
+	}
+	hs.CurNumber, hs.CurHash = tip.Number, tip.Hash
+	if err := hs.Store(db); err != nil {
+		return nil, err
+	}
+	if err := hs.delOldHeaders(db, uint64(len(imported))*chains.PrunePerHeader); err != nil {
+		return nil, err
+	}
+	log.Info("stored new bsc block headers", "count", len(imported), "number", tip.Number, "hash", tip.Hash,
+		"finalized", tip.FinalizedNumber)
+	return imported, nil
+}
+
+func (hs *HeaderStore) GetCurrentNumberAndHash(db types.StateDB) (uint64, common.Hash, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, common.Hash{}, err
+	}
+	return hs.CurNumber, hs.CurHash, nil
+}
+
+func (hs *HeaderStore) GetHashByNumber(db types.StateDB, number uint64) (common.Hash, error) {
+	if err := hs.Load(db); err != nil {
+		return common.Hash{}, err
+	}
+	if number > hs.CurNumber {
+		return common.Hash{}, nil
+	}
+	header := hs.loadHeader(db, number)
+	if header == nil {
+		return common.Hash{}, nil
+	}
+	return header.Hash(), nil
+}
+
+// GetFinalizedHeader returns the canonical header at number if fast finality has finalized it
+func (hs *HeaderStore) GetFinalizedHeader(db types.StateDB, number uint64) (*Header, error) {
+	if err := hs.Load(db); err != nil {
+		return nil, err
+	}
+	head := hs.loadSnapshot(db, hs.CurNumber, hs.CurHash)
+	if head == nil {
+		return nil, errors.New("head snapshot not found")
+	}
+	if number > head.FinalizedNumber {
+		return nil, errHeaderNotFinalized
+	}
+	header := hs.loadHeader(db, number)
+	if header == nil && number < hs.earliestNumber(db) {
+		return nil, fmt.Errorf("header was pruned, number: %d, earliest stored number: %d", number, hs.earliestNumber(db))
+	}
+	if header == nil {
+		return nil, fmt.Errorf("get header by number failed, number: %d", number)
+	}
+	if number == head.FinalizedNumber && header.Hash() != head.FinalizedHash {
+		return nil, errHeaderNotFinalized
+	}
+	return header, nil
+}
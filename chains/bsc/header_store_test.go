@@ -0,0 +1,229 @@
+package bsc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/state"
+)
+
+func newTestHeaderStore(t *testing.T, c *testChain) (*HeaderStore, *state.StateDB) {
+	db := newTestStateDB()
+	hs := NewHeaderStore(testChainID)
+	assert.Nil(t, hs.ResetHeaderStore(db, c.checkpoints(), big.NewInt(testEpochLength*diffInTurn)))
+	return hs, db
+}
+
+func TestResetHeaderStore(t *testing.T) {
+	c := newTestChain(t, newTestValidators(t, 3))
+	hs, db := newTestHeaderStore(t, c)
+
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(testEpochLength), number)
+	assert.Equal(t, c.cur.Hash(), hash)
+
+	// the checkpoint is trusted as finalized
+	header, err := hs.GetFinalizedHeader(db, testEpochLength)
+	assert.Nil(t, err)
+	assert.Equal(t, c.cur.Hash(), header.Hash())
+
+	// a single header doesn't tell the validators that seal the current epoch
+	err = hs.ResetHeaderStore(db, encodeHeaders(t, c.cur), big.NewInt(1))
+	assert.Equal(t, errCheckpointHeaderRequired, err)
+
+	// the latest checkpoint must be an epoch block
+	err = hs.ResetHeaderStore(db, encodeHeaders(t, c.prev, c.chain(c.cur, 1)[0]), big.NewInt(1))
+	assert.Equal(t, errCheckpointHeaderRequired, err)
+
+	_, _, err = NewHeaderStore(uint64(chains.ChainTypeBSC)).GetCurrentNumberAndHash(db)
+	assert.Equal(t, errNotInitialized, err)
+}
+
+func TestInsertHeaders(t *testing.T) {
+	c := newTestChain(t, newTestValidators(t, 3))
+	hs, db := newTestHeaderStore(t, c)
+
+	headers := c.chain(c.cur, 5)
+	i, err := new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers...), testChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	imported, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(imported))
+
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(25), number)
+	assert.Equal(t, headers[4].Hash(), hash)
+	hash, err = hs.GetHashByNumber(db, 22)
+	assert.Nil(t, err)
+	assert.Equal(t, headers[1].Hash(), hash)
+
+	// block 25 justifies 24 on top of 23, which is finalized
+	header, err := hs.GetFinalizedHeader(db, 23)
+	assert.Nil(t, err)
+	assert.Equal(t, headers[2].Hash(), header.Hash())
+	_, err = hs.GetFinalizedHeader(db, 24)
+	assert.Equal(t, errHeaderNotFinalized, err)
+
+	// headers must extend a known header
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, c.chain(headers[4], 2)[1]))
+	assert.Equal(t, errUnknownAncestor, err)
+}
+
+func TestHeaderRetention(t *testing.T) {
+	c := newTestChain(t, newTestValidators(t, 3))
+	db := newTestStateDB()
+	hs := NewHeaderStore(testChainID)
+	assert.Nil(t, hs.SetRetention(db, 3))
+	assert.Nil(t, hs.ResetHeaderStore(db, c.checkpoints(), big.NewInt(testEpochLength*diffInTurn)))
+
+	headers := c.chain(c.cur, 5)
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	earliest, err := hs.EarliestStoredNumber(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(23), earliest)
+	hash, err := hs.GetHashByNumber(db, 22)
+	assert.Nil(t, err)
+	assert.Equal(t, common.Hash{}, hash)
+	_, err = hs.GetFinalizedHeader(db, 22)
+	assert.ErrorContains(t, err, "header was pruned")
+	header, err := hs.GetFinalizedHeader(db, 23)
+	assert.Nil(t, err)
+	assert.Equal(t, headers[2].Hash(), header.Hash())
+
+	// the retention of another chain doesn't apply
+	other := NewHeaderStore(uint64(chains.ChainTypeBSC))
+	assert.Equal(t, uint64(0), other.retention(db))
+}
+
+func TestValidateHeaderChainErrors(t *testing.T) {
+	validators := newTestValidators(t, 3)
+	c := newTestChain(t, validators)
+	_, db := newTestHeaderStore(t, c)
+	outsider := newTestValidators(t, 1)[0]
+
+	tests := []struct {
+		name string
+		opts headerOpts
+		err  error
+	}{
+		{name: "wrong difficulty", opts: headerOpts{difficulty: diffNoTurn}, err: errWrongDifficulty},
+		{name: "invalid difficulty", opts: headerOpts{difficulty: 3}, err: errInvalidDifficulty},
+		{name: "unauthorized validator", opts: headerOpts{signer: outsider}, err: errUnauthorizedValidator},
+		{name: "coinbase mismatch", opts: headerOpts{modify: func(header *Header) {
+			header.Coinbase = validators[1].addr
+		}}, err: errCoinBaseMisMatch},
+		{name: "older block time", opts: headerOpts{modify: func(header *Header) {
+			header.Time = 0
+		}}, err: errOlderBlockTime},
+		{name: "insufficient votes", opts: headerOpts{voters: 1}, err: errInsufficientVotes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := c.next(c.cur, tt.opts)
+			_, err := new(Validate).ValidateHeaderChain(db, encodeHeaders(t, header), testChainID)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+
+	// the previous validator may not seal the next block
+	headers := c.chain(c.cur, 1)
+	headers = append(headers, c.next(headers[0], headerOpts{signer: c.inturn(21)}))
+	i, err := new(Validate).ValidateHeaderChain(db, encodeHeaders(t, headers...), testChainID)
+	assert.Equal(t, errRecentlySigned, err)
+	assert.Equal(t, 1, i)
+
+	// an attestation must justify the parent
+	first := c.next(c.cur, headerOpts{noVote: true})
+	second := c.next(first, headerOpts{modify: func(header *Header) {
+		attestation := c.attest(&VoteData{SourceNumber: 19, SourceHash: common.HexToHash("0x13"), TargetNumber: 20, TargetHash: c.cur.Hash()}, 0)
+		header.Extra = c.extra(nil, attestation)
+	}})
+	i, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, first, second), testChainID)
+	assert.Equal(t, errInvalidAttestation, err)
+	assert.Equal(t, 1, i)
+}
+
+func TestValidatorSetSwitch(t *testing.T) {
+	validators := newTestValidators(t, 3)
+	c := newTestChain(t, validators)
+	hs, db := newTestHeaderStore(t, c)
+
+	headers := c.chain(c.cur, testEpochLength-1)
+	next := newTestValidators(t, 4)
+	epoch := c.next(headers[len(headers)-1], headerOpts{validators: next})
+	headers = append(headers, epoch)
+	// the new validators take over after the miner history check of the old set
+	headers = append(headers, c.chain(epoch, 10)...)
+	assert.Equal(t, next, c.sealers(42))
+	assert.Equal(t, validators, c.sealers(41))
+
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+	number, _, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), number)
+
+	// the old validators can no longer seal blocks
+	header := c.next(headers[len(headers)-1], headerOpts{signer: validators[0], difficulty: diffNoTurn, noVote: true})
+	_, err = new(Validate).ValidateHeaderChain(db, encodeHeaders(t, header), testChainID)
+	assert.Equal(t, errUnauthorizedValidator, err)
+}
+
+func TestInsertHeadersReorg(t *testing.T) {
+	c := newTestChain(t, newTestValidators(t, 3))
+	hs, db := newTestHeaderStore(t, c)
+
+	headers := c.chain(c.cur, 5)
+	_, err := hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	// a fork that doesn't justify a higher block or gather more difficulty is ignored
+	side := c.next(headers[3], headerOpts{noVote: true})
+	imported, err := hs.InsertHeaders(db, encodeHeaders(t, side))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(imported))
+	_, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, headers[4].Hash(), hash)
+
+	// a fork that justifies a higher block becomes canonical
+	fork := append([]*Header{side}, c.chain(side, 2)...)
+	imported, err = hs.InsertHeaders(db, encodeHeaders(t, fork...))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(imported))
+	number, hash, err := hs.GetCurrentNumberAndHash(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(27), number)
+	assert.Equal(t, fork[2].Hash(), hash)
+	hash, err = hs.GetHashByNumber(db, 25)
+	assert.Nil(t, err)
+	assert.Equal(t, side.Hash(), hash)
+
+	// finalized headers can't be reorganized
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, c.chain(headers[0], 1)...))
+	assert.Equal(t, errReorgBelowFinalized, err)
+}
+
+func TestChainConfigEpochLength(t *testing.T) {
+	config, err := GetChainConfig(MainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(200), config.epochLength(1745903099))
+	assert.Equal(t, uint64(500), config.epochLength(1745903100))
+	assert.Equal(t, uint64(1000), config.epochLength(1751250600))
+
+	// the longer epoch starts on the first block that is a multiple of it
+	assert.Equal(t, uint64(200), config.nextEpochLength(200, 48000000, 1745903100))
+	assert.Equal(t, uint64(500), config.nextEpochLength(200, 47999999, 1745903100))
+
+	_, err = GetChainConfig(1)
+	assert.NotNil(t, err)
+}
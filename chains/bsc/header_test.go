@@ -0,0 +1,94 @@
+package bsc
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// extraFixture is the extra data of an epoch header of a live network, as published with the
+// extradump tool of bnb-chain/bsc. The bohr variants carry the turn length of the next set.
+type extraFixture struct {
+	ChainID uint64        `json:"chainId"`
+	Number  uint64        `json:"number"`
+	Bohr    bool          `json:"bohr"`
+	Extra   hexutil.Bytes `json:"extraData"`
+}
+
+func loadExtraFixture(t *testing.T, name string) (*Header, *ChainConfig) {
+	data, err := os.ReadFile("testdata/epoch_extra.json")
+	assert.Nil(t, err)
+	var fixtures map[string]*extraFixture
+	assert.Nil(t, json.Unmarshal(data, &fixtures))
+	fixture, ok := fixtures[name]
+	if !ok {
+		t.Fatalf("missing fixture %s", name)
+	}
+
+	config, err := GetChainConfig(fixture.ChainID)
+	assert.Nil(t, err)
+	header := &Header{Number: new(big.Int).SetUint64(fixture.Number), Extra: fixture.Extra}
+	if fixture.Bohr {
+		header.Time = *config.BohrTime
+	}
+	return header, config
+}
+
+func TestParseValidatorsFixture(t *testing.T) {
+	for _, name := range []string{"mainnetEpoch", "mainnetEpochBohr"} {
+		header, config := loadExtraFixture(t, name)
+		validators, err := parseValidators(header, config)
+		assert.Nil(t, err)
+		assert.Equal(t, 21, len(validators))
+		assert.Equal(t, common.HexToAddress("0xcc8e6d00c17eb431350c6c50d8b8f05176b90b11"), validators[14].Address)
+		assert.Equal(t, common.FromHex("0xb2d4c6283c44a1c7bd503aaba7666e9f0c830e0ff016c1c750a5e48757a713d0836b1cabfd5c281b1de3b77d1c192183"), validators[18].VoteAddress)
+	}
+
+	header, config := loadExtraFixture(t, "mainnetEpoch")
+	turnLength, err := parseTurnLength(header, config)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultTurnLength, turnLength)
+
+	header, config = loadExtraFixture(t, "mainnetEpochBohr")
+	turnLength, err = parseTurnLength(header, config)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), turnLength)
+}
+
+func TestVoteAttestationFixture(t *testing.T) {
+	for _, name := range []string{"chapelEpoch", "chapelEpochBohr"} {
+		header, config := loadExtraFixture(t, name)
+		validators, err := parseValidators(header, config)
+		assert.Nil(t, err)
+		assert.Equal(t, 7, len(validators))
+
+		attestation, err := getVoteAttestation(header, config, config.Epoch)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(32096999), attestation.Data.TargetNumber)
+		assert.Equal(t, common.HexToHash("0x0edc71ce80105a3220a87bea2792fa340d66c59002f02b0a09349ed1ed284070"), attestation.Data.TargetHash)
+
+		// the epoch kept its validator set, so the votes for the parent were cast by the
+		// validators the header lists
+		snap := &Snapshot{
+			Number:         attestation.Data.TargetNumber,
+			Hash:           attestation.Data.TargetHash,
+			VoteValidators: sortValidators(validators),
+			Attestation:    &VoteData{TargetNumber: attestation.Data.SourceNumber, TargetHash: attestation.Data.SourceHash},
+		}
+		assert.Nil(t, snap.verifyVoteAttestation(attestation))
+
+		snap.Hash = common.HexToHash("0x01")
+		assert.Equal(t, errInvalidAttestation, snap.verifyVoteAttestation(attestation))
+
+		// the signature doesn't cover another vote
+		attestation.Data.SourceHash = common.HexToHash("0x01")
+		snap.Hash = attestation.Data.TargetHash
+		snap.Attestation.TargetHash = attestation.Data.SourceHash
+		assert.Equal(t, errAggregateVerifyFailed, snap.verifyVoteAttestation(attestation))
+	}
+}
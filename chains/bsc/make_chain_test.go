@@ -0,0 +1,248 @@
+package bsc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	bls "github.com/mapprotocol/atlas/chains/eth2/bls12381"
+	blscommon "github.com/mapprotocol/atlas/chains/eth2/bls12381/common"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+)
+
+// The chains are built the way a parlia validator seals and votes for blocks, with generated
+// keys instead of the keys of a live network. The extra data layout and the vote signatures
+// are checked against live network headers in header_test.go.
+const (
+	testChainID     = 714
+	testEpochLength = 20
+)
+
+func init() {
+	chainConfigs[testChainID] = &ChainConfig{
+		ChainID:  testChainID,
+		Epoch:    testEpochLength,
+		BohrTime: newUint64(0),
+	}
+}
+
+func newTestStateDB() *state.StateDB {
+	db, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return db
+}
+
+type testValidator struct {
+	key    *ecdsa.PrivateKey
+	blsKey blscommon.SecretKey
+	addr   common.Address
+}
+
+// newTestValidators generates n validators sorted by address
+func newTestValidators(t *testing.T, n int) []*testValidator {
+	validators := make([]*testValidator, n)
+	for i := range validators {
+		key, err := crypto.GenerateKey()
+		assert.Nil(t, err)
+		blsKey, err := bls.RandKey()
+		assert.Nil(t, err)
+		validators[i] = &testValidator{key: key, blsKey: blsKey, addr: crypto.PubkeyToAddress(key.PublicKey)}
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i].addr[:], validators[j].addr[:]) < 0
+	})
+	return validators
+}
+
+type headerOpts struct {
+	signer     *testValidator // the in-turn validator by default
+	difficulty int64          // follows the signer by default
+	noVote     bool
+	voters     int              // the number of votes of the attestation, all the validators by default
+	validators []*testValidator // listed by an epoch header, the sealing validators by default
+	modify     func(header *Header)
+}
+
+type testChain struct {
+	t *testing.T
+	// sets maps the first block sealed by a validator set to the set
+	sets      map[uint64][]*testValidator
+	justified map[common.Hash]*VoteData
+	prev, cur *Header
+}
+
+func newTestChain(t *testing.T, validators []*testValidator) *testChain {
+	c := &testChain{
+		t:         t,
+		sets:      map[uint64][]*testValidator{0: validators},
+		justified: make(map[common.Hash]*VoteData),
+	}
+	c.prev = c.seal(&Header{
+		Coinbase:   validators[0].addr,
+		UncleHash:  ethtypes.EmptyUncleHash,
+		Difficulty: big.NewInt(diffInTurn),
+		Number:     big.NewInt(0),
+		GasLimit:   30000000,
+		Extra:      c.extra(validators, nil),
+	}, validators[0])
+
+	// the checkpoint carries an attestation that is trusted on reset
+	justified := &VoteData{SourceNumber: 18, SourceHash: common.HexToHash("0x12"), TargetNumber: 19, TargetHash: common.HexToHash("0x13")}
+	c.cur = c.seal(&Header{
+		ParentHash: justified.TargetHash,
+		Coinbase:   c.inturn(testEpochLength).addr,
+		UncleHash:  ethtypes.EmptyUncleHash,
+		Difficulty: big.NewInt(diffInTurn),
+		Number:     big.NewInt(testEpochLength),
+		GasLimit:   30000000,
+		Time:       60,
+		Extra:      c.extra(validators, &VoteAttestation{Data: justified}),
+	}, c.inturn(testEpochLength))
+	c.justified[c.cur.Hash()] = justified
+	return c
+}
+
+func (c *testChain) checkpoints() []byte {
+	data, err := rlp.EncodeToBytes([]*Header{c.prev, c.cur})
+	assert.Nil(c.t, err)
+	return data
+}
+
+func (c *testChain) sealers(number uint64) []*testValidator {
+	var (
+		from uint64
+		set  []*testValidator
+	)
+	for f, s := range c.sets {
+		if f <= number && (set == nil || f >= from) {
+			from, set = f, s
+		}
+	}
+	return set
+}
+
+func (c *testChain) inturn(number uint64) *testValidator {
+	set := c.sealers(number)
+	return set[number%uint64(len(set))]
+}
+
+func (c *testChain) extra(validators []*testValidator, attestation *VoteAttestation) []byte {
+	extra := make([]byte, extraVanity)
+	if validators != nil {
+		extra = append(extra, byte(len(validators)))
+		for _, v := range validators {
+			extra = append(extra, v.addr.Bytes()...)
+			extra = append(extra, v.blsKey.PublicKey().Marshal()...)
+		}
+		extra = append(extra, byte(defaultTurnLength))
+	}
+	if attestation != nil {
+		data, err := rlp.EncodeToBytes(attestation)
+		assert.Nil(c.t, err)
+		extra = append(extra, data...)
+	}
+	return append(extra, make([]byte, extraSeal)...)
+}
+
+func (c *testChain) seal(header *Header, signer *testValidator) *Header {
+	sig, err := crypto.Sign(SealHash(header, big.NewInt(testChainID)).Bytes(), signer.key)
+	assert.Nil(c.t, err)
+	copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+	return header
+}
+
+// attest aggregates the votes of the first voters validators that sealed the target
+func (c *testChain) attest(data *VoteData, voters int) *VoteAttestation {
+	set := c.sealers(data.TargetNumber)
+	if voters == 0 {
+		voters = len(set)
+	}
+	attestation := &VoteAttestation{Data: data}
+	var sigs []blscommon.Signature
+	for i := 0; i < voters; i++ {
+		attestation.VoteAddressSet |= 1 << uint(i)
+		sigs = append(sigs, set[i].blsKey.Sign(data.Hash().Bytes()))
+	}
+	copy(attestation.AggSignature[:], bls.AggregateSignatures(sigs).Marshal())
+	return attestation
+}
+
+func (c *testChain) next(parent *Header, opts headerOpts) *Header {
+	number := parent.Number.Uint64() + 1
+	signer := opts.signer
+	if signer == nil {
+		signer = c.inturn(number)
+	}
+	difficulty := opts.difficulty
+	if difficulty == 0 {
+		difficulty = diffNoTurn
+		if signer == c.inturn(number) {
+			difficulty = diffInTurn
+		}
+	}
+
+	var attestation *VoteAttestation
+	justified := c.justified[parent.Hash()]
+	if !opts.noVote {
+		data := &VoteData{
+			SourceNumber: justified.TargetNumber,
+			SourceHash:   justified.TargetHash,
+			TargetNumber: parent.Number.Uint64(),
+			TargetHash:   parent.Hash(),
+		}
+		attestation = c.attest(data, opts.voters)
+		justified = &VoteData{TargetNumber: data.TargetNumber, TargetHash: data.TargetHash}
+	}
+
+	var validators []*testValidator
+	if number%testEpochLength == 0 {
+		validators = opts.validators
+		if validators == nil {
+			validators = c.sealers(number)
+		}
+		checkLen := uint64(len(c.sealers(number))/2+1)*defaultTurnLength - 1
+		c.sets[number+checkLen+1] = validators
+	}
+
+	header := &Header{
+		ParentHash:  parent.Hash(),
+		UncleHash:   ethtypes.EmptyUncleHash,
+		Coinbase:    signer.addr,
+		ReceiptHash: ethtypes.EmptyRootHash,
+		Difficulty:  big.NewInt(difficulty),
+		Number:      new(big.Int).SetUint64(number),
+		GasLimit:    30000000,
+		Time:        parent.Time + 3,
+		Extra:       c.extra(validators, attestation),
+	}
+	if opts.modify != nil {
+		opts.modify(header)
+	}
+	header = c.seal(header, signer)
+	c.justified[header.Hash()] = justified
+	return header
+}
+
+// chain generates n in-turn headers after parent, each one justifying its parent
+func (c *testChain) chain(parent *Header, n int) []*Header {
+	headers := make([]*Header, 0, n)
+	for i := 0; i < n; i++ {
+		parent = c.next(parent, headerOpts{})
+		headers = append(headers, parent)
+	}
+	return headers
+}
+
+func encodeHeaders(t *testing.T, headers ...*Header) []byte {
+	data, err := rlp.EncodeToBytes(headers)
+	assert.Nil(t, err)
+	return data
+}
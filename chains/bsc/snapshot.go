@@ -0,0 +1,278 @@
+package bsc
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	bls "github.com/mapprotocol/atlas/chains/eth2/bls12381"
+	blscommon "github.com/mapprotocol/atlas/chains/eth2/bls12381/common"
+)
+
+type ValidatorInfo struct {
+	Address     common.Address
+	VoteAddress []byte
+}
+
+// Recent records the validator that sealed a block
+type Recent struct {
+	Number uint64
+	Signer common.Address
+}
+
+// Snapshot is the state of the parlia validator set after the block Number
+type Snapshot struct {
+	Number      uint64
+	Hash        common.Hash
+	Time        uint64
+	TD          *big.Int
+	EpochLength uint64
+	TurnLength  uint64
+	// Validators seal the blocks after the snapshot, sorted by address
+	Validators []*ValidatorInfo
+	// VoteValidators sealed the snapshot block and vote for it as target, sorted by address
+	VoteValidators []*ValidatorInfo
+	Recents        []*Recent
+	// PendingValidators are listed by the last epoch header and replace Validators once
+	// the miner history of the old set has been checked
+	PendingValidators []*ValidatorInfo
+	PendingTurnLength uint64
+	// Attestation is the latest justified vote, its target is the justified block
+	Attestation     *VoteData `rlp:"nil"`
+	FinalizedNumber uint64
+	FinalizedHash   common.Hash
+}
+
+func sortValidators(validators []*ValidatorInfo) []*ValidatorInfo {
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i].Address[:], validators[j].Address[:]) < 0
+	})
+	return validators
+}
+
+func copyValidators(validators []*ValidatorInfo) []*ValidatorInfo {
+	if validators == nil {
+		return nil
+	}
+	cpy := make([]*ValidatorInfo, len(validators))
+	for i, v := range validators {
+		cpy[i] = &ValidatorInfo{Address: v.Address, VoteAddress: common.CopyBytes(v.VoteAddress)}
+	}
+	return cpy
+}
+
+func (s *Snapshot) copy() *Snapshot {
+	cpy := &Snapshot{
+		Number:            s.Number,
+		Hash:              s.Hash,
+		Time:              s.Time,
+		TD:                new(big.Int).Set(s.TD),
+		EpochLength:       s.EpochLength,
+		TurnLength:        s.TurnLength,
+		Validators:        copyValidators(s.Validators),
+		VoteValidators:    copyValidators(s.VoteValidators),
+		Recents:           make([]*Recent, len(s.Recents)),
+		PendingValidators: copyValidators(s.PendingValidators),
+		PendingTurnLength: s.PendingTurnLength,
+		FinalizedNumber:   s.FinalizedNumber,
+		FinalizedHash:     s.FinalizedHash,
+	}
+	for i, r := range s.Recents {
+		cpy.Recents[i] = &Recent{Number: r.Number, Signer: r.Signer}
+	}
+	if s.Attestation != nil {
+		data := *s.Attestation
+		cpy.Attestation = &data
+	}
+	return cpy
+}
+
+// minerHistoryCheckLen is the number of blocks a validator must wait before sealing again,
+// it's also the delay after an epoch block before the new validator set takes over
+func (s *Snapshot) minerHistoryCheckLen() uint64 {
+	return (uint64(len(s.Validators))/2+1)*s.TurnLength - 1
+}
+
+func (s *Snapshot) indexOf(validator common.Address) int {
+	for i, v := range s.Validators {
+		if v.Address == validator {
+			return i
+		}
+	}
+	return -1
+}
+
+// inturn returns whether the validator is in turn to seal the block after the snapshot
+func (s *Snapshot) inturn(validator common.Address) bool {
+	offset := (s.Number + 1) / s.TurnLength % uint64(len(s.Validators))
+	return s.Validators[offset].Address == validator
+}
+
+func (s *Snapshot) signRecently(validator common.Address) bool {
+	times := uint64(0)
+	for _, r := range s.Recents {
+		if r.Signer == validator {
+			times++
+		}
+	}
+	return times >= s.TurnLength
+}
+
+// pruneRecents drops the seals that are out of the miner history window of the block number
+func (s *Snapshot) pruneRecents(number uint64) {
+	limit := s.minerHistoryCheckLen() + 1
+	recents := s.Recents[:0]
+	for _, r := range s.Recents {
+		if r.Number+limit > number {
+			recents = append(recents, r)
+		}
+	}
+	s.Recents = recents
+}
+
+// verifyHeader checks the fields of the header that don't depend on the validator set
+func (s *Snapshot) verifyHeader(header *Header) error {
+	if header.Number == nil || header.Number.Uint64() != s.Number+1 || header.ParentHash != s.Hash {
+		return errUnknownAncestor
+	}
+	if len(header.Extra) < extraVanity {
+		return errMissingVanity
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		return errMissingSignature
+	}
+	if header.UncleHash != types.EmptyUncleHash {
+		return errInvalidUncleHash
+	}
+	if header.Difficulty == nil || (header.Difficulty.Cmp(big.NewInt(diffInTurn)) != 0 && header.Difficulty.Cmp(big.NewInt(diffNoTurn)) != 0) {
+		return errInvalidDifficulty
+	}
+	if header.Time < s.Time {
+		return errOlderBlockTime
+	}
+	if header.GasUsed > header.GasLimit {
+		return errInvalidGasUsed
+	}
+	return nil
+}
+
+// verifyVoteAttestation checks that the attestation justifies the parent of its header with the
+// votes of at least 2/3 of the validators that sealed the parent
+func (s *Snapshot) verifyVoteAttestation(attestation *VoteAttestation) error {
+	data := attestation.Data
+	if data.TargetNumber != s.Number || data.TargetHash != s.Hash {
+		return errInvalidAttestation
+	}
+	if s.Attestation != nil && (data.SourceNumber != s.Attestation.TargetNumber || data.SourceHash != s.Attestation.TargetHash) {
+		return errInvalidAttestation
+	}
+
+	validators := s.VoteValidators
+	if len(validators) > 64 || attestation.VoteAddressSet>>uint(len(validators)) != 0 {
+		return errInvalidAttestation
+	}
+	pubKeys := make([]blscommon.PublicKey, 0, len(validators))
+	for i, v := range validators {
+		if attestation.VoteAddressSet&(1<<uint(i)) == 0 {
+			continue
+		}
+		pubKey, err := bls.PublicKeyFromBytes(v.VoteAddress)
+		if err != nil {
+			return err
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if len(pubKeys)*3 < len(validators)*2 {
+		return errInsufficientVotes
+	}
+
+	signature, err := bls.SignatureFromBytes(attestation.AggSignature[:])
+	if err != nil {
+		return err
+	}
+	if !signature.FastAggregateVerify(pubKeys, data.Hash()) {
+		return errAggregateVerifyFailed
+	}
+	return nil
+}
+
+// apply verifies the header on top of the snapshot and returns the snapshot after it
+func (s *Snapshot) apply(header *Header, config *ChainConfig) (*Snapshot, error) {
+	if err := s.verifyHeader(header); err != nil {
+		return nil, err
+	}
+	number := header.Number.Uint64()
+
+	var (
+		validators []*ValidatorInfo
+		turnLength uint64
+		err        error
+	)
+	if number%s.EpochLength == 0 {
+		if validators, err = parseValidators(header, config); err != nil {
+			return nil, err
+		}
+		if turnLength, err = parseTurnLength(header, config); err != nil {
+			return nil, err
+		}
+	}
+
+	signer, err := ecrecover(header, new(big.Int).SetUint64(config.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	if signer != header.Coinbase {
+		return nil, errCoinBaseMisMatch
+	}
+
+	snap := s.copy()
+	snap.pruneRecents(number)
+	if snap.indexOf(signer) < 0 {
+		return nil, errUnauthorizedValidator
+	}
+	if snap.signRecently(signer) {
+		return nil, errRecentlySigned
+	}
+	inturn := snap.inturn(signer)
+	if inturn && header.Difficulty.Uint64() != diffInTurn {
+		return nil, errWrongDifficulty
+	}
+	if !inturn && header.Difficulty.Uint64() != diffNoTurn {
+		return nil, errWrongDifficulty
+	}
+
+	attestation, err := getVoteAttestation(header, config, s.EpochLength)
+	if err != nil {
+		return nil, err
+	}
+	if attestation != nil {
+		if err := s.verifyVoteAttestation(attestation); err != nil {
+			return nil, err
+		}
+		data := *attestation.Data
+		snap.Attestation = &data
+		if data.SourceNumber+1 == data.TargetNumber && data.SourceNumber > snap.FinalizedNumber {
+			snap.FinalizedNumber, snap.FinalizedHash = data.SourceNumber, data.SourceHash
+		}
+	}
+
+	snap.Recents = append(snap.Recents, &Recent{Number: number, Signer: signer})
+	snap.VoteValidators = copyValidators(snap.Validators)
+	if validators != nil {
+		snap.PendingValidators = sortValidators(validators)
+		snap.PendingTurnLength = turnLength
+	}
+	if len(snap.PendingValidators) > 0 && number%snap.EpochLength == snap.minerHistoryCheckLen() {
+		snap.Validators = snap.PendingValidators
+		snap.TurnLength = snap.PendingTurnLength
+		snap.PendingValidators, snap.PendingTurnLength = nil, 0
+	}
+
+	snap.Number, snap.Hash, snap.Time = number, header.Hash(), header.Time
+	snap.TD.Add(snap.TD, header.Difficulty)
+	snap.EpochLength = config.nextEpochLength(snap.EpochLength, number, header.Time)
+	return snap, nil
+}
@@ -0,0 +1,40 @@
+package bsc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+type Validate struct{}
+
+func (v *Validate) ValidateHeaderChain(db types.StateDB, headers []byte, chainType chains.ChainType) (int, error) {
+	var chain []*Header
+	if err := rlp.DecodeBytes(headers, &chain); err != nil {
+		log.Error("rlp decode bsc headers failed.", "err", err)
+		return 0, chains.ErrRLPDecode
+	}
+	if len(chain) == 0 {
+		return 0, errors.New("headers cannot be empty")
+	}
+	for i, header := range chain {
+		if header.Number == nil || header.Difficulty == nil {
+			return i, errors.New("invalid header number or difficulty is nil")
+		}
+		if i > 0 && (header.Number.Uint64() != chain[i-1].Number.Uint64()+1 || header.ParentHash != chain[i-1].Hash()) {
+			return i, fmt.Errorf("non contiguous insert: item %d is #%d, item %d is #%d", i-1, chain[i-1].Number, i, header.Number)
+		}
+	}
+
+	hs := NewHeaderStore(uint64(chainType))
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	_, i, err := hs.applyHeaders(db, chain)
+	return i, err
+}
@@ -0,0 +1,66 @@
+package bsc
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// TxProve proves a receipt of a BSC block that fast finality has finalized
+type TxProve struct {
+	Receipt     *ethtypes.Receipt
+	Prove       light.NodeList
+	BlockNumber uint64
+	TxIndex     uint
+}
+
+type Verify struct {
+	chainID uint64
+}
+
+func NewVerify(chainID uint64) *Verify {
+	return &Verify{chainID: chainID}
+}
+
+func (v *Verify) Verify(db types.StateDB, routerContractAddr common.Address, txProveBytes []byte) (logs []byte, err error) {
+	var txProve TxProve
+	if err := rlp.DecodeBytes(txProveBytes, &txProve); err != nil {
+		return nil, err
+	}
+	if txProve.Receipt == nil {
+		return nil, errors.New("receipt cannot be empty")
+	}
+
+	header, err := NewHeaderStore(v.chainID).GetFinalizedHeader(db, txProve.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.verifyProof(header.ReceiptHash, &txProve); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(txProve.Receipt.Logs)
+}
+
+func (v *Verify) verifyProof(receiptsRoot common.Hash, txProve *TxProve) error {
+	var buf bytes.Buffer
+	rs := ethtypes.Receipts{txProve.Receipt}
+	rs.EncodeIndex(0, &buf)
+	giveReceipt := buf.Bytes()
+
+	key := rlp.AppendUint64(nil, uint64(txProve.TxIndex))
+	getReceipt, err := trie.VerifyProof(receiptsRoot, key, txProve.Prove.NodeSet())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(giveReceipt, getReceipt) {
+		return errors.New("receipt mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package bsc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReceipts() ethtypes.Receipts {
+	var receipts ethtypes.Receipts
+	for i := 0; i < 3; i++ {
+		receipts = append(receipts, &ethtypes.Receipt{
+			Type:              ethtypes.LegacyTxType,
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*ethtypes.Log{{
+				Address: common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6"),
+				Topics:  []common.Hash{common.HexToHash("0x155e433be3576195943c515e1096620bc754e11b3a4b60fda7c4628caf373635")},
+				Data:    []byte{byte(i)},
+			}},
+		})
+	}
+	return receipts
+}
+
+func encodeTxProve(t *testing.T, tr *trie.Trie, number uint64, receipt *ethtypes.Receipt, txIndex uint) []byte {
+	key, err := rlp.EncodeToBytes(txIndex)
+	assert.Nil(t, err)
+	proof := light.NewNodeSet()
+	assert.Nil(t, tr.Prove(key, 0, proof))
+
+	input, err := rlp.EncodeToBytes(TxProve{
+		Receipt:     receipt,
+		Prove:       proof.NodeList(),
+		BlockNumber: number,
+		TxIndex:     txIndex,
+	})
+	assert.Nil(t, err)
+	return input
+}
+
+func TestVerify(t *testing.T) {
+	receipts := newTestReceipts()
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	assert.Nil(t, err)
+	for i := range receipts {
+		key, err := rlp.EncodeToBytes(uint(i))
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		receipts.EncodeIndex(i, &buf)
+		tr.Update(key, buf.Bytes())
+	}
+
+	c := newTestChain(t, newTestValidators(t, 3))
+	hs, db := newTestHeaderStore(t, c)
+	header := c.next(c.cur, headerOpts{modify: func(header *Header) {
+		header.ReceiptHash = tr.Hash()
+	}})
+	headers := append([]*Header{header}, c.chain(header, 1)...)
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, headers...))
+	assert.Nil(t, err)
+
+	router := common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6")
+	verify := NewVerify(testChainID)
+	_, err = verify.Verify(db, router, encodeTxProve(t, tr, 21, receipts[1], 1))
+	assert.Equal(t, errHeaderNotFinalized, err)
+
+	// block 23 finalizes block 21 with the vote for 22
+	_, err = hs.InsertHeaders(db, encodeHeaders(t, c.chain(headers[1], 1)...))
+	assert.Nil(t, err)
+	logs, err := verify.Verify(db, router, encodeTxProve(t, tr, 21, receipts[1], 1))
+	assert.Nil(t, err)
+	expLogs, err := rlp.EncodeToBytes(receipts[1].Logs)
+	assert.Nil(t, err)
+	assert.Equal(t, expLogs, logs)
+
+	_, err = verify.Verify(db, router, encodeTxProve(t, tr, 21, receipts[2], 1))
+	assert.EqualError(t, err, "receipt mismatch")
+
+	// the receipt is not in block 22
+	_, err = verify.Verify(db, router, encodeTxProve(t, tr, 22, receipts[1], 1))
+	assert.NotNil(t, err)
+}
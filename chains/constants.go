@@ -17,11 +17,31 @@ const (
 const (
 	ChainTypeETH     ChainType = 1
 	ChainTypeETHTest ChainType = 34434
+
+	ChainTypeETHSepolia ChainType = 11155111
+	ChainTypeETHHolesky ChainType = 17000
+)
+
+const (
+	ChainTypeBSC     ChainType = 56
+	ChainTypeBSCTest ChainType = 97
+)
+
+// bitcoin has no chain id, these identify it in cross-chain messages
+const (
+	ChainTypeBTC     ChainType = 1360095883558913
+	ChainTypeBTCTest ChainType = 1360095883558914
 )
 
 const (
 	ChainGroupMAP = 1000
 	ChainGroupETH = 1001
+	// ChainGroupETH2 proves receipts against execution payloads finalized by the eth2 light client
+	ChainGroupETH2 = 1002
+	// ChainGroupBSC follows the parlia validator set and fast finality of BNB Smart Chain
+	ChainGroupBSC = 1003
+	// ChainGroupBTC follows the bitcoin chain with the most work and verifies transactions by SPV
+	ChainGroupBTC = 1004
 )
 
 var ChainTypeList = []ChainType{
@@ -30,16 +50,43 @@ var ChainTypeList = []ChainType{
 	ChainTypeMAPDev,
 	ChainTypeETH,
 	ChainTypeETHTest,
+	ChainTypeETHSepolia,
+	ChainTypeETHHolesky,
+	ChainTypeBSC,
+	ChainTypeBSCTest,
+	ChainTypeBTC,
+	ChainTypeBTCTest,
+}
+
+// chainTypeForks holds the fork activating each chain type added after genesis, the chain
+// is not supported before it
+var chainTypeForks = map[ChainType]func(*params.ChainConfig, *big.Int) bool{
+	ChainTypeETHSepolia: (*params.ChainConfig).IsEth2Testnets,
+	ChainTypeETHHolesky: (*params.ChainConfig).IsEth2Testnets,
+	ChainTypeBSC:        (*params.ChainConfig).IsBSC,
+	ChainTypeBSCTest:    (*params.ChainConfig).IsBSC,
+	ChainTypeBTC:        (*params.ChainConfig).IsBTC,
+	ChainTypeBTCTest:    (*params.ChainConfig).IsBTC,
 }
 
 var chainType2ChainGroup = map[ChainType]ChainGroup{
-	ChainTypeETH:     ChainGroupETH,
-	ChainTypeETHTest: ChainGroupETH,
+	ChainTypeETH:        ChainGroupETH,
+	ChainTypeETHTest:    ChainGroupETH,
+	ChainTypeETHSepolia: ChainGroupETH2,
+	ChainTypeETHHolesky: ChainGroupETH2,
+	ChainTypeBSC:        ChainGroupBSC,
+	ChainTypeBSCTest:    ChainGroupBSC,
+	ChainTypeBTC:        ChainGroupBTC,
+	ChainTypeBTCTest:    ChainGroupBTC,
 }
 
 var chainType2ChainID = map[ChainType]uint64{
 	ChainTypeETH:     params.MainNetChainID,
 	ChainTypeETHTest: params.TestNetChainID,
+	ChainTypeBSC:     params.MainNetChainID,
+	ChainTypeBSCTest: params.TestNetChainID,
+	ChainTypeBTC:     params.MainNetChainID,
+	ChainTypeBTCTest: params.TestNetChainID,
 }
 
 var chainType2LondonBlock = map[ChainType]*big.Int{
@@ -48,29 +95,48 @@ var chainType2LondonBlock = map[ChainType]*big.Int{
 }
 
 var (
-	EthereumHeaderStoreAddress = common.BytesToAddress([]byte("EthereumHeaderStoreAddress"))
+	EthereumHeaderStoreAddress  = common.BytesToAddress([]byte("EthereumHeaderStoreAddress"))
+	Eth2LightClientStoreAddress = common.BytesToAddress([]byte("Eth2LightClientStoreAddress"))
+	BSCHeaderStoreAddress       = common.BytesToAddress([]byte("BSCHeaderStoreAddress"))
+	BitcoinHeaderStoreAddress   = common.BytesToAddress([]byte("BitcoinHeaderStoreAddress"))
+	HeaderRetentionAddress      = common.BytesToAddress([]byte("HeaderRetentionAddress"))
 )
 
 type ChainType uint64
 type ChainGroup uint64
 
-func IsSupportedChain(chain ChainType) bool {
+func isActiveChain(chain ChainType, config *params.ChainConfig, num *big.Int) bool {
+	isFork, ok := chainTypeForks[chain]
+	return !ok || isFork(config, num)
+}
+
+func IsSupportedChain(chain ChainType, config *params.ChainConfig, num *big.Int) bool {
 	for _, c := range ChainTypeList {
 		if c == chain {
-			return true
+			return isActiveChain(chain, config, num)
 		}
 	}
 	return false
 }
 
-func ChainType2ChainGroup(chain ChainType) (ChainGroup, error) {
+func ChainType2ChainGroup(chain ChainType, config *params.ChainConfig, num *big.Int) (ChainGroup, error) {
 	group, ok := chainType2ChainGroup[chain]
-	if !ok {
+	if !ok || !isActiveChain(chain, config, num) {
 		return 0, ErrNotSupportChain
 	}
 	return group, nil
 }
 
+// ChainType2VerifyGroup returns the group verifying the receipts of the chain at the given
+// block. Ethereum mainnet left proof of work at the merge, since the eth2 verify fork its
+// receipts are verified by the eth2 light client.
+func ChainType2VerifyGroup(chain ChainType, config *params.ChainConfig, num *big.Int) (ChainGroup, error) {
+	if chain == ChainTypeETH && config.IsEth2Verify(num) {
+		return ChainGroupETH2, nil
+	}
+	return ChainType2ChainGroup(chain, config, num)
+}
+
 func ChainType2ChainID(chain ChainType) (uint64, error) {
 	chainID, ok := chainType2ChainID[chain]
 	if !ok {
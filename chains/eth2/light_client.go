@@ -2,18 +2,26 @@ package eth2
 
 import (
 	"fmt"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/mapprotocol/atlas/chains/eth2/bls12381"
+	"github.com/mapprotocol/atlas/params"
 	ssz "github.com/prysmaticlabs/fastssz"
 )
 
 const MinSyncCommitteeParticipants uint64 = 1
 const EpochsPerSyncCommitteePeriod uint64 = 256
 const SlotsPerEpoch uint64 = 32
+const SyncCommitteeSize = 512
 
 const FinalizedRootIndex uint32 = 105
 const NextSyncCommitteeIndex uint32 = 55
 
+// Electra grows the beacon state past 32 fields, which adds one level to the state tree
+const FinalizedRootIndexElectra uint32 = 169
+const NextSyncCommitteeIndexElectra uint32 = 87
+
 const BeaconBlockBodyTreeExecutionPayloadIndex uint64 = 25
 const ExecutionPayloadProofSize int = 4
 
@@ -25,15 +33,14 @@ const ExecutionProofSize = L1BeaconBlockBodyProofSize + L2ExecutionPayloadProofS
 
 var DomainSyncCommittee = [4]byte{0x07, 0x00, 0x00, 0x00}
 
-func VerifyLightClientUpdate(input []byte) error {
-	verify, err := decodeLightClientVerifyV2(input)
+func VerifyLightClientUpdate(chainConfig *params.ChainConfig, num *big.Int, input []byte) error {
+	verify, err := decodeLightClientVerify(input)
 	if err != nil {
-		log.Warn("decodeLightClientVerifyV2", "error", err)
-		verify, err = decodeLightClientVerifyV1(input)
-		if err != nil {
-			log.Warn("decodeLightClientVerifyV1", "error", err)
-			return err
-		}
+		return err
+	}
+
+	if err := checkNetwork(chainConfig, num, verify.state.chainID); err != nil {
+		return fmt.Errorf("new network failed: %v", err)
 	}
 
 	switch verify.update.(type) {
@@ -43,7 +50,7 @@ func VerifyLightClientUpdate(input []byte) error {
 			return err
 		}
 	case *LightClientUpdateV2:
-		if err := verifyFinalityV2(verify.update.(*LightClientUpdateV2)); err != nil {
+		if err := verifyFinalityV2(verify.state, verify.update.(*LightClientUpdateV2)); err != nil {
 			log.Warn("verifyFinalityV2", "error", err)
 			return err
 		}
@@ -66,13 +73,18 @@ func VerifyLightClientUpdate(input []byte) error {
 	return nil
 }
 
-func verifyFinalityV2(update *LightClientUpdateV2) error {
+func verifyFinalityV2(state *LightClientState, update *LightClientUpdateV2) error {
+	config, err := newNetworkConfig(state.chainID)
+	if err != nil {
+		return fmt.Errorf("new network failed: %v", err)
+	}
+
 	leaf, err := update.finalizedHeader.HashTreeRoot()
 	if err != nil {
 		return fmt.Errorf("failed to compute hash tree root of finalized header: %v", err)
 	}
 	proof := ssz.Proof{
-		Index:  int(FinalizedRootIndex),
+		Index:  int(config.finalizedRootIndex(update.attestedHeader.Slot)),
 		Leaf:   leaf[:],
 		Hashes: update.finalityBranch,
 	}
@@ -89,6 +101,10 @@ func verifyFinalityV2(update *LightClientUpdateV2) error {
 		return fmt.Errorf("invalid execution payload proof size, exp: %d, got: %d", ExecutionPayloadProofSize, len(update.executionBranch))
 	}
 
+	if err := checkExecutionPayloadFork(config, update); err != nil {
+		return err
+	}
+
 	executionPayloadHash, err := update.finalizedExecution.HashTreeRoot()
 	if err != nil {
 		return fmt.Errorf("compute execution payload merkel root failed: %v", err)
@@ -111,6 +127,26 @@ func verifyFinalityV2(update *LightClientUpdateV2) error {
 	return nil
 }
 
+// checkExecutionPayloadFork makes sure the execution payload header container matches the fork
+// of the finalized header, otherwise the hash tree root would be computed over the wrong layout
+func checkExecutionPayloadFork(config *NetworkConfig, update *LightClientUpdateV2) error {
+	isDeneb := config.isDeneb(update.finalizedHeader.Slot)
+	switch update.finalizedExecution.(type) {
+	case *ExecutionPayloadDeneb:
+		if !isDeneb {
+			return fmt.Errorf("deneb execution payload for pre-deneb slot %d", update.finalizedHeader.Slot)
+		}
+	case *ExecutionPayload:
+		if isDeneb {
+			return fmt.Errorf("pre-deneb execution payload for deneb slot %d", update.finalizedHeader.Slot)
+		}
+	default:
+		return fmt.Errorf("invalid execution payload type")
+	}
+
+	return nil
+}
+
 func verifyFinalityV1(update *LightClientUpdateV1) error {
 	leaf, err := update.finalizedHeader.HashTreeRoot()
 	if err != nil {
@@ -161,9 +197,14 @@ func verifyFinalityV1(update *LightClientUpdateV1) error {
 }
 
 func verifyNextSyncCommittee(state *LightClientState, update ILightClientUpdate) error {
+	config, err := newNetworkConfig(state.chainID)
+	if err != nil {
+		return fmt.Errorf("new network failed: %v", err)
+	}
+
 	// The active header will always be the finalized header because we don't accept updates without the finality update.
-	updatePeriod := computeSyncCommitteePeriod(update.GetFinalizedHeader().Slot)
-	finalizedPeriod := computeSyncCommitteePeriod(state.finalizedHeader.Slot)
+	updatePeriod := config.computeSyncCommitteePeriod(update.GetFinalizedHeader().Slot)
+	finalizedPeriod := config.computeSyncCommitteePeriod(state.finalizedHeader.Slot)
 
 	// Verify that the `next_sync_committee`, if present, actually is the next sync committee saved in the
 	// state of the `active_header`
@@ -173,7 +214,7 @@ func verifyNextSyncCommittee(state *LightClientState, update ILightClientUpdate)
 			return fmt.Errorf("failed to compute hash tree root of finalized header: %v", err)
 		}
 		proof := ssz.Proof{
-			Index:  int(NextSyncCommitteeIndex),
+			Index:  int(config.nextSyncCommitteeIndex(update.GetAttestedHeader().Slot)),
 			Leaf:   leaf[:],
 			Hashes: update.GetNextSyncCommitteeBranch(),
 		}
@@ -200,13 +241,13 @@ func verifyBlsSignatures(state *LightClientState, update ILightClientUpdate) err
 		return fmt.Errorf("not enought sync committe count %d", syncCommitteeCount)
 	}
 
-	finalizedPeriod := computeSyncCommitteePeriod(state.finalizedHeader.Slot)
 	config, err := newNetworkConfig(state.chainID)
 	if err != nil {
 		return fmt.Errorf("new network failed: %v", err)
 	}
 
-	signaturePeriod := computeSyncCommitteePeriod(update.GetSignatureSlot())
+	finalizedPeriod := config.computeSyncCommitteePeriod(state.finalizedHeader.Slot)
+	signaturePeriod := config.computeSyncCommitteePeriod(update.GetSignatureSlot())
 	var syncCommittee *SyncCommittee
 
 	// Verify signature period does not skip a sync committee period
@@ -0,0 +1,539 @@
+package eth2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	bls "github.com/mapprotocol/atlas/chains/eth2/bls12381"
+	blscommon "github.com/mapprotocol/atlas/chains/eth2/bls12381/common"
+	"github.com/mapprotocol/atlas/params"
+	ssz "github.com/prysmaticlabs/fastssz"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mainnetChainID        = 1
+	slotsPerSyncPeriod    = SlotsPerEpoch * EpochsPerSyncCommitteePeriod
+	syncCommitteeSize     = 512
+	distinctCommitteeKeys = 4
+)
+
+// sparseTree is a merkle tree in generalized index form where every node that is not
+// set explicitly and not above a set node is a zero leaf.
+type sparseTree struct {
+	depth  int
+	leaves map[uint64][]byte
+}
+
+func newSparseTree(depth int) *sparseTree {
+	return &sparseTree{depth: depth, leaves: make(map[uint64][]byte)}
+}
+
+func (t *sparseTree) set(gindex uint64, leaf []byte) {
+	t.leaves[gindex] = leaf
+}
+
+func (t *sparseTree) node(gindex uint64) []byte {
+	if leaf, ok := t.leaves[gindex]; ok {
+		return leaf
+	}
+	if gindex >= uint64(1)<<t.depth {
+		return make([]byte, 32)
+	}
+	return hashFn(append(append([]byte{}, t.node(gindex*2)...), t.node(gindex*2+1)...))
+}
+
+func (t *sparseTree) branch(gindex uint64) [][32]byte {
+	var branch [][32]byte
+	for ; gindex > 1; gindex /= 2 {
+		branch = append(branch, common.BytesToHash(t.node(gindex^1)))
+	}
+	return branch
+}
+
+type forkTestVector struct {
+	update            ILightNodeLightClientUpdateV3
+	finalizedHeader   ILightNodeBeaconBlockHeader
+	curSyncCommittee  ILightNodeSyncCommittee
+	nextSyncCommittee ILightNodeSyncCommittee
+	chainID           uint64
+}
+
+func (v *forkTestVector) encodeV3(t *testing.T) []byte {
+	args, err := genAbiArgsV3()
+	assert.Nil(t, err)
+	input, err := args.Pack(v.update, v.finalizedHeader, v.curSyncCommittee, v.nextSyncCommittee, v.chainID)
+	assert.Nil(t, err)
+	return input
+}
+
+func (v *forkTestVector) encodeV2(t *testing.T) []byte {
+	exe := v.update.FinalizedExecution
+	update := ILightNodeLightClientUpdateV2{
+		AttestedHeader:          v.update.AttestedHeader,
+		NextSyncCommittee:       v.update.NextSyncCommittee,
+		NextSyncCommitteeBranch: v.update.NextSyncCommitteeBranch,
+		FinalizedHeader:         v.update.FinalizedHeader,
+		FinalityBranch:          v.update.FinalityBranch,
+		FinalizedExecution: ILightNodeExecution{
+			ParentHash:       exe.ParentHash,
+			FeeRecipient:     exe.FeeRecipient,
+			StateRoot:        exe.StateRoot,
+			ReceiptsRoot:     exe.ReceiptsRoot,
+			LogsBloom:        exe.LogsBloom,
+			PrevRandao:       exe.PrevRandao,
+			BlockNumber:      exe.BlockNumber,
+			GasLimit:         exe.GasLimit,
+			GasUsed:          exe.GasUsed,
+			Timestamp:        exe.Timestamp,
+			ExtraData:        exe.ExtraData,
+			BaseFeePerGas:    exe.BaseFeePerGas,
+			BlockHash:        exe.BlockHash,
+			TransactionsRoot: exe.TransactionsRoot,
+			WithdrawalsRoot:  exe.WithdrawalsRoot,
+		},
+		ExecutionBranch: v.update.ExecutionBranch,
+		SyncAggregate:   v.update.SyncAggregate,
+		SignatureSlot:   v.update.SignatureSlot,
+	}
+	args, err := genAbiArgs()
+	assert.Nil(t, err)
+	input, err := args.Pack(update, v.finalizedHeader, v.curSyncCommittee, v.nextSyncCommittee, v.chainID)
+	assert.Nil(t, err)
+	return input
+}
+
+func newTestSyncCommittee(t *testing.T) (ILightNodeSyncCommittee, []blscommon.SecretKey) {
+	var keys []blscommon.SecretKey
+	var pubkeys [][]byte
+	for i := 0; i < distinctCommitteeKeys; i++ {
+		key, err := bls.RandKey()
+		assert.Nil(t, err)
+		keys = append(keys, key)
+	}
+	for i := 0; i < syncCommitteeSize; i++ {
+		pubkeys = append(pubkeys, keys[i%distinctCommitteeKeys].PublicKey().Marshal())
+	}
+	aggregate, err := bls.AggregatePublicKeys(pubkeys)
+	assert.Nil(t, err)
+
+	return ILightNodeSyncCommittee{
+		Pubkeys:         bytes.Join(pubkeys, nil),
+		AggregatePubkey: aggregate.Marshal(),
+	}, keys
+}
+
+// signSyncAggregate signs the attested header with every member of the sync committee
+func signSyncAggregate(t *testing.T, config *NetworkConfig, attestedHeader ILightNodeBeaconBlockHeader,
+	signatureSlot uint64, signers []blscommon.SecretKey) ILightNodeSyncAggregate {
+	domain, err := ComputeDomain(DomainSyncCommittee, config.computeForkVersionBySlot(signatureSlot - 1)[:], config.GenesisValidatorsRoot[:])
+	assert.Nil(t, err)
+	signingRoot, err := ComputeSigningRoot(attestedHeader.toBeaconBlockHeader(), domain)
+	assert.Nil(t, err)
+	var signatures []blscommon.Signature
+	for i := 0; i < syncCommitteeSize; i++ {
+		signatures = append(signatures, signers[i%distinctCommitteeKeys].Sign(signingRoot[:]))
+	}
+
+	return ILightNodeSyncAggregate{
+		SyncCommitteeBits:      bytes.Repeat([]byte{0xff}, syncCommitteeSize/8),
+		SyncCommitteeSignature: bls.AggregateSignatures(signatures).Marshal(),
+	}
+}
+
+// makeForkTestVector builds a fully signed light client update whose finalized header is at
+// the given epoch, with the state tree laid out for the fork active at that epoch.
+func makeForkTestVector(t *testing.T, epoch uint64) *forkTestVector {
+	return makeForkTestVectorWithReceiptsRoot(t, epoch, common.HexToHash("0x04"))
+}
+
+func makeForkTestVectorWithReceiptsRoot(t *testing.T, epoch uint64, receiptsRoot common.Hash) *forkTestVector {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	finalizedSlot := epoch * SlotsPerEpoch
+	if finalizedSlot%slotsPerSyncPeriod == 0 {
+		finalizedSlot++
+	}
+	attestedSlot := finalizedSlot + 2*SlotsPerEpoch
+	signatureSlot := attestedSlot + 1
+
+	curSyncCommittee, _ := newTestSyncCommittee(t)
+	nextSyncCommittee, signers := newTestSyncCommittee(t)
+	updateNextSyncCommittee, _ := newTestSyncCommittee(t)
+
+	execution := ILightNodeExecutionV3{
+		ParentHash:       common.HexToHash("0x01"),
+		FeeRecipient:     common.HexToAddress("0x02"),
+		StateRoot:        common.HexToHash("0x03"),
+		ReceiptsRoot:     receiptsRoot,
+		LogsBloom:        make([]byte, 256),
+		PrevRandao:       common.HexToHash("0x05"),
+		BlockNumber:      big.NewInt(19426587),
+		GasLimit:         big.NewInt(30000000),
+		GasUsed:          big.NewInt(12345678),
+		Timestamp:        big.NewInt(1710338135),
+		ExtraData:        []byte("atlas"),
+		BaseFeePerGas:    big.NewInt(22000000000),
+		BlockHash:        common.HexToHash("0x06"),
+		TransactionsRoot: common.HexToHash("0x07"),
+		WithdrawalsRoot:  common.HexToHash("0x08"),
+		BlobGasUsed:      big.NewInt(393216),
+		ExcessBlobGas:    big.NewInt(79691776),
+	}
+	executionRoot, err := execution.toExecutionPayloadDeneb().HashTreeRoot()
+	assert.Nil(t, err)
+
+	body := newSparseTree(4)
+	body.set(BeaconBlockBodyTreeExecutionPayloadIndex, executionRoot[:])
+
+	finalizedHeader := ILightNodeBeaconBlockHeader{
+		Slot:          finalizedSlot,
+		ProposerIndex: 42,
+		ParentRoot:    common.HexToHash("0x09"),
+		StateRoot:     common.HexToHash("0x0a"),
+		BodyRoot:      common.BytesToHash(body.node(1)),
+	}
+	finalizedRoot, err := finalizedHeader.toBeaconBlockHeader().HashTreeRoot()
+	assert.Nil(t, err)
+	nextSyncCommitteeRoot, err := SyncCommitteeRoot(updateNextSyncCommittee.toSyncCommittee())
+	assert.Nil(t, err)
+
+	stateDepth := 6
+	if config.isElectra(attestedSlot) {
+		stateDepth = 7
+	}
+	state := newSparseTree(stateDepth)
+	state.set(uint64(config.finalizedRootIndex(attestedSlot)), finalizedRoot[:])
+	state.set(uint64(config.nextSyncCommitteeIndex(attestedSlot)), nextSyncCommitteeRoot[:])
+
+	attestedHeader := ILightNodeBeaconBlockHeader{
+		Slot:          attestedSlot,
+		ProposerIndex: 7,
+		ParentRoot:    common.HexToHash("0x0b"),
+		StateRoot:     common.BytesToHash(state.node(1)),
+		BodyRoot:      common.HexToHash("0x0c"),
+	}
+
+	// the trusted state is finalized one sync committee period before the update
+	trustedHeader := finalizedHeader
+	trustedHeader.Slot = finalizedSlot - slotsPerSyncPeriod
+
+	return &forkTestVector{
+		update: ILightNodeLightClientUpdateV3{
+			AttestedHeader:          attestedHeader,
+			NextSyncCommittee:       updateNextSyncCommittee,
+			NextSyncCommitteeBranch: state.branch(uint64(config.nextSyncCommitteeIndex(attestedSlot))),
+			FinalizedHeader:         finalizedHeader,
+			FinalityBranch:          state.branch(uint64(config.finalizedRootIndex(attestedSlot))),
+			FinalizedExecution:      execution,
+			ExecutionBranch:         body.branch(BeaconBlockBodyTreeExecutionPayloadIndex),
+			SyncAggregate:           signSyncAggregate(t, config, attestedHeader, signatureSlot, signers),
+			SignatureSlot:           signatureSlot,
+		},
+		finalizedHeader:   trustedHeader,
+		curSyncCommittee:  curSyncCommittee,
+		nextSyncCommittee: nextSyncCommittee,
+		chainID:           mainnetChainID,
+	}
+}
+
+func TestComputeForkVersion(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	assert.Nil(t, config.computeForkVersion(config.BellatrixForkEpoch-1))
+	assert.Equal(t, config.BellatrixForkVersion, *config.computeForkVersion(config.BellatrixForkEpoch))
+	assert.Equal(t, config.CapellaForkVersion, *config.computeForkVersion(config.CapellaForkEpoch))
+	assert.Equal(t, config.CapellaForkVersion, *config.computeForkVersion(config.DenebForkEpoch - 1))
+	assert.Equal(t, config.DenebForkVersion, *config.computeForkVersion(config.DenebForkEpoch))
+	assert.Equal(t, config.DenebForkVersion, *config.computeForkVersion(config.ElectraForkEpoch - 1))
+	assert.Equal(t, config.ElectraForkVersion, *config.computeForkVersion(config.ElectraForkEpoch))
+
+	goerli, err := newNetworkConfig(5)
+	assert.Nil(t, err)
+	assert.Equal(t, goerli.DenebForkVersion, *goerli.computeForkVersion(goerli.DenebForkEpoch + 1000000))
+}
+
+func TestStateGeneralizedIndex(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	denebSlot := config.DenebForkEpoch * SlotsPerEpoch
+	electraSlot := config.ElectraForkEpoch * SlotsPerEpoch
+	assert.Equal(t, FinalizedRootIndex, config.finalizedRootIndex(denebSlot))
+	assert.Equal(t, NextSyncCommitteeIndex, config.nextSyncCommitteeIndex(denebSlot))
+	assert.Equal(t, FinalizedRootIndexElectra, config.finalizedRootIndex(electraSlot))
+	assert.Equal(t, NextSyncCommitteeIndexElectra, config.nextSyncCommitteeIndex(electraSlot))
+}
+
+func TestExecutionPayloadDenebHashTreeRoot(t *testing.T) {
+	capella := &ExecutionPayload{
+		LogsBloom:       make([]byte, 256),
+		BlockNumber:     big.NewInt(1),
+		BaseFeePerGas:   big.NewInt(7),
+		WithdrawalsRoot: common.HexToHash("0x01"),
+	}
+	deneb := &ExecutionPayloadDeneb{ExecutionPayload: *capella}
+
+	capellaRoot, err := capella.HashTreeRoot()
+	assert.Nil(t, err)
+	denebRoot, err := deneb.HashTreeRoot()
+	assert.Nil(t, err)
+	// 17 fields are merkleized over 32 leaves instead of 16, so zero blob fields still change the root
+	assert.NotEqual(t, capellaRoot, denebRoot)
+
+	deneb.BlobGasUsed = 131072
+	blobRoot, err := deneb.HashTreeRoot()
+	assert.Nil(t, err)
+	assert.NotEqual(t, denebRoot, blobRoot)
+}
+
+type fixtureHeader struct {
+	Slot          uint64      `json:"slot"`
+	ProposerIndex uint64      `json:"proposerIndex"`
+	ParentRoot    common.Hash `json:"parentRoot"`
+	StateRoot     common.Hash `json:"stateRoot"`
+	BodyRoot      common.Hash `json:"bodyRoot"`
+}
+
+func (h *fixtureHeader) toHeader() ILightNodeBeaconBlockHeader {
+	return ILightNodeBeaconBlockHeader{
+		Slot:          h.Slot,
+		ProposerIndex: h.ProposerIndex,
+		ParentRoot:    h.ParentRoot,
+		StateRoot:     h.StateRoot,
+		BodyRoot:      h.BodyRoot,
+	}
+}
+
+type fixtureSyncCommittee struct {
+	Pubkeys         hexutil.Bytes `json:"pubkeys"`
+	AggregatePubkey hexutil.Bytes `json:"aggregatePubkey"`
+}
+
+func (c *fixtureSyncCommittee) toSyncCommittee() ILightNodeSyncCommittee {
+	return ILightNodeSyncCommittee{Pubkeys: c.Pubkeys, AggregatePubkey: c.AggregatePubkey}
+}
+
+// updateFixture is a light client update over a published beacon block. The finalized
+// header, execution payload header and execution branch are taken from the block, the
+// attested state is a real state of the fork with the block set as finalized checkpoint
+// and the Goerli sync committee of goerli_bellatrix_state.json as next sync committee.
+type updateFixture struct {
+	Source             string        `json:"source"`
+	Fork               string        `json:"fork"`
+	FinalizedHeader    fixtureHeader `json:"finalizedHeader"`
+	FinalizedExecution struct {
+		ParentHash       common.Hash    `json:"parentHash"`
+		FeeRecipient     common.Address `json:"feeRecipient"`
+		StateRoot        common.Hash    `json:"stateRoot"`
+		ReceiptsRoot     common.Hash    `json:"receiptsRoot"`
+		LogsBloom        hexutil.Bytes  `json:"logsBloom"`
+		PrevRandao       common.Hash    `json:"prevRandao"`
+		BlockNumber      uint64         `json:"blockNumber"`
+		GasLimit         uint64         `json:"gasLimit"`
+		GasUsed          uint64         `json:"gasUsed"`
+		Timestamp        uint64         `json:"timestamp"`
+		ExtraData        hexutil.Bytes  `json:"extraData"`
+		BaseFeePerGas    string         `json:"baseFeePerGas"`
+		BlockHash        common.Hash    `json:"blockHash"`
+		TransactionsRoot common.Hash    `json:"transactionsRoot"`
+		WithdrawalsRoot  common.Hash    `json:"withdrawalsRoot"`
+		BlobGasUsed      uint64         `json:"blobGasUsed"`
+		ExcessBlobGas    uint64         `json:"excessBlobGas"`
+	} `json:"finalizedExecution"`
+	ExecutionBranch         []common.Hash        `json:"executionBranch"`
+	AttestedHeader          fixtureHeader        `json:"attestedHeader"`
+	FinalityBranch          []common.Hash        `json:"finalityBranch"`
+	NextSyncCommittee       fixtureSyncCommittee `json:"nextSyncCommittee"`
+	NextSyncCommitteeBranch []common.Hash        `json:"nextSyncCommitteeBranch"`
+}
+
+// stateFixture holds the finality and next sync committee proofs of a published beacon state
+type stateFixture struct {
+	Source                  string               `json:"source"`
+	Slot                    uint64               `json:"slot"`
+	StateRoot               common.Hash          `json:"stateRoot"`
+	FinalizedRoot           common.Hash          `json:"finalizedRoot"`
+	FinalityBranch          []common.Hash        `json:"finalityBranch"`
+	NextSyncCommittee       fixtureSyncCommittee `json:"nextSyncCommittee"`
+	NextSyncCommitteeRoot   common.Hash          `json:"nextSyncCommitteeRoot"`
+	NextSyncCommitteeBranch []common.Hash        `json:"nextSyncCommitteeBranch"`
+}
+
+func loadFixture(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, v))
+}
+
+func hashesToBranch(hashes []common.Hash) [][32]byte {
+	branch := make([][32]byte, 0, len(hashes))
+	for _, h := range hashes {
+		branch = append(branch, h)
+	}
+	return branch
+}
+
+// loadForkTestVector builds a mainnet light client update from the published fixture of the
+// given fork. Only the sync committees signing the attested header are generated.
+func loadForkTestVector(t *testing.T, fork string) *forkTestVector {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	var fixture updateFixture
+	loadFixture(t, "update_"+fork+".json", &fixture)
+	assert.Equal(t, fork, fixture.Fork)
+
+	exe := fixture.FinalizedExecution
+	baseFee, ok := new(big.Int).SetString(exe.BaseFeePerGas, 10)
+	assert.True(t, ok)
+
+	attestedHeader := fixture.AttestedHeader.toHeader()
+	signatureSlot := attestedHeader.Slot + 1
+	curSyncCommittee, _ := newTestSyncCommittee(t)
+	nextSyncCommittee, signers := newTestSyncCommittee(t)
+
+	trustedHeader := fixture.FinalizedHeader.toHeader()
+	trustedHeader.Slot -= slotsPerSyncPeriod
+
+	return &forkTestVector{
+		update: ILightNodeLightClientUpdateV3{
+			AttestedHeader:          attestedHeader,
+			NextSyncCommittee:       fixture.NextSyncCommittee.toSyncCommittee(),
+			NextSyncCommitteeBranch: hashesToBranch(fixture.NextSyncCommitteeBranch),
+			FinalizedHeader:         fixture.FinalizedHeader.toHeader(),
+			FinalityBranch:          hashesToBranch(fixture.FinalityBranch),
+			FinalizedExecution: ILightNodeExecutionV3{
+				ParentHash:       exe.ParentHash,
+				FeeRecipient:     exe.FeeRecipient,
+				StateRoot:        exe.StateRoot,
+				ReceiptsRoot:     exe.ReceiptsRoot,
+				LogsBloom:        exe.LogsBloom,
+				PrevRandao:       exe.PrevRandao,
+				BlockNumber:      new(big.Int).SetUint64(exe.BlockNumber),
+				GasLimit:         new(big.Int).SetUint64(exe.GasLimit),
+				GasUsed:          new(big.Int).SetUint64(exe.GasUsed),
+				Timestamp:        new(big.Int).SetUint64(exe.Timestamp),
+				ExtraData:        exe.ExtraData,
+				BaseFeePerGas:    baseFee,
+				BlockHash:        exe.BlockHash,
+				TransactionsRoot: exe.TransactionsRoot,
+				WithdrawalsRoot:  exe.WithdrawalsRoot,
+				BlobGasUsed:      new(big.Int).SetUint64(exe.BlobGasUsed),
+				ExcessBlobGas:    new(big.Int).SetUint64(exe.ExcessBlobGas),
+			},
+			ExecutionBranch: hashesToBranch(fixture.ExecutionBranch),
+			SyncAggregate:   signSyncAggregate(t, config, attestedHeader, signatureSlot, signers),
+			SignatureSlot:   signatureSlot,
+		},
+		finalizedHeader:   trustedHeader,
+		curSyncCommittee:  curSyncCommittee,
+		nextSyncCommittee: nextSyncCommittee,
+		chainID:           mainnetChainID,
+	}
+}
+
+func TestGoerliStateProofs(t *testing.T) {
+	config, err := newNetworkConfig(5)
+	assert.Nil(t, err)
+
+	var fixture stateFixture
+	loadFixture(t, "goerli_bellatrix_state.json", &fixture)
+
+	ret, err := ssz.VerifyProof(fixture.StateRoot[:], &ssz.Proof{
+		Index:  int(config.finalizedRootIndex(fixture.Slot)),
+		Leaf:   fixture.FinalizedRoot[:],
+		Hashes: bytes32ArrayToBytesArray(hashesToBranch(fixture.FinalityBranch)),
+	})
+	assert.Nil(t, err)
+	assert.True(t, ret)
+
+	committee := fixture.NextSyncCommittee.toSyncCommittee()
+	root, err := SyncCommitteeRoot(committee.toSyncCommittee())
+	assert.Nil(t, err)
+	assert.Equal(t, fixture.NextSyncCommitteeRoot, common.Hash(root))
+
+	ret, err = ssz.VerifyProof(fixture.StateRoot[:], &ssz.Proof{
+		Index:  int(config.nextSyncCommitteeIndex(fixture.Slot)),
+		Leaf:   root[:],
+		Hashes: bytes32ArrayToBytesArray(hashesToBranch(fixture.NextSyncCommitteeBranch)),
+	})
+	assert.Nil(t, err)
+	assert.True(t, ret)
+}
+
+func TestVerifyLightClientUpdateCapella(t *testing.T) {
+	vector := loadForkTestVector(t, "capella")
+	update := vector.update.toLightClientUpdateV2()
+	state := ConvertToLightClientState(&vector.finalizedHeader, &vector.curSyncCommittee, &vector.nextSyncCommittee, vector.chainID)
+
+	// the blob gas fields change the payload root
+	err := verifyFinalityV2(state, update)
+	assert.EqualError(t, err, fmt.Sprintf("deneb execution payload for pre-deneb slot %d", vector.update.FinalizedHeader.Slot))
+
+	update.finalizedExecution = &update.finalizedExecution.(*ExecutionPayloadDeneb).ExecutionPayload
+	assert.Nil(t, verifyFinalityV2(state, update))
+	assert.Nil(t, verifyNextSyncCommittee(state, update))
+	assert.Nil(t, verifyBlsSignatures(state, update))
+}
+
+func TestVerifyLightClientUpdateDeneb(t *testing.T) {
+	vector := loadForkTestVector(t, "deneb")
+	input := vector.encodeV3(t)
+
+	verify, err := decodeLightClientVerify(input)
+	assert.Nil(t, err)
+	assert.IsType(t, &ExecutionPayloadDeneb{}, verify.update.(*LightClientUpdateV2).finalizedExecution)
+	assert.Equal(t, 6, len(verify.update.GetFinalityBranch()))
+	assert.Equal(t, 5, len(verify.update.GetNextSyncCommitteeBranch()))
+
+	assert.Nil(t, VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), input))
+}
+
+func TestVerifyLightClientUpdateElectra(t *testing.T) {
+	vector := loadForkTestVector(t, "electra")
+	input := vector.encodeV3(t)
+
+	verify, err := decodeLightClientVerify(input)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(verify.update.GetFinalityBranch()))
+	assert.Equal(t, 6, len(verify.update.GetNextSyncCommitteeBranch()))
+
+	assert.Nil(t, VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), input))
+}
+
+func TestVerifyLightClientUpdateElectraWithDenebBranch(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	// an update attested before Electra carries Deneb state branches,
+	// shifting it into Electra must make the proofs fail
+	vector := loadForkTestVector(t, "deneb")
+	vector.update.AttestedHeader.Slot = config.ElectraForkEpoch * SlotsPerEpoch
+	vector.update.SignatureSlot = vector.update.AttestedHeader.Slot + 1
+
+	err = VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), vector.encodeV3(t))
+	assert.EqualError(t, err, "VerifyProof return err: Invalid proof length")
+}
+
+func TestVerifyLightClientUpdateDenebWithCapellaPayload(t *testing.T) {
+	vector := loadForkTestVector(t, "deneb")
+	update := vector.update.toLightClientUpdateV2()
+	update.finalizedExecution = &update.finalizedExecution.(*ExecutionPayloadDeneb).ExecutionPayload
+	state := ConvertToLightClientState(&vector.finalizedHeader, &vector.curSyncCommittee, &vector.nextSyncCommittee, vector.chainID)
+
+	err := verifyFinalityV2(state, update)
+	assert.EqualError(t, err, fmt.Sprintf("pre-deneb execution payload for deneb slot %d", vector.update.FinalizedHeader.Slot))
+
+	// a Capella encoded update for a Deneb slot is decoded with the Deneb layout and can't verify
+	assert.NotNil(t, VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), vector.encodeV2(t)))
+}
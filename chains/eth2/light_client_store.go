@@ -0,0 +1,344 @@
+package eth2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// MaxExecutionHeaderLimit is the number of finalized execution headers kept in the store,
+// older slots are overwritten in a ring buffer
+const MaxExecutionHeaderLimit = 100000
+
+var ErrNotInitialized = errors.New("please initialize eth2 light client store")
+
+// ExecutionHeaderInfo is the execution block data of a finalized beacon block that is kept
+// for verifying receipt proofs
+type ExecutionHeaderInfo struct {
+	Slot uint64
+	// BodyRoot of the finalized beacon block, the execution payload is proven against it
+	BodyRoot     common.Hash
+	BlockNumber  uint64
+	BlockHash    common.Hash
+	ReceiptsRoot common.Hash
+}
+
+// LightClientStore is the state of the native eth2 light client of a beacon network, it is
+// persisted in the state of chains.Eth2LightClientStoreAddress under the chain id of the network
+type LightClientStore struct {
+	ChainID              uint64
+	FinalizedHeader      *BeaconBlockHeader
+	CurrentSyncCommittee *SyncCommittee
+	NextSyncCommittee    *SyncCommittee
+	FinalizedExecution   *ExecutionHeaderInfo
+}
+
+// ReceiptProof proves a receipt against the receipts root of a finalized execution block
+type ReceiptProof struct {
+	Slot    uint64
+	Receipt *ethtypes.Receipt
+	Prove   light.NodeList
+	TxIndex uint
+}
+
+func storeDbKey(chainID uint64) common.Hash {
+	str := fmt.Sprintf("%s-%d", "eth2store", chainID)
+	return common.BytesToHash([]byte(str))
+}
+
+func executionDbKey(chainID, slot uint64) common.Hash {
+	str := fmt.Sprintf("%s-%d-%d", "eth2execution", chainID, slot%MaxExecutionHeaderLimit)
+	return common.BytesToHash([]byte(str))
+}
+
+// LoadLightClientStore reads the light client store of the chain id from the state
+func LoadLightClientStore(db types.StateDB, chainID uint64) (*LightClientStore, error) {
+	data := db.GetPOWState(chains.Eth2LightClientStoreAddress, storeDbKey(chainID))
+	if len(data) == 0 {
+		return nil, ErrNotInitialized
+	}
+
+	store := new(LightClientStore)
+	if err := rlp.DecodeBytes(data, store); err != nil {
+		log.Error("LightClientStore RLP decode failed", "err", err)
+		return nil, fmt.Errorf("LightClientStore RLP decode failed, error: %s", err.Error())
+	}
+	return store, nil
+}
+
+// Store writes the light client store to the state
+func (s *LightClientStore) Store(db types.StateDB) error {
+	data, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		log.Error("Failed to RLP encode LightClientStore", "err", err)
+		return err
+	}
+
+	db.SetPOWState(chains.Eth2LightClientStoreAddress, storeDbKey(s.ChainID), data)
+	return nil
+}
+
+func (s *LightClientStore) storeExecution(db types.StateDB, info *ExecutionHeaderInfo) error {
+	data, err := rlp.EncodeToBytes(info)
+	if err != nil {
+		log.Error("Failed to RLP encode ExecutionHeaderInfo", "err", err)
+		return err
+	}
+
+	db.SetPOWState(chains.Eth2LightClientStoreAddress, executionDbKey(s.ChainID, info.Slot), data)
+	return nil
+}
+
+// GetExecution returns the execution block data of the finalized beacon block at the slot
+func (s *LightClientStore) GetExecution(db types.StateDB, slot uint64) (*ExecutionHeaderInfo, error) {
+	data := db.GetPOWState(chains.Eth2LightClientStoreAddress, executionDbKey(s.ChainID, slot))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no finalized execution header at slot %d", slot)
+	}
+
+	info := new(ExecutionHeaderInfo)
+	if err := rlp.DecodeBytes(data, info); err != nil {
+		log.Error("ExecutionHeaderInfo RLP decode failed", "err", err)
+		return nil, fmt.Errorf("ExecutionHeaderInfo RLP decode failed, error: %s", err.Error())
+	}
+	// the ring buffer slot may have been overwritten by a newer header
+	if info.Slot != slot {
+		return nil, fmt.Errorf("no finalized execution header at slot %d", slot)
+	}
+	return info, nil
+}
+
+func (s *LightClientStore) state() *LightClientState {
+	return &LightClientState{
+		finalizedHeader:      s.FinalizedHeader,
+		currentSyncCommittee: s.CurrentSyncCommittee,
+		nextSyncCommittee:    s.NextSyncCommittee,
+		chainID:              s.ChainID,
+	}
+}
+
+// InitLightClientStore initializes (or resets) the light client store from an abi encoded
+// bootstrap of the trusted finalized beacon header, its sync committees and execution block
+func InitLightClientStore(db types.StateDB, chainConfig *params.ChainConfig, num *big.Int, input []byte) (*LightClientStore, error) {
+	store, err := decodeBootstrap(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNetwork(chainConfig, num, store.ChainID); err != nil {
+		return nil, fmt.Errorf("new network failed: %v", err)
+	}
+	if len(store.CurrentSyncCommittee.Pubkeys) != SyncCommitteeSize || len(store.NextSyncCommittee.Pubkeys) != SyncCommitteeSize {
+		return nil, fmt.Errorf("invalid sync committee size, exp: %d", SyncCommitteeSize)
+	}
+
+	if err := store.storeExecution(db, store.FinalizedExecution); err != nil {
+		return nil, err
+	}
+	if err := store.Store(db); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// ApplyLightClientUpdate verifies an abi encoded light client update against the stored
+// sync committees of the chain id and advances the finalized header of the store
+func ApplyLightClientUpdate(db types.StateDB, chainID uint64, input []byte) (*LightClientStore, error) {
+	store, err := LoadLightClientStore(db, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := decodeLightClientUpdate(input, store.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.validateUpdate(update); err != nil {
+		log.Warn("validateUpdate", "error", err)
+		return nil, err
+	}
+
+	state := store.state()
+	if err := verifyFinalityV2(state, update); err != nil {
+		log.Warn("verifyFinalityV2", "error", err)
+		return nil, err
+	}
+
+	if err := verifyNextSyncCommittee(state, update); err != nil {
+		log.Warn("verifyNextSyncCommittee", "error", err)
+		return nil, err
+	}
+
+	if err := verifyBlsSignatures(state, update); err != nil {
+		log.Warn("verifyBlsSignatures", "error", err)
+		return nil, err
+	}
+
+	if err := store.applyUpdate(db, update); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *LightClientStore) validateUpdate(update *LightClientUpdateV2) error {
+	config, err := newNetworkConfig(s.ChainID)
+	if err != nil {
+		return fmt.Errorf("new network failed: %v", err)
+	}
+
+	finalizedSlot := update.finalizedHeader.Slot
+	if finalizedSlot <= s.FinalizedHeader.Slot {
+		return fmt.Errorf("finalized slot %d is not newer than the stored slot %d", finalizedSlot, s.FinalizedHeader.Slot)
+	}
+	if update.attestedHeader.Slot < finalizedSlot || update.signatureSlot <= update.attestedHeader.Slot {
+		return fmt.Errorf("invalid update slots, finalized: %d, attested: %d, signature: %d",
+			finalizedSlot, update.attestedHeader.Slot, update.signatureSlot)
+	}
+
+	storePeriod := config.computeSyncCommitteePeriod(s.FinalizedHeader.Slot)
+	updatePeriod := config.computeSyncCommitteePeriod(finalizedSlot)
+	if updatePeriod != storePeriod && updatePeriod != storePeriod+1 {
+		return fmt.Errorf("update period should be %d or %d, but got %d", storePeriod, storePeriod+1, updatePeriod)
+	}
+
+	if updatePeriod == storePeriod+1 && len(update.nextSyncCommittee.Pubkeys) != SyncCommitteeSize {
+		return fmt.Errorf("invalid next sync committee size, exp: %d, got: %d", SyncCommitteeSize, len(update.nextSyncCommittee.Pubkeys))
+	}
+	return nil
+}
+
+func (s *LightClientStore) applyUpdate(db types.StateDB, update *LightClientUpdateV2) error {
+	config, err := newNetworkConfig(s.ChainID)
+	if err != nil {
+		return fmt.Errorf("new network failed: %v", err)
+	}
+
+	// the next sync committee of the update is only proven when it crosses a period boundary
+	storePeriod := config.computeSyncCommitteePeriod(s.FinalizedHeader.Slot)
+	updatePeriod := config.computeSyncCommitteePeriod(update.finalizedHeader.Slot)
+	if updatePeriod == storePeriod+1 {
+		s.CurrentSyncCommittee = s.NextSyncCommittee
+		s.NextSyncCommittee = update.nextSyncCommittee
+	}
+
+	execution, err := executionPayloadOf(update)
+	if err != nil {
+		return err
+	}
+	s.FinalizedHeader = update.finalizedHeader
+	s.FinalizedExecution = &ExecutionHeaderInfo{
+		Slot:         update.finalizedHeader.Slot,
+		BodyRoot:     common.BytesToHash(update.finalizedHeader.BodyRoot),
+		BlockNumber:  execution.BlockNumber.Uint64(),
+		BlockHash:    execution.BlockHash,
+		ReceiptsRoot: execution.ReceiptsRoot,
+	}
+
+	if err := s.storeExecution(db, s.FinalizedExecution); err != nil {
+		return err
+	}
+	return s.Store(db)
+}
+
+func executionPayloadOf(update *LightClientUpdateV2) (*ExecutionPayload, error) {
+	switch execution := update.finalizedExecution.(type) {
+	case *ExecutionPayloadDeneb:
+		return &execution.ExecutionPayload, nil
+	case *ExecutionPayload:
+		return execution, nil
+	default:
+		return nil, fmt.Errorf("invalid execution payload type")
+	}
+}
+
+// VerifyReceiptProof verifies an rlp encoded ReceiptProof against the stored receipts root
+// and returns the rlp encoded logs of the receipt
+func (s *LightClientStore) VerifyReceiptProof(db types.StateDB, proofBytes []byte) ([]byte, error) {
+	var proof ReceiptProof
+	if err := rlp.DecodeBytes(proofBytes, &proof); err != nil {
+		return nil, err
+	}
+	if proof.Receipt == nil {
+		return nil, errors.New("receipt cannot be empty")
+	}
+
+	execution, err := s.GetExecution(db, proof.Slot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyReceipt(execution.ReceiptsRoot, proof.Receipt, proof.Prove, proof.TxIndex); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(proof.Receipt.Logs)
+}
+
+func verifyReceipt(receiptsRoot common.Hash, receipt *ethtypes.Receipt, prove light.NodeList, txIndex uint) error {
+	var buf bytes.Buffer
+	rs := ethtypes.Receipts{receipt}
+	rs.EncodeIndex(0, &buf)
+	giveReceipt := buf.Bytes()
+
+	var key []byte
+	key = rlp.AppendUint64(key[:0], uint64(txIndex))
+
+	getReceipt, err := trie.VerifyProof(receiptsRoot, key, prove.NodeSet())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(giveReceipt, getReceipt) {
+		return errors.New("receipt mismatch")
+	}
+	return nil
+}
+
+func decodeBootstrap(input []byte) (*LightClientStore, error) {
+	args, err := genBootstrapAbiArgs()
+	if err != nil {
+		return nil, fmt.Errorf("gen abi args failed: %v", err)
+	}
+
+	ret, err := args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+
+	finalizedBeaconHeader := new(ILightNodeBeaconBlockHeader)
+	curSyncCommittee := new(ILightNodeSyncCommittee)
+	nextSyncCommittee := new(ILightNodeSyncCommittee)
+	chainId := new(uint64)
+	blockNumber := new(*big.Int)
+	blockHash := new([32]byte)
+	receiptsRoot := new([32]byte)
+	if err := args.Copy(&[]interface{}{finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, chainId,
+		blockNumber, blockHash, receiptsRoot}, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+
+	header := finalizedBeaconHeader.toBeaconBlockHeader()
+	return &LightClientStore{
+		ChainID:              *chainId,
+		FinalizedHeader:      header,
+		CurrentSyncCommittee: curSyncCommittee.toSyncCommittee(),
+		NextSyncCommittee:    nextSyncCommittee.toSyncCommittee(),
+		FinalizedExecution: &ExecutionHeaderInfo{
+			Slot:         header.Slot,
+			BodyRoot:     common.BytesToHash(header.BodyRoot),
+			BlockNumber:  (*blockNumber).Uint64(),
+			BlockHash:    *blockHash,
+			ReceiptsRoot: *receiptsRoot,
+		},
+	}, nil
+}
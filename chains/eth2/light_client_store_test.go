@@ -0,0 +1,222 @@
+package eth2
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	atlasstate "github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStateDB() *atlasstate.StateDB {
+	db, _ := atlasstate.New(common.Hash{}, atlasstate.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return db
+}
+
+func newTestReceipts() ethtypes.Receipts {
+	var receipts ethtypes.Receipts
+	for i := 0; i < 3; i++ {
+		receipts = append(receipts, &ethtypes.Receipt{
+			Type:              ethtypes.DynamicFeeTxType,
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Bloom:             ethtypes.Bloom{},
+			Logs: []*ethtypes.Log{{
+				Address: common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6"),
+				Topics:  []common.Hash{common.HexToHash("0x155e433be3576195943c515e1096620bc754e11b3a4b60fda7c4628caf373635")},
+				Data:    []byte{byte(i)},
+			}},
+		})
+	}
+	return receipts
+}
+
+func newTestReceiptsTrie(t *testing.T, receipts ethtypes.Receipts) *trie.Trie {
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	assert.Nil(t, err)
+	for i := range receipts {
+		key, err := rlp.EncodeToBytes(uint(i))
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		receipts.EncodeIndex(i, &buf)
+		tr.Update(key, buf.Bytes())
+	}
+	return tr
+}
+
+func encodeReceiptProof(t *testing.T, tr *trie.Trie, slot uint64, receipt *ethtypes.Receipt, txIndex uint) []byte {
+	key, err := rlp.EncodeToBytes(txIndex)
+	assert.Nil(t, err)
+	proof := light.NewNodeSet()
+	assert.Nil(t, tr.Prove(key, 0, proof))
+
+	input, err := rlp.EncodeToBytes(ReceiptProof{
+		Slot:    slot,
+		Receipt: receipt,
+		Prove:   proof.NodeList(),
+		TxIndex: txIndex,
+	})
+	assert.Nil(t, err)
+	return input
+}
+
+func (v *forkTestVector) encodeBootstrap(t *testing.T) []byte {
+	args, err := genBootstrapAbiArgs()
+	assert.Nil(t, err)
+	input, err := args.Pack(v.finalizedHeader, v.curSyncCommittee, v.nextSyncCommittee, v.chainID,
+		big.NewInt(19000000), common.HexToHash("0x0d"), common.HexToHash("0x0e"))
+	assert.Nil(t, err)
+	return input
+}
+
+func (v *forkTestVector) encodeUpdateV3(t *testing.T) []byte {
+	var arg abi.Argument
+	assert.Nil(t, arg.UnmarshalJSON([]byte(UpdateV3ABIJSON)))
+	input, err := abi.Arguments{arg}.Pack(v.update)
+	assert.Nil(t, err)
+	return input
+}
+
+func TestLightClientStoreNotInitialized(t *testing.T) {
+	db := newTestStateDB()
+	_, err := LoadLightClientStore(db, mainnetChainID)
+	assert.Equal(t, ErrNotInitialized, err)
+
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+	vector := makeForkTestVector(t, config.DenebForkEpoch+10)
+	_, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.Equal(t, ErrNotInitialized, err)
+}
+
+func TestLightClientStore(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	receipts := newTestReceipts()
+	tr := newTestReceiptsTrie(t, receipts)
+	vector := makeForkTestVectorWithReceiptsRoot(t, config.DenebForkEpoch+10, tr.Hash())
+	db := newTestStateDB()
+
+	// bootstrap
+	store, err := InitLightClientStore(db, params.TestChainConfig, big.NewInt(1), vector.encodeBootstrap(t))
+	assert.Nil(t, err)
+	loaded, err := LoadLightClientStore(db, mainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, store, loaded)
+	assert.Equal(t, vector.finalizedHeader.Slot, loaded.FinalizedHeader.Slot)
+	assert.Equal(t, uint64(19000000), loaded.FinalizedExecution.BlockNumber)
+	assert.Equal(t, SyncCommitteeSize, len(loaded.CurrentSyncCommittee.Pubkeys))
+
+	execution, err := loaded.GetExecution(db, vector.finalizedHeader.Slot)
+	assert.Nil(t, err)
+	assert.Equal(t, common.HexToHash("0x0d"), execution.BlockHash)
+
+	// update into the next sync committee period
+	store, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.Nil(t, err)
+	loaded, err = LoadLightClientStore(db, mainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, store, loaded)
+	assert.Equal(t, vector.update.FinalizedHeader.Slot, loaded.FinalizedHeader.Slot)
+	assert.Equal(t, vector.nextSyncCommittee.toSyncCommittee(), loaded.CurrentSyncCommittee)
+	assert.Equal(t, vector.update.NextSyncCommittee.toSyncCommittee(), loaded.NextSyncCommittee)
+	assert.Equal(t, uint64(19426587), loaded.FinalizedExecution.BlockNumber)
+
+	execution, err = loaded.GetExecution(db, vector.update.FinalizedHeader.Slot)
+	assert.Nil(t, err)
+	assert.Equal(t, common.HexToHash("0x06"), execution.BlockHash)
+	assert.Equal(t, tr.Hash(), execution.ReceiptsRoot)
+
+	_, err = loaded.GetExecution(db, vector.update.FinalizedHeader.Slot+1)
+	assert.EqualError(t, err, fmt.Sprintf("no finalized execution header at slot %d", vector.update.FinalizedHeader.Slot+1))
+
+	// the same update can't be applied twice
+	_, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.EqualError(t, err, fmt.Sprintf("finalized slot %d is not newer than the stored slot %d",
+		vector.update.FinalizedHeader.Slot, vector.update.FinalizedHeader.Slot))
+
+	// receipt proofs
+	slot := vector.update.FinalizedHeader.Slot
+	logs, err := loaded.VerifyReceiptProof(db, encodeReceiptProof(t, tr, slot, receipts[1], 1))
+	assert.Nil(t, err)
+	expLogs, err := rlp.EncodeToBytes(receipts[1].Logs)
+	assert.Nil(t, err)
+	assert.Equal(t, expLogs, logs)
+
+	_, err = loaded.VerifyReceiptProof(db, encodeReceiptProof(t, tr, slot, receipts[2], 1))
+	assert.EqualError(t, err, "receipt mismatch")
+
+	_, err = loaded.VerifyReceiptProof(db, encodeReceiptProof(t, tr, vector.finalizedHeader.Slot, receipts[1], 1))
+	assert.NotNil(t, err)
+}
+
+func TestLightClientStoreRejectsUnsignedUpdate(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	vector := makeForkTestVector(t, config.DenebForkEpoch+10)
+	db := newTestStateDB()
+	_, err = InitLightClientStore(db, params.TestChainConfig, big.NewInt(1), vector.encodeBootstrap(t))
+	assert.Nil(t, err)
+
+	// signed by a committee the store doesn't know
+	other := makeForkTestVector(t, config.DenebForkEpoch+10)
+	vector.update.SyncAggregate = other.update.SyncAggregate
+	_, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.EqualError(t, err, "fast aggregate verify failed")
+
+	store, err := LoadLightClientStore(db, mainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, vector.finalizedHeader.Slot, store.FinalizedHeader.Slot)
+}
+
+func TestLightClientStorePerChain(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	vector := makeForkTestVector(t, config.DenebForkEpoch+10)
+	db := newTestStateDB()
+	_, err = InitLightClientStore(db, params.TestChainConfig, big.NewInt(1), vector.encodeBootstrap(t))
+	assert.Nil(t, err)
+
+	// a goerli bootstrap doesn't replace the mainnet store
+	goerli := *vector
+	goerli.chainID = 5
+	goerli.finalizedHeader.Slot -= slotsPerSyncPeriod
+	_, err = InitLightClientStore(db, params.TestChainConfig, big.NewInt(1), goerli.encodeBootstrap(t))
+	assert.Nil(t, err)
+
+	_, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.Nil(t, err)
+
+	mainnet, err := LoadLightClientStore(db, mainnetChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(mainnetChainID), mainnet.ChainID)
+	assert.Equal(t, vector.update.FinalizedHeader.Slot, mainnet.FinalizedHeader.Slot)
+
+	store, err := LoadLightClientStore(db, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), store.ChainID)
+	assert.Equal(t, goerli.finalizedHeader.Slot, store.FinalizedHeader.Slot)
+
+	// the execution headers are kept per chain as well
+	_, err = store.GetExecution(db, vector.finalizedHeader.Slot)
+	assert.NotNil(t, err)
+	_, err = mainnet.GetExecution(db, vector.finalizedHeader.Slot)
+	assert.Nil(t, err)
+
+	_, err = LoadLightClientStore(db, 11155111)
+	assert.Equal(t, ErrNotInitialized, err)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/atlas/params"
 	"github.com/stretchr/testify/assert"
 	"math/big"
 	"testing"
@@ -1683,6 +1684,6 @@ func TestVerifyLightClientUpdateV1(t *testing.T) {
 	data, err := hexutil.Decode(INPUT)
 	assert.Nil(t, err)
 
-	err = VerifyLightClientUpdate(data)
+	err = VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), data)
 	assert.Nil(t, err)
 }
@@ -2,6 +2,7 @@ package eth2
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	fssz "github.com/prysmaticlabs/fastssz"
 	"math/big"
 )
 
@@ -14,9 +15,11 @@ type LightClientUpdateV2 struct {
 	nextSyncCommittee       *SyncCommittee
 	nextSyncCommitteeBranch [][]byte
 	// The finalized beacon block header attested to by Merkle branch
-	finalizedHeader    *BeaconBlockHeader
-	finalityBranch     [][]byte
-	finalizedExecution *ExecutionPayload
+	finalizedHeader *BeaconBlockHeader
+	finalityBranch  [][]byte
+	// Execution payload header of `finalized_header`, *ExecutionPayload before Deneb
+	// and *ExecutionPayloadDeneb since
+	finalizedExecution fssz.HashRoot
 	executionBranch    [][]byte
 	// Sync committee aggregate signature
 	syncAggregate *SyncAggregate
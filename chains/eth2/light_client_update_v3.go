@@ -0,0 +1,91 @@
+package eth2
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// ILightNodeLightClientUpdateV3 is an auto generated low-level Go binding around an user-defined struct.
+type ILightNodeLightClientUpdateV3 struct {
+	AttestedHeader          ILightNodeBeaconBlockHeader
+	NextSyncCommittee       ILightNodeSyncCommittee
+	NextSyncCommitteeBranch [][32]byte
+	FinalizedHeader         ILightNodeBeaconBlockHeader
+	FinalityBranch          [][32]byte
+	FinalizedExecution      ILightNodeExecutionV3
+	ExecutionBranch         [][32]byte
+	SyncAggregate           ILightNodeSyncAggregate
+	SignatureSlot           uint64
+}
+
+// ILightNodeExecutionV3 is an auto generated low-level Go binding around an user-defined struct.
+type ILightNodeExecutionV3 struct {
+	ParentHash       [32]byte
+	FeeRecipient     common.Address
+	StateRoot        [32]byte
+	ReceiptsRoot     [32]byte
+	LogsBloom        []byte
+	PrevRandao       [32]byte
+	BlockNumber      *big.Int
+	GasLimit         *big.Int
+	GasUsed          *big.Int
+	Timestamp        *big.Int
+	ExtraData        []byte
+	BaseFeePerGas    *big.Int
+	BlockHash        [32]byte
+	TransactionsRoot [32]byte
+	WithdrawalsRoot  [32]byte
+	BlobGasUsed      *big.Int
+	ExcessBlobGas    *big.Int
+}
+
+func ConvertToLightClientVerifyV3(update *ILightNodeLightClientUpdateV3,
+	finalizedBeaconHeader *ILightNodeBeaconBlockHeader,
+	curSyncCommittee *ILightNodeSyncCommittee,
+	nextSyncCommittee *ILightNodeSyncCommittee,
+	chainId uint64) *LightClientVerify {
+	return &LightClientVerify{
+		update: update.toLightClientUpdateV2(),
+		state:  ConvertToLightClientState(finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, chainId),
+	}
+}
+
+// toLightClientUpdateV2 converts the Deneb update binding, the verification logic is shared
+// with the Capella update and only the execution payload header container differs.
+func (update *ILightNodeLightClientUpdateV3) toLightClientUpdateV2() *LightClientUpdateV2 {
+	return &LightClientUpdateV2{
+		attestedHeader:          update.AttestedHeader.toBeaconBlockHeader(),
+		nextSyncCommittee:       update.NextSyncCommittee.toSyncCommittee(),
+		nextSyncCommitteeBranch: bytes32ArrayToBytesArray(update.NextSyncCommitteeBranch),
+		finalizedHeader:         update.FinalizedHeader.toBeaconBlockHeader(),
+		finalityBranch:          bytes32ArrayToBytesArray(update.FinalityBranch),
+		finalizedExecution:      update.FinalizedExecution.toExecutionPayloadDeneb(),
+		executionBranch:         bytes32ArrayToBytesArray(update.ExecutionBranch),
+		syncAggregate:           update.SyncAggregate.toSyncAggregate(),
+		signatureSlot:           update.SignatureSlot,
+	}
+}
+
+func (execution *ILightNodeExecutionV3) toExecutionPayloadDeneb() *ExecutionPayloadDeneb {
+	return &ExecutionPayloadDeneb{
+		ExecutionPayload: ExecutionPayload{
+			ParentHash:       common.BytesToHash(execution.ParentHash[:]),
+			FeeRecipient:     common.BytesToAddress(execution.FeeRecipient[:]),
+			StateRoot:        common.BytesToHash(execution.StateRoot[:]),
+			ReceiptsRoot:     common.BytesToHash(execution.ReceiptsRoot[:]),
+			LogsBloom:        execution.LogsBloom,
+			PrevRandao:       common.BytesToHash(execution.PrevRandao[:]),
+			BlockNumber:      execution.BlockNumber,
+			GasLimit:         execution.GasLimit.Uint64(),
+			GasUsed:          execution.GasUsed.Uint64(),
+			Timestamp:        execution.Timestamp.Uint64(),
+			ExtraData:        execution.ExtraData,
+			BaseFeePerGas:    execution.BaseFeePerGas,
+			BlockHash:        common.BytesToHash(execution.BlockHash[:]),
+			TransactionsRoot: common.BytesToHash(execution.TransactionsRoot[:]),
+			WithdrawalsRoot:  common.BytesToHash(execution.WithdrawalsRoot[:]),
+		},
+		BlobGasUsed:   execution.BlobGasUsed.Uint64(),
+		ExcessBlobGas: execution.ExcessBlobGas.Uint64(),
+	}
+}
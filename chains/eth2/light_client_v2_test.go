@@ -3,6 +3,7 @@ package eth2
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mapprotocol/atlas/params"
 	"github.com/stretchr/testify/assert"
 	"math/big"
 	"testing"
@@ -1634,7 +1635,7 @@ var state = LightClientState{
 }
 
 func TestVerifyFinality(t *testing.T) {
-	err := verifyFinalityV2(&update)
+	err := verifyFinalityV2(&state, &update)
 	assert.Nil(t, err)
 }
 
@@ -1681,6 +1682,6 @@ func TestVerifyLightClientUpdate(t *testing.T) {
 	data, err := hexutil.Decode(INPUT)
 	assert.Nil(t, err)
 
-	err = VerifyLightClientUpdate(data)
+	err = VerifyLightClientUpdate(params.TestChainConfig, big.NewInt(1), data)
 	assert.Nil(t, err)
 }
@@ -1,48 +1,237 @@
 package eth2
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// farFutureEpoch marks a fork that is not scheduled on a network.
+const farFutureEpoch = math.MaxUint64
 
 type NetworkConfig struct {
-	GenesisValidatorsRoot [32]byte
-	BellatrixForkVersion  ForkVersion
-	BellatrixForkEpoch    uint64
-	CapellaForkVersion    ForkVersion
-	CapellaForkEpoch      uint64
+	Name                         string
+	GenesisValidatorsRoot        [32]byte
+	SlotsPerEpoch                uint64
+	EpochsPerSyncCommitteePeriod uint64
+	BellatrixForkVersion         ForkVersion
+	BellatrixForkEpoch           uint64
+	CapellaForkVersion           ForkVersion
+	CapellaForkEpoch             uint64
+	DenebForkVersion             ForkVersion
+	DenebForkEpoch               uint64
+	ElectraForkVersion           ForkVersion
+	ElectraForkEpoch             uint64
 }
 
-func newNetworkConfig(chainID uint64) (*NetworkConfig, error) {
-	switch chainID {
-	case 1: // Mainnet
-		return &NetworkConfig{
+var (
+	networksLock sync.RWMutex
+	// networks is the registry of beacon networks keyed by execution layer chain id
+	networks = map[uint64]*NetworkConfig{
+		1: { // Mainnet
+			Name: "mainnet",
 			GenesisValidatorsRoot: [32]byte{
 				0x4b, 0x36, 0x3d, 0xb9, 0x4e, 0x28, 0x61, 0x20, 0xd7, 0x6e, 0xb9, 0x05, 0x34,
 				0x0f, 0xdd, 0x4e, 0x54, 0xbf, 0xe9, 0xf0, 0x6b, 0xf3, 0x3f, 0xf6, 0xcf, 0x5a,
 				0xd2, 0x7f, 0x51, 0x1b, 0xfe, 0x95,
 			},
-			BellatrixForkVersion: [4]byte{0x02, 0x00, 0x00, 0x00},
-			BellatrixForkEpoch:   144896,
-			CapellaForkVersion:   [4]byte{0x03, 0x00, 0x00, 0x00},
-			CapellaForkEpoch:     194048,
-		}, nil
-	case 5: // Goerli
-		return &NetworkConfig{
+			SlotsPerEpoch:                SlotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: EpochsPerSyncCommitteePeriod,
+			BellatrixForkVersion:         [4]byte{0x02, 0x00, 0x00, 0x00},
+			BellatrixForkEpoch:           144896,
+			CapellaForkVersion:           [4]byte{0x03, 0x00, 0x00, 0x00},
+			CapellaForkEpoch:             194048,
+			DenebForkVersion:             [4]byte{0x04, 0x00, 0x00, 0x00},
+			DenebForkEpoch:               269568,
+			ElectraForkVersion:           [4]byte{0x05, 0x00, 0x00, 0x00},
+			ElectraForkEpoch:             364032,
+		},
+		5: { // Goerli
+			Name: "goerli",
 			GenesisValidatorsRoot: [32]byte{
 				0x04, 0x3d, 0xb0, 0xd9, 0xa8, 0x38, 0x13, 0x55, 0x1e, 0xe2, 0xf3, 0x34, 0x50,
 				0xd2, 0x37, 0x97, 0x75, 0x7d, 0x43, 0x09, 0x11, 0xa9, 0x32, 0x05, 0x30, 0xad,
 				0x8a, 0x0e, 0xab, 0xc4, 0x3e, 0xfb,
 			},
-			BellatrixForkVersion: [4]byte{0x02, 0x00, 0x10, 0x20},
-			BellatrixForkEpoch:   112260,
-			CapellaForkVersion:   [4]byte{0x03, 0x00, 0x10, 0x20},
-			CapellaForkEpoch:     162304,
-		}, nil
-	default:
+			SlotsPerEpoch:                SlotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: EpochsPerSyncCommitteePeriod,
+			BellatrixForkVersion:         [4]byte{0x02, 0x00, 0x10, 0x20},
+			BellatrixForkEpoch:           112260,
+			CapellaForkVersion:           [4]byte{0x03, 0x00, 0x10, 0x20},
+			CapellaForkEpoch:             162304,
+			DenebForkVersion:             [4]byte{0x04, 0x00, 0x10, 0x20},
+			DenebForkEpoch:               231680,
+			// Goerli was deprecated before Electra and never scheduled it
+			ElectraForkVersion: [4]byte{0x05, 0x00, 0x10, 0x20},
+			ElectraForkEpoch:   farFutureEpoch,
+		},
+		11155111: { // Sepolia
+			Name: "sepolia",
+			GenesisValidatorsRoot: [32]byte{
+				0xd8, 0xea, 0x17, 0x1f, 0x3c, 0x94, 0xae, 0xa2, 0x1e, 0xbc, 0x42, 0xa1, 0xed,
+				0x61, 0x05, 0x2a, 0xcf, 0x3f, 0x92, 0x09, 0xc0, 0x0e, 0x4e, 0xfb, 0xaa, 0xdd,
+				0xac, 0x09, 0xed, 0x9b, 0x80, 0x78,
+			},
+			SlotsPerEpoch:                SlotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: EpochsPerSyncCommitteePeriod,
+			BellatrixForkVersion:         [4]byte{0x90, 0x00, 0x00, 0x71},
+			BellatrixForkEpoch:           100,
+			CapellaForkVersion:           [4]byte{0x90, 0x00, 0x00, 0x72},
+			CapellaForkEpoch:             56832,
+			DenebForkVersion:             [4]byte{0x90, 0x00, 0x00, 0x73},
+			DenebForkEpoch:               132608,
+			ElectraForkVersion:           [4]byte{0x90, 0x00, 0x00, 0x74},
+			ElectraForkEpoch:             222464,
+		},
+		17000: { // Holesky
+			Name: "holesky",
+			GenesisValidatorsRoot: [32]byte{
+				0x91, 0x43, 0xaa, 0x7c, 0x61, 0x5a, 0x7f, 0x71, 0x15, 0xe2, 0xb6, 0xaa, 0xc3,
+				0x19, 0xc0, 0x35, 0x29, 0xdf, 0x82, 0x42, 0xae, 0x70, 0x5f, 0xba, 0x9d, 0xf3,
+				0x9b, 0x79, 0xc5, 0x9f, 0xa8, 0xb1,
+			},
+			SlotsPerEpoch:                SlotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: EpochsPerSyncCommitteePeriod,
+			BellatrixForkVersion:         [4]byte{0x03, 0x01, 0x70, 0x00},
+			BellatrixForkEpoch:           0,
+			CapellaForkVersion:           [4]byte{0x04, 0x01, 0x70, 0x00},
+			CapellaForkEpoch:             256,
+			DenebForkVersion:             [4]byte{0x05, 0x01, 0x70, 0x00},
+			DenebForkEpoch:               29696,
+			ElectraForkVersion:           [4]byte{0x06, 0x01, 0x70, 0x00},
+			ElectraForkEpoch:             115968,
+		},
+	}
+	// testnetNetworks are the built-in networks that are only available to the
+	// precompiles from the eth2 testnets fork block, unless the chain config declares them
+	testnetNetworks = map[uint64]struct{}{11155111: {}, 17000: {}}
+)
+
+func newNetworkConfig(chainID uint64) (*NetworkConfig, error) {
+	networksLock.RLock()
+	defer networksLock.RUnlock()
+
+	config, ok := networks[chainID]
+	if !ok {
 		return nil, fmt.Errorf("unsupported network chain ID %d", chainID)
 	}
+	return config, nil
+}
+
+// checkNetwork returns an error if the precompiles can't use the beacon network of
+// the chain id at the given block.
+func checkNetwork(chainConfig *params.ChainConfig, num *big.Int, chainID uint64) error {
+	if _, err := newNetworkConfig(chainID); err != nil {
+		return err
+	}
+
+	networksLock.RLock()
+	_, testnet := testnetNetworks[chainID]
+	networksLock.RUnlock()
+
+	if testnet && !chainConfig.IsEth2Testnets(num) {
+		return fmt.Errorf("unsupported network chain ID %d", chainID)
+	}
+	return nil
+}
+
+// RegisterNetwork adds a beacon network to the registry, replacing any network
+// registered under the same chain id.
+func RegisterNetwork(network *params.BeaconNetworkConfig) error {
+	config, err := toNetworkConfig(network)
+	if err != nil {
+		return fmt.Errorf("invalid beacon network %q (chain ID %d): %v", network.Name, network.ChainID, err)
+	}
+
+	networksLock.Lock()
+	defer networksLock.Unlock()
+
+	if old, ok := networks[network.ChainID]; ok {
+		log.Warn("Overriding eth2 beacon network", "chainID", network.ChainID, "old", old.Name, "new", config.Name)
+	}
+	networks[network.ChainID] = config
+	delete(testnetNetworks, network.ChainID)
+	return nil
+}
+
+// RegisterChainConfigNetworks registers the beacon networks declared in the chain config.
+func RegisterChainConfigNetworks(chainConfig *params.ChainConfig) error {
+	for _, network := range chainConfig.Eth2Networks {
+		if err := RegisterNetwork(network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toNetworkConfig(network *params.BeaconNetworkConfig) (*NetworkConfig, error) {
+	if network.GenesisValidatorsRoot == (common.Hash{}) {
+		return nil, fmt.Errorf("missing genesis validators root")
+	}
+
+	config := &NetworkConfig{
+		Name:                         network.Name,
+		GenesisValidatorsRoot:        network.GenesisValidatorsRoot,
+		SlotsPerEpoch:                network.SlotsPerEpoch,
+		EpochsPerSyncCommitteePeriod: network.EpochsPerSyncCommitteePeriod,
+	}
+	if config.SlotsPerEpoch == 0 {
+		config.SlotsPerEpoch = SlotsPerEpoch
+	}
+	if config.EpochsPerSyncCommitteePeriod == 0 {
+		config.EpochsPerSyncCommitteePeriod = EpochsPerSyncCommitteePeriod
+	}
+
+	forks := []struct {
+		name        string
+		version     []byte
+		epoch       *uint64
+		forkVersion *ForkVersion
+		forkEpoch   *uint64
+	}{
+		{"bellatrix", network.BellatrixForkVersion, network.BellatrixForkEpoch, &config.BellatrixForkVersion, &config.BellatrixForkEpoch},
+		{"capella", network.CapellaForkVersion, network.CapellaForkEpoch, &config.CapellaForkVersion, &config.CapellaForkEpoch},
+		{"deneb", network.DenebForkVersion, network.DenebForkEpoch, &config.DenebForkVersion, &config.DenebForkEpoch},
+		{"electra", network.ElectraForkVersion, network.ElectraForkEpoch, &config.ElectraForkVersion, &config.ElectraForkEpoch},
+	}
+	last := uint64(0)
+	for _, fork := range forks {
+		*fork.forkEpoch = farFutureEpoch
+		if fork.epoch == nil {
+			continue
+		}
+		if len(fork.version) != ForkVersionByteLength {
+			return nil, fmt.Errorf("invalid %s fork version length %d", fork.name, len(fork.version))
+		}
+		if *fork.epoch < last {
+			return nil, fmt.Errorf("%s fork epoch %d is before the previous fork epoch %d", fork.name, *fork.epoch, last)
+		}
+		copy(fork.forkVersion[:], fork.version)
+		*fork.forkEpoch = *fork.epoch
+		last = *fork.epoch
+	}
+	if config.BellatrixForkEpoch == farFutureEpoch {
+		return nil, fmt.Errorf("missing bellatrix fork epoch")
+	}
+
+	return config, nil
 }
 
 // Return the fork version at the given epoch
 func (nc *NetworkConfig) computeForkVersion(epoch uint64) *ForkVersion {
+	if epoch >= nc.ElectraForkEpoch {
+		return &nc.ElectraForkVersion
+	}
+
+	if epoch >= nc.DenebForkEpoch {
+		return &nc.DenebForkVersion
+	}
+
 	if epoch >= nc.CapellaForkEpoch {
 		return &nc.CapellaForkVersion
 	}
@@ -56,5 +245,43 @@ func (nc *NetworkConfig) computeForkVersion(epoch uint64) *ForkVersion {
 
 // Return the fork version at the given epoch
 func (nc *NetworkConfig) computeForkVersionBySlot(slot uint64) *ForkVersion {
-	return nc.computeForkVersion(computeEpochAtSlot(slot))
+	return nc.computeForkVersion(nc.computeEpochAtSlot(slot))
+}
+
+func (nc *NetworkConfig) computeEpochAtSlot(slot uint64) uint64 {
+	return slot / nc.SlotsPerEpoch
+}
+
+func (nc *NetworkConfig) computeSyncCommitteePeriod(slot uint64) uint64 {
+	return nc.computeEpochAtSlot(slot) / nc.EpochsPerSyncCommitteePeriod
+}
+
+// isDeneb reports whether the given slot is at or after the Deneb fork, where the
+// execution payload header gained the blob gas fields.
+func (nc *NetworkConfig) isDeneb(slot uint64) bool {
+	return nc.computeEpochAtSlot(slot) >= nc.DenebForkEpoch
+}
+
+// isElectra reports whether the given slot is at or after the Electra fork, where the
+// beacon state grew past 32 fields and its generalized indices changed.
+func (nc *NetworkConfig) isElectra(slot uint64) bool {
+	return nc.computeEpochAtSlot(slot) >= nc.ElectraForkEpoch
+}
+
+// finalizedRootIndex returns the generalized index of the finalized checkpoint root
+// in the beacon state at the given slot.
+func (nc *NetworkConfig) finalizedRootIndex(slot uint64) uint32 {
+	if nc.isElectra(slot) {
+		return FinalizedRootIndexElectra
+	}
+	return FinalizedRootIndex
+}
+
+// nextSyncCommitteeIndex returns the generalized index of the next sync committee
+// in the beacon state at the given slot.
+func (nc *NetworkConfig) nextSyncCommitteeIndex(slot uint64) uint32 {
+	if nc.isElectra(slot) {
+		return NextSyncCommitteeIndexElectra
+	}
+	return NextSyncCommitteeIndex
 }
@@ -0,0 +1,132 @@
+package eth2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestBuiltinNetworks(t *testing.T) {
+	for _, chainID := range []uint64{1, 5, 11155111, 17000} {
+		config, err := newNetworkConfig(chainID)
+		assert.Nil(t, err)
+		assert.NotEqual(t, [32]byte{}, config.GenesisValidatorsRoot)
+		assert.Equal(t, SlotsPerEpoch, config.SlotsPerEpoch)
+		assert.NotNil(t, config.computeForkVersion(config.DenebForkEpoch))
+	}
+
+	_, err := newNetworkConfig(12345)
+	assert.EqualError(t, err, "unsupported network chain ID 12345")
+}
+
+func TestRegisterChainConfigNetworks(t *testing.T) {
+	chainConfig := &params.ChainConfig{
+		ChainID: big.NewInt(22776),
+		Eth2Networks: []*params.BeaconNetworkConfig{{
+			Name:                  "chain-config-devnet",
+			ChainID:               3238203,
+			GenesisValidatorsRoot: common.HexToHash("0x01"),
+			BellatrixForkVersion:  common.FromHex("0x40000091"),
+			BellatrixForkEpoch:    uint64Ptr(0),
+		}},
+	}
+	assert.Nil(t, RegisterChainConfigNetworks(chainConfig))
+
+	config, err := newNetworkConfig(3238203)
+	assert.Nil(t, err)
+	assert.Equal(t, ForkVersion{0x40, 0x00, 0x00, 0x91}, *config.computeForkVersion(1000))
+}
+
+func TestCheckNetwork(t *testing.T) {
+	chainConfig := &params.ChainConfig{
+		ChainID:           big.NewInt(22776),
+		Eth2TestnetsBlock: big.NewInt(100),
+		Eth2Networks: []*params.BeaconNetworkConfig{{
+			Name:                  "holesky",
+			ChainID:               17000,
+			GenesisValidatorsRoot: common.HexToHash("0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1"),
+			BellatrixForkVersion:  common.FromHex("0x03017000"),
+			BellatrixForkEpoch:    uint64Ptr(0),
+		}},
+	}
+	holesky, err := newNetworkConfig(17000)
+	assert.Nil(t, err)
+	defer func() {
+		networks[17000] = holesky
+		testnetNetworks[17000] = struct{}{}
+	}()
+
+	assert.Nil(t, checkNetwork(chainConfig, big.NewInt(0), 1))
+	assert.Nil(t, checkNetwork(chainConfig, big.NewInt(0), 5))
+	assert.EqualError(t, checkNetwork(chainConfig, big.NewInt(0), 12345), "unsupported network chain ID 12345")
+
+	// the built-in testnets are only available from the fork block
+	assert.EqualError(t, checkNetwork(chainConfig, big.NewInt(99), 11155111), "unsupported network chain ID 11155111")
+	assert.EqualError(t, checkNetwork(chainConfig, big.NewInt(99), 17000), "unsupported network chain ID 17000")
+	assert.Nil(t, checkNetwork(chainConfig, big.NewInt(100), 11155111))
+	assert.Nil(t, checkNetwork(chainConfig, big.NewInt(100), 17000))
+
+	// unless the chain config declares them
+	assert.Nil(t, RegisterChainConfigNetworks(chainConfig))
+	assert.Nil(t, checkNetwork(chainConfig, big.NewInt(0), 17000))
+	assert.EqualError(t, checkNetwork(chainConfig, big.NewInt(0), 11155111), "unsupported network chain ID 11155111")
+}
+
+func TestRegisterNetworkInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		network *params.BeaconNetworkConfig
+		err     string
+	}{
+		{
+			name:    "missing root",
+			network: &params.BeaconNetworkConfig{ChainID: 3238204},
+			err:     `invalid beacon network "" (chain ID 3238204): missing genesis validators root`,
+		},
+		{
+			name: "missing bellatrix",
+			network: &params.BeaconNetworkConfig{
+				ChainID:               3238204,
+				GenesisValidatorsRoot: common.HexToHash("0x01"),
+			},
+			err: `invalid beacon network "" (chain ID 3238204): missing bellatrix fork epoch`,
+		},
+		{
+			name: "bad version",
+			network: &params.BeaconNetworkConfig{
+				ChainID:               3238204,
+				GenesisValidatorsRoot: common.HexToHash("0x01"),
+				BellatrixForkVersion:  common.FromHex("0x0102"),
+				BellatrixForkEpoch:    uint64Ptr(0),
+			},
+			err: `invalid beacon network "" (chain ID 3238204): invalid bellatrix fork version length 2`,
+		},
+		{
+			name: "unordered forks",
+			network: &params.BeaconNetworkConfig{
+				ChainID:               3238204,
+				GenesisValidatorsRoot: common.HexToHash("0x01"),
+				BellatrixForkVersion:  common.FromHex("0x01000000"),
+				BellatrixForkEpoch:    uint64Ptr(10),
+				CapellaForkVersion:    common.FromHex("0x02000000"),
+				CapellaForkEpoch:      uint64Ptr(5),
+			},
+			err: `invalid beacon network "" (chain ID 3238204): capella fork epoch 5 is before the previous fork epoch 10`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualError(t, RegisterNetwork(test.network), test.err)
+		})
+	}
+
+	_, err := newNetworkConfig(3238204)
+	assert.NotNil(t, err)
+}
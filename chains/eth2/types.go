@@ -130,6 +130,25 @@ func (e *ExecutionPayload) HashTreeRoot() ([32]byte, error) {
 func (e *ExecutionPayload) HashTreeRootWith(hh *fssz.Hasher) (err error) {
 	indx := hh.Index()
 
+	if err = e.putFields(hh); err != nil {
+		return
+	}
+
+	// Field (14) 'WithdrawalsRoot'
+	if !bytes.Equal(e.WithdrawalsRoot[:], make([]byte, 32)) {
+		hh.PutBytes(e.WithdrawalsRoot.Bytes())
+	}
+
+	if fssz.EnableVectorizedHTR {
+		hh.MerkleizeVectorizedHTR(indx)
+	} else {
+		hh.Merkleize(indx)
+	}
+	return
+}
+
+// putFields appends the Bellatrix execution payload header fields (0 - 13) to the hasher
+func (e *ExecutionPayload) putFields(hh *fssz.Hasher) (err error) {
 	// Field (0) 'ParentHash'
 	hh.PutBytes(e.ParentHash.Bytes())
 
@@ -189,11 +208,39 @@ func (e *ExecutionPayload) HashTreeRootWith(hh *fssz.Hasher) (err error) {
 	// Field (13) 'Transactions'
 	hh.PutBytes(e.TransactionsRoot.Bytes())
 
-	// Field (14) 'WithdrawalsRoot'
-	if !bytes.Equal(e.WithdrawalsRoot[:], make([]byte, 32)) {
-		hh.PutBytes(e.WithdrawalsRoot.Bytes())
+	return
+}
+
+// ExecutionPayloadDeneb is the execution payload header used since Deneb, it appends the
+// blob gas fields to the Capella layout. Electra keeps the same header layout.
+type ExecutionPayloadDeneb struct {
+	ExecutionPayload
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
+}
+
+// HashTreeRoot ssz hashes the ExecutionPayloadDeneb object
+func (e *ExecutionPayloadDeneb) HashTreeRoot() ([32]byte, error) {
+	return fssz.HashWithDefaultHasher(e)
+}
+
+// HashTreeRootWith ssz hashes the ExecutionPayloadDeneb object with a hasher
+func (e *ExecutionPayloadDeneb) HashTreeRootWith(hh *fssz.Hasher) (err error) {
+	indx := hh.Index()
+
+	if err = e.putFields(hh); err != nil {
+		return
 	}
 
+	// Field (14) 'WithdrawalsRoot'
+	hh.PutBytes(e.WithdrawalsRoot.Bytes())
+
+	// Field (15) 'BlobGasUsed'
+	hh.PutUint64(e.BlobGasUsed)
+
+	// Field (16) 'ExcessBlobGas'
+	hh.PutUint64(e.ExcessBlobGas)
+
 	if fssz.EnableVectorizedHTR {
 		hh.MerkleizeVectorizedHTR(indx)
 	} else {
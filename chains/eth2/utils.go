@@ -16,9 +16,12 @@ import (
 const ABIJSON = "{\"components\":[{\"components\":[{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"structILightNode.BeaconBlockHeader\",\"name\":\"attestedHeader\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"structILightNode.SyncCommittee\",\"name\":\"nextSyncCommittee\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"nextSyncCommitteeBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"structILightNode.BeaconBlockHeader\",\"name\":\"finalizedHeader\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"finalityBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"parentHash\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"sha3Uncles\",\"type\":\"bytes\"},{\"internalType\":\"address\",\"name\":\"miner\",\"type\":\"address\"},{\"internalType\":\"bytes\",\"name\":\"stateRoot\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"transactionsRoot\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"receiptsRoot\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"logsBloom\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"difficulty\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"number\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasLimit\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasUsed\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"extraData\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"mixHash\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"nonce\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"baseFeePerGas\",\"type\":\"uint256\"}],\"internalType\":\"structILightNode.BlockHeader\",\"name\":\"finalizedExeHeader\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"exeFinalityBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"syncCommitteeBits\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"syncCommitteeSignature\",\"type\":\"bytes\"}],\"internalType\":\"structILightNode.SyncAggregate\",\"name\":\"syncAggregate\",\"type\":\"tuple\"},{\"internalType\":\"uint64\",\"name\":\"signatureSlot\",\"type\":\"uint64\"}],\"internalType\":\"structILightNode.LightClientUpdate\",\"name\":\"update\",\"type\":\"tuple\"},{\"components\":[{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"structILightNode.BeaconBlockHeader\",\"name\":\"finalizedHeader\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"structILightNode.SyncCommittee\",\"name\":\"currentSyncCommittee\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"structILightNode.SyncCommittee\",\"name\":\"nextSyncCommittee\",\"type\":\"tuple\"},{\"internalType\":\"uint64\",\"name\":\"chainID\",\"type\":\"uint64\"}],\"internalType\":\"structILightNode.LightClientState\",\"name\":\"state\",\"type\":\"tuple\"}],\"indexed\":false,\"internalType\":\"structILightNode.LightClientVerify\",\"name\":\"verify\",\"type\":\"tuple\"}"
 
 const UpdateABIJSON = "{\"components\":[{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.BeaconBlockHeader\",\"name\":\"finalizedHeader\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"struct Types.SyncCommittee\",\"name\":\"nextSyncCommittee\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"nextSyncCommitteeBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.BeaconBlockHeader\",\"name\":\"finalizedHeader\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"finalityBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes32\",\"name\":\"parentHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"feeRecipient\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"receiptsRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"logsBloom\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"prevRandao\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"blockNumber\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasLimit\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasUsed\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"extraData\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"baseFeePerGas\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"blockHash\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"transactionsRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"withdrawalsRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.Execution\",\"name\":\"finalizedExecution\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"executionBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"syncCommitteeBits\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"syncCommitteeSignature\",\"type\":\"bytes\"}],\"internalType\":\"struct Types.SyncAggregate\",\"name\":\"syncAggregate\",\"type\":\"tuple\"},{\"internalType\":\"uint64\",\"name\":\"signatureSlot\",\"type\":\"uint64\"}],\"internalType\":\"struct Types.LightClientUpdate\",\"type\":\"tuple\"}"
+const UpdateV3ABIJSON = "{\"components\":[{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.BeaconBlockHeader\",\"name\":\"attestedHeader\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"struct Types.SyncCommittee\",\"name\":\"nextSyncCommittee\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"nextSyncCommitteeBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.BeaconBlockHeader\",\"name\":\"finalizedHeader\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"finalityBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes32\",\"name\":\"parentHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"feeRecipient\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"receiptsRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"logsBloom\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"prevRandao\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"blockNumber\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasLimit\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"gasUsed\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"extraData\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"baseFeePerGas\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"blockHash\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"transactionsRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"withdrawalsRoot\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"blobGasUsed\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"excessBlobGas\",\"type\":\"uint256\"}],\"internalType\":\"struct Types.Execution\",\"name\":\"finalizedExecution\",\"type\":\"tuple\"},{\"internalType\":\"bytes32[]\",\"name\":\"executionBranch\",\"type\":\"bytes32[]\"},{\"components\":[{\"internalType\":\"bytes\",\"name\":\"syncCommitteeBits\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"syncCommitteeSignature\",\"type\":\"bytes\"}],\"internalType\":\"struct Types.SyncAggregate\",\"name\":\"syncAggregate\",\"type\":\"tuple\"},{\"internalType\":\"uint64\",\"name\":\"signatureSlot\",\"type\":\"uint64\"}],\"internalType\":\"struct Types.LightClientUpdate\",\"type\":\"tuple\"}"
 const BeaconHeaderABIJSON = "{\"components\":[{\"internalType\":\"uint64\",\"name\":\"slot\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"proposerIndex\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"parentRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"stateRoot\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"bodyRoot\",\"type\":\"bytes32\"}],\"internalType\":\"struct Types.BeaconBlockHeader\",\"type\":\"tuple\"}"
 const SyncCommitteeABIJSON = "{\"components\":[{\"internalType\":\"bytes\",\"name\":\"pubkeys\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"aggregatePubkey\",\"type\":\"bytes\"}],\"internalType\":\"struct Types.SyncCommittee\",\"type\":\"tuple\"}"
 const ChainIdABIJSON = "{\"internalType\":\"uint64\",\"type\":\"uint64\"}"
+const BlockNumberABIJSON = "{\"internalType\":\"uint256\",\"type\":\"uint256\"}"
+const Bytes32ABIJSON = "{\"internalType\":\"bytes32\",\"type\":\"bytes32\"}"
 
 func decodeLightClientVerify(input []byte) (*LightClientVerify, error) {
 	verify, err := decodeLightClientVerifyV2(input)
@@ -29,6 +32,21 @@ func decodeLightClientVerify(input []byte) (*LightClientVerify, error) {
 			log.Warn("decodeLightClientVerifyV1", "error", err)
 			return nil, err
 		}
+		return verify, nil
+	}
+
+	// The Capella layout is a prefix of the Deneb one, so the execution payload header
+	// container is selected by the fork of the finalized header rather than by trial decoding
+	config, err := newNetworkConfig(verify.state.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("new network failed: %v", err)
+	}
+	if config.isDeneb(verify.update.GetFinalizedHeader().Slot) {
+		verify, err = decodeLightClientVerifyV3(input)
+		if err != nil {
+			log.Warn("decodeLightClientVerifyV3", "error", err)
+			return nil, err
+		}
 	}
 
 	return verify, nil
@@ -77,9 +95,80 @@ func decodeLightClientVerifyV2(input []byte) (*LightClientVerify, error) {
 	return ConvertToLightClientVerify(update, finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, *chainId), nil
 }
 
+func decodeLightClientVerifyV3(input []byte) (*LightClientVerify, error) {
+	args, err := genAbiArgsV3()
+	if err != nil {
+		return nil, fmt.Errorf("gen abi args failed: %v", err)
+	}
+
+	ret, err := args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+
+	update := new(ILightNodeLightClientUpdateV3)
+	finalizedBeaconHeader := new(ILightNodeBeaconBlockHeader)
+	curSyncCommittee := new(ILightNodeSyncCommittee)
+	nextSyncCommittee := new(ILightNodeSyncCommittee)
+	chainId := new(uint64)
+	if err := args.Copy(&[]interface{}{update, finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, chainId}, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+
+	return ConvertToLightClientVerifyV3(update, finalizedBeaconHeader, curSyncCommittee, nextSyncCommittee, *chainId), nil
+}
+
+// decodeLightClientUpdate decodes a single light client update for the light client store,
+// the update layout is selected by the fork of the finalized header like decodeLightClientVerify
+func decodeLightClientUpdate(input []byte, chainID uint64) (*LightClientUpdateV2, error) {
+	config, err := newNetworkConfig(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("new network failed: %v", err)
+	}
+
+	var updateArg abi.Argument
+	if err := updateArg.UnmarshalJSON([]byte(UpdateABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal update abi json failed: %v", err)
+	}
+	args := abi.Arguments{updateArg}
+	ret, err := args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+	update := new(ILightNodeLightClientUpdateV2)
+	if err := args.Copy(&update, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+	if !config.isDeneb(update.FinalizedHeader.Slot) {
+		return update.toLightClientUpdateV2(), nil
+	}
+
+	if err := updateArg.UnmarshalJSON([]byte(UpdateV3ABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal update abi json failed: %v", err)
+	}
+	args = abi.Arguments{updateArg}
+	ret, err = args.Unpack(input)
+	if err != nil {
+		return nil, fmt.Errorf("unpack input failed: %v", err)
+	}
+	updateV3 := new(ILightNodeLightClientUpdateV3)
+	if err := args.Copy(&updateV3, ret); err != nil {
+		return nil, fmt.Errorf("copy unpacked result failed: %v", err)
+	}
+	return updateV3.toLightClientUpdateV2(), nil
+}
+
 func genAbiArgs() (abi.Arguments, error) {
+	return genAbiArgsWithUpdate(UpdateABIJSON)
+}
+
+func genAbiArgsV3() (abi.Arguments, error) {
+	return genAbiArgsWithUpdate(UpdateV3ABIJSON)
+}
+
+func genAbiArgsWithUpdate(updateABIJSON string) (abi.Arguments, error) {
 	var updateArg, beaconHeaderArg, syncCommitteeArg, chainIdArg abi.Argument
-	if err := updateArg.UnmarshalJSON([]byte(UpdateABIJSON)); err != nil {
+	if err := updateArg.UnmarshalJSON([]byte(updateABIJSON)); err != nil {
 		return nil, fmt.Errorf("unmarshal update abi json failed: %v", err)
 	}
 
@@ -98,12 +187,24 @@ func genAbiArgs() (abi.Arguments, error) {
 	return abi.Arguments{updateArg, beaconHeaderArg, syncCommitteeArg, syncCommitteeArg, chainIdArg}, nil
 }
 
-func computeEpochAtSlot(slot uint64) uint64 {
-	return slot / SlotsPerEpoch
-}
+// genBootstrapAbiArgs returns the arguments of the light client store bootstrap:
+// (finalizedHeader, curSyncCommittee, nextSyncCommittee, chainID, blockNumber, blockHash, receiptsRoot)
+func genBootstrapAbiArgs() (abi.Arguments, error) {
+	args, err := genAbiArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	var blockNumberArg, bytes32Arg abi.Argument
+	if err := blockNumberArg.UnmarshalJSON([]byte(BlockNumberABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal block number abi json failed: %v", err)
+	}
+
+	if err := bytes32Arg.UnmarshalJSON([]byte(Bytes32ABIJSON)); err != nil {
+		return nil, fmt.Errorf("unmarshal bytes32 abi json failed: %v", err)
+	}
 
-func computeSyncCommitteePeriod(slot uint64) uint64 {
-	return computeEpochAtSlot(slot) / EpochsPerSyncCommitteePeriod
+	return append(args[1:], blockNumberArg, bytes32Arg, bytes32Arg), nil
 }
 
 func getParticipantPubkeys(public_keys [][]byte, sync_committee_bits bitfield.Bitvector512) ([]bls2.PublicKey, error) {
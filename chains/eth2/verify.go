@@ -0,0 +1,108 @@
+package eth2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	fssz "github.com/prysmaticlabs/fastssz"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// TxProve proves a receipt of a post-merge ethereum block: the execution payload header is
+// proven against the body root of a beacon block finalized by the light client store, and
+// the receipt is proven against the receipts root of the execution payload
+type TxProve struct {
+	Slot uint64
+	// Execution is the execution payload header of the finalized beacon block, the blob
+	// gas fields are ignored before deneb
+	Execution       *ExecutionPayloadDeneb
+	ExecutionBranch [][]byte
+	Receipt         *ethtypes.Receipt
+	Prove           light.NodeList
+	TxIndex         uint
+}
+
+type Verify struct {
+	chainID uint64
+}
+
+func NewVerify(chainID uint64) *Verify {
+	return &Verify{chainID: chainID}
+}
+
+func (v *Verify) Verify(db types.StateDB, routerContractAddr common.Address, txProveBytes []byte) (logs []byte, err error) {
+	txProve, err := v.decode(txProveBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := LoadLightClientStore(db, v.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	finalized, err := store.GetExecution(db, txProve.Slot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.verifyExecution(finalized.BodyRoot, txProve); err != nil {
+		return nil, err
+	}
+
+	if err := verifyReceipt(txProve.Execution.ReceiptsRoot, txProve.Receipt, txProve.Prove, txProve.TxIndex); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(txProve.Receipt.Logs)
+}
+
+func (v *Verify) decode(txProveBytes []byte) (*TxProve, error) {
+	var txProve TxProve
+	if err := rlp.DecodeBytes(txProveBytes, &txProve); err != nil {
+		return nil, err
+	}
+	if txProve.Execution == nil || txProve.Receipt == nil {
+		return nil, errors.New("execution and receipt cannot be empty")
+	}
+	return &txProve, nil
+}
+
+func (v *Verify) verifyExecution(bodyRoot common.Hash, txProve *TxProve) error {
+	config, err := newNetworkConfig(v.chainID)
+	if err != nil {
+		return fmt.Errorf("new network failed: %v", err)
+	}
+
+	if len(txProve.ExecutionBranch) != ExecutionPayloadProofSize {
+		return fmt.Errorf("invalid execution payload proof size, exp: %d, got: %d", ExecutionPayloadProofSize, len(txProve.ExecutionBranch))
+	}
+
+	var execution fssz.HashRoot = &txProve.Execution.ExecutionPayload
+	if config.isDeneb(txProve.Slot) {
+		execution = txProve.Execution
+	}
+	executionPayloadHash, err := execution.HashTreeRoot()
+	if err != nil {
+		return fmt.Errorf("compute execution payload merkel root failed: %v", err)
+	}
+
+	proof := fssz.Proof{
+		Index:  int(BeaconBlockBodyTreeExecutionPayloadIndex),
+		Leaf:   executionPayloadHash[:],
+		Hashes: txProve.ExecutionBranch,
+	}
+	ret, err := fssz.VerifyProof(bodyRoot[:], &proof)
+	if err != nil {
+		return fmt.Errorf("VerifyProof return err: %v", err)
+	}
+
+	if !ret {
+		return errors.New("invalid execution payload proof")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package eth2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/mapprotocol/atlas/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeTxProve(t *testing.T, tr *trie.Trie, vector *forkTestVector, receipt *ethtypes.Receipt, txIndex uint) []byte {
+	key, err := rlp.EncodeToBytes(txIndex)
+	assert.Nil(t, err)
+	proof := light.NewNodeSet()
+	assert.Nil(t, tr.Prove(key, 0, proof))
+
+	input, err := rlp.EncodeToBytes(TxProve{
+		Slot:            vector.update.FinalizedHeader.Slot,
+		Execution:       vector.update.FinalizedExecution.toExecutionPayloadDeneb(),
+		ExecutionBranch: bytes32ArrayToBytesArray(vector.update.ExecutionBranch),
+		Receipt:         receipt,
+		Prove:           proof.NodeList(),
+		TxIndex:         txIndex,
+	})
+	assert.Nil(t, err)
+	return input
+}
+
+func TestVerify(t *testing.T) {
+	config, err := newNetworkConfig(mainnetChainID)
+	assert.Nil(t, err)
+
+	receipts := newTestReceipts()
+	tr := newTestReceiptsTrie(t, receipts)
+	vector := makeForkTestVectorWithReceiptsRoot(t, config.DenebForkEpoch+10, tr.Hash())
+	db := newTestStateDB()
+	_, err = InitLightClientStore(db, params.TestChainConfig, big.NewInt(1), vector.encodeBootstrap(t))
+	assert.Nil(t, err)
+	_, err = ApplyLightClientUpdate(db, mainnetChainID, vector.encodeUpdateV3(t))
+	assert.Nil(t, err)
+
+	router := common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6")
+	logs, err := NewVerify(mainnetChainID).Verify(db, router, encodeTxProve(t, tr, vector, receipts[2], 2))
+	assert.Nil(t, err)
+	expLogs, err := rlp.EncodeToBytes(receipts[2].Logs)
+	assert.Nil(t, err)
+	assert.Equal(t, expLogs, logs)
+
+	// every network has its own store
+	_, err = NewVerify(11155111).Verify(db, router, encodeTxProve(t, tr, vector, receipts[2], 2))
+	assert.Equal(t, ErrNotInitialized, err)
+
+	_, err = NewVerify(mainnetChainID).Verify(db, router, encodeTxProve(t, tr, vector, receipts[0], 2))
+	assert.EqualError(t, err, "receipt mismatch")
+
+	// an execution payload that is not the one of the finalized block
+	tampered := *vector
+	tampered.update.FinalizedExecution.ReceiptsRoot = common.HexToHash("0x04")
+	_, err = NewVerify(mainnetChainID).Verify(db, router, encodeTxProve(t, tr, &tampered, receipts[2], 2))
+	assert.EqualError(t, err, "invalid execution payload proof")
+
+	tampered = *vector
+	tampered.update.ExecutionBranch = tampered.update.ExecutionBranch[1:]
+	_, err = NewVerify(mainnetChainID).Verify(db, router, encodeTxProve(t, tr, &tampered, receipts[2], 2))
+	assert.EqualError(t, err, "invalid execution payload proof size, exp: 4, got: 3")
+
+	// only finalized slots known to the store can be proven
+	tampered = *vector
+	tampered.update.FinalizedHeader.Slot++
+	_, err = NewVerify(mainnetChainID).Verify(db, router, encodeTxProve(t, tr, &tampered, receipts[2], 2))
+	assert.NotNil(t, err)
+}
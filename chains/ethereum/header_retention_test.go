@@ -0,0 +1,141 @@
+package ethereum
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/chains"
+)
+
+func makeRetentionHeaders(parent *Header, n int) []*Header {
+	headers := make([]*Header, 0, n)
+	for i := 0; i < n; i++ {
+		h := &Header{
+			ParentHash: parent.Hash(),
+			Difficulty: big.NewInt(1),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Extra:      []byte{},
+		}
+		headers = append(headers, h)
+		parent = h
+	}
+	return headers
+}
+
+func TestHeaderRetention(t *testing.T) {
+	db := getStateDB()
+	genesis := &Header{Difficulty: big.NewInt(1), Number: big.NewInt(1000), Extra: []byte{}}
+	enc, _ := rlp.EncodeToBytes(genesis)
+
+	hs := NewHeaderStore(uint64(chains.ChainTypeETHTest))
+	if err := hs.SetRetention(db, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := hs.ResetHeaderStore(db, enc, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	headers := makeRetentionHeaders(genesis, 30)
+	for i := 0; i < len(headers); i += 5 {
+		data, _ := rlp.EncodeToBytes(headers[i : i+5])
+		if _, err := hs.InsertHeaders(db, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	earliest, err := hs.EarliestStoredNumber(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earliest != 1021 {
+		t.Fatalf("earliest stored number %d, want 1021", earliest)
+	}
+	for number := uint64(1000); number < 1021; number++ {
+		if hash := hs.ReadCanonicalHash(number, db); hash != (common.Hash{}) {
+			t.Fatalf("canonical hash of %d not pruned", number)
+		}
+		if data := db.GetPOWState(chains.EthereumHeaderStoreAddress, hs.headerDbKey(number)); len(data) != 0 {
+			t.Fatalf("header of %d not pruned", number)
+		}
+	}
+	for _, h := range headers[len(headers)-10:] {
+		if hs.GetHeaderByNumber(h.Number.Uint64(), db) == nil {
+			t.Fatalf("header of %d pruned", h.Number)
+		}
+	}
+
+	// headers behind the window are rejected
+	data, _ := rlp.EncodeToBytes(headers[15:20])
+	if _, err := new(Validate).ValidateHeaderChain(db, data, chains.ChainTypeETHTest); err == nil || !strings.Contains(err.Error(), "obsolete block") {
+		t.Fatalf("obsolete headers validated, err = %v", err)
+	}
+}
+
+func TestHeaderRetentionUnset(t *testing.T) {
+	db := getStateDB()
+	genesis := &Header{Difficulty: big.NewInt(1), Number: big.NewInt(1000), Extra: []byte{}}
+	enc, _ := rlp.EncodeToBytes(genesis)
+
+	// the retention of another ethereum chain doesn't apply
+	if err := NewHeaderStore(uint64(chains.ChainTypeETH)).SetRetention(db, 10); err != nil {
+		t.Fatal(err)
+	}
+	hs := NewHeaderStore(uint64(chains.ChainTypeETHTest))
+	if err := hs.ResetHeaderStore(db, enc, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := rlp.EncodeToBytes(makeRetentionHeaders(genesis, 20))
+	if _, err := hs.InsertHeaders(db, data); err != nil {
+		t.Fatal(err)
+	}
+	// nothing is pruned without a retention setting
+	if hs.GetHeaderByNumber(1000, db) == nil {
+		t.Fatal("header pruned without retention setting")
+	}
+	if earliest, _ := hs.EarliestStoredNumber(db); earliest != 0 {
+		t.Fatalf("earliest stored number %d, want 0", earliest)
+	}
+}
+
+func TestHeaderRetentionPruneBound(t *testing.T) {
+	db := getStateDB()
+	genesis := &Header{Difficulty: big.NewInt(1), Number: big.NewInt(1000), Extra: []byte{}}
+	enc, _ := rlp.EncodeToBytes(genesis)
+
+	hs := NewHeaderStore(uint64(chains.ChainTypeETHTest))
+	if err := hs.SetRetention(db, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := hs.ResetHeaderStore(db, enc, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	headers := makeRetentionHeaders(genesis, 46)
+	data, _ := rlp.EncodeToBytes(headers[:40])
+	if _, err := hs.InsertHeaders(db, data); err != nil {
+		t.Fatal(err)
+	}
+
+	// shrinking the window only prunes PrunePerHeader numbers per inserted header
+	if err := hs.SetRetention(db, 10); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []uint64{1002, 1012} {
+		batch := headers[40:41]
+		if i == 1 {
+			batch = headers[41:46]
+		}
+		data, _ := rlp.EncodeToBytes(batch)
+		if _, err := hs.InsertHeaders(db, data); err != nil {
+			t.Fatal(err)
+		}
+		if earliest, _ := hs.EarliestStoredNumber(db); earliest != want {
+			t.Fatalf("earliest stored number %d, want %d", earliest, want)
+		}
+		if hs.GetHeaderByNumber(want-1, db) != nil || hs.GetHeaderByNumber(want, db) == nil {
+			t.Fatalf("headers not pruned up to %d", want)
+		}
+	}
+}
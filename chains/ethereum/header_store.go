@@ -53,6 +53,7 @@ type Cache struct {
 type HeaderStore struct {
 	CurNumber uint64
 	CurHash   common.Hash
+	chainID   uint64 // chain whose header retention applies, it isn't stored
 	//CanonicalNumberToHash []*common.Hash
 }
 
@@ -85,8 +86,129 @@ func (hs *HeaderStore) loopIdx(number uint64) uint64 {
 	return idx
 }
 
-func (hs *HeaderStore) delOldHeaders() {
+func (hs *HeaderStore) earliestDbKey() common.Hash {
+	return common.BytesToHash([]byte("eth2map-earliest"))
+}
+
+// retention returns the governance configured number of recent headers to keep, zero if
+// it was never configured. The headers of all ethereum chains share one store, the setting
+// of the chain the store is used for applies.
+func (hs *HeaderStore) retention(db types.StateDB) uint64 {
+	retention := chains.GetHeaderRetention(db, chains.ChainType(hs.chainID))
+	if retention > MaxHeaderLimit {
+		retention = MaxHeaderLimit
+	}
+	return retention
+}
 
+// window returns the number of recent headers the store keeps
+func (hs *HeaderStore) window(db types.StateDB) uint64 {
+	if retention := hs.retention(db); retention != 0 {
+		return retention
+	}
+	return MaxHeaderLimit
+}
+
+// earliestNumber returns the lowest block number whose headers may still be stored
+func (hs *HeaderStore) earliestNumber(db types.StateDB) uint64 {
+	var earliest uint64
+	if data := db.GetPOWState(chains.EthereumHeaderStoreAddress, hs.earliestDbKey()); len(data) != 0 {
+		if err := rlp.DecodeBytes(data, &earliest); err != nil {
+			log.Error("decode earliest stored number failed", "err", err)
+		}
+	}
+	// the ring of slots only holds the last MaxHeaderLimit numbers
+	if hs.CurNumber >= MaxHeaderLimit && earliest < hs.CurNumber-MaxHeaderLimit+1 {
+		earliest = hs.CurNumber - MaxHeaderLimit + 1
+	}
+	return earliest
+}
+
+func (hs *HeaderStore) storeEarliestNumber(db types.StateDB, number uint64) error {
+	data, err := rlp.EncodeToBytes(number)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(chains.EthereumHeaderStoreAddress, hs.earliestDbKey(), data)
+	return nil
+}
+
+// pruneNumber deletes the headers, tds and canonical hash stored in the slot of number
+// that are below keepFrom
+func (hs *HeaderStore) pruneNumber(db types.StateDB, number, keepFrom uint64) error {
+	address := chains.EthereumHeaderStoreAddress
+	lh, err := hs.LoadHeader(number, db)
+	if err != nil {
+		return err
+	}
+	if len(lh.Headers) != 0 {
+		for key, data := range lh.Headers {
+			if h := decodeHeader(data, common.HexToHash(key)); h == nil || h.Number.Uint64() < keepFrom {
+				delete(lh.Headers, key)
+				delete(lh.TDs, key)
+			}
+		}
+		if len(lh.Headers) == 0 {
+			db.SetPOWState(address, hs.headerDbKey(number), nil)
+		} else if err := hs.StoreHeader(db, number, lh); err != nil {
+			return err
+		}
+	}
+	hash := hs.ReadCanonicalHash(number, db)
+	if _, ok := lh.Headers[hash.String()]; hash != (common.Hash{}) && !ok {
+		db.SetPOWState(address, hs.canonicalHeaderDbKey(number), nil)
+	}
+	return nil
+}
+
+// delOldHeaders deletes the headers evicted from the configured retention window, at most
+// limit block numbers of them. Stores without a retention setting keep overwriting the
+// ring of MaxHeaderLimit slots.
+func (hs *HeaderStore) delOldHeaders(db types.StateDB, limit uint64) error {
+	retention := hs.retention(db)
+	if retention == 0 || hs.CurNumber < retention {
+		return nil
+	}
+	keepFrom := hs.CurNumber - retention + 1
+	earliest := hs.earliestNumber(db)
+	if earliest >= keepFrom {
+		return nil
+	}
+	end := keepFrom
+	if end-earliest > limit {
+		end = earliest + limit
+	}
+	for number := earliest; number < end; number++ {
+		if err := hs.pruneNumber(db, number, keepFrom); err != nil {
+			return err
+		}
+	}
+	log.Debug("pruned ethereum headers", "from", earliest, "to", end)
+	return hs.storeEarliestNumber(db, end)
+}
+
+// EarliestStoredNumber returns the lowest block number whose header may still be used
+// to verify receipts
+func (hs *HeaderStore) EarliestStoredNumber(db types.StateDB) (uint64, error) {
+	if err := hs.Load(db); err != nil {
+		return 0, err
+	}
+	return hs.earliestNumber(db), nil
+}
+
+// MinRetention returns the lower bound of the retention setting
+func (hs *HeaderStore) MinRetention() uint64 {
+	return chains.MinHeaderRetention
+}
+
+// MaxRetention returns the upper bound of the retention setting
+func (hs *HeaderStore) MaxRetention() uint64 {
+	return MaxHeaderLimit
+}
+
+// SetRetention sets the number of recent headers of the chain the store keeps
+func (hs *HeaderStore) SetRetention(db types.StateDB, retention uint64) error {
+	return chains.SetHeaderRetention(db, chains.ChainType(hs.chainID), retention)
 }
 
 func encodeHeader(header *Header) []byte {
@@ -106,8 +228,8 @@ func decodeHeader(data []byte, hash common.Hash) *Header {
 	return header
 }
 
-func NewHeaderStore() *HeaderStore {
-	return &HeaderStore{}
+func NewHeaderStore(chainID uint64) *HeaderStore {
+	return &HeaderStore{chainID: chainID}
 }
 
 func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, ethHeaders []byte, td *big.Int) error {
@@ -123,10 +245,16 @@ func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, ethHeaders []byte,
 	h := &HeaderStore{
 		CurHash:   hash,
 		CurNumber: number,
+		chainID:   hs.chainID,
 	}
 	if err := h.Store(state); err != nil {
 		return err
 	}
+	if h.retention(state) != 0 {
+		if err := h.storeEarliestNumber(state, number); err != nil {
+			return err
+		}
+	}
 	startNumber = number
 	firstHeader := &LightHeader{
 		Headers: make(map[string][]byte),
@@ -141,7 +269,7 @@ func (hs *HeaderStore) ResetHeaderStore(state types.StateDB, ethHeaders []byte,
 }
 
 func cloneHeaderStore(src *HeaderStore) (dst *HeaderStore, err error) {
-	dst = NewHeaderStore()
+	dst = NewHeaderStore(src.chainID)
 	if err := tools.DeepCopy(src, dst); err != nil {
 		return nil, err
 	}
@@ -307,6 +435,15 @@ func (hs *HeaderStore) WriteHeaderAndTd(hash common.Hash, number uint64, td *big
 	if err != nil {
 		return err
 	}
+	if hs.retention(db) != 0 {
+		// drop the headers left in the slot by the numbers MaxHeaderLimit apart
+		for key, data := range loadHeader.Headers {
+			if h := decodeHeader(data, common.HexToHash(key)); h == nil || h.Number.Uint64() != number {
+				delete(loadHeader.Headers, key)
+				delete(loadHeader.TDs, key)
+			}
+		}
+	}
 	loadHeader.Headers[hash.String()] = encodeHeader(header)
 	loadHeader.TDs[hash.String()] = td
 	// store
@@ -458,7 +595,7 @@ func (hs *HeaderStore) WriteHeaders(db types.StateDB, ethHeaders []byte) (*heade
 	if reorg {
 		if !chainAlreadyCanon {
 			for i := lastNumber + 1; ; i++ {
-				if i <= hs.CurNumber-MaxHeaderLimit+1 {
+				if i <= hs.CurNumber-hs.window(db)+1 {
 					log.Info("chainAlreadyCanon=false, obsolete block", "current", hs.CurNumber, "calNumber", i)
 					continue
 				}
@@ -502,9 +639,11 @@ func (hs *HeaderStore) WriteHeaders(db types.StateDB, ethHeaders []byte) (*heade
 			hs.WriteCanonicalHash(hn.Hash, hn.Number, db)
 		}
 
-		hs.delOldHeaders()
 		hs.CurHash = lastHash
 		hs.CurNumber = lastNumber
+		if err := hs.delOldHeaders(db, uint64(len(inserted))*chains.PrunePerHeader); err != nil {
+			return &headerWriteResult{}, err
+		}
 
 		// Chain status is canonical since this insert was a reorg.
 		// Note that all inserts which have higher TD than existing are 'reorg'.
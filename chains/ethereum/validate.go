@@ -57,7 +57,7 @@ func (v *Validate) ValidateHeaderChain(db types.StateDB, headers []byte, chainTy
 		}
 	}
 
-	hs := NewHeaderStore()
+	hs := NewHeaderStore(uint64(chainType))
 	if err := hs.Load(db); err != nil {
 		return 0, err
 	}
@@ -74,7 +74,7 @@ func (v *Validate) ValidateHeaderChain(db types.StateDB, headers []byte, chainTy
 		return 0, fmt.Errorf("non contiguous insert, current number: %d, first number: %d", currentNumber, firstNumber)
 	}
 
-	if firstNumber.Uint64() <= currentNumber-MaxHeaderLimit+1 {
+	if firstNumber.Uint64() <= currentNumber-hs.window(db)+1 {
 		return 0, fmt.Errorf("obsolete block, current number: %d, first number: %d", currentNumber, firstNumber)
 	}
 
@@ -71,11 +71,14 @@ func (v *Verify) decode(txProveBytes []byte) (*TxProve, error) {
 //}
 
 func (v *Verify) getReceiptsRoot(db types.StateDB, blockNumber uint64) (common.Hash, error) {
-	hs := NewHeaderStore()
+	hs := new(HeaderStore)
 	if err := hs.Load(db); err != nil {
 		return common.Hash{}, err
 	}
 	header := hs.GetHeaderByNumber(blockNumber, db)
+	if header == nil && blockNumber < hs.earliestNumber(db) {
+		return common.Hash{}, fmt.Errorf("header was pruned, number: %d, earliest stored number: %d", blockNumber, hs.earliestNumber(db))
+	}
 	if header == nil {
 		return common.Hash{}, fmt.Errorf("get header by number failed, number: %d", blockNumber)
 	}
@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/bitcoin"
+	"github.com/mapprotocol/atlas/chains/bsc"
 	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/params"
@@ -43,12 +45,22 @@ func (c *Chain) GetHashByNumber(db types.StateDB, number uint64) (common.Hash, e
 	return c.HeaderStore.GetHashByNumber(db, number)
 }
 
-func ChainFactory(group chains.ChainGroup) (IChain, error) {
+func ChainFactory(group chains.ChainGroup, chain chains.ChainType) (IChain, error) {
 	switch group {
 	case chains.ChainGroupETH:
 		return &Chain{
 			Validate:    new(ethereum.Validate),
-			HeaderStore: new(ethereum.HeaderStore),
+			HeaderStore: ethereum.NewHeaderStore(uint64(chain)),
+		}, nil
+	case chains.ChainGroupBSC:
+		return &Chain{
+			Validate:    new(bsc.Validate),
+			HeaderStore: bsc.NewHeaderStore(uint64(chain)),
+		}, nil
+	case chains.ChainGroupBTC:
+		return &Chain{
+			Validate:    new(bitcoin.Validate),
+			HeaderStore: bitcoin.NewHeaderStore(uint64(chain)),
 		}, nil
 	}
 
@@ -6,6 +6,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/bitcoin"
+	"github.com/mapprotocol/atlas/chains/bsc"
 	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/params"
@@ -23,10 +25,23 @@ type IHeaderStore interface {
 	GetHashByNumber(db types.StateDB, number uint64) (common.Hash, error)
 }
 
-func HeaderStoreFactory(group chains.ChainGroup) (IHeaderStore, error) {
+// IRetention is implemented by the header stores that prune the headers outside of a
+// governance configured window of recent headers
+type IRetention interface {
+	MinRetention() uint64
+	MaxRetention() uint64
+	SetRetention(db types.StateDB, retention uint64) error
+	EarliestStoredNumber(db types.StateDB) (uint64, error)
+}
+
+func HeaderStoreFactory(group chains.ChainGroup, chain chains.ChainType) (IHeaderStore, error) {
 	switch group {
 	case chains.ChainGroupETH:
-		return new(ethereum.HeaderStore), nil
+		return ethereum.NewHeaderStore(uint64(chain)), nil
+	case chains.ChainGroupBSC:
+		return bsc.NewHeaderStore(uint64(chain)), nil
+	case chains.ChainGroupBTC:
+		return bitcoin.NewHeaderStore(uint64(chain)), nil
 	}
 	return nil, chains.ErrNotSupportChain
 }
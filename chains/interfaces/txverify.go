@@ -4,6 +4,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/bitcoin"
+	"github.com/mapprotocol/atlas/chains/bsc"
+	"github.com/mapprotocol/atlas/chains/eth2"
 	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 )
@@ -12,10 +15,16 @@ type IVerify interface {
 	Verify(db types.StateDB, router common.Address, txProveBytes []byte) (logs []byte, err error)
 }
 
-func VerifyFactory(group chains.ChainGroup) (IVerify, error) {
+func VerifyFactory(group chains.ChainGroup, chain chains.ChainType) (IVerify, error) {
 	switch group {
 	case chains.ChainGroupETH:
 		return new(ethereum.Verify), nil
+	case chains.ChainGroupETH2:
+		return eth2.NewVerify(uint64(chain)), nil
+	case chains.ChainGroupBSC:
+		return bsc.NewVerify(uint64(chain)), nil
+	case chains.ChainGroupBTC:
+		return bitcoin.NewVerify(uint64(chain)), nil
 	}
 	return nil, chains.ErrNotSupportChain
 }
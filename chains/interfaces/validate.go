@@ -2,6 +2,8 @@ package interfaces
 
 import (
 	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/chains/bitcoin"
+	"github.com/mapprotocol/atlas/chains/bsc"
 	"github.com/mapprotocol/atlas/chains/ethereum"
 	"github.com/mapprotocol/atlas/core/types"
 )
@@ -14,6 +16,10 @@ func ValidateFactory(group chains.ChainGroup) (IValidate, error) {
 	switch group {
 	case chains.ChainGroupETH:
 		return new(ethereum.Validate), nil
+	case chains.ChainGroupBSC:
+		return new(bsc.Validate), nil
+	case chains.ChainGroupBTC:
+		return new(bitcoin.Validate), nil
 	}
 	return nil, chains.ErrNotSupportChain
 }
@@ -0,0 +1,46 @@
+package chains
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// MinHeaderRetention is the lowest number of recent headers a header store can be
+// configured to keep, it leaves room for the reorgs of the source chain
+const MinHeaderRetention = 1024
+
+// PrunePerHeader bounds the number of evicted block numbers deleted per inserted header,
+// so the storage cleared by an insertion is paid by the price per byte of the headers it
+// inserts
+const PrunePerHeader = 2
+
+func headerRetentionKey(chain ChainType) common.Hash {
+	return common.BytesToHash([]byte(fmt.Sprintf("retention-%d", chain)))
+}
+
+// GetHeaderRetention returns the number of recent headers of the chain kept by its header
+// store, zero means it was never configured and the header store keeps its default window
+func GetHeaderRetention(db types.StateDB, chain ChainType) uint64 {
+	data := db.GetPOWState(HeaderRetentionAddress, headerRetentionKey(chain))
+	if len(data) == 0 {
+		return 0
+	}
+	var retention uint64
+	if err := rlp.DecodeBytes(data, &retention); err != nil {
+		return 0
+	}
+	return retention
+}
+
+// SetHeaderRetention sets the number of recent headers of the chain kept by its header store
+func SetHeaderRetention(db types.StateDB, chain ChainType, retention uint64) error {
+	data, err := rlp.EncodeToBytes(retention)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(HeaderRetentionAddress, headerRetentionKey(chain), data)
+	return nil
+}
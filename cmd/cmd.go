@@ -109,6 +109,16 @@ var (
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
 		utils.VerifyCheckPointFlag,
+		utils.CheckPointSourceFlag,
+		utils.CheckPointNetworkFlag,
+		utils.CheckPointSenderFlag,
+		utils.CheckPointBitcoindFlag,
+		utils.CheckPointFileFlag,
+		utils.CheckPointPolicyFlag,
+		utils.CheckPointPublishFlag,
+		utils.CheckPointIntervalFlag,
+		utils.CheckPointKeyFlag,
+		utils.CheckPointFeeRateFlag,
 		//utils.MiningEnabledFlag,
 		//utils.MinerThreadsFlag,
 		//utils.MinerNotifyFlag,
@@ -130,6 +140,8 @@ var (
 		utils.TestnetFlag,
 		utils.SingleFlag,
 		utils.VMEnableDebugFlag,
+		utils.VMParallelFlag,
+		utils.VMParallelWorkersFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
 		utils.FakePoWFlag,
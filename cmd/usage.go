@@ -52,6 +52,16 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.LightKDFFlag,
 			utils.WhitelistFlag,
 			utils.VerifyCheckPointFlag,
+			utils.CheckPointSourceFlag,
+			utils.CheckPointNetworkFlag,
+			utils.CheckPointSenderFlag,
+			utils.CheckPointBitcoindFlag,
+			utils.CheckPointFileFlag,
+			utils.CheckPointPolicyFlag,
+			utils.CheckPointPublishFlag,
+			utils.CheckPointIntervalFlag,
+			utils.CheckPointKeyFlag,
+			utils.CheckPointFeeRateFlag,
 		},
 	},
 	{
@@ -191,6 +201,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
 			utils.VMEnableDebugFlag,
+			utils.VMParallelFlag,
+			utils.VMParallelWorkersFlag,
 		},
 	},
 	{
@@ -197,6 +197,19 @@ type Istanbul interface {
 
 	// GenerateRandomness will generate the random beacon randomness
 	GenerateRandomness(parentHash common.Hash) (common.Hash, common.Hash, error)
+
+	// PendingDoubleSignEvidence returns the double sign evidence which wasn't processed on chain yet
+	PendingDoubleSignEvidence() []*istanbul.DoubleSignEvidence
+
+	// DiscardDoubleSignEvidence drops the double sign evidence once processed on chain
+	DiscardDoubleSignEvidence(hash common.Hash)
+
+	// SignTx signs the transaction with the validator key
+	SignTx(tx *types.Transaction, signer types.Signer) (*types.Transaction, error)
+
+	// FinalizeWithEVMRunner is Finalize making the system contract calls through the given
+	// EVMRunner, so that they can be traced
+	FinalizeWithEVMRunner(chain ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, vmRunner vm.EVMRunner)
 }
 
 // ChainContext defines a small collection of methods needed to access the local
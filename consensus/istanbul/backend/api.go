@@ -17,6 +17,7 @@
 package backend
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/mapprotocol/atlas/tools"
@@ -24,6 +25,7 @@ import (
 	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/mapprotocol/atlas/consensus"
@@ -36,6 +38,7 @@ import (
 	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
 	blscrypto "github.com/mapprotocol/atlas/helper/bls"
 )
 
@@ -317,6 +320,22 @@ func (api *API) GetLookbackWindow(number *rpc.BlockNumber) (uint64, error) {
 	return api.istanbul.LookbackWindow(header, state), nil
 }
 
+// GetJailedValidators retrieves the validator accounts jailed for downtime and the last epoch
+// they are excluded from the validator sets
+func (api *API) GetJailedValidators(number *rpc.BlockNumber) ([]*vm.JailedValidator, error) {
+	header, err := api.getHeaderByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := api.istanbul.stateAt(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.GetJailedValidators(state)
+}
+
 func (api *API) Activity() (map[string]interface{}, error) {
 	header := api.chain.CurrentHeader()
 	if header == nil {
@@ -410,3 +429,72 @@ func (api *API) GetEpochInfo(epochNumber uint64) *EpochInfo {
 	}
 	return epochInfo
 }
+
+type ValidatorKeys struct {
+	Address  common.Address `json:"address"`
+	G1PubKey G1PublicKey    `json:"g1_pub_key"`
+	G2PubKey hexutil.Bytes  `json:"g2_pub_key"`
+}
+
+type ValidatorSetChange struct {
+	Epoch       string          `json:"epoch"`
+	BlockNumber string          `json:"block_number"`
+	BlockHash   common.Hash     `json:"block_hash"`
+	Threshold   string          `json:"threshold"`
+	Added       []ValidatorKeys `json:"added"`
+	Removed     []ValidatorKeys `json:"removed"`
+}
+
+func newValidatorKeys(validators []istanbul.ValidatorData) []ValidatorKeys {
+	keys := make([]ValidatorKeys, 0, len(validators))
+	for _, v := range validators {
+		keys = append(keys, ValidatorKeys{
+			Address: v.Address,
+			G1PubKey: G1PublicKey{
+				X: tools.Bytes2Hex(v.BLSG1PublicKey[:32]),
+				Y: tools.Bytes2Hex(v.BLSG1PublicKey[32:]),
+			},
+			G2PubKey: v.BLSPublicKey[:],
+		})
+	}
+	return keys
+}
+
+// ValidatorSetChanged sends a notification each time the last block of an epoch is committed,
+// with the validators added to and removed from the validator set of the next epoch.
+func (api *API) ValidatorSetChanged(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		changes := make(chan istanbul.ValidatorSetChangedEvent)
+		changesSub := api.istanbul.SubscribeValidatorSetChangedEvent(changes)
+		defer changesSub.Unsubscribe()
+
+		for {
+			select {
+			case c := <-changes:
+				notifier.Notify(rpcSub.ID, &ValidatorSetChange{
+					Epoch:       strconv.FormatUint(c.Epoch, 10),
+					BlockNumber: strconv.FormatUint(c.BlockNumber, 10),
+					BlockHash:   c.BlockHash,
+					Threshold:   strconv.Itoa(c.Threshold),
+					Added:       newValidatorKeys(c.Added),
+					Removed:     newValidatorKeys(c.Removed),
+				})
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-changesSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,243 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// maxActivityBlocks is the largest block range scanned for the signing activity of a signer
+const maxActivityBlocks = 100000
+
+var (
+	errEpochNotSigned   = errors.New("no signed block of the epoch yet")
+	errInvalidRange     = errors.New("invalid block range")
+	errTooManyBlocks    = fmt.Errorf("block range exceeds %d blocks", maxActivityBlocks)
+	errMissingSignature = errors.New("missing parent aggregated seal")
+)
+
+// ValidatorActivity is the signing activity of a validator over a range of blocks. A block is
+// signed by the validators in the parent aggregated seal of the next block.
+type ValidatorActivity struct {
+	Address           common.Address `json:"address"`
+	Signed            uint64         `json:"signed"`
+	Missed            uint64         `json:"missed"`
+	LongestMissStreak uint64         `json:"longestMissStreak"`
+	Proposed          uint64         `json:"proposed"`
+	// UpBlocks and LastSignedBlock are the accumulated uptime of the validator in the epoch
+	// if the node monitored it
+	UpBlocks        *uint64 `json:"upBlocks,omitempty"`
+	LastSignedBlock *uint64 `json:"lastSignedBlock,omitempty"`
+
+	streak       uint64
+	missedBlocks []uint64
+}
+
+// EpochActivity is the signing activity of the validators of an epoch up to its last
+// signed block
+type EpochActivity struct {
+	Epoch      uint64               `json:"epoch"`
+	FirstBlock uint64               `json:"firstBlock"`
+	LastBlock  uint64               `json:"lastBlock"`
+	Validators []*ValidatorActivity `json:"validators"`
+}
+
+// SignerHistory is the signing activity of a signer over the blocks it was a validator of
+type SignerHistory struct {
+	*ValidatorActivity
+	FromBlock    uint64   `json:"fromBlock"`
+	ToBlock      uint64   `json:"toBlock"`
+	MissedBlocks []uint64 `json:"missedBlocks"`
+}
+
+// activityCounter accumulates the signing activity of the validators block after block
+type activityCounter struct {
+	activity  map[common.Address]*ValidatorActivity
+	order     []common.Address
+	recordFor common.Address
+}
+
+func newActivityCounter() *activityCounter {
+	return &activityCounter{activity: make(map[common.Address]*ValidatorActivity)}
+}
+
+func (c *activityCounter) get(address common.Address) *ValidatorActivity {
+	a, ok := c.activity[address]
+	if !ok {
+		a = &ValidatorActivity{Address: address}
+		c.activity[address] = a
+		c.order = append(c.order, address)
+	}
+	return a
+}
+
+// add accounts the block signed by the validators in the bitmap and proposed by the proposer
+func (c *activityCounter) add(number uint64, validators []istanbul.Validator, bitmap *big.Int, proposer common.Address) {
+	for i, val := range validators {
+		a := c.get(val.Address())
+		if val.Address() == proposer {
+			a.Proposed++
+		}
+		if bitmap.Bit(i) == 1 {
+			a.Signed++
+			a.streak = 0
+			continue
+		}
+		a.Missed++
+		a.streak++
+		if a.streak > a.LongestMissStreak {
+			a.LongestMissStreak = a.streak
+		}
+		if val.Address() == c.recordFor {
+			a.missedBlocks = append(a.missedBlocks, number)
+		}
+	}
+}
+
+// scanActivity accounts the blocks from..to of the canonical chain, the last block must have
+// a child carrying its aggregated seal
+func (api *API) scanActivity(c *activityCounter, from, to uint64) error {
+	var (
+		epoch      uint64
+		validators []istanbul.Validator
+	)
+	header := api.chain.GetHeaderByNumber(from)
+	for number := from; number <= to; number++ {
+		if header == nil {
+			return errUnknownBlock
+		}
+		child := api.chain.GetHeaderByNumber(number + 1)
+		if child == nil || child.ParentHash != header.Hash() {
+			return errUnknownBlock
+		}
+		// the validator set only changes at the epoch boundaries
+		if e := istanbul.GetEpochNumber(number, api.istanbul.EpochSize()); validators == nil || e != epoch {
+			epoch = e
+			validators = api.istanbul.GetValidators(new(big.Int).SetUint64(number-1), header.ParentHash)
+		}
+		extra, err := types.ExtractIstanbulExtra(child)
+		if err != nil {
+			return err
+		}
+		if extra.ParentAggregatedSeal.Bitmap == nil {
+			return errMissingSignature
+		}
+		proposer, err := api.istanbul.Author(header)
+		if err != nil {
+			return err
+		}
+		c.add(number, validators, extra.ParentAggregatedSeal.Bitmap, proposer)
+		header = child
+	}
+	return nil
+}
+
+// lastSignedBlock returns the last block whose aggregated seal is in the chain
+func (api *API) lastSignedBlock() (uint64, error) {
+	head := api.chain.CurrentHeader()
+	if head == nil || head.Number.Sign() == 0 {
+		return 0, errUnknownBlock
+	}
+	return head.Number.Uint64() - 1, nil
+}
+
+// GetActivity retrieves the signed and missed blocks, the longest miss streak and the proposals
+// of the validators of the epoch, along with their accumulated uptime.
+func (api *API) GetActivity(epoch uint64) (*EpochActivity, error) {
+	first, err := istanbul.GetEpochFirstBlockNumber(epoch, api.istanbul.EpochSize())
+	if err != nil {
+		return nil, err
+	}
+	last := istanbul.GetEpochLastBlockNumber(epoch, api.istanbul.EpochSize())
+	signed, err := api.lastSignedBlock()
+	if err != nil {
+		return nil, err
+	}
+	if signed < first {
+		return nil, errEpochNotSigned
+	}
+	if last > signed {
+		last = signed
+	}
+
+	c := newActivityCounter()
+	// list the validators of the epoch in validator set order, even without blocks
+	header := api.chain.GetHeaderByNumber(first - 1)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	validators := api.istanbul.GetValidators(header.Number, header.Hash())
+	for _, val := range validators {
+		c.get(val.Address())
+	}
+	if err := api.scanActivity(c, first, last); err != nil {
+		return nil, err
+	}
+
+	// the uptime entries are indexed by validator set position
+	if accumulated := store.New(api.istanbul.db).ReadAccumulatedEpochUptime(epoch); accumulated != nil && len(accumulated.Entries) >= len(validators) {
+		for i, val := range validators {
+			entry := accumulated.Entries[i]
+			a := c.activity[val.Address()]
+			a.UpBlocks, a.LastSignedBlock = &entry.UpBlocks, &entry.LastSignedBlock
+		}
+	}
+
+	activity := &EpochActivity{Epoch: epoch, FirstBlock: first, LastBlock: last}
+	for _, address := range c.order {
+		activity.Validators = append(activity.Validators, c.activity[address])
+	}
+	return activity, nil
+}
+
+// GetSignerHistory retrieves the signed and missed blocks, the longest miss streak and the
+// proposals of the signer between the blocks it was a validator of in the range.
+func (api *API) GetSignerHistory(address common.Address, fromBlock, toBlock rpc.BlockNumber) (*SignerHistory, error) {
+	signed, err := api.lastSignedBlock()
+	if err != nil {
+		return nil, err
+	}
+	from, err := api.getHeaderByNumber(&fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.getHeaderByNumber(&toBlock)
+	if err != nil {
+		return nil, err
+	}
+	start, end := from.Number.Uint64(), to.Number.Uint64()
+	if start == 0 {
+		start = 1
+	}
+	if end > signed {
+		end = signed
+	}
+	if start > end {
+		return nil, errInvalidRange
+	}
+	if end-start+1 > maxActivityBlocks {
+		return nil, errTooManyBlocks
+	}
+
+	c := newActivityCounter()
+	c.recordFor = address
+	if err := api.scanActivity(c, start, end); err != nil {
+		return nil, err
+	}
+	a, ok := c.activity[address]
+	if !ok {
+		a = &ValidatorActivity{Address: address}
+	}
+	history := &SignerHistory{ValidatorActivity: a, FromBlock: start, ToBlock: end, MissedBlocks: a.missedBlocks}
+	if history.MissedBlocks == nil {
+		history.MissedBlocks = []uint64{}
+	}
+	return history, nil
+}
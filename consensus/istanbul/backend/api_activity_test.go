@@ -0,0 +1,204 @@
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+)
+
+func TestActivityCounter(t *testing.T) {
+	var (
+		v1 = common.HexToAddress("0x11")
+		v2 = common.HexToAddress("0x12")
+		v3 = common.HexToAddress("0x13")
+	)
+	validators := []istanbul.Validator{
+		validator.New(v1, blscrypto.SerializedPublicKey{}),
+		validator.New(v2, blscrypto.SerializedPublicKey{}),
+		validator.New(v3, blscrypto.SerializedPublicKey{}),
+	}
+	blocks := []struct {
+		bitmap   int64
+		proposer common.Address
+	}{
+		{7, v1}, // 111
+		{5, v2}, // 101
+		{5, v3}, // 101
+		{3, v1}, // 011
+		{5, v2}, // 101
+		{7, v3}, // 111
+	}
+
+	c := newActivityCounter()
+	c.recordFor = v2
+	for i, b := range blocks {
+		c.add(uint64(i+1), validators, big.NewInt(b.bitmap), b.proposer)
+	}
+
+	want := []ValidatorActivity{
+		{Address: v1, Signed: 6, Proposed: 2},
+		{Address: v2, Signed: 3, Missed: 3, LongestMissStreak: 2, Proposed: 2, missedBlocks: []uint64{2, 3, 5}},
+		{Address: v3, Signed: 5, Missed: 1, LongestMissStreak: 1, Proposed: 2},
+	}
+	if !reflect.DeepEqual(c.order, []common.Address{v1, v2, v3}) {
+		t.Fatalf("validator order %v", c.order)
+	}
+	for _, w := range want {
+		a := *c.activity[w.Address]
+		a.streak = 0
+		if !reflect.DeepEqual(a, w) {
+			t.Errorf("activity of %x: got %+v, expected %+v", w.Address, a, w)
+		}
+	}
+}
+
+// activityChain is a mock chain whose head is its latest header
+type activityChain struct {
+	*mockBlockchain
+	head *types.Header
+}
+
+func (bc *activityChain) CurrentHeader() *types.Header {
+	return bc.head
+}
+
+func (bc *activityChain) NewEVMRunnerForCurrentBlock() (vm.EVMRunner, error) {
+	return nil, errors.New("no state in the mock chain")
+}
+
+func (bc *activityChain) NewEVMRunner(header *types.Header, state types.StateDB) vm.EVMRunner {
+	return nil
+}
+
+func TestGetActivity(t *testing.T) {
+	accounts := newTesterAccountPool()
+	a, b, c := accounts.address("A"), accounts.address("B"), accounts.address("C")
+
+	// the genesis validators are A and B, the last block of the first epoch replaces B by C
+	extra := func(added []common.Address, removed, parentSeal int64) []byte {
+		payload, err := rlp.EncodeToBytes(&types.IstanbulExtra{
+			AddedValidators:             added,
+			AddedValidatorsPublicKeys:   make([]blscrypto.SerializedPublicKey, len(added)),
+			AddedValidatorsG1PublicKeys: make([]blscrypto.SerializedG1PublicKey, len(added)),
+			RemovedValidators:           big.NewInt(removed),
+			Seal:                        []byte{},
+			AggregatedSeal:              types.IstanbulAggregatedSeal{},
+			ParentAggregatedSeal:        types.IstanbulAggregatedSeal{Bitmap: big.NewInt(parentSeal), Signature: []byte{}, Round: common.Big0},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(bytes.Repeat([]byte{0x00}, types.IstanbulExtraVanity), payload...)
+	}
+	blocks := []struct {
+		proposer   string
+		parentSeal int64 // the signers of the parent block
+	}{
+		{"A", 0}, // 1
+		{"B", 3}, // 2
+		{"A", 1}, // 3
+		{"A", 1}, // 4, last block of epoch 1
+		{"C", 3}, // 5
+		{"A", 2}, // 6
+		{"C", 3}, // 7
+		{"C", 2}, // 8, last block of epoch 2
+		{"A", 2}, // 9
+	}
+	chain := &activityChain{mockBlockchain: &mockBlockchain{headers: make(map[uint64]*types.Header)}}
+	parent := &types.Header{Number: common.Big0, Extra: extra([]common.Address{a, b}, 0, 0)}
+	chain.AddHeader(0, parent)
+	for i, block := range blocks {
+		header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(int64(i + 1)), Time: uint64(i + 1)}
+		if i+1 == 4 {
+			header.Extra = extra([]common.Address{c}, 2, block.parentSeal)
+		} else {
+			header.Extra = extra(nil, 0, block.parentSeal)
+		}
+		accounts.sign(header, block.proposer)
+		chain.AddHeader(header.Number.Uint64(), header)
+		parent = header
+	}
+	chain.head = parent
+
+	config := *istanbul.DefaultConfig
+	config.Epoch = 4
+	config.ReplicaStateDBPath = ""
+	config.ValidatorEnodeDBPath = ""
+	config.VersionCertificateDBPath = ""
+	config.RoundStateDBPath = ""
+	engine := New(&config, rawdb.NewMemoryDatabase()).(*Backend)
+	engine.chain = chain
+	api := &API{chain: chain, istanbul: engine}
+
+	// the uptime entries of the second epoch are joined by validator set position
+	store.New(engine.db).WriteAccumulatedEpochUptime(2, &uptime.Uptime{
+		LatestBlock: 8,
+		Entries:     []uptime.UptimeEntry{{UpBlocks: 1, LastSignedBlock: 6}, {UpBlocks: 4, LastSignedBlock: 8}},
+	})
+
+	type activity struct {
+		Address                                     common.Address
+		Signed, Missed, LongestMissStreak, Proposed uint64
+		UpBlocks, LastSignedBlock                   uint64
+		monitored                                   bool
+	}
+	check := func(epoch uint64, first, last uint64, want []activity) {
+		have, err := api.GetActivity(epoch)
+		if err != nil {
+			t.Fatalf("epoch %d: %v", epoch, err)
+		}
+		if have.FirstBlock != first || have.LastBlock != last {
+			t.Errorf("epoch %d: block range mismatch: have %d-%d, want %d-%d", epoch, have.FirstBlock, have.LastBlock, first, last)
+		}
+		if len(have.Validators) != len(want) {
+			t.Fatalf("epoch %d: validator count mismatch: have %d, want %d", epoch, len(have.Validators), len(want))
+		}
+		for i, v := range have.Validators {
+			got := activity{Address: v.Address, Signed: v.Signed, Missed: v.Missed, LongestMissStreak: v.LongestMissStreak, Proposed: v.Proposed}
+			if v.UpBlocks != nil {
+				got.UpBlocks, got.LastSignedBlock, got.monitored = *v.UpBlocks, *v.LastSignedBlock, true
+			}
+			if got != want[i] {
+				t.Errorf("epoch %d, validator %d: activity mismatch: have %+v, want %+v", epoch, i, got, want[i])
+			}
+		}
+	}
+	check(1, 1, 4, []activity{
+		{Address: a, Signed: 4, Proposed: 3},
+		{Address: b, Signed: 2, Missed: 2, LongestMissStreak: 2, Proposed: 1},
+	})
+	check(2, 5, 8, []activity{
+		{Address: a, Signed: 1, Missed: 3, LongestMissStreak: 2, Proposed: 1, UpBlocks: 1, LastSignedBlock: 6, monitored: true},
+		{Address: c, Signed: 4, Proposed: 3, UpBlocks: 4, LastSignedBlock: 8, monitored: true},
+	})
+	if _, err := api.GetActivity(3); err != errEpochNotSigned {
+		t.Errorf("activity of an unsigned epoch: err = %v", err)
+	}
+
+	// the replaced validator is only accounted over the epoch it was in the set
+	history, err := api.GetSignerHistory(b, rpc.EarliestBlockNumber, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.FromBlock != 1 || history.ToBlock != 8 {
+		t.Errorf("history range mismatch: have %d-%d, want 1-8", history.FromBlock, history.ToBlock)
+	}
+	if history.Signed != 2 || history.Missed != 2 || !reflect.DeepEqual(history.MissedBlocks, []uint64{2, 3}) {
+		t.Errorf("history mismatch: have signed %d, missed %v, want signed 2, missed [2 3]", history.Signed, history.MissedBlocks)
+	}
+}
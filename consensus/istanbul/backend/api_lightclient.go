@@ -0,0 +1,640 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mapprotocol/atlas/consensus"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// maxLightClientEpochs is the largest number of validator set transitions returned
+// in a single proof
+const maxLightClientEpochs = 128
+
+var (
+	errGenesisProof        = errors.New("the genesis block has no aggregated seal")
+	errInvalidTrustedEpoch = errors.New("trusted epoch must be between 1 and the epoch of the block")
+	errTooManyEpochs       = fmt.Errorf("more than %d epochs after the trusted epoch", maxLightClientEpochs)
+	errNoSigners           = errors.New("empty aggregated seal bitmap")
+)
+
+// The ABI encodings follow the structures of the MAP light clients deployed on the
+// other chains:
+/*
+	struct BlockHeader {
+		bytes parentHash;
+		address coinbase;
+		bytes root;
+		bytes txHash;
+		bytes receiptHash;
+		bytes bloom;
+		uint256 number;
+		uint256 gasLimit;
+		uint256 gasUsed;
+		uint256 time;
+		bytes extraData;
+		bytes mixDigest;
+		bytes nonce;
+		uint256 baseFee;
+		bytes mmrRoot; // after the mmr fork
+	}
+
+	struct AggregatedSeal { uint256 bitmap; bytes signature; uint256 round; }
+
+	struct IstanbulExtra {
+		address[] validators;
+		bytes[] addedPubKey;
+		bytes[] addedG1PubKey;
+		uint256 removeList;
+		bytes seal;
+		AggregatedSeal aggregatedSeal;
+		AggregatedSeal parentAggregatedSeal;
+	}
+
+	struct G2 { bytes32 xr; bytes32 xi; bytes32 yr; bytes32 yi; }
+
+	struct TxLog { address addr; bytes[] topics; bytes data; }
+
+	struct TxReceipt {
+		uint256 receiptType;
+		bytes postStateOrStatus;
+		uint256 cumulativeGasUsed;
+		bytes bloom;
+		TxLog[] logs;
+	}
+
+	struct ReceiptProof {
+		BlockHeader header;
+		IstanbulExtra ist;
+		G2 aggPk;
+		TxReceipt receipt;
+		bytes keyIndex;
+		bytes[] proof;
+	}
+
+	function updateBlockHeader(BlockHeader memory header, IstanbulExtra memory ist, G2 memory aggPk) external;
+	function verifyProofData(bytes memory receiptProof) external; // abi.encode(ReceiptProof)
+*/
+// The light clients following the chain after the mmr fork hash the headers with the
+// mmr root, the headers before it are encoded without the field.
+
+func abiComponent(name, typ string, components ...abi.ArgumentMarshaling) abi.ArgumentMarshaling {
+	return abi.ArgumentMarshaling{Name: name, Type: typ, Components: components}
+}
+
+func mustNewTupleType(components ...abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType("tuple", "", components)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+var (
+	abiBlockHeaderComponents = []abi.ArgumentMarshaling{
+		abiComponent("parentHash", "bytes"),
+		abiComponent("coinbase", "address"),
+		abiComponent("root", "bytes"),
+		abiComponent("txHash", "bytes"),
+		abiComponent("receiptHash", "bytes"),
+		abiComponent("bloom", "bytes"),
+		abiComponent("number", "uint256"),
+		abiComponent("gasLimit", "uint256"),
+		abiComponent("gasUsed", "uint256"),
+		abiComponent("time", "uint256"),
+		abiComponent("extraData", "bytes"),
+		abiComponent("mixDigest", "bytes"),
+		abiComponent("nonce", "bytes"),
+		abiComponent("baseFee", "uint256"),
+	}
+	abiBlockHeaderMmrComponents = append(append([]abi.ArgumentMarshaling{}, abiBlockHeaderComponents...),
+		abiComponent("mmrRoot", "bytes"),
+	)
+	abiAggregatedSealComponents = []abi.ArgumentMarshaling{
+		abiComponent("bitmap", "uint256"),
+		abiComponent("signature", "bytes"),
+		abiComponent("round", "uint256"),
+	}
+	abiIstanbulExtraComponents = []abi.ArgumentMarshaling{
+		abiComponent("validators", "address[]"),
+		abiComponent("addedPubKey", "bytes[]"),
+		abiComponent("addedG1PubKey", "bytes[]"),
+		abiComponent("removeList", "uint256"),
+		abiComponent("seal", "bytes"),
+		abiComponent("aggregatedSeal", "tuple", abiAggregatedSealComponents...),
+		abiComponent("parentAggregatedSeal", "tuple", abiAggregatedSealComponents...),
+	}
+	abiG2Components = []abi.ArgumentMarshaling{
+		abiComponent("xr", "bytes32"),
+		abiComponent("xi", "bytes32"),
+		abiComponent("yr", "bytes32"),
+		abiComponent("yi", "bytes32"),
+	}
+	abiTxReceiptComponents = []abi.ArgumentMarshaling{
+		abiComponent("receiptType", "uint256"),
+		abiComponent("postStateOrStatus", "bytes"),
+		abiComponent("cumulativeGasUsed", "uint256"),
+		abiComponent("bloom", "bytes"),
+		abiComponent("logs", "tuple[]",
+			abiComponent("addr", "address"),
+			abiComponent("topics", "bytes[]"),
+			abiComponent("data", "bytes"),
+		),
+	}
+
+	// lightClientHeaderArgs are the arguments of updateBlockHeader
+	lightClientHeaderArgs    = newLightClientHeaderArgs(abiBlockHeaderComponents)
+	lightClientHeaderMmrArgs = newLightClientHeaderArgs(abiBlockHeaderMmrComponents)
+	// lightClientReceiptProofArgs is the ReceiptProof decoded by verifyProofData
+	lightClientReceiptProofArgs    = newLightClientReceiptProofArgs(abiBlockHeaderComponents)
+	lightClientReceiptProofMmrArgs = newLightClientReceiptProofArgs(abiBlockHeaderMmrComponents)
+)
+
+func newLightClientHeaderArgs(header []abi.ArgumentMarshaling) abi.Arguments {
+	return abi.Arguments{
+		{Name: "header", Type: mustNewTupleType(header...)},
+		{Name: "ist", Type: mustNewTupleType(abiIstanbulExtraComponents...)},
+		{Name: "aggPk", Type: mustNewTupleType(abiG2Components...)},
+	}
+}
+
+func newLightClientReceiptProofArgs(header []abi.ArgumentMarshaling) abi.Arguments {
+	return abi.Arguments{
+		{Name: "receiptProof", Type: mustNewTupleType(
+			abiComponent("header", "tuple", header...),
+			abiComponent("ist", "tuple", abiIstanbulExtraComponents...),
+			abiComponent("aggPk", "tuple", abiG2Components...),
+			abiComponent("receipt", "tuple", abiTxReceiptComponents...),
+			abiComponent("keyIndex", "bytes"),
+			abiComponent("proof", "bytes[]"),
+		)},
+	}
+}
+
+// lightClientArgs returns the arguments of updateBlockHeader and the ReceiptProof
+// encoding the header of the given number
+func lightClientArgs(config *params.ChainConfig, number *big.Int) (abi.Arguments, abi.Arguments) {
+	if config.IsMmr(number) {
+		return lightClientHeaderMmrArgs, lightClientReceiptProofMmrArgs
+	}
+	return lightClientHeaderArgs, lightClientReceiptProofArgs
+}
+
+type abiBlockHeader struct {
+	ParentHash  []byte
+	Coinbase    common.Address
+	Root        []byte
+	TxHash      []byte
+	ReceiptHash []byte
+	Bloom       []byte
+	Number      *big.Int
+	GasLimit    *big.Int
+	GasUsed     *big.Int
+	Time        *big.Int
+	ExtraData   []byte
+	MixDigest   []byte
+	Nonce       []byte
+	BaseFee     *big.Int
+	MmrRoot     []byte
+}
+
+type abiAggregatedSeal struct {
+	Bitmap    *big.Int
+	Signature []byte
+	Round     *big.Int
+}
+
+type abiIstanbulExtra struct {
+	Validators           []common.Address
+	AddedPubKey          [][]byte
+	AddedG1PubKey        [][]byte
+	RemoveList           *big.Int
+	Seal                 []byte
+	AggregatedSeal       abiAggregatedSeal
+	ParentAggregatedSeal abiAggregatedSeal
+}
+
+type abiG2 struct {
+	Xr [32]byte
+	Xi [32]byte
+	Yr [32]byte
+	Yi [32]byte
+}
+
+type abiTxLog struct {
+	Addr   common.Address
+	Topics [][]byte
+	Data   []byte
+}
+
+type abiTxReceipt struct {
+	ReceiptType       *big.Int
+	PostStateOrStatus []byte
+	CumulativeGasUsed *big.Int
+	Bloom             []byte
+	Logs              []abiTxLog
+}
+
+type abiReceiptProof struct {
+	Header   abiBlockHeader
+	Ist      abiIstanbulExtra
+	AggPk    abiG2
+	Receipt  abiTxReceipt
+	KeyIndex []byte
+	Proof    [][]byte
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b
+}
+
+func toABIBlockHeader(h *types.Header) abiBlockHeader {
+	header := abiBlockHeader{
+		ParentHash:  h.ParentHash.Bytes(),
+		Coinbase:    h.Coinbase,
+		Root:        h.Root.Bytes(),
+		TxHash:      h.TxHash.Bytes(),
+		ReceiptHash: h.ReceiptHash.Bytes(),
+		Bloom:       h.Bloom.Bytes(),
+		Number:      bigOrZero(h.Number),
+		GasLimit:    new(big.Int).SetUint64(h.GasLimit),
+		GasUsed:     new(big.Int).SetUint64(h.GasUsed),
+		Time:        new(big.Int).SetUint64(h.Time),
+		ExtraData:   h.Extra,
+		MixDigest:   h.MixDigest.Bytes(),
+		Nonce:       h.Nonce[:],
+		BaseFee:     bigOrZero(h.BaseFee),
+		MmrRoot:     []byte{},
+	}
+	if h.MmrRoot != nil {
+		header.MmrRoot = h.MmrRoot.Bytes()
+	}
+	return header
+}
+
+func toABIAggregatedSeal(seal types.IstanbulAggregatedSeal) abiAggregatedSeal {
+	return abiAggregatedSeal{
+		Bitmap:    bigOrZero(seal.Bitmap),
+		Signature: seal.Signature,
+		Round:     bigOrZero(seal.Round),
+	}
+}
+
+func toABIIstanbulExtra(extra *types.IstanbulExtra) abiIstanbulExtra {
+	ist := abiIstanbulExtra{
+		Validators:           extra.AddedValidators,
+		AddedPubKey:          make([][]byte, 0, len(extra.AddedValidatorsPublicKeys)),
+		AddedG1PubKey:        make([][]byte, 0, len(extra.AddedValidatorsG1PublicKeys)),
+		RemoveList:           bigOrZero(extra.RemovedValidators),
+		Seal:                 extra.Seal,
+		AggregatedSeal:       toABIAggregatedSeal(extra.AggregatedSeal),
+		ParentAggregatedSeal: toABIAggregatedSeal(extra.ParentAggregatedSeal),
+	}
+	if ist.Validators == nil {
+		ist.Validators = []common.Address{}
+	}
+	for _, pk := range extra.AddedValidatorsPublicKeys {
+		ist.AddedPubKey = append(ist.AddedPubKey, common.CopyBytes(pk[:]))
+	}
+	for _, pk := range extra.AddedValidatorsG1PublicKeys {
+		ist.AddedG1PubKey = append(ist.AddedG1PubKey, common.CopyBytes(pk[:]))
+	}
+	return ist
+}
+
+// toABIG2 splits a marshalled G2 point, whose coordinates are serialized with the
+// imaginary part first, into the coordinates of the light clients
+func toABIG2(pk []byte) abiG2 {
+	var g2 abiG2
+	copy(g2.Xi[:], pk[0:32])
+	copy(g2.Xr[:], pk[32:64])
+	copy(g2.Yi[:], pk[64:96])
+	copy(g2.Yr[:], pk[96:128])
+	return g2
+}
+
+func toABITxReceipt(r *types.Receipt) abiTxReceipt {
+	receipt := abiTxReceipt{
+		ReceiptType:       new(big.Int).SetUint64(uint64(r.Type)),
+		PostStateOrStatus: r.PostState,
+		CumulativeGasUsed: new(big.Int).SetUint64(r.CumulativeGasUsed),
+		Bloom:             r.Bloom.Bytes(),
+		Logs:              make([]abiTxLog, 0, len(r.Logs)),
+	}
+	if len(r.PostState) == 0 {
+		if r.Status == types.ReceiptStatusSuccessful {
+			receipt.PostStateOrStatus = []byte{0x01}
+		} else {
+			receipt.PostStateOrStatus = []byte{}
+		}
+	}
+	for _, l := range r.Logs {
+		topics := make([][]byte, 0, len(l.Topics))
+		for _, topic := range l.Topics {
+			topics = append(topics, topic.Bytes())
+		}
+		receipt.Logs = append(receipt.Logs, abiTxLog{Addr: l.Address, Topics: topics, Data: l.Data})
+	}
+	return receipt
+}
+
+// receiptProof returns the key of the receipt with the given index in the receipt
+// trie and the trie nodes proving it against root
+func receiptProof(receipts types.Receipts, index int, root common.Hash) ([]byte, [][]byte, error) {
+	hash, key, proof, err := types.DeriveProof(receipts, index)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hash != root {
+		return nil, nil, fmt.Errorf("receipt root mismatch: have %x, want %x", hash, root)
+	}
+	return key, proof, nil
+}
+
+// LightClientValidator is a validator with its BLS keys on both curves
+type LightClientValidator struct {
+	Address  common.Address `json:"address"`
+	G1PubKey hexutil.Bytes  `json:"g1PubKey"`
+	G2PubKey hexutil.Bytes  `json:"g2PubKey"`
+}
+
+// LightClientEpoch is the validator set signing the blocks of an epoch
+type LightClientEpoch struct {
+	Epoch      hexutil.Uint64         `json:"epoch"`
+	Validators []LightClientValidator `json:"validators"`
+}
+
+// LightClientHeader is a header with its aggregated seal and the aggregated public key
+// of the validators who signed it
+type LightClientHeader struct {
+	Header    *types.Header `json:"header"`
+	Bitmap    *hexutil.Big  `json:"bitmap"`
+	Signature hexutil.Bytes `json:"signature"`
+	Round     *hexutil.Big  `json:"round"`
+	AggPk     hexutil.Bytes `json:"aggPk"`
+	// RLP is the encoding of the header
+	RLP hexutil.Bytes `json:"rlp"`
+	// ABI is the encoding of the (header, ist, aggPk) arguments of updateBlockHeader
+	ABI hexutil.Bytes `json:"abi"`
+}
+
+// LightClientEpochTransition is the last header of an epoch, which is signed by the
+// validators of the epoch and elects the validators of the next one
+type LightClientEpochTransition struct {
+	Header *LightClientHeader `json:"header"`
+	Next   *LightClientEpoch  `json:"next"`
+}
+
+// LightClientHeaderProof proves a header to a light client which trusts the
+// validator set of an epoch
+type LightClientHeaderProof struct {
+	TrustedEpoch *LightClientEpoch             `json:"trustedEpoch"`
+	Transitions  []*LightClientEpochTransition `json:"transitions"`
+	Header       *LightClientHeader            `json:"header"`
+}
+
+// LightClientReceiptProof proves the receipt of a transaction to a light client
+type LightClientReceiptProof struct {
+	*LightClientHeaderProof
+	Receipt  *types.Receipt  `json:"receipt"`
+	KeyIndex hexutil.Bytes   `json:"keyIndex"`
+	Proof    []hexutil.Bytes `json:"proof"`
+	// RLP is the encoding of [header, aggPk, receipt, keyIndex, proof]
+	RLP hexutil.Bytes `json:"rlp"`
+	// ABI is the encoding of the ReceiptProof decoded by verifyProofData
+	ABI hexutil.Bytes `json:"abi"`
+}
+
+// LightClientAPI provides the proofs the light clients of Atlas on other chains are
+// updated and verified with
+type LightClientAPI struct {
+	chain    consensus.ChainHeaderReader
+	istanbul *Backend
+}
+
+func (api *LightClientAPI) getHeaderByNumber(number rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	switch number {
+	case rpc.LatestBlockNumber:
+		header = api.chain.CurrentHeader()
+	case rpc.PendingBlockNumber:
+		return nil, fmt.Errorf("can't use pending block within istanbul")
+	case rpc.EarliestBlockNumber:
+		header = api.chain.GetHeaderByNumber(0)
+	default:
+		header = api.chain.GetHeaderByNumber(uint64(number))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// epochValidators returns the validator set of the epoch, which is elected by the
+// last block of the previous epoch
+func (api *LightClientAPI) epochValidators(epoch uint64) (*LightClientEpoch, error) {
+	header := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(epoch-1, api.istanbul.config.Epoch))
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.istanbul.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	validators := snap.validators()
+	ret := &LightClientEpoch{
+		Epoch:      hexutil.Uint64(epoch),
+		Validators: make([]LightClientValidator, 0, len(validators)),
+	}
+	for _, v := range validators {
+		ret.Validators = append(ret.Validators, LightClientValidator{
+			Address:  v.Address,
+			G1PubKey: common.CopyBytes(v.BLSG1PublicKey[:]),
+			G2PubKey: common.CopyBytes(v.BLSPublicKey[:]),
+		})
+	}
+	return ret, nil
+}
+
+// signersPublicKey aggregates the BLS public keys of the validators who signed the header
+func (api *LightClientAPI) signersPublicKey(header *types.Header, seal types.IstanbulAggregatedSeal) ([]byte, error) {
+	snap, err := api.istanbul.snapshot(api.chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pks []*blscrypto.PublicKey
+	for i, v := range snap.validators() {
+		if seal.Bitmap == nil || seal.Bitmap.Bit(i) != 1 {
+			continue
+		}
+		pk, err := blscrypto.UnmarshalPk(v.BLSPublicKey[:])
+		if err != nil {
+			return nil, err
+		}
+		pks = append(pks, pk)
+	}
+	if len(pks) == 0 {
+		return nil, errNoSigners
+	}
+	return blscrypto.AggregatePK(pks).Marshal(), nil
+}
+
+func (api *LightClientAPI) signedHeader(header *types.Header) (*LightClientHeader, *types.IstanbulExtra, error) {
+	if header.Number.Sign() == 0 {
+		return nil, nil, errGenesisProof
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	aggPk, err := api.signersPublicKey(header, extra.AggregatedSeal)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerArgs, _ := lightClientArgs(api.chain.Config(), header.Number)
+	abiEnc, err := headerArgs.Pack(toABIBlockHeader(header), toABIIstanbulExtra(extra), toABIG2(aggPk))
+	if err != nil {
+		return nil, nil, err
+	}
+	return &LightClientHeader{
+		Header:    header,
+		Bitmap:    (*hexutil.Big)(bigOrZero(extra.AggregatedSeal.Bitmap)),
+		Signature: extra.AggregatedSeal.Signature,
+		Round:     (*hexutil.Big)(bigOrZero(extra.AggregatedSeal.Round)),
+		AggPk:     aggPk,
+		RLP:       enc,
+		ABI:       abiEnc,
+	}, extra, nil
+}
+
+func (api *LightClientAPI) headerProof(header *types.Header, trustedEpoch uint64) (*LightClientHeaderProof, *types.IstanbulExtra, error) {
+	epochSize := api.istanbul.config.Epoch
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), epochSize)
+	if trustedEpoch == 0 || trustedEpoch > epoch {
+		return nil, nil, errInvalidTrustedEpoch
+	}
+	if epoch-trustedEpoch > maxLightClientEpochs {
+		return nil, nil, errTooManyEpochs
+	}
+
+	trusted, err := api.epochValidators(trustedEpoch)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof := &LightClientHeaderProof{
+		TrustedEpoch: trusted,
+		Transitions:  make([]*LightClientEpochTransition, 0, epoch-trustedEpoch),
+	}
+	for e := trustedEpoch; e < epoch; e++ {
+		last := api.chain.GetHeaderByNumber(istanbul.GetEpochLastBlockNumber(e, epochSize))
+		if last == nil {
+			return nil, nil, errUnknownBlock
+		}
+		signed, _, err := api.signedHeader(last)
+		if err != nil {
+			return nil, nil, err
+		}
+		next, err := api.epochValidators(e + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		proof.Transitions = append(proof.Transitions, &LightClientEpochTransition{Header: signed, Next: next})
+	}
+	signed, extra, err := api.signedHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof.Header = signed
+	return proof, extra, nil
+}
+
+// GetHeaderProof retrieves the proof of the header of the given block, starting from
+// the validator set of the trusted epoch.
+func (api *LightClientAPI) GetHeaderProof(number rpc.BlockNumber, trustedEpoch uint64) (*LightClientHeaderProof, error) {
+	header, err := api.getHeaderByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	proof, _, err := api.headerProof(header, trustedEpoch)
+	return proof, err
+}
+
+// GetReceiptProof retrieves the proof of the receipt of the given transaction, starting
+// from the validator set of the trusted epoch.
+func (api *LightClientAPI) GetReceiptProof(txHash common.Hash, trustedEpoch uint64) (*LightClientReceiptProof, error) {
+	tx, blockHash, number, index := rawdb.ReadTransaction(api.istanbul.db, txHash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %x not found", txHash)
+	}
+	header := api.chain.GetHeader(blockHash, number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	receipts := rawdb.ReadReceipts(api.istanbul.db, blockHash, number, api.chain.Config())
+	if uint64(len(receipts)) <= index {
+		return nil, fmt.Errorf("receipts of block #%d not found", number)
+	}
+	receipt := receipts[index]
+
+	headerProof, extra, err := api.headerProof(header, trustedEpoch)
+	if err != nil {
+		return nil, err
+	}
+	key, nodes, err := receiptProof(receipts, int(index), header.ReceiptHash)
+	if err != nil {
+		return nil, err
+	}
+	receiptEnc, err := receipt.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	aggPk := headerProof.Header.AggPk
+	enc, err := rlp.EncodeToBytes([]interface{}{header, []byte(aggPk), receiptEnc, key, nodes})
+	if err != nil {
+		return nil, err
+	}
+	_, receiptProofArgs := lightClientArgs(api.chain.Config(), header.Number)
+	abiEnc, err := receiptProofArgs.Pack(&abiReceiptProof{
+		Header:   toABIBlockHeader(header),
+		Ist:      toABIIstanbulExtra(extra),
+		AggPk:    toABIG2(aggPk),
+		Receipt:  toABITxReceipt(receipt),
+		KeyIndex: key,
+		Proof:    nodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proof := make([]hexutil.Bytes, 0, len(nodes))
+	for _, node := range nodes {
+		proof = append(proof, node)
+	}
+	return &LightClientReceiptProof{
+		LightClientHeaderProof: headerProof,
+		Receipt:                receipt,
+		KeyIndex:               key,
+		Proof:                  proof,
+		RLP:                    enc,
+		ABI:                    abiEnc,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package backend
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+func TestLightClientReceiptProof(t *testing.T) {
+	var receipts types.Receipts
+	for i := 0; i < 20; i++ {
+		receipt := &types.Receipt{
+			Type:              types.DynamicFeeTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*types.Log{{
+				Address: common.BigToAddress(big.NewInt(int64(i))),
+				Topics:  []common.Hash{common.BigToHash(big.NewInt(int64(i)))},
+				Data:    []byte{byte(i)},
+			}},
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts = append(receipts, receipt)
+	}
+	root := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+
+	for _, index := range []int{0, 1, 7, 19} {
+		key, nodes, err := receiptProof(receipts, index, root)
+		if err != nil {
+			t.Fatalf("receipt %d: %v", index, err)
+		}
+		db := memorydb.New()
+		for _, node := range nodes {
+			db.Put(crypto.Keccak256(node), node)
+		}
+		value, err := trie.VerifyProof(root, key, db)
+		if err != nil {
+			t.Fatalf("receipt %d: invalid proof: %v", index, err)
+		}
+		want, _ := receipts[index].MarshalBinary()
+		if !bytes.Equal(value, want) {
+			t.Fatalf("receipt %d: proved %x, want %x", index, value, want)
+		}
+	}
+	if _, _, err := receiptProof(receipts, 0, common.Hash{1}); err == nil {
+		t.Fatal("proof against a wrong receipt root succeeded")
+	}
+}
+
+func TestLightClientHeaderProof(t *testing.T) {
+	chain, engine := newBlockChain(1, true)
+	defer stopEngine(engine)
+	defer chain.Stop()
+
+	block, err := engine.signBlock(makeBlockWithoutSeal(chain, engine, chain.Genesis()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := block.Header()
+	seal := types.IstanbulAggregatedSeal{
+		Bitmap:    big.NewInt(1),
+		Signature: make([]byte, types.IstanbulExtraBlsSignature),
+		Round:     big.NewInt(0),
+	}
+	if err := writeAggregatedSeal(header, seal, false); err != nil {
+		t.Fatal(err)
+	}
+	api := &LightClientAPI{chain: chain, istanbul: engine}
+
+	proof, _, err := api.headerProof(header, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Transitions) != 0 {
+		t.Fatalf("%d transitions within the trusted epoch", len(proof.Transitions))
+	}
+	validators := engine.GetValidators(common.Big0, chain.Genesis().Hash())
+	if len(proof.TrustedEpoch.Validators) != 1 || proof.TrustedEpoch.Validators[0].Address != validators[0].Address() {
+		t.Fatalf("trusted validators mismatch: %v", proof.TrustedEpoch.Validators)
+	}
+	pk := validators[0].BLSPublicKey()
+	if !bytes.Equal(proof.Header.AggPk, pk[:]) {
+		t.Fatalf("aggregated public key %x, want %x", proof.Header.AggPk, pk)
+	}
+
+	var decoded types.Header
+	if err := rlp.DecodeBytes(proof.Header.RLP, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != header.Hash() {
+		t.Fatalf("rlp header hash %x, want %x", decoded.Hash(), header.Hash())
+	}
+	unpacked, err := lightClientHeaderArgs.Unpack(proof.Header.ABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unpacked) != 3 {
+		t.Fatalf("%d unpacked arguments, want 3", len(unpacked))
+	}
+
+	// after the mmr fork the light clients hash the headers with the mmr root
+	config := *chain.Config()
+	config.MmrBlock = big.NewInt(0)
+	mmrRoot := common.HexToHash("0x01")
+	forked := types.CopyHeader(header)
+	forked.MmrRoot = &mmrRoot
+	headerArgs, receiptProofArgs := lightClientArgs(&config, forked.Number)
+	if len(receiptProofArgs[0].Type.TupleElems[0].TupleElems) != len(abiBlockHeaderMmrComponents) {
+		t.Fatal("receipt proof header without the mmr root")
+	}
+	enc, err := headerArgs.Pack(toABIBlockHeader(forked), toABIIstanbulExtra(&types.IstanbulExtra{}), toABIG2(proof.Header.AggPk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unpacked, err = headerArgs.Unpack(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abiHeader := *abi.ConvertType(unpacked[0], new(abiBlockHeader)).(*abiBlockHeader)
+	rebuilt := &types.Header{
+		ParentHash:  common.BytesToHash(abiHeader.ParentHash),
+		Coinbase:    abiHeader.Coinbase,
+		Root:        common.BytesToHash(abiHeader.Root),
+		TxHash:      common.BytesToHash(abiHeader.TxHash),
+		ReceiptHash: common.BytesToHash(abiHeader.ReceiptHash),
+		Bloom:       types.BytesToBloom(abiHeader.Bloom),
+		Number:      abiHeader.Number,
+		GasLimit:    abiHeader.GasLimit.Uint64(),
+		GasUsed:     abiHeader.GasUsed.Uint64(),
+		Time:        abiHeader.Time.Uint64(),
+		Extra:       abiHeader.ExtraData,
+		MixDigest:   common.BytesToHash(abiHeader.MixDigest),
+		BaseFee:     abiHeader.BaseFee,
+	}
+	copy(rebuilt.Nonce[:], abiHeader.Nonce)
+	root := common.BytesToHash(abiHeader.MmrRoot)
+	rebuilt.MmrRoot = &root
+	if rebuilt.Hash() != forked.Hash() {
+		t.Fatalf("abi header hash %x, want %x", rebuilt.Hash(), forked.Hash())
+	}
+	if headerArgs, _ := lightClientArgs(chain.Config(), forked.Number); len(headerArgs[0].Type.TupleElems) != len(abiBlockHeaderComponents) {
+		t.Fatal("mmr root encoded before the fork")
+	}
+
+	if _, _, err := api.headerProof(header, 2); err != errInvalidTrustedEpoch {
+		t.Fatalf("trusted epoch after the block: err = %v", err)
+	}
+	if _, err := api.GetHeaderProof(rpc.EarliestBlockNumber, 1); err == nil {
+		t.Fatal("proof of the genesis block succeeded")
+	}
+}
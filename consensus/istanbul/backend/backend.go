@@ -276,6 +276,9 @@ type Backend struct {
 	delegateSignFeed  event.Feed
 	delegateSignScope event.SubscriptionScope
 
+	validatorSetFeed  event.Feed
+	validatorSetScope event.SubscriptionScope
+
 	// Metric timer used to record block finalization times.
 	finalizationTimer metrics.Timer
 	// Metric timer used to record epoch reward distribution times.
@@ -462,6 +465,7 @@ func (sb *Backend) SelfNode() *enode.Node {
 // Close the backend
 func (sb *Backend) Close() error {
 	sb.delegateSignScope.Close()
+	sb.validatorSetScope.Close()
 	var errs []error
 	if err := sb.valEnodeTable.Close(); err != nil {
 		errs = append(errs, err)
@@ -575,11 +579,75 @@ func (sb *Backend) Commit(proposal istanbul.Proposal, aggregatedSeal types.Istan
 			return err
 		}
 	}
+
 	go sb.onNewConsensusBlock(block, result.Receipts, result.Logs, result.State)
 
 	return nil
 }
 
+// postValidatorSetChanged posts the validator set change of the block if it is the last block
+// of an epoch
+func (sb *Backend) postValidatorSetChanged(block *types.Block) {
+	if !istanbul.IsLastBlockOfEpoch(block.NumberU64(), sb.config.Epoch) {
+		return
+	}
+	changed, err := sb.newValidatorSetChangedEvent(block)
+	if err != nil {
+		sb.logger.Warn("Failed to compute the validator set change", "number", block.NumberU64(), "err", err)
+		return
+	}
+	sb.validatorSetFeed.Send(*changed)
+}
+
+// newValidatorSetChangedEvent returns the validator set change of the last block of an epoch
+func (sb *Backend) newValidatorSetChangedEvent(block *types.Block) (*istanbul.ValidatorSetChangedEvent, error) {
+	extra, err := types.ExtractIstanbulExtra(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	parentNumber := block.NumberU64() - 1
+	parentValSet := sb.getValidators(parentNumber, block.ParentHash())
+	added, removed, threshold, err := validatorSetChange(parentValSet, extra)
+	if err != nil {
+		return nil, err
+	}
+	return &istanbul.ValidatorSetChangedEvent{
+		Epoch:       istanbul.GetEpochNumber(block.NumberU64(), sb.config.Epoch) + 1,
+		BlockNumber: block.NumberU64(),
+		BlockHash:   block.Hash(),
+		Added:       added,
+		Removed:     removed,
+		Threshold:   threshold,
+	}, nil
+}
+
+// validatorSetChange applies the validator set diff of the istanbul extra to the validator set
+// and returns the added and removed validators with the quorum size of the resulting set
+func validatorSetChange(valSet istanbul.ValidatorSet, extra *types.IstanbulExtra) (added, removed []istanbul.ValidatorData, threshold int, err error) {
+	added, err = istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators, extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	removed = make([]istanbul.ValidatorData, 0)
+	for i, val := range valSet.List() {
+		if extra.RemovedValidators.Bit(i) == 1 {
+			removed = append(removed, *val.AsData())
+		}
+	}
+
+	newValSet := valSet.Copy()
+	if !newValSet.RemoveValidators(extra.RemovedValidators) || !newValSet.AddValidators(added) {
+		return nil, nil, 0, errInvalidValidatorSetDiff
+	}
+	return added, removed, newValSet.MinQuorumSize(), nil
+}
+
+// SubscribeValidatorSetChangedEvent subscribes a channel to the validator set changes of the
+// canonical epoch blocks, whether committed or synced
+func (sb *Backend) SubscribeValidatorSetChangedEvent(ch chan<- istanbul.ValidatorSetChangedEvent) event.Subscription {
+	return sb.validatorSetScope.Track(sb.validatorSetFeed.Subscribe(ch))
+}
+
 // EventMux implements istanbul.Backend.EventMux
 func (sb *Backend) EventMux() *event.TypeMux {
 	return sb.istanbulEventMux
@@ -658,6 +726,11 @@ func (sb *Backend) getNewValidatorSet(header *types.Header, state *state.StateDB
 	if err != nil {
 		return nil, err
 	}
+	if sb.chain.Config().IsSlashing(header.Number) {
+		if newValSetAddresses, err = sb.filterJailedValidators(header, state, vmRunner, newValSetAddresses); err != nil {
+			return nil, err
+		}
+	}
 	newValSet, err := validators.GetValidatorData(vmRunner, newValSetAddresses)
 	return newValSet, err
 }
@@ -17,6 +17,7 @@
 package backend
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
@@ -24,9 +25,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mapprotocol/atlas/consensus/istanbul"
 	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/rawdb"
 	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
 )
 
 func TestSign(t *testing.T) {
@@ -172,3 +176,93 @@ func TestGetProposer(t *testing.T) {
 	}
 
 }
+
+func TestValidatorSetChange(t *testing.T) {
+	vset, _ := newTestValidatorSet(4)
+	addedAddrs := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	extra := &types.IstanbulExtra{
+		AddedValidators:             addedAddrs,
+		AddedValidatorsPublicKeys:   make([]blscrypto.SerializedPublicKey, len(addedAddrs)),
+		AddedValidatorsG1PublicKeys: make([]blscrypto.SerializedG1PublicKey, len(addedAddrs)),
+		RemovedValidators:           big.NewInt(0xa), // validators 1 and 3
+	}
+
+	added, removed, threshold, err := validatorSetChange(vset, extra)
+	if err != nil {
+		t.Fatalf("error mismatch: have %v, want nil", err)
+	}
+	if len(added) != 2 || added[0].Address != addedAddrs[0] || added[1].Address != addedAddrs[1] {
+		t.Errorf("added validators mismatch: have %v, want %v", added, addedAddrs)
+	}
+	if len(removed) != 2 || removed[0].Address != vset.GetByIndex(1).Address() || removed[1].Address != vset.GetByIndex(3).Address() {
+		t.Errorf("removed validators mismatch: have %v", removed)
+	}
+	if threshold != vset.MinQuorumSize() {
+		t.Errorf("threshold mismatch: have %v, want %v", threshold, vset.MinQuorumSize())
+	}
+	if vset.Size() != 4 {
+		t.Errorf("validator set modified: have size %v, want 4", vset.Size())
+	}
+
+	// removing a validator out of the set must fail
+	extra.RemovedValidators = big.NewInt(0x10)
+	if _, _, _, err := validatorSetChange(vset, extra); err != errInvalidValidatorSetDiff {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidValidatorSetDiff)
+	}
+}
+
+func TestPostValidatorSetChanged(t *testing.T) {
+	accounts := newTesterAccountPool()
+	a, b, c := accounts.address("A"), accounts.address("B"), accounts.address("C")
+	newHeader := func(number int64, added []common.Address, removed int64) *types.Header {
+		payload, err := rlp.EncodeToBytes(&types.IstanbulExtra{
+			AddedValidators:             added,
+			AddedValidatorsPublicKeys:   make([]blscrypto.SerializedPublicKey, len(added)),
+			AddedValidatorsG1PublicKeys: make([]blscrypto.SerializedG1PublicKey, len(added)),
+			RemovedValidators:           big.NewInt(removed),
+			Seal:                        []byte{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.Header{Number: big.NewInt(number), Extra: append(bytes.Repeat([]byte{0x00}, types.IstanbulExtraVanity), payload...)}
+	}
+	genesis := newHeader(0, []common.Address{a, b}, 0)
+	chain := &activityChain{mockBlockchain: &mockBlockchain{headers: map[uint64]*types.Header{0: genesis}}}
+
+	config := *istanbul.DefaultConfig
+	config.Epoch = 4
+	config.ReplicaStateDBPath = ""
+	config.ValidatorEnodeDBPath = ""
+	config.VersionCertificateDBPath = ""
+	config.RoundStateDBPath = ""
+	engine := New(&config, rawdb.NewMemoryDatabase()).(*Backend)
+	engine.chain = chain
+
+	changes := make(chan istanbul.ValidatorSetChangedEvent, 1)
+	sub := engine.SubscribeValidatorSetChangedEvent(changes)
+	defer sub.Unsubscribe()
+
+	// blocks inside an epoch change nothing
+	engine.postValidatorSetChanged(types.NewBlockWithHeader(newHeader(3, nil, 0)))
+	select {
+	case changed := <-changes:
+		t.Fatalf("validator set change posted inside an epoch: %+v", changed)
+	default:
+	}
+
+	// the last block of the epoch replaces B by C, wherever it comes from
+	last := newHeader(4, []common.Address{c}, 2)
+	engine.postValidatorSetChanged(types.NewBlockWithHeader(last))
+	select {
+	case changed := <-changes:
+		if changed.Epoch != 2 || changed.BlockNumber != 4 || changed.BlockHash != last.Hash() {
+			t.Errorf("validator set change mismatch: have epoch %d at block %d %x, want epoch 2 at block 4 %x", changed.Epoch, changed.BlockNumber, changed.BlockHash, last.Hash())
+		}
+		if len(changed.Added) != 1 || changed.Added[0].Address != c || len(changed.Removed) != 1 || changed.Removed[0].Address != b {
+			t.Errorf("validator set diff mismatch: have added %v, removed %v", changed.Added, changed.Removed)
+		}
+	default:
+		t.Fatal("validator set change not posted for the last block of the epoch")
+	}
+}
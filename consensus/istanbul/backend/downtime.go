@@ -0,0 +1,140 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime"
+	"github.com/mapprotocol/atlas/consensus/istanbul/uptime/store"
+	"github.com/mapprotocol/atlas/contracts/accounts"
+	"github.com/mapprotocol/atlas/contracts/locked_gold"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// releaseJailedValidators releases the validators jailed up to the epoch of the header, so that
+// they may be elected for the next one. It does not depend on the uptime of the epoch and is kept
+// apart from the slashing, which fails when the uptime is missing (e.g. after a snapshot sync).
+func (sb *Backend) releaseJailedValidators(header *types.Header, state *state.StateDB) error {
+	return vm.ReleaseJailedValidators(state, istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize()))
+}
+
+// isDowntimeSlashingEpoch returns whether the downtime of the epoch of the header may be slashed.
+// The uptimes written before the slashing fork lack the missed blocks counters, so only the epochs
+// starting from the fork block are slashed.
+func isDowntimeSlashingEpoch(config *params.ChainConfig, header *types.Header, epochSize uint64) bool {
+	first := istanbul.MustGetEpochFirstBlockGivenBlockNumber(header.Number.Uint64(), epochSize)
+	return config.IsSlashing(new(big.Int).SetUint64(first))
+}
+
+// slashDowntimeValidators slashes the governance set fraction of the locked gold of the validators
+// which missed more than the allowed consecutive blocks in the epoch and jails them, so that they
+// are not elected in the next epochs. The VM address must be registered as a slasher of the
+// LockedGold contract for the slashing to succeed, the validators are jailed anyway.
+func (sb *Backend) slashDowntimeValidators(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner) error {
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
+	logger := sb.logger.New("func", "Backend.slashDowntimeValidators", "blocknum", header.Number.Uint64(), "epoch", epoch)
+
+	config, err := vm.GetDowntimeSlashingConfig(state)
+	if err != nil {
+		return err
+	}
+	if config.MaxMissedBlocks == 0 {
+		return nil
+	}
+
+	signerSet := sb.GetValidators(big.NewInt(header.Number.Int64()-1), header.ParentHash)
+	if len(signerSet) == 0 {
+		return errors.New("unable to fetch validator set to slash downtime validators")
+	}
+	monitor := uptime.NewMonitor(store.New(sb.db), sb.EpochSize(), sb.LookbackWindow(header, state))
+	down, err := monitor.DowntimeValidators(epoch, len(signerSet), config.MaxMissedBlocks)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range down {
+		signer := signerSet[d.Index].Address()
+		account, err := accounts.GetSignerToAccountMethod(vmRunner, signer)
+		if err != nil || account == (common.Address{}) {
+			logger.Error("Failed to get account of downtime validator", "signer", signer, "err", err)
+			continue
+		}
+
+		penalty := new(big.Int)
+		if locked, err := locked_gold.GetAccountTotalLockedGold(vmRunner, account); err != nil {
+			logger.Error("Failed to get locked gold of downtime validator", "account", account, "err", err)
+		} else {
+			penalty.Mul(locked, new(big.Int).SetUint64(config.SlashFraction)).Div(penalty, big.NewInt(10000))
+		}
+		if err := locked_gold.Slash(vmRunner, account, penalty); err != nil {
+			logger.Error("Failed to slash downtime validator", "account", account, "penalty", penalty, "err", err)
+			penalty = new(big.Int)
+		}
+
+		until := epoch + config.JailEpochs
+		if config.JailEpochs > 0 {
+			if err := vm.JailValidator(state, account, until); err != nil {
+				return err
+			}
+		}
+		if err := vm.AddDowntimeSlashedLog(state, header.Number.Uint64(), account, signer, epoch, d.MissedBlocks, penalty, until); err != nil {
+			return err
+		}
+		logger.Warn("Slashed downtime validator", "account", account, "signer", signer, "missedBlocks", d.MissedBlocks, "penalty", penalty, "jailedUntil", until)
+	}
+	return nil
+}
+
+// filterJailedValidators removes the signers of the jailed validator accounts from the validator
+// set elected at the end of the epoch. The elected set is kept whole if every signer is jailed.
+func (sb *Backend) filterJailedValidators(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner, signers []common.Address) ([]common.Address, error) {
+	jailed, err := vm.GetJailedValidators(state)
+	if err != nil || len(jailed) == 0 {
+		return signers, err
+	}
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
+	until := make(map[common.Address]uint64, len(jailed))
+	for _, v := range jailed {
+		until[v.Account] = v.Until
+	}
+
+	elected := make([]common.Address, 0, len(signers))
+	for _, signer := range signers {
+		account, err := accounts.GetSignerToAccountMethod(vmRunner, signer)
+		if err != nil {
+			return nil, err
+		}
+		if until[account] > epoch {
+			sb.logger.Info("Skipping jailed validator", "account", account, "signer", signer, "jailedUntil", until[account])
+			continue
+		}
+		elected = append(elected, signer)
+	}
+	if len(elected) == 0 {
+		sb.logger.Warn("All the elected validators are jailed, keeping the elected set", "epoch", epoch)
+		return signers, nil
+	}
+	return elected, nil
+}
@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func TestDowntimeSlashingEpoch(t *testing.T) {
+	config := &params.ChainConfig{SlashingBlock: big.NewInt(15)}
+	tests := []struct {
+		number uint64
+		want   bool
+	}{
+		{10, false}, // epoch started before the fork
+		{20, false}, // the fork is in the middle of the epoch
+		{30, true},
+		{40, true},
+	}
+	for _, tt := range tests {
+		header := &types.Header{Number: new(big.Int).SetUint64(tt.number)}
+		if have := isDowntimeSlashingEpoch(config, header, 10); have != tt.want {
+			t.Errorf("block %d: slashing mismatch: have %v, want %v", tt.number, have, tt.want)
+		}
+	}
+
+	// the epoch starting at the fork block is slashed
+	config.SlashingBlock = big.NewInt(11)
+	if !isDowntimeSlashingEpoch(config, &types.Header{Number: big.NewInt(20)}, 10) {
+		t.Error("epoch starting at the fork block not slashed")
+	}
+	config.SlashingBlock = nil
+	if isDowntimeSlashingEpoch(config, &types.Header{Number: big.NewInt(20)}, 10) {
+		t.Error("epoch slashed without the fork")
+	}
+}
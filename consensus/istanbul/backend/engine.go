@@ -39,6 +39,7 @@ import (
 	ethChain "github.com/mapprotocol/atlas/core/chain"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
 	blscrypto "github.com/mapprotocol/atlas/helper/bls"
 	"github.com/mapprotocol/atlas/params"
 	"golang.org/x/crypto/sha3"
@@ -486,6 +487,11 @@ func (sb *Backend) LookbackWindow(header *types.Header, state *state.StateDB) ui
 // Note: The block header and state database might be updated to reflect any
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction) {
+	sb.FinalizeWithEVMRunner(chain, header, state, txs, sb.chain.NewEVMRunner(header, state))
+}
+
+// FinalizeWithEVMRunner implements consensus.Istanbul.FinalizeWithEVMRunner
+func (sb *Backend) FinalizeWithEVMRunner(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, vmRunner vm.EVMRunner) {
 	start := time.Now()
 	defer sb.finalizationTimer.UpdateSince(start)
 
@@ -500,7 +506,6 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 	state.Prepare(common.Hash{}, len(txs))
 
 	snapshot := state.Snapshot()
-	vmRunner := sb.chain.NewEVMRunner(header, state)
 	err := sb.setInitialGoldTokenTotalSupplyIfUnset(vmRunner)
 	if err != nil {
 		state.RevertToSnapshot(snapshot)
@@ -512,12 +517,26 @@ func (sb *Backend) Finalize(chain consensus.ChainHeaderReader, header *types.Hea
 	lastBlockOfEpoch := istanbul.IsLastBlockOfEpoch(header.Number.Uint64(), sb.config.Epoch)
 	if lastBlockOfEpoch {
 		snapshot = state.Snapshot()
-		err = sb.distributeEpochRewards(header, state, chain.Config().EnableRewardBlock, chain.Config().BN256ForkBlock,
+		err = sb.distributeEpochRewards(header, state, vmRunner, chain.Config(), chain.Config().EnableRewardBlock, chain.Config().BN256ForkBlock,
 			chain.Config().DeregisterBlock)
 		if err != nil {
 			sb.logger.Error("Failed to distribute epoch rewards", "blockNumber", header.Number, "err", err)
 			state.RevertToSnapshot(snapshot)
 		}
+		if chain.Config().IsSlashing(header.Number) {
+			snapshot = state.Snapshot()
+			if err = sb.releaseJailedValidators(header, state); err != nil {
+				sb.logger.Error("Failed to release jailed validators", "blockNumber", header.Number, "err", err)
+				state.RevertToSnapshot(snapshot)
+			}
+			if isDowntimeSlashingEpoch(chain.Config(), header, sb.EpochSize()) {
+				snapshot = state.Snapshot()
+				if err = sb.slashDowntimeValidators(header, state, vmRunner); err != nil {
+					sb.logger.Error("Failed to slash downtime validators", "blockNumber", header.Number, "err", err)
+					state.RevertToSnapshot(snapshot)
+				}
+			}
+		}
 	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
@@ -607,6 +626,11 @@ func (sb *Backend) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &API{chain: chain, istanbul: sb},
 		Public:    true,
+	}, {
+		Namespace: "lightclient",
+		Version:   "1.0",
+		Service:   &LightClientAPI{chain: chain, istanbul: sb},
+		Public:    true,
 	}}
 }
 
@@ -618,6 +642,7 @@ func (sb *Backend) SetChain(chain consensus.ChainContext, currentBlock func() *t
 	if bc, ok := chain.(*ethChain.BlockChain); ok {
 		go sb.newChainHeadLoop(bc)
 		go sb.updateReplicaStateLoop(bc)
+		go sb.validatorSetChangedLoop(bc)
 	}
 
 }
@@ -627,6 +652,10 @@ func (sb *Backend) newChainHeadLoop(bc *ethChain.BlockChain) {
 	// Batched. For stats & announce
 	chainHeadCh := make(chan ethCore.ChainHeadEvent, 10)
 	chainHeadSub := bc.SubscribeChainHeadEvent(chainHeadCh)
+	if chainHeadSub == nil {
+		// The chain was stopped before the loop started
+		return
+	}
 	defer chainHeadSub.Unsubscribe()
 
 	for {
@@ -640,11 +669,37 @@ func (sb *Backend) newChainHeadLoop(bc *ethChain.BlockChain) {
 	}
 }
 
+// Loop to post the validator set changes. Listens to chain events to avoid batching, so that
+// the last blocks of the epochs are seen whether they were committed or synced.
+func (sb *Backend) validatorSetChangedLoop(bc *ethChain.BlockChain) {
+	chainEventCh := make(chan ethCore.ChainEvent, 10)
+	chainEventSub := bc.SubscribeChainEvent(chainEventCh)
+	if chainEventSub == nil {
+		// The chain was stopped before the loop started
+		return
+	}
+	defer chainEventSub.Unsubscribe()
+
+	for {
+		select {
+		case chainEvent := <-chainEventCh:
+			sb.postValidatorSetChanged(chainEvent.Block)
+		case err := <-chainEventSub.Err():
+			log.Error("Error in istanbul's subscription to the blockchain's chain event", "err", err)
+			return
+		}
+	}
+}
+
 // Loop to update replica state. Listens to chain events to avoid batching.
 func (sb *Backend) updateReplicaStateLoop(bc *ethChain.BlockChain) {
 	// Unbatched event listener
 	chainEventCh := make(chan ethCore.ChainEvent, 10)
 	chainEventSub := bc.SubscribeChainEvent(chainEventCh)
+	if chainEventSub == nil {
+		// The chain was stopped before the loop started
+		return
+	}
 	defer chainEventSub.Unsubscribe()
 
 	for {
@@ -38,14 +38,12 @@ import (
 	"time"
 )
 
-func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.StateDB,
+func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner, config *params.ChainConfig,
 	EnableRewardBlock, bn256Block, deregisterBlock *big.Int) error {
 	start := time.Now()
 	defer sb.rewardDistributionTimer.UpdateSince(start)
 	logger := sb.logger.New("func", "Backend.distributeEpochPaymentsAndRewards", "blocknum", header.Number.Uint64())
 
-	vmRunner := sb.chain.NewEVMRunner(header, state)
-
 	communityPartnerAddress, err := epoch_rewards.GetCommunityPartnerAddress(vmRunner)
 	if err != nil {
 		return err
@@ -74,7 +72,7 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 	if err != nil {
 		return err
 	}
-	uptimeRets, ignores, err := sb.updateValidatorScores(header, state, signerSet)
+	uptimeRets, ignores, err := sb.updateValidatorScores(header, state, vmRunner, signerSet)
 	if err != nil {
 		return err
 	}
@@ -84,6 +82,13 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 		if err != nil {
 			return err
 		}
+		// Reward relayers with a share of the validator rewards
+		if config.IsRelayerReward(header.Number) {
+			validatorVoterReward, err = sb.distributeRelayerRewards(header, state, vmRunner, config, validatorVoterReward)
+			if err != nil {
+				return err
+			}
+		}
 		// Reward Validators And voters
 		totalValidatorRewards, voterRewardData, err := sb.distributeValidatorRewards(vmRunner, signerSet, validators_, validatorVoterReward, scores)
 		if err != nil {
@@ -155,7 +160,7 @@ func (sb *Backend) distributeEpochRewards(header *types.Header, state *state.Sta
 	return nil
 }
 
-func (sb *Backend) updateValidatorScores(header *types.Header, state *state.StateDB, valSet []istanbul.Validator) ([]*big.Int, []bool, error) {
+func (sb *Backend) updateValidatorScores(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner, valSet []istanbul.Validator) ([]*big.Int, []bool, error) {
 	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
 	logger := sb.logger.New("func", "Backend.updateValidatorScores", "blocknum", header.Number.Uint64(), "epoch", epoch, "epochsize", sb.EpochSize())
 	ignore := make([]bool, len(valSet), len(valSet))
@@ -174,8 +179,6 @@ func (sb *Backend) updateValidatorScores(header *types.Header, state *state.Stat
 		return nil, nil, err
 	}
 
-	vmRunner := sb.chain.NewEVMRunner(header, state)
-
 	for i, val := range valSet {
 		logger.Trace("Updating validator score", "uptime", uptimes[i], "address", val.Address())
 		uptimeRet, isValidator, err := validators.UpdateValidatorScore(vmRunner, val.Address(), uptimes[i])
@@ -211,6 +214,31 @@ func (sb *Backend) distributeValidatorRewards(vmRunner vm.EVMRunner, signerSet [
 	return totalValidatorRewards, voterRewards, nil
 }
 
+// distributeRelayerRewards credits the relayers with the configured share of the validator rewards
+// proportional to the headers they got accepted in the epoch, and returns the remaining validator rewards
+func (sb *Backend) distributeRelayerRewards(header *types.Header, state *state.StateDB, vmRunner vm.EVMRunner, config *params.ChainConfig, validatorReward *big.Int) (*big.Int, error) {
+	if config.Istanbul == nil || config.Istanbul.RelayerRewardRate == 0 {
+		return validatorReward, nil
+	}
+	relayerReward := new(big.Int).SetUint64(config.Istanbul.RelayerRewardRate)
+	relayerReward.Mul(relayerReward, validatorReward).Div(relayerReward, new(big.Int).SetUint64(params.MaxRelayerRewardRate))
+
+	epoch := istanbul.GetEpochNumber(header.Number.Uint64(), sb.EpochSize())
+	distributed, err := vm.DistributeRelayerRewards(state, epoch, relayerReward)
+	if err != nil {
+		return nil, err
+	}
+	if distributed.Sign() == 0 {
+		return validatorReward, nil
+	}
+	// the rewards are claimed from the header store
+	if err := gold_token.Mint(vmRunner, params.HeaderStoreAddress, distributed); err != nil {
+		return nil, err
+	}
+	log.Info("distributeRelayerRewards", "epoch", epoch, "totalRelayerRewards", distributed.String())
+	return new(big.Int).Sub(validatorReward, distributed), nil
+}
+
 func (sb *Backend) setInitialGoldTokenTotalSupplyIfUnset(vmRunner vm.EVMRunner) error {
 	totalSupply, err := gold_token.GetTotalSupply(vmRunner)
 	if err != nil {
@@ -0,0 +1,45 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// PendingDoubleSignEvidence returns the double sign evidence detected by the core or
+// received from the other validators which wasn't processed on chain yet
+func (sb *Backend) PendingDoubleSignEvidence() []*istanbul.DoubleSignEvidence {
+	return sb.core.PendingEvidence()
+}
+
+// DiscardDoubleSignEvidence drops the double sign evidence once processed on chain
+func (sb *Backend) DiscardDoubleSignEvidence(hash common.Hash) {
+	sb.core.DiscardEvidence(hash)
+}
+
+// SignTx signs the transaction with the validator key, the proposer sends the double
+// sign evidence in transactions signed this way
+func (sb *Backend) SignTx(tx *types.Transaction, signer types.Signer) (*types.Transaction, error) {
+	sig, err := sb.wallets().Ecdsa.SignHash(signer.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
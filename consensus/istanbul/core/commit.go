@@ -191,6 +191,7 @@ func (c *core) handleCheckedCommitForCurrentSequence(msg *istanbul.Message, comm
 	if err := c.verifyCommittedSeal(commit, validator, fork, cur); err != nil {
 		return errInvalidCommittedSeal
 	}
+	c.checkDoubleSign(msg, commit.Subject)
 
 	newValSet, err := c.backend.NextBlockValidators(c.current.Proposal())
 	if err != nil {
@@ -17,7 +17,6 @@
 package core
 
 import (
-	"bytes"
 	"fmt"
 	"math"
 	"math/big"
@@ -130,6 +129,8 @@ type core struct {
 	current   RoundState
 	handlerWg *sync.WaitGroup
 
+	signedMessages signedMessages
+
 	roundChangeSet *roundChangeSet
 
 	pendingRequests   *prque.Prque
@@ -223,11 +224,7 @@ func (c *core) ForceRoundChange() {
 
 // PrepareCommittedSeal returns a committed seal for the given hash and round number.
 func PrepareCommittedSeal(hash common.Hash, round *big.Int) []byte {
-	var buf bytes.Buffer
-	buf.Write(hash.Bytes())
-	buf.Write(round.Bytes())
-	buf.Write([]byte{byte(istanbul.MsgCommit)})
-	return buf.Bytes()
+	return istanbul.PrepareCommittedSeal(hash, round)
 }
 
 // GetAggregatedSeal aggregates all the given seals for a given message set to a bls aggregated
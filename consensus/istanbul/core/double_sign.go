@@ -0,0 +1,119 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+)
+
+// A validator signs a single digest per round, PREPARE and COMMIT messages of the same
+// validator for different digests in the same round are a double sign. The evidence is
+// stored in the roundstate db and sent to the other validators, so that any proposer
+// can submit it to the slashing precompile.
+
+type signedKey struct {
+	round   uint64
+	address common.Address
+}
+
+type signedMessage struct {
+	msg    *istanbul.Message
+	digest common.Hash
+}
+
+// signedMessages keeps the first PREPARE or COMMIT message of every validator in each
+// round of the current sequence
+type signedMessages struct {
+	sequence *big.Int
+	messages map[signedKey]signedMessage
+}
+
+// add records the message signed for the subject and returns the message of the same
+// sender conflicting with it, if any
+func (s *signedMessages) add(msg *istanbul.Message, sub *istanbul.Subject) *istanbul.Message {
+	if s.sequence == nil || s.sequence.Cmp(sub.View.Sequence) != 0 {
+		s.sequence = new(big.Int).Set(sub.View.Sequence)
+		s.messages = make(map[signedKey]signedMessage)
+	}
+	key := signedKey{round: sub.View.Round.Uint64(), address: msg.Address}
+	prev, ok := s.messages[key]
+	if !ok {
+		s.messages[key] = signedMessage{msg: msg, digest: sub.Digest}
+		return nil
+	}
+	if prev.digest != sub.Digest {
+		return prev.msg
+	}
+	return nil
+}
+
+// checkDoubleSign records a PREPARE or COMMIT message of the current sequence with a
+// verified signature, and reports the double sign if its sender signed another digest
+// in the same round.
+func (c *core) checkDoubleSign(msg *istanbul.Message, sub *istanbul.Subject) {
+	prev := c.signedMessages.add(msg, sub)
+	if prev == nil {
+		return
+	}
+	evidence := istanbul.NewDoubleSignEvidence(prev, msg)
+	logger := c.newLogger("func", "checkDoubleSign", "signer", msg.Address, "msg_view", sub.View, "evidence", evidence.Hash())
+	logger.Warn("Detected double sign")
+
+	if err := c.rsdb.AddEvidence(evidence); err != nil {
+		logger.Error("Failed to store double sign evidence", "err", err)
+	}
+	c.broadcast(istanbul.NewDoubleSignEvidenceMessage(evidence, c.address))
+}
+
+// handleDoubleSignEvidence stores the double sign evidence sent by another validator
+func (c *core) handleDoubleSignEvidence(msg *istanbul.Message) error {
+	evidence := msg.DoubleSignEvidence()
+	if evidence == nil {
+		return errInvalidMessage
+	}
+	if err := evidence.Verify(); err != nil {
+		return err
+	}
+	validator := c.current.GetValidatorByAddress(evidence.Signer())
+	if validator == nil {
+		return errInvalidValidatorAddress
+	}
+	fork, cur := new(big.Int).Set(c.backend.ChainConfig().BN256ForkBlock), new(big.Int).Set(evidence.View().Sequence)
+	if err := evidence.VerifyCommittedSeals(validator.BLSPublicKey(), fork, cur); err != nil {
+		return errInvalidCommittedSeal
+	}
+	return c.rsdb.AddEvidence(evidence)
+}
+
+// PendingEvidence returns the double sign evidence which wasn't discarded yet
+func (c *core) PendingEvidence() []*istanbul.DoubleSignEvidence {
+	evidence, err := c.rsdb.GetEvidence()
+	if err != nil {
+		c.logger.Error("Failed to read double sign evidence", "err", err)
+	}
+	return evidence
+}
+
+// DiscardEvidence drops the double sign evidence once processed on chain
+func (c *core) DiscardEvidence(hash common.Hash) {
+	if err := c.rsdb.DeleteEvidence(hash); err != nil {
+		c.logger.Error("Failed to delete double sign evidence", "hash", hash, "err", err)
+	}
+}
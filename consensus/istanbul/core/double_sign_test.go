@@ -0,0 +1,94 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+)
+
+func TestSignedMessagesAdd(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	prepare := func(view *istanbul.View, digest common.Hash) (*istanbul.Message, *istanbul.Subject) {
+		sub := &istanbul.Subject{View: view, Digest: digest}
+		msg := istanbul.NewPrepareMessage(sub, addr)
+		if err := msg.Sign(func(data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), key)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return msg, sub
+	}
+
+	var s signedMessages
+	first, sub := prepare(newView(1, 0), common.HexToHash("01"))
+	if prev := s.add(first, sub); prev != nil {
+		t.Fatal("first message reported as double sign")
+	}
+	if prev := s.add(prepare(newView(1, 0), common.HexToHash("01"))); prev != nil {
+		t.Fatal("same digest reported as double sign")
+	}
+	if prev := s.add(prepare(newView(1, 1), common.HexToHash("02"))); prev != nil {
+		t.Fatal("next round reported as double sign")
+	}
+	second, sub := prepare(newView(1, 0), common.HexToHash("02"))
+	if prev := s.add(second, sub); prev != first {
+		t.Fatalf("conflicting message: have %v, want %v", prev, first)
+	}
+	if err := istanbul.NewDoubleSignEvidence(first, second).Verify(); err != nil {
+		t.Fatalf("invalid evidence: %v", err)
+	}
+
+	// A new sequence drops the messages of the previous one
+	if prev := s.add(prepare(newView(2, 0), common.HexToHash("01"))); prev != nil {
+		t.Fatal("next sequence reported as double sign")
+	}
+	if prev := s.add(prepare(newView(2, 0), common.HexToHash("03"))); prev == nil {
+		t.Fatal("double sign of the new sequence not detected")
+	}
+}
+
+func TestRSDBEvidence(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	evidenceFor := func(seq uint64) *istanbul.DoubleSignEvidence {
+		var msgs []*istanbul.Message
+		for _, digest := range []common.Hash{common.HexToHash("01"), common.HexToHash("02")} {
+			msg := istanbul.NewPrepareMessage(&istanbul.Subject{View: newView(seq, 0), Digest: digest}, addr)
+			if err := msg.Sign(func(data []byte) ([]byte, error) {
+				return crypto.Sign(crypto.Keccak256(data), key)
+			}); err != nil {
+				t.Fatal(err)
+			}
+			msgs = append(msgs, msg)
+		}
+		return istanbul.NewDoubleSignEvidence(msgs[0], msgs[1])
+	}
+
+	rsdb, _ := newRoundStateDB("", &RoundStateDBOptions{withGarbageCollector: false})
+	first, second := evidenceFor(1), evidenceFor(2)
+	finishOnError(t, rsdb.AddEvidence(first))
+	finishOnError(t, rsdb.AddEvidence(second))
+	finishOnError(t, rsdb.AddEvidence(first))
+
+	evidence, err := rsdb.GetEvidence()
+	finishOnError(t, err)
+	if len(evidence) != 2 {
+		t.Fatalf("%d evidence stored, want 2", len(evidence))
+	}
+	for _, e := range evidence {
+		if err := e.Verify(); err != nil {
+			t.Fatalf("stored evidence %x: %v", e.Hash(), err)
+		}
+	}
+
+	finishOnError(t, rsdb.DeleteEvidence(first.Hash()))
+	evidence, err = rsdb.GetEvidence()
+	finishOnError(t, err)
+	if len(evidence) != 1 || evidence[0].Hash() != second.Hash() {
+		t.Fatalf("evidence after delete: %v", evidence)
+	}
+}
@@ -203,6 +203,8 @@ func (c *core) handleCheckedMsg(msg *istanbul.Message, src istanbul.Validator) e
 		return catchFutureMessages(c.handleCommit(msg))
 	case istanbul.MsgRoundChange:
 		return catchFutureMessages(c.handleRoundChange(msg))
+	case istanbul.MsgDoubleSignEvidence:
+		return c.handleDoubleSignEvidence(msg)
 	default:
 		logger.Error("Invalid message", "m", msg)
 	}
@@ -39,6 +39,7 @@ const (
 	dbVersionKey = "version"  // Version of the database to flush if changes
 	lastViewKey  = "lastView" // Last View that we know of
 	rsKey        = "rs"       // Database Key Pefix for RoundState
+	evidenceKey  = "ev"       // Database Key Prefix for double sign evidence
 )
 
 type RoundStateDB interface {
@@ -48,6 +49,12 @@ type RoundStateDB interface {
 	GetOldestValidView() (*istanbul.View, error)
 	GetRoundStateFor(view *istanbul.View) (RoundState, error)
 	UpdateLastRoundState(rs RoundState) error
+	// AddEvidence stores double sign evidence until it is processed on chain
+	AddEvidence(evidence *istanbul.DoubleSignEvidence) error
+	// GetEvidence returns all the stored double sign evidence
+	GetEvidence() ([]*istanbul.DoubleSignEvidence, error)
+	// DeleteEvidence removes the double sign evidence with the given hash
+	DeleteEvidence(hash common.Hash) error
 	Close() error
 }
 
@@ -229,6 +236,33 @@ func (rsdb *roundStateDBImpl) GetRoundStateFor(view *istanbul.View) (RoundState,
 	return &entry, nil
 }
 
+func (rsdb *roundStateDBImpl) AddEvidence(evidence *istanbul.DoubleSignEvidence) error {
+	entryBytes, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		return err
+	}
+	return rsdb.db.Put(evidence2Key(evidence.Hash()), entryBytes, nil)
+}
+
+func (rsdb *roundStateDBImpl) GetEvidence() ([]*istanbul.DoubleSignEvidence, error) {
+	iter := rsdb.db.NewIterator(util.BytesPrefix([]byte(evidenceKey)), nil)
+	defer iter.Release()
+
+	var evidence []*istanbul.DoubleSignEvidence
+	for iter.Next() {
+		var entry istanbul.DoubleSignEvidence
+		if err := rlp.DecodeBytes(iter.Value(), &entry); err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, &entry)
+	}
+	return evidence, iter.Error()
+}
+
+func (rsdb *roundStateDBImpl) DeleteEvidence(hash common.Hash) error {
+	return rsdb.db.Delete(evidence2Key(hash), nil)
+}
+
 func (rsdb *roundStateDBImpl) Close() error {
 	if rsdb.opts.withGarbageCollector {
 		rsdb.stopGarbageCollector()
@@ -304,6 +338,10 @@ func key2View(key []byte) *istanbul.View {
 	}
 }
 
+func evidence2Key(hash common.Hash) []byte {
+	return append([]byte(evidenceKey), hash.Bytes()...)
+}
+
 type StopFn func()
 
 func RunTaskRepeateadly(task func(), period time.Duration) StopFn {
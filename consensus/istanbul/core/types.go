@@ -34,6 +34,10 @@ type Engine interface {
 	ParentCommits() MessageSet
 	// ForceRoundChange will force round change to the current desiredRound + 1
 	ForceRoundChange()
+	// PendingEvidence returns the double sign evidence which wasn't discarded yet
+	PendingEvidence() []*istanbul.DoubleSignEvidence
+	// DiscardEvidence drops the double sign evidence once processed on chain
+	DiscardEvidence(hash common.Hash)
 }
 
 // State represents the IBFT state
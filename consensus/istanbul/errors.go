@@ -44,4 +44,12 @@ var (
 	ErrValidatorNotProxied = errors.New("validator not proxied")
 	// ErrInvalidEnodeCertMsgMapOldVersion is returned if a validator sends old enode certificate message
 	ErrInvalidEnodeCertMsgMapOldVersion = errors.New("invalid enode certificate message map because of old version")
+	// ErrInvalidEvidenceMessage is returned if a double sign evidence doesn't hold two PREPARE or COMMIT messages
+	ErrInvalidEvidenceMessage = errors.New("evidence message is not a prepare or commit")
+	// ErrEvidenceSignerMismatch is returned if the messages of a double sign evidence are from different signers
+	ErrEvidenceSignerMismatch = errors.New("evidence messages from different signers")
+	// ErrEvidenceViewMismatch is returned if the messages of a double sign evidence are for different views
+	ErrEvidenceViewMismatch = errors.New("evidence messages for different views")
+	// ErrEvidenceSameDigest is returned if the messages of a double sign evidence don't conflict
+	ErrEvidenceSameDigest = errors.New("evidence messages for the same digest")
 )
@@ -16,7 +16,10 @@
 
 package istanbul
 
-import "github.com/ethereum/go-ethereum/p2p/enode"
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
 
 // RequestEvent is posted to propose a proposal
 type RequestEvent struct {
@@ -37,3 +40,17 @@ type MessageWithPeerIDEvent struct {
 // FinalCommittedEvent is posted when a proposal is committed
 type FinalCommittedEvent struct {
 }
+
+// ValidatorSetChangedEvent is posted when the last block of an epoch is inserted in the
+// canonical chain, with the validators added to and removed from the validator set of the
+// next epoch
+type ValidatorSetChangedEvent struct {
+	// Epoch is the epoch the new validator set is elected for
+	Epoch       uint64
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Added       []ValidatorData
+	Removed     []ValidatorData
+	// Threshold is the quorum size of the new validator set
+	Threshold int
+}
@@ -17,6 +17,7 @@
 package istanbul
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"fmt"
 	"github.com/ethereum/go-ethereum/log"
@@ -506,6 +507,104 @@ type CommittedSubject struct {
 	EpochValidatorSetSeal []byte
 }
 
+// ## DoubleSignEvidence #################################################################
+
+// NewDoubleSignEvidenceMessage constructs a Message instance with the given sender and
+// evidence. Both the evidence instance and the serialized bytes of evidence are part of
+// the returned Message.
+func NewDoubleSignEvidenceMessage(evidence *DoubleSignEvidence, sender common.Address) *Message {
+	message := &Message{
+		Address:            sender,
+		Code:               MsgDoubleSignEvidence,
+		doubleSignEvidence: evidence,
+	}
+	setMessageBytes(message, evidence)
+	return message
+}
+
+// DoubleSignEvidence holds two PREPARE or COMMIT messages signed by the same validator
+// for the same sequence and round, but for different digests.
+type DoubleSignEvidence struct {
+	First  *Message
+	Second *Message
+}
+
+// NewDoubleSignEvidence returns the evidence of the two conflicting messages. The
+// messages are ordered by digest, so the evidence of a pair of messages is unique.
+func NewDoubleSignEvidence(a, b *Message) *DoubleSignEvidence {
+	if sa, sb := a.subject(), b.subject(); sa != nil && sb != nil && bytes.Compare(sa.Digest[:], sb.Digest[:]) > 0 {
+		a, b = b, a
+	}
+	return &DoubleSignEvidence{First: a, Second: b}
+}
+
+// Hash returns the hash identifying the evidence
+func (e *DoubleSignEvidence) Hash() common.Hash {
+	return RLPHash(e)
+}
+
+// Signer returns the address of the validator which signed both messages
+func (e *DoubleSignEvidence) Signer() common.Address {
+	return e.First.Address
+}
+
+// View returns the view both messages were signed for
+func (e *DoubleSignEvidence) View() *View {
+	return e.First.subject().View
+}
+
+// Verify checks that the evidence holds two conflicting messages signed by the same
+// signer. It doesn't check the committed seals, see VerifyCommittedSeals.
+func (e *DoubleSignEvidence) Verify() error {
+	if e.First == nil || e.Second == nil {
+		return ErrInvalidEvidenceMessage
+	}
+	first, second := e.First.subject(), e.Second.subject()
+	if first == nil || second == nil || first.View == nil || second.View == nil {
+		return ErrInvalidEvidenceMessage
+	}
+	if e.First.Address != e.Second.Address {
+		return ErrEvidenceSignerMismatch
+	}
+	if first.View.Sequence == nil || first.View.Round == nil || second.View.Sequence == nil || second.View.Round == nil ||
+		first.View.Cmp(second.View) != 0 {
+		return ErrEvidenceViewMismatch
+	}
+	if first.Digest == second.Digest {
+		return ErrEvidenceSameDigest
+	}
+	for _, msg := range []*Message{e.First, e.Second} {
+		data, err := msg.PayloadNoSig()
+		if err != nil {
+			return err
+		}
+		signer, err := GetSignatureAddress(data, msg.Signature)
+		if err != nil {
+			return err
+		}
+		if signer != msg.Address {
+			return ErrInvalidSigner
+		}
+	}
+	return nil
+}
+
+// VerifyCommittedSeals checks the committed seals of the COMMIT messages of the evidence
+// against the BLS public key of the signer.
+func (e *DoubleSignEvidence) VerifyCommittedSeals(pubKey blscrypto.SerializedPublicKey, fork, cur *big.Int) error {
+	for _, msg := range []*Message{e.First, e.Second} {
+		if msg.Code != MsgCommit {
+			continue
+		}
+		commit := msg.Commit()
+		seal := PrepareCommittedSeal(commit.Subject.Digest, commit.Subject.View.Round)
+		if err := blscrypto.CryptoType().VerifySignature(pubKey, seal, []byte{}, commit.CommittedSeal, false, false, fork, cur); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ## ForwardMessage #################################################################
 
 // NewForwardMessage constructs a Message instance with the given sender and
@@ -615,6 +714,7 @@ const (
 	MsgPrepare
 	MsgCommit
 	MsgRoundChange
+	MsgDoubleSignEvidence
 )
 
 // Message is a wrapper used for all istanbul communication. It encapsulates
@@ -647,6 +747,7 @@ type Message struct {
 	enodeCertificate    *EnodeCertificate
 	versionCertificates []*VersionCertificate
 	valEnodeShareData   *ValEnodesShareData
+	doubleSignEvidence  *DoubleSignEvidence
 }
 
 // setMessageBytes sets the Msg field of msg to the rlp serialised bytes of
@@ -695,6 +796,10 @@ func (m *Message) DecodeMessage() error {
 			return err
 		}
 		m.roundChange = p
+	case MsgDoubleSignEvidence:
+		var e *DoubleSignEvidence
+		err = m.decode(&e)
+		m.doubleSignEvidence = e
 	case QueryEnodeMsg:
 		var q *QueryEnodeData
 		err = m.decode(&q)
@@ -822,6 +927,25 @@ func (m *Message) RoundChange() *RoundChange {
 	return m.roundChange
 }
 
+// DoubleSignEvidence returns the evidence if this is a double sign evidence message.
+func (m *Message) DoubleSignEvidence() *DoubleSignEvidence {
+	return m.doubleSignEvidence
+}
+
+// subject returns the subject signed by a PREPARE or COMMIT message, nil for the other
+// messages.
+func (m *Message) subject() *Subject {
+	switch m.Code {
+	case MsgPrepare:
+		return m.prepare
+	case MsgCommit:
+		if m.committedSubject != nil {
+			return m.committedSubject.Subject
+		}
+	}
+	return nil
+}
+
 // QueryEnode returns query enode data if this is a query enode message.
 func (m *Message) QueryEnodeMsg() *QueryEnodeData {
 	return m.queryEnode
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/mapprotocol/atlas/core/types"
@@ -391,3 +392,68 @@ func TestForwardMessageRLPEncoding(t *testing.T) {
 		t.Fatalf("RLP Encode/Decode mismatch. Got %v, expected %v", result, original)
 	}
 }
+
+func TestDoubleSignEvidenceVerify(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	signFn := func(data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	}
+	prepare := func(view *View, digest common.Hash) *Message {
+		msg := NewPrepareMessage(&Subject{View: view, Digest: digest}, addr)
+		if err := msg.Sign(signFn); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	first, second := prepare(dummyView(), common.HexToHash("01")), prepare(dummyView(), common.HexToHash("02"))
+	evidence := NewDoubleSignEvidence(second, first)
+	if err := evidence.Verify(); err != nil {
+		t.Fatalf("valid evidence: %v", err)
+	}
+	if evidence.First != first || evidence.Hash() != NewDoubleSignEvidence(first, second).Hash() {
+		t.Fatal("evidence is not ordered by digest")
+	}
+	if evidence.Signer() != addr {
+		t.Fatalf("signer %x, want %x", evidence.Signer(), addr)
+	}
+
+	rawVal, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DoubleSignEvidence
+	if err := rlp.DecodeBytes(rawVal, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(); err != nil {
+		t.Fatalf("decoded evidence: %v", err)
+	}
+	if decoded.Hash() != evidence.Hash() {
+		t.Fatalf("decoded evidence hash %x, want %x", decoded.Hash(), evidence.Hash())
+	}
+
+	nextRound := &View{Round: big.NewInt(16), Sequence: big.NewInt(42)}
+	forged := *second
+	forged.Address = common.HexToAddress("AABB")
+	tampered := prepare(dummyView(), common.HexToHash("02"))
+	tampered.Signature = first.Signature
+
+	for _, tc := range []struct {
+		name     string
+		evidence *DoubleSignEvidence
+		want     error
+	}{
+		{"same digest", &DoubleSignEvidence{First: first, Second: prepare(dummyView(), common.HexToHash("01"))}, ErrEvidenceSameDigest},
+		{"different round", &DoubleSignEvidence{First: first, Second: prepare(nextRound, common.HexToHash("02"))}, ErrEvidenceViewMismatch},
+		{"different signer", &DoubleSignEvidence{First: first, Second: &forged}, ErrEvidenceSignerMismatch},
+		{"wrong signature", &DoubleSignEvidence{First: first, Second: tampered}, ErrInvalidSigner},
+		{"not a prepare or commit", &DoubleSignEvidence{First: first, Second: dummyRoundChangeMessage()}, ErrInvalidEvidenceMessage},
+		{"missing message", &DoubleSignEvidence{First: first}, ErrInvalidEvidenceMessage},
+	} {
+		if err := tc.evidence.Verify(); err != tc.want {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
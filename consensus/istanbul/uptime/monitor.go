@@ -33,10 +33,14 @@ type UptimeEntry struct {
 	// Numbers of blocks validator is considered UP within monitored window
 	UpBlocks        uint64
 	LastSignedBlock uint64
+	// Numbers of consecutive monitored blocks the validator didn't sign up to the latest block
+	MissedBlocks uint64 `rlp:"optional"`
+	// Longest run of consecutive monitored blocks the validator didn't sign within the epoch
+	MaxMissedBlocks uint64 `rlp:"optional"`
 }
 
 func (u *UptimeEntry) String() string {
-	return fmt.Sprintf("UptimeEntry { upBlocks: %v, lastBlock: %v}", u.UpBlocks, u.LastSignedBlock)
+	return fmt.Sprintf("UptimeEntry { upBlocks: %v, lastBlock: %v, missedBlocks: %v, maxMissedBlocks: %v}", u.UpBlocks, u.LastSignedBlock, u.MissedBlocks, u.MaxMissedBlocks)
 }
 
 // Monitor is responsible for monitoring uptime by processing blocks
@@ -105,6 +109,33 @@ func (um *Monitor) ComputeValidatorsUptime(epoch uint64, valSetSize int) ([]*big
 	return uptimes, nil
 }
 
+// Downtime is the longest run of consecutive monitored blocks a validator didn't sign in an epoch
+type Downtime struct {
+	// Index of the validator in the validator set of the epoch
+	Index        int
+	MissedBlocks uint64
+}
+
+// DowntimeValidators returns the validators which didn't sign more than maxMissedBlocks consecutive
+// monitored blocks during the given epoch, in validator set order
+func (um *Monitor) DowntimeValidators(epoch uint64, valSetSize int, maxMissedBlocks uint64) ([]Downtime, error) {
+	accumulated := um.store.ReadAccumulatedEpochUptime(epoch)
+	if accumulated == nil {
+		return nil, errors.New("accumulated uptimes not found")
+	}
+	if len(accumulated.Entries) < valSetSize {
+		return nil, fmt.Errorf("accumulated uptimes found got: %d, want: %d", len(accumulated.Entries), valSetSize)
+	}
+
+	var down []Downtime
+	for i, entry := range accumulated.Entries[:valSetSize] {
+		if entry.MaxMissedBlocks > maxMissedBlocks {
+			down = append(down, Downtime{Index: i, MissedBlocks: entry.MaxMissedBlocks})
+		}
+	}
+	return down, nil
+}
+
 func (um *Monitor) GetValidatorsActivity(epoch, numberWithinEpoch uint64, valSetSize int) ([]UptimeEntry, []float64, error) {
 	logger := um.logger.New("func", "Monitor.GetValidatorsActivity", "epoch", epoch)
 
@@ -189,11 +220,23 @@ func updateUptime(uptime *Uptime, blockNumber uint64, bitmap *big.Int, lookbackW
 			uptime.Entries[i].LastSignedBlock = blockNumber
 		}
 
+		if !monitoringWindow.Contains(blockNumber) {
+			continue
+		}
 		// If block number is to be monitored, then check if lastSignedBlock is within current lookback window
-		if monitoringWindow.Contains(blockNumber) && currentLookbackWindow.Contains(uptime.Entries[i].LastSignedBlock) {
+		if currentLookbackWindow.Contains(uptime.Entries[i].LastSignedBlock) {
 			// since within currentLookbackWindow there's at least one signed block (lastSignedBlock) validator is considered UP
 			uptime.Entries[i].UpBlocks++
 		}
+		// Track the consecutive monitored blocks the validator missed for downtime slashing
+		if bitmap.Bit(i) == 1 {
+			uptime.Entries[i].MissedBlocks = 0
+		} else {
+			uptime.Entries[i].MissedBlocks++
+			if uptime.Entries[i].MissedBlocks > uptime.Entries[i].MaxMissedBlocks {
+				uptime.Entries[i].MaxMissedBlocks = uptime.Entries[i].MissedBlocks
+			}
+		}
 	}
 	return uptime
 }
@@ -38,22 +38,31 @@ func TestUptime(t *testing.T) {
 			{
 				UpBlocks:        5,
 				LastSignedBlock: 5,
+				MissedBlocks:    1,
+				MaxMissedBlocks: 1,
 			},
 			{
 				UpBlocks:        5,
 				LastSignedBlock: 6,
+				MaxMissedBlocks: 1,
 			},
 			{
 				UpBlocks:        0,
 				LastSignedBlock: 0,
+				MissedBlocks:    5,
+				MaxMissedBlocks: 5,
 			},
 			{
 				UpBlocks:        0,
 				LastSignedBlock: 0,
+				MissedBlocks:    5,
+				MaxMissedBlocks: 5,
 			},
 			{
 				UpBlocks:        0,
 				LastSignedBlock: 0,
+				MissedBlocks:    5,
+				MaxMissedBlocks: 5,
 			},
 		},
 	}
@@ -102,3 +111,47 @@ func TestUptimeSingle(t *testing.T) {
 		t.Fatalf("uptimes were not updated correctly, got %v, expected %v", uptimes, expected)
 	}
 }
+
+type testStore map[uint64]*Uptime
+
+func (s testStore) ReadAccumulatedEpochUptime(epoch uint64) *Uptime { return s[epoch] }
+
+func (s testStore) WriteAccumulatedEpochUptime(epoch uint64, uptime *Uptime) { s[epoch] = uptime }
+
+func TestDowntimeValidators(t *testing.T) {
+	var uptimes *Uptime
+	monitoringWindow := MustMonitoringWindow(1, 20, 2) // [3,19]
+	for block := uint64(1); block < 20; block++ {
+		bitmap := big.NewInt(7) // 111
+		if block >= 5 && block < 9 {
+			bitmap = big.NewInt(5) // 101: the second validator misses 4 consecutive blocks
+		}
+		if block == 12 || block == 15 {
+			bitmap = big.NewInt(3) // 011: the third validator misses 2 separate blocks
+		}
+		uptimes = updateUptime(uptimes, block, bitmap, 2, monitoringWindow)
+	}
+
+	store := testStore{1: uptimes}
+	monitor := NewMonitor(store, 20, 2)
+	for _, tc := range []struct {
+		maxMissedBlocks uint64
+		want            []Downtime
+	}{
+		{0, []Downtime{{Index: 1, MissedBlocks: 4}, {Index: 2, MissedBlocks: 1}}},
+		{1, []Downtime{{Index: 1, MissedBlocks: 4}}},
+		{3, []Downtime{{Index: 1, MissedBlocks: 4}}},
+		{4, nil},
+	} {
+		down, err := monitor.DowntimeValidators(1, 3, tc.maxMissedBlocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(down, tc.want) {
+			t.Errorf("max missed blocks %d: got %v, expected %v", tc.maxMissedBlocks, down, tc.want)
+		}
+	}
+	if _, err := monitor.DowntimeValidators(2, 3, 0); err == nil {
+		t.Error("downtime computed without accumulated uptimes")
+	}
+}
@@ -2068,6 +2068,116 @@ const FeeCurrencyStr = `[
 	}
 ]`
 
+// FeeCurrencyTokenStr is the ABI of the ERC20 tokens used as fee currencies, which let the
+// VM debit and credit the gas fees of the transactions
+const FeeCurrencyTokenStr = `[
+	{
+		"constant": true,
+		"inputs": [
+			{
+				"name": "account",
+				"type": "address"
+			}
+		],
+		"name": "balanceOf",
+		"outputs": [
+			{
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{
+				"name": "from",
+				"type": "address"
+			},
+			{
+				"name": "value",
+				"type": "uint256"
+			}
+		],
+		"name": "debitGasFees",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{
+				"name": "from",
+				"type": "address"
+			},
+			{
+				"name": "feeRecipient",
+				"type": "address"
+			},
+			{
+				"name": "gatewayFeeRecipient",
+				"type": "address"
+			},
+			{
+				"name": "communityFund",
+				"type": "address"
+			},
+			{
+				"name": "refund",
+				"type": "uint256"
+			},
+			{
+				"name": "tipTxFee",
+				"type": "uint256"
+			},
+			{
+				"name": "gatewayFee",
+				"type": "uint256"
+			},
+			{
+				"name": "baseTxFee",
+				"type": "uint256"
+			}
+		],
+		"name": "creditGasFees",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+const SortedOraclesStr = `[
+	{
+		"constant": true,
+		"inputs": [
+			{
+				"name": "token",
+				"type": "address"
+			}
+		],
+		"name": "medianRate",
+		"outputs": [
+			{
+				"name": "",
+				"type": "uint256"
+			},
+			{
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
 const ElectionsStr string = `[
     {
       "inputs": [
@@ -8454,3 +8564,73 @@ const AccountsStr = `[
       "type": "function"
     }
   ]`
+
+// LockedGoldStr holds the slashing methods of the LockedGold contract
+const LockedGoldStr = `[
+    {
+      "constant": true,
+      "inputs": [
+        {
+          "internalType": "address",
+          "name": "account",
+          "type": "address"
+        }
+      ],
+      "name": "getAccountTotalLockedGold",
+      "outputs": [
+        {
+          "internalType": "uint256",
+          "name": "",
+          "type": "uint256"
+        }
+      ],
+      "payable": false,
+      "stateMutability": "view",
+      "type": "function"
+    },
+    {
+      "constant": false,
+      "inputs": [
+        {
+          "internalType": "address",
+          "name": "account",
+          "type": "address"
+        },
+        {
+          "internalType": "uint256",
+          "name": "penalty",
+          "type": "uint256"
+        },
+        {
+          "internalType": "address",
+          "name": "reporter",
+          "type": "address"
+        },
+        {
+          "internalType": "uint256",
+          "name": "reward",
+          "type": "uint256"
+        },
+        {
+          "internalType": "address[]",
+          "name": "lessers",
+          "type": "address[]"
+        },
+        {
+          "internalType": "address[]",
+          "name": "greaters",
+          "type": "address[]"
+        },
+        {
+          "internalType": "uint256[]",
+          "name": "indices",
+          "type": "uint256[]"
+        }
+      ],
+      "name": "slash",
+      "outputs": [],
+      "payable": false,
+      "stateMutability": "nonpayable",
+      "type": "function"
+    }
+  ]`
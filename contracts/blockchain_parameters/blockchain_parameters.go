@@ -66,8 +66,6 @@ func GetIntrinsicGasForAlternativeFeeCurrencyOrDefault(vmRunner vm.EVMRunner) ui
 // getIntrinsicGasForAlternativeFeeCurrency retrieves the intrisic gas for transactions that pay gas in
 // with an alternative currency
 func getIntrinsicGasForAlternativeFeeCurrency(vmRunner vm.EVMRunner) (uint64, error) {
-	return 0, nil
-	//TODO Replace with the following in the future
 	var gas *big.Int
 	err := intrinsicGasForAlternativeFeeCurrencyMethod.Query(vmRunner, &gas)
 
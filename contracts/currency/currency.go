@@ -0,0 +1,179 @@
+package currency
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	medianRateMethod   = contracts.NewRegisteredContractMethod(params.SortedOraclesRegistryId, abis.SortedOracles, "medianRate", params.MaxGasForMedianRate)
+	getWhitelistMethod = contracts.NewRegisteredContractMethod(params.FeeCurrencyWhitelistRegistryId, abis.FeeCurrency, "getWhitelist", params.MaxGasForGetWhiteList)
+
+	balanceOfMethod     = contracts.NewMethod(abis.FeeCurrencyToken, "balanceOf", params.MaxGasToReadErc20Balance)
+	debitGasFeesMethod  = contracts.NewMethod(abis.FeeCurrencyToken, "debitGasFees", params.MaxGasForDebitGasFeesTransactions)
+	creditGasFeesMethod = contracts.NewMethod(abis.FeeCurrencyToken, "creditGasFees", params.MaxGasForCreditGasFeesTransactions)
+)
+
+// ExchangeRate is the rate of a fee currency to the native token, numerator units of the
+// currency are worth denominator units of the native token
+type ExchangeRate struct {
+	numerator   *big.Int
+	denominator *big.Int
+}
+
+// NewExchangeRate creates an exchange rate, both terms must be positive
+func NewExchangeRate(numerator *big.Int, denominator *big.Int) (*ExchangeRate, error) {
+	if numerator == nil || numerator.Sign() <= 0 || denominator == nil || denominator.Sign() <= 0 {
+		return nil, contracts.ErrExchangeRateZero
+	}
+	return &ExchangeRate{numerator, denominator}, nil
+}
+
+// ToBase converts an amount of the currency to the native token
+func (er *ExchangeRate) ToBase(currencyAmount *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(currencyAmount, er.denominator), er.numerator)
+}
+
+// FromBase converts an amount of the native token to the currency
+func (er *ExchangeRate) FromBase(baseAmount *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(baseAmount, er.numerator), er.denominator)
+}
+
+// Currency is a fee currency with its exchange rate, a nil address is the native token
+type Currency struct {
+	Address *common.Address
+	rate    ExchangeRate
+}
+
+// ToBase converts an amount of the currency to the native token
+func (c *Currency) ToBase(currencyAmount *big.Int) *big.Int {
+	return c.rate.ToBase(currencyAmount)
+}
+
+// FromBase converts an amount of the native token to the currency
+func (c *Currency) FromBase(baseAmount *big.Int) *big.Int {
+	return c.rate.FromBase(baseAmount)
+}
+
+// CmpToCurrency compares an amount of the currency with an amount of another currency,
+// without rounding: val1 * numerator2 * denominator1 against val2 * numerator1 * denominator2
+func (c *Currency) CmpToCurrency(currencyAmount *big.Int, sndCurrencyAmount *big.Int, sndCurrency *Currency) int {
+	left := new(big.Int).Mul(currencyAmount, c.rate.denominator)
+	left.Mul(left, sndCurrency.rate.numerator)
+	right := new(big.Int).Mul(sndCurrencyAmount, sndCurrency.rate.denominator)
+	right.Mul(right, c.rate.numerator)
+	return left.Cmp(right)
+}
+
+var nativeCurrency = Currency{rate: ExchangeRate{common.Big1, common.Big1}}
+
+// Manager retrieves the fee currencies with their exchange rates, caching them. It must be
+// discarded when the state of the runner changes.
+type Manager struct {
+	vmRunner   vm.EVMRunner
+	currencies map[common.Address]*Currency
+}
+
+// NewManager creates a currency manager querying the contracts through vmRunner
+func NewManager(vmRunner vm.EVMRunner) *Manager {
+	return &Manager{
+		vmRunner:   vmRunner,
+		currencies: make(map[common.Address]*Currency),
+	}
+}
+
+// GetCurrency returns the currency at the address, nil is the native token
+func (m *Manager) GetCurrency(currencyAddress *common.Address) (*Currency, error) {
+	if currencyAddress == nil {
+		return &nativeCurrency, nil
+	}
+	if c, ok := m.currencies[*currencyAddress]; ok {
+		return c, nil
+	}
+	rate, err := GetExchangeRate(m.vmRunner, currencyAddress)
+	if err != nil {
+		return nil, err
+	}
+	address := *currencyAddress
+	c := &Currency{Address: &address, rate: *rate}
+	m.currencies[address] = c
+	return c, nil
+}
+
+// CmpValues compares val1 in currency1 with val2 in currency2, the values are compared as is
+// when an exchange rate is missing
+func (m *Manager) CmpValues(val1 *big.Int, currency1 *common.Address, val2 *big.Int, currency2 *common.Address) int {
+	if (currency1 == nil && currency2 == nil) || (currency1 != nil && currency2 != nil && *currency1 == *currency2) {
+		return val1.Cmp(val2)
+	}
+	c1, err1 := m.GetCurrency(currency1)
+	c2, err2 := m.GetCurrency(currency2)
+	if err1 != nil || err2 != nil {
+		log.Warn("Failed to retrieve the exchange rates, comparing the values without conversion", "currency1", currency1, "err1", err1, "currency2", currency2, "err2", err2)
+		return val1.Cmp(val2)
+	}
+	return c1.CmpToCurrency(val1, val2, c2)
+}
+
+// GetExchangeRate returns the median oracle rate of the currency, nil is the native token
+func GetExchangeRate(vmRunner vm.EVMRunner, currency *common.Address) (*ExchangeRate, error) {
+	if currency == nil {
+		return &nativeCurrency.rate, nil
+	}
+	var rate [2]*big.Int
+	if err := medianRateMethod.Query(vmRunner, &rate, *currency); err != nil {
+		return nil, err
+	}
+	return NewExchangeRate(rate[0], rate[1])
+}
+
+// GetWhitelist returns the currencies accepted to pay the gas fees
+func GetWhitelist(vmRunner vm.EVMRunner) ([]common.Address, error) {
+	var whitelist []common.Address
+	err := getWhitelistMethod.Query(vmRunner, &whitelist)
+	return whitelist, err
+}
+
+// IsWhitelisted returns whether the currency is accepted to pay the gas fees, the native
+// token always is
+func IsWhitelisted(vmRunner vm.EVMRunner, currency *common.Address) bool {
+	if currency == nil {
+		return true
+	}
+	whitelist, err := GetWhitelist(vmRunner)
+	if err != nil {
+		log.Warn("Failed to get the fee currency whitelist", "err", err)
+		return false
+	}
+	for _, address := range whitelist {
+		if address == *currency {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBalanceOf returns the balance of the account in the currency token
+func GetBalanceOf(vmRunner vm.EVMRunner, account common.Address, currency common.Address) (*big.Int, error) {
+	var balance *big.Int
+	err := balanceOfMethod.Bind(currency).Query(vmRunner, &balance, account)
+	return balance, err
+}
+
+// DebitGasFees withdraws the gas fees of a transaction from the account in the currency token
+func DebitGasFees(vmRunner vm.EVMRunner, currency common.Address, from common.Address, value *big.Int) error {
+	return debitGasFeesMethod.Bind(currency).Execute(vmRunner, nil, common.Big0, from, value)
+}
+
+// CreditGasFees refunds the unused gas to the account and pays the tip to the fee recipient in the
+// currency token, the base fee is credited to the zero address to burn it
+func CreditGasFees(vmRunner vm.EVMRunner, currency common.Address, from, feeRecipient common.Address, refund, tipTxFee, baseTxFee *big.Int) error {
+	return creditGasFeesMethod.Bind(currency).Execute(vmRunner, nil, common.Big0, from, feeRecipient, params.ZeroAddress, params.ZeroAddress,
+		refund, tipTxFee, common.Big0, baseTxFee)
+}
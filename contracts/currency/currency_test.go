@@ -0,0 +1,112 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/contracts/testutil"
+	"github.com/mapprotocol/atlas/params"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	stableToken = common.HexToAddress("0x0765")
+	otherToken  = common.HexToAddress("0x0766")
+)
+
+// medianRate quotes 2 stable tokens for 1 native token and 4 other tokens for 1 native token
+func medianRate(token common.Address) (*big.Int, *big.Int) {
+	switch token {
+	case stableToken:
+		return big.NewInt(2), big.NewInt(1)
+	case otherToken:
+		return big.NewInt(4), big.NewInt(1)
+	}
+	return common.Big0, common.Big0
+}
+
+type tokenMock struct {
+	testutil.ContractMock
+	balances map[common.Address]*big.Int
+}
+
+func (t *tokenMock) BalanceOf(account common.Address) *big.Int {
+	if balance, ok := t.balances[account]; ok {
+		return balance
+	}
+	return common.Big0
+}
+
+func TestGetExchangeRate(t *testing.T) {
+	testutil.TestFailOnFailingRunner(t, GetExchangeRate, &stableToken)
+	testutil.TestFailsWhenContractNotDeployed(t, contracts.ErrSmartContractNotDeployed, GetExchangeRate, &stableToken)
+
+	t.Run("should convert with the oracle rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		runner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", medianRate)
+
+		rate, err := GetExchangeRate(runner, &stableToken)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rate.FromBase(big.NewInt(100))).To(Equal(big.NewInt(200)))
+		g.Expect(rate.ToBase(big.NewInt(100))).To(Equal(big.NewInt(50)))
+	})
+
+	t.Run("should fail on a zero rate", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		runner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", medianRate)
+
+		unknown := common.HexToAddress("0x0999")
+		_, err := GetExchangeRate(runner, &unknown)
+		g.Expect(err).To(MatchError(contracts.ErrExchangeRateZero))
+	})
+
+	t.Run("should not query the native token", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		rate, err := GetExchangeRate(testutil.FailingVmRunner{}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rate.ToBase(big.NewInt(100))).To(Equal(big.NewInt(100)))
+	})
+}
+
+func TestCmpValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+	runner := testutil.NewSingleMethodRunner(params.SortedOraclesRegistryId, "medianRate", medianRate)
+	manager := NewManager(runner)
+
+	g.Expect(manager.CmpValues(big.NewInt(10), nil, big.NewInt(20), nil)).To(Equal(-1))
+	// 20 stable tokens are worth 10 native tokens
+	g.Expect(manager.CmpValues(big.NewInt(20), &stableToken, big.NewInt(10), nil)).To(Equal(0))
+	g.Expect(manager.CmpValues(big.NewInt(21), &stableToken, big.NewInt(10), nil)).To(Equal(1))
+	g.Expect(manager.CmpValues(big.NewInt(10), nil, big.NewInt(21), &stableToken)).To(Equal(-1))
+	// 2 stable tokens are worth 4 other tokens
+	g.Expect(manager.CmpValues(big.NewInt(2), &stableToken, big.NewInt(4), &otherToken)).To(Equal(0))
+	g.Expect(manager.CmpValues(big.NewInt(2), &stableToken, big.NewInt(3), &otherToken)).To(Equal(1))
+}
+
+func TestIsWhitelisted(t *testing.T) {
+	g := NewGomegaWithT(t)
+	runner := testutil.NewSingleMethodRunner(params.FeeCurrencyWhitelistRegistryId, "getWhitelist", func() []common.Address {
+		return []common.Address{stableToken}
+	})
+
+	g.Expect(IsWhitelisted(runner, nil)).To(BeTrue())
+	g.Expect(IsWhitelisted(runner, &stableToken)).To(BeTrue())
+	g.Expect(IsWhitelisted(runner, &otherToken)).To(BeFalse())
+	g.Expect(IsWhitelisted(testutil.FailingVmRunner{}, &stableToken)).To(BeFalse())
+}
+
+func TestGetBalanceOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+	account := common.HexToAddress("0x01")
+	token := &tokenMock{balances: map[common.Address]*big.Int{account: big.NewInt(1000)}}
+	token.ContractMock = testutil.NewContractMock(abis.FeeCurrencyToken, token)
+	runner := testutil.NewMockEVMRunner()
+	runner.RegisterContract(stableToken, token)
+
+	balance, err := GetBalanceOf(runner, account, stableToken)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(balance).To(Equal(big.NewInt(1000)))
+}
@@ -0,0 +1,34 @@
+package locked_gold
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/contracts"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	getAccountTotalLockedGoldMethod = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "getAccountTotalLockedGold", params.MaxGasForGetAccountTotalLockedGold)
+	slashMethod                     = contracts.NewRegisteredContractMethod(params.LockedGoldRegistryId, abis.LockedGold, "slash", params.MaxGasForSlashing)
+)
+
+func GetAccountTotalLockedGold(vmRunner vm.EVMRunner, account common.Address) (*big.Int, error) {
+	var locked *big.Int
+	err := getAccountTotalLockedGoldMethod.Query(vmRunner, &locked, account)
+	return locked, err
+}
+
+// Slash slashes the penalty from the locked gold of the account without rewarding a reporter.
+// The caller of the VM must be registered as a slasher of the LockedGold contract.
+func Slash(vmRunner vm.EVMRunner, account common.Address, penalty *big.Int) error {
+	if penalty.Cmp(new(big.Int)) <= 0 {
+		return nil
+	}
+
+	err := slashMethod.Execute(vmRunner, nil, common.Big0, account, penalty, params.ZeroAddress, common.Big0,
+		[]common.Address{}, []common.Address{}, []*big.Int{})
+	return err
+}
@@ -74,7 +74,7 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		return consensus.ErrPrunedAncestor
 	}
-	return nil
+	return v.validateMmrRoot(header)
 }
 
 // ValidateState validates the various changes that happen after a state
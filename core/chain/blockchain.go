@@ -210,6 +210,10 @@ type BlockChain struct {
 	running       int32          // 0 if chain is running, 1 when stopped
 	procInterrupt int32          // interrupt signaler for block processing
 
+	mmrLock     sync.RWMutex  // Lock for the merkle mountain range updates
+	mmrBuilding bool          // Whether the merkle mountain range is built in the background
+	mmrDone     chan struct{} // Closed once the background build of the merkle mountain range caught up with the head
+
 	engine     consensus.Engine
 	validator  abstract.Validator // Block and state validator abstract
 	prefetcher abstract.Prefetcher
@@ -380,6 +384,12 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		}
 	}
 
+	// Catch up the merkle mountain range with the head, it is built from the
+	// genesis the first time the mmr fork is configured
+	if err := bc.startMmr(); err != nil {
+		return nil, err
+	}
+
 	// Load any existing snapshot, regenerating it if loading failed
 	if bc.cacheConfig.SnapshotLimit > 0 {
 		// If the chain was rewound past the snapshot persistent layer (causing
@@ -672,6 +682,10 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	headBlockGauge.Update(int64(block.NumberU64()))
 	bc.chainmu.Unlock()
 
+	// The blocks up to the pivot were never pushed to the merkle mountain range, build
+	// it in the background and check the roots committed by their headers
+	bc.startMmrBuild()
+
 	// Destroy any existing state snapshot and regenerate it in the background,
 	// also resuming the normal maintenance of any previously paused snapshot.
 	if bc.snaps != nil {
@@ -832,6 +846,12 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	}
 	bc.currentBlock.Store(block)
 	headBlockGauge.Update(int64(block.NumberU64()))
+
+	// Validating the children of the block needs the range up to the block, exit the
+	// node rather than walking back the headers missing from it for every block
+	if err := bc.writeHeadMmr(block.Header()); err != nil {
+		log.Crit("Failed to update merkle mountain range", "number", block.NumberU64(), "err", err)
+	}
 }
 
 // Genesis retrieves the chain's genesis block.
@@ -0,0 +1,352 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mapprotocol/atlas/consensus"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// The merkle mountain range holds the hashes of the canonical blocks from the genesis,
+// the header of block n commits the root of the range of blocks 0..n-1 after the mmr
+// fork. Its nodes are persisted by position, a rewound leaf is overwritten by the
+// next canonical block of the same number.
+
+// mmrBuildBatch is the number of blocks pushed to the merkle mountain range at a time
+// by the background build
+const mmrBuildBatch = 10000
+
+// mmrLeafWeight is the difficulty of every leaf, istanbul blocks have no difficulty
+// so every block weighs the same
+var mmrLeafWeight = big.NewInt(1)
+
+var (
+	errMissingMmrRoot    = errors.New("missing mmr root")
+	errUnexpectedMmrRoot = errors.New("mmr root before the mmr fork")
+)
+
+// invalidMmrRootError is returned by the background build when a canonical header
+// commits another root than the range of its ancestors
+type invalidMmrRootError struct {
+	number     uint64
+	have, want common.Hash
+}
+
+func (e *invalidMmrRootError) Error() string {
+	return fmt.Sprintf("mmr root mismatch #%d: have %x, want %x", e.number, e.have, e.want)
+}
+
+func newMmrLeaf(hash common.Hash) *mmr.Node {
+	return mmr.NewNode(hash, mmrLeafWeight, nil, nil, 0)
+}
+
+func mmrNodeReader(db ethdb.KeyValueReader) mmr.NodeReader {
+	return func(pos uint64) (*mmr.Node, error) {
+		return rawdb.ReadMmrNode(db, pos), nil
+	}
+}
+
+// readMmrLeaf returns the block hash of the leaf with the given index, the empty hash
+// if it was never pushed
+func readMmrLeaf(db ethdb.KeyValueReader, index uint64) common.Hash {
+	if node := rawdb.ReadMmrNode(db, mmr.LeafPosition(index)); node != nil {
+		return node.GetHash()
+	}
+	return common.Hash{}
+}
+
+// updateMmr pushes the canonical block hashes up to the head to the merkle mountain
+// range, the leaves of a chain abandoned by a reorg or a rewind are dropped first.
+// With verify, the root committed by every canonical header after the fork is checked
+// once the range holds its ancestors.
+func (bc *BlockChain) updateMmr(head *types.Header, verify bool) error {
+	if bc.chainConfig.MmrBlock == nil {
+		return nil
+	}
+	var (
+		number  = head.Number.Uint64()
+		leafNum = rawdb.ReadMmrLeafNumber(bc.db)
+	)
+	if leafNum > number+1 {
+		leafNum = number + 1
+	}
+	for leafNum > 0 && readMmrLeaf(bc.db, leafNum-1) != rawdb.ReadCanonicalHash(bc.db, leafNum-1) {
+		leafNum--
+	}
+	if missing := number + 1 - leafNum; missing > 1 {
+		log.Info("Updating merkle mountain range", "from", leafNum, "blocks", missing)
+	}
+
+	batch := bc.db.NewBatch()
+	// Nodes written in the batch are read back from memory until the batch is flushed
+	nodes := make(map[uint64]*mmr.Node)
+	read := func(pos uint64) (*mmr.Node, error) {
+		if node, ok := nodes[pos]; ok {
+			return node, nil
+		}
+		return rawdb.ReadMmrNode(bc.db, pos), nil
+	}
+	write := func(pos uint64, node *mmr.Node) error {
+		nodes[pos] = node
+		rawdb.WriteMmrNode(batch, pos, node)
+		return nil
+	}
+	for ; leafNum <= number; leafNum++ {
+		hash := rawdb.ReadCanonicalHash(bc.db, leafNum)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("missing canonical hash #%d", leafNum)
+		}
+		if err := mmr.AppendLeaf(read, write, leafNum, newMmrLeaf(hash)); err != nil {
+			return err
+		}
+		if verify {
+			if err := bc.verifyMmrRoot(read, leafNum+1); err != nil {
+				return err
+			}
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			rawdb.WriteMmrLeafNumber(batch, leafNum+1)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			nodes = make(map[uint64]*mmr.Node)
+		}
+	}
+	rawdb.WriteMmrLeafNumber(batch, leafNum)
+	return batch.Write()
+}
+
+// verifyMmrRoot checks the root committed by the canonical header of the given number
+// against the range of the leaves before it
+func (bc *BlockChain) verifyMmrRoot(read mmr.NodeReader, number uint64) error {
+	header := bc.GetHeaderByNumber(number)
+	if header == nil || !bc.chainConfig.IsMmr(header.Number) {
+		return nil
+	}
+	if header.MmrRoot == nil {
+		return errMissingMmrRoot
+	}
+	root, err := mmr.RootFromReader(read, number)
+	if err != nil {
+		return err
+	}
+	if root.GetHash() != *header.MmrRoot {
+		return &invalidMmrRootError{number: number, have: *header.MmrRoot, want: root.GetHash()}
+	}
+	return nil
+}
+
+// startMmr catches up the merkle mountain range with the head at startup. While the
+// mmr fork is ahead of the head, as no block needs it yet, or the range is more than
+// a batch behind, the range is built in the background, otherwise it is built before
+// the chain is used.
+func (bc *BlockChain) startMmr() error {
+	bc.mmrDone = make(chan struct{})
+	if bc.chainConfig.MmrBlock == nil {
+		close(bc.mmrDone)
+		return nil
+	}
+	head := bc.CurrentBlock().Header()
+	if !bc.chainConfig.IsMmr(new(big.Int).Add(head.Number, big.NewInt(1))) || rawdb.ReadMmrLeafNumber(bc.db)+mmrBuildBatch <= head.Number.Uint64() {
+		bc.mmrBuilding = true
+		bc.wg.Add(1)
+		go bc.buildMmr(bc.mmrDone)
+		return nil
+	}
+	defer close(bc.mmrDone)
+	return bc.updateMmr(head, false)
+}
+
+// startMmrBuild starts the background build of the merkle mountain range, unless
+// it's running already.
+func (bc *BlockChain) startMmrBuild() {
+	if bc.chainConfig.MmrBlock == nil {
+		return
+	}
+	bc.mmrLock.Lock()
+	defer bc.mmrLock.Unlock()
+
+	if bc.mmrBuilding {
+		return
+	}
+	bc.mmrBuilding = true
+	bc.mmrDone = make(chan struct{})
+	bc.wg.Add(1)
+	go bc.buildMmr(bc.mmrDone)
+}
+
+// buildMmr pushes the canonical blocks to the merkle mountain range in batches until
+// it reaches the head, the head blocks written meanwhile are left to it. The roots
+// of the headers after the fork, which weren't validated against the range while it
+// was behind, are checked on the way and the chain is rewound below the first
+// invalid one.
+func (bc *BlockChain) buildMmr(done chan struct{}) {
+	defer bc.wg.Done()
+	defer close(done)
+
+	for {
+		select {
+		case <-bc.quit:
+			return
+		default:
+		}
+		bc.mmrLock.Lock()
+		var (
+			head   = bc.CurrentBlock().Header()
+			target = head
+		)
+		if leafNum := rawdb.ReadMmrLeafNumber(bc.db); leafNum+mmrBuildBatch <= head.Number.Uint64() {
+			if header := bc.GetHeaderByNumber(leafNum + mmrBuildBatch - 1); header != nil {
+				target = header
+			}
+		}
+		err := bc.updateMmr(target, true)
+		finished := err != nil || target == head
+		if finished {
+			bc.mmrBuilding = false
+		}
+		bc.mmrLock.Unlock()
+
+		var invalid *invalidMmrRootError
+		switch {
+		case errors.As(err, &invalid):
+			log.Error("Invalid merkle mountain range root, rewinding the chain", "number", invalid.number, "err", err)
+			if err := bc.SetHead(invalid.number - 1); err != nil {
+				log.Error("Failed to rewind the chain", "err", err)
+			}
+		case err != nil:
+			log.Error("Failed to build merkle mountain range", "err", err)
+		}
+		if finished {
+			return
+		}
+	}
+}
+
+// writeHeadMmr pushes the new head block to the merkle mountain range. Head blocks
+// are left to the background build while it runs, a range more than a batch behind
+// the head is handed to a new build rather than updated on the head write.
+func (bc *BlockChain) writeHeadMmr(head *types.Header) error {
+	if bc.chainConfig.MmrBlock == nil {
+		return nil
+	}
+	bc.mmrLock.Lock()
+	if bc.mmrBuilding {
+		bc.mmrLock.Unlock()
+		return nil
+	}
+	if rawdb.ReadMmrLeafNumber(bc.db)+mmrBuildBatch <= head.Number.Uint64() {
+		bc.mmrLock.Unlock()
+		bc.startMmrBuild()
+		return nil
+	}
+	defer bc.mmrLock.Unlock()
+	return bc.updateMmr(head, false)
+}
+
+// mmrPending returns whether the merkle mountain range is built in the background,
+// the roots committed by the headers are then checked by the build.
+func (bc *BlockChain) mmrPending() bool {
+	bc.mmrLock.RLock()
+	defer bc.mmrLock.RUnlock()
+	return bc.mmrBuilding
+}
+
+// MmrRoot returns the root of the merkle mountain range of the parent and all its
+// ancestors, which is the root committed by the children of the parent. The parent
+// doesn't need to be canonical.
+func (bc *BlockChain) MmrRoot(parent *types.Header) (common.Hash, error) {
+	bc.mmrLock.RLock()
+	defer bc.mmrLock.RUnlock()
+
+	var (
+		leafNum = rawdb.ReadMmrLeafNumber(bc.db)
+		hash    = parent.Hash()
+		hashes  []common.Hash
+	)
+	// Collect the ancestors missing from the canonical range, base is the number of
+	// leaves shared with it
+	base := parent.Number.Uint64() + 1
+	for base > 0 && (base > leafNum || readMmrLeaf(bc.db, base-1) != hash) {
+		header := bc.GetHeader(hash, base-1)
+		if header == nil {
+			return common.Hash{}, consensus.ErrUnknownAncestor
+		}
+		hashes = append(hashes, hash)
+		hash = header.ParentHash
+		base--
+	}
+
+	nodes := make(map[uint64]*mmr.Node)
+	read := func(pos uint64) (*mmr.Node, error) {
+		if node, ok := nodes[pos]; ok {
+			return node, nil
+		}
+		return rawdb.ReadMmrNode(bc.db, pos), nil
+	}
+	write := func(pos uint64, node *mmr.Node) error {
+		nodes[pos] = node
+		return nil
+	}
+	for i := len(hashes) - 1; i >= 0; i-- {
+		if err := mmr.AppendLeaf(read, write, base, newMmrLeaf(hashes[i])); err != nil {
+			return common.Hash{}, err
+		}
+		base++
+	}
+	root, err := mmr.RootFromReader(read, base)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return root.GetHash(), nil
+}
+
+// GetMmrProof returns the proof of the canonical block against the merkle mountain
+// range committed by the canonical header tip, which holds the blocks before tip.
+func GetMmrProof(db ethdb.Reader, block, tip uint64) (*mmr.ProofInfo, error) {
+	if block >= tip {
+		return nil, fmt.Errorf("block #%d is not an ancestor of #%d", block, tip)
+	}
+	if tip > rawdb.ReadMmrLeafNumber(db) || readMmrLeaf(db, tip-1) != rawdb.ReadCanonicalHash(db, tip-1) {
+		return nil, fmt.Errorf("merkle mountain range of #%d not available", tip)
+	}
+	return mmr.GenerateProofFromReader(mmrNodeReader(db), block, tip)
+}
+
+// validateMmrRoot checks the merkle mountain range root committed by the header
+// against the ancestors of the header.
+func (v *BlockValidator) validateMmrRoot(header *types.Header) error {
+	if !v.config.IsMmr(header.Number) {
+		if header.MmrRoot != nil {
+			return errUnexpectedMmrRoot
+		}
+		return nil
+	}
+	if header.MmrRoot == nil {
+		return errMissingMmrRoot
+	}
+	if v.bc.mmrPending() {
+		return nil
+	}
+	parent := v.bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	root, err := v.bc.MmrRoot(parent)
+	if err != nil {
+		return err
+	}
+	if root != *header.MmrRoot {
+		return fmt.Errorf("mmr root mismatch: have %x, want %x", *header.MmrRoot, root)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethparams "github.com/ethereum/go-ethereum/params"
+
+	"github.com/mapprotocol/atlas/consensus/consensustest"
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func TestBlockChainMmr(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = (&Genesis{BaseFee: big.NewInt(ethparams.InitialBaseFee)}).MustCommit(db)
+		engine  = consensustest.NewFaker()
+		config  = *params.AllEthashProtocolChanges
+	)
+	// the range is maintained as soon as the fork is scheduled
+	config.MmrBlock = big.NewInt(1000)
+	blockchain, err := NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+	<-blockchain.mmrDone
+
+	blocks := makeBlockChain(genesis, 20, engine, db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 21 {
+		t.Fatalf("mmr leaf number %d, want 21", leafNum)
+	}
+	memory := mmr.NewMMR()
+	memory.Push(newMmrLeaf(genesis.Hash()))
+	for _, block := range blocks {
+		memory.Push(newMmrLeaf(block.Hash()))
+		root, err := blockchain.MmrRoot(block.Header())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != memory.GetRoot2() {
+			t.Fatalf("mmr root mismatch after #%d", block.NumberU64())
+		}
+	}
+	oldRoot := memory.GetRoot2()
+
+	proof, err := GetMmrProof(db, 3, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := blockchain.MmrRoot(blocks[18].Header()); proof.RootHash != want {
+		t.Fatal("proof root is not the root committed by the tip")
+	}
+	if pBlocks, _ := mmr.VerifyRequiredBlocks2(proof); !proof.VerifyProof2(pBlocks) {
+		t.Fatal("mmr proof not verified")
+	}
+	if _, err := GetMmrProof(db, 3, 22); err == nil {
+		t.Fatal("proof against a missing tip generated")
+	}
+
+	// reorg onto a longer fork, the abandoned leaves are replaced
+	fork := makeBlockChain(blocks[9], 15, engine, db, forkSeed)
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatal(err)
+	}
+	if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 26 {
+		t.Fatalf("mmr leaf number %d, want 26", leafNum)
+	}
+	memory = mmr.NewMMR()
+	memory.Push(newMmrLeaf(genesis.Hash()))
+	for _, block := range append(blocks[:10:10], fork...) {
+		memory.Push(newMmrLeaf(block.Hash()))
+	}
+	if root, _ := blockchain.MmrRoot(fork[len(fork)-1].Header()); root != memory.GetRoot2() {
+		t.Fatal("mmr root mismatch after reorg")
+	}
+	// the root of the abandoned chain is still computed from its blocks
+	if root, _ := blockchain.MmrRoot(blocks[19].Header()); root != oldRoot {
+		t.Fatal("mmr root of side chain mismatch")
+	}
+}
+
+func TestBlockChainMmrBuild(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = (&Genesis{BaseFee: big.NewInt(ethparams.InitialBaseFee)}).MustCommit(db)
+		engine  = consensustest.NewFaker()
+		config  = *params.AllEthashProtocolChanges
+	)
+	blockchain, err := NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := makeBlockChain(genesis, 20, engine, db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	blockchain.Stop()
+	if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 0 {
+		t.Fatalf("mmr leaf number %d without the fork, want 0", leafNum)
+	}
+
+	// the fork is ahead of the head, the range is built in the background
+	config.MmrBlock = big.NewInt(1000)
+	blockchain, err = NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-blockchain.mmrDone
+	if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 21 {
+		t.Fatalf("mmr leaf number %d, want 21", leafNum)
+	}
+	blockchain.Stop()
+
+	// the fork is reached, the range is built before the chain is used
+	rawdb.WriteMmrLeafNumber(db, 0)
+	config.MmrBlock = big.NewInt(0)
+	blockchain, err = NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+	if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 21 {
+		t.Fatalf("mmr leaf number %d, want 21", leafNum)
+	}
+	memory := mmr.NewMMR()
+	memory.Push(newMmrLeaf(genesis.Hash()))
+	for _, block := range blocks {
+		memory.Push(newMmrLeaf(block.Hash()))
+	}
+	if root, _ := blockchain.MmrRoot(blocks[19].Header()); root != memory.GetRoot2() {
+		t.Fatal("mmr root mismatch after build")
+	}
+}
+
+func TestValidateMmrRoot(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = (&Genesis{BaseFee: big.NewInt(ethparams.InitialBaseFee)}).MustCommit(db)
+		engine  = consensustest.NewFaker()
+		config  = *params.AllEthashProtocolChanges
+	)
+	config.MmrBlock = big.NewInt(1000)
+	blockchain, err := NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+	<-blockchain.mmrDone
+
+	blocks := makeBlockChain(genesis, 5, engine, db, canonicalSeed)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+	header := types.CopyHeader(blocks[4].Header())
+	root, err := blockchain.MmrRoot(blocks[3].Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := NewBlockValidator(&config, blockchain, engine)
+	if err := before.validateMmrRoot(header); err != nil {
+		t.Fatalf("header without mmr root rejected before the fork: %v", err)
+	}
+	header.MmrRoot = &root
+	if err := before.validateMmrRoot(header); err != errUnexpectedMmrRoot {
+		t.Fatalf("mmr root accepted before the fork, err = %v", err)
+	}
+
+	forked := config
+	forked.MmrBlock = big.NewInt(0)
+	after := NewBlockValidator(&forked, blockchain, engine)
+	if err := after.validateMmrRoot(header); err != nil {
+		t.Fatalf("valid mmr root rejected: %v", err)
+	}
+	header.MmrRoot = &blocks[0].Header().Root
+	if err := after.validateMmrRoot(header); err == nil {
+		t.Fatal("invalid mmr root accepted")
+	}
+	header.MmrRoot = nil
+	if err := after.validateMmrRoot(header); err != errMissingMmrRoot {
+		t.Fatalf("missing mmr root accepted, err = %v", err)
+	}
+}
+
+// relinkMmrBlocks commits the merkle mountain range roots in the headers from the fork
+// on, the root committed by the header of number bad is corrupted
+func relinkMmrBlocks(genesis *types.Block, blocks []*types.Block, fork, bad uint64) []*types.Block {
+	var (
+		memory  = mmr.NewMMR()
+		relayed = make([]*types.Block, len(blocks))
+		parent  = genesis.Hash()
+	)
+	memory.Push(newMmrLeaf(genesis.Hash()))
+	for i, block := range blocks {
+		header := block.Header()
+		header.ParentHash = parent
+		if header.Number.Uint64() >= fork {
+			root := memory.GetRoot2()
+			if header.Number.Uint64() == bad {
+				root = common.Hash{1}
+			}
+			header.MmrRoot = &root
+		}
+		relayed[i] = types.NewBlockWithHeader(header).WithBody(block.Transactions(), block.Randomness(), block.EpochSnarkData())
+		parent = relayed[i].Hash()
+		memory.Push(newMmrLeaf(parent))
+	}
+	return relayed
+}
+
+func TestBlockChainMmrFastSync(t *testing.T) {
+	var (
+		engine = consensustest.NewFaker()
+		config = *params.AllEthashProtocolChanges
+	)
+	config.MmrBlock = big.NewInt(5)
+
+	for _, bad := range []uint64{0, 12} {
+		db := rawdb.NewMemoryDatabase()
+		genesis := (&Genesis{BaseFee: big.NewInt(ethparams.InitialBaseFee)}).MustCommit(db)
+		// the generation leaves the state of the pivot in the database
+		blocks, receipts := GenerateChain(params.TestChainConfig, genesis, engine, db, 20, func(i int, b *BlockGen) {})
+		blockchain, err := NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		<-blockchain.mmrDone
+
+		// import the chain the way fast sync does, the roots aren't validated yet
+		synced := relinkMmrBlocks(genesis, blocks, 5, bad)
+		headers := make([]*types.Header, len(synced))
+		for i, block := range synced {
+			headers[i] = block.Header()
+		}
+		if _, err := blockchain.InsertHeaderChain(headers, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := blockchain.InsertReceiptChain(synced, receipts, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := blockchain.FastSyncCommitHead(synced[19].Hash()); err != nil {
+			t.Fatal(err)
+		}
+		blockchain.mmrLock.RLock()
+		done := blockchain.mmrDone
+		blockchain.mmrLock.RUnlock()
+		<-done
+
+		if bad == 0 {
+			if leafNum := rawdb.ReadMmrLeafNumber(db); leafNum != 21 {
+				t.Fatalf("mmr leaf number %d, want 21", leafNum)
+			}
+			if head := blockchain.CurrentBlock().NumberU64(); head != 20 {
+				t.Fatalf("head #%d, want #20", head)
+			}
+		} else if head := blockchain.CurrentHeader().Number.Uint64(); head != bad-1 {
+			// the chain is rewound below the header committing an invalid root
+			t.Fatalf("head header #%d after an invalid mmr root, want #%d", head, bad-1)
+		}
+		blockchain.Stop()
+	}
+}
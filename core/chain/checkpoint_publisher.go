@@ -0,0 +1,291 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/tools/utils/btcapi"
+)
+
+const (
+	// taprootDust is the smallest change output relayed by bitcoin nodes
+	taprootDust = 330
+	// publishRetryInterval is the time to wait before retrying a failed publication
+	publishRetryInterval = time.Minute
+)
+
+var errInsufficientFunds = errors.New("insufficient funds to publish the checkpoint")
+
+// checkPointChain is the part of the blockchain the checkpoint publisher reads
+type checkPointChain interface {
+	CurrentBlock() *types.Block
+	GetHeaderByNumber(number uint64) *types.Header
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// CheckpointPublisher anchors the state root of every Interval-th block on bitcoin, in the
+// OP_RETURN output of a transaction sent by the checkpoint sender. Istanbul blocks are final
+// once imported, so the block is anchored as soon as it becomes the head or an ancestor of it.
+type CheckpointPublisher struct {
+	chain       checkPointChain
+	db          ethdb.Database
+	broadcaster CheckpointBroadcaster
+	key         *btcec.PrivateKey
+	address     btcutil.Address
+	interval    uint64
+	feeRate     int64
+
+	last      uint64    // height of the last published checkpoint
+	lastRetry time.Time // time of the last failed publication
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// LoadCheckPointKey reads a hex encoded bitcoin private key from file
+func LoadCheckPointKey(file string) (*btcec.PrivateKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != btcec.PrivKeyBytesLen {
+		return nil, fmt.Errorf("invalid checkpoint key in %s", file)
+	}
+	key, _ := btcec.PrivKeyFromBytes(raw)
+	return key, nil
+}
+
+// taprootAddress returns the key path only taproot address of the key
+func taprootAddress(key *btcec.PrivateKey, network *chaincfg.Params) (btcutil.Address, error) {
+	tapKey := txscript.ComputeTaprootKeyNoScript(key.PubKey())
+	return btcutil.NewAddressTaproot(schnorr.SerializePubKey(tapKey), network)
+}
+
+func NewCheckpointPublisher(config *CheckPointConfig, bc checkPointChain, db ethdb.Database) (*CheckpointPublisher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	source, err := NewCheckpointSource(config)
+	if err != nil {
+		return nil, err
+	}
+	broadcaster, ok := source.(CheckpointBroadcaster)
+	if !ok {
+		return nil, fmt.Errorf("the %s checkpoint source can't publish checkpoints", config.Source)
+	}
+	key, err := LoadCheckPointKey(config.Key)
+	if err != nil {
+		return nil, err
+	}
+	network, _ := config.NetParams()
+	return newCheckpointPublisher(bc, db, broadcaster, key, network, config.Sender, config.Interval, config.FeeRate)
+}
+
+func newCheckpointPublisher(bc checkPointChain, db ethdb.Database, broadcaster CheckpointBroadcaster, key *btcec.PrivateKey,
+	network *chaincfg.Params, sender string, interval uint64, feeRate int64) (*CheckpointPublisher, error) {
+	address, err := taprootAddress(key, network)
+	if err != nil {
+		return nil, err
+	}
+	// the checkpoints are only trusted when they are sent by the configured sender
+	if address.EncodeAddress() != sender {
+		return nil, fmt.Errorf("the checkpoint key belongs to %s, not the sender %s", address.EncodeAddress(), sender)
+	}
+	p := &CheckpointPublisher{
+		chain:       bc,
+		db:          db,
+		broadcaster: broadcaster,
+		key:         key,
+		address:     address,
+		interval:    interval,
+		feeRate:     feeRate,
+		quit:        make(chan struct{}),
+	}
+	if cps := rawdb.ReadPublishedCheckPoints(db); len(cps) > 0 {
+		p.last = cps[len(cps)-1].Height
+	}
+	return p, nil
+}
+
+// Start implements node.Lifecycle, starting the publishing loop
+func (p *CheckpointPublisher) Start() error {
+	p.wg.Add(1)
+	go p.loop()
+	log.Info("Checkpoint publisher started", "sender", p.address.EncodeAddress(), "interval", p.interval, "last", p.last)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the publishing loop
+func (p *CheckpointPublisher) Stop() error {
+	close(p.quit)
+	p.wg.Wait()
+	log.Info("Checkpoint publisher stopped")
+	return nil
+}
+
+func (p *CheckpointPublisher) loop() {
+	defer p.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := p.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	if head := p.chain.CurrentBlock(); head != nil {
+		p.update(head.NumberU64())
+	}
+	for {
+		select {
+		case ev := <-headCh:
+			p.update(ev.Block.NumberU64())
+		case <-sub.Err():
+			return
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// update publishes the latest checkpoint at or below the head if it isn't published yet, the
+// checkpoints missed while the node was offline are skipped as only the latest one is used
+func (p *CheckpointPublisher) update(head uint64) {
+	height := head / p.interval * p.interval
+	if height == 0 || height <= p.last {
+		return
+	}
+	if time.Since(p.lastRetry) < publishRetryInterval {
+		return
+	}
+	if _, err := p.publish(height); err != nil {
+		log.Error("Failed to publish the checkpoint", "height", height, "err", err)
+		p.lastRetry = time.Now()
+	}
+}
+
+// publish anchors the state root of the block at height and records the publication
+func (p *CheckpointPublisher) publish(height uint64) (*rawdb.PublishedCheckPoint, error) {
+	header := p.chain.GetHeaderByNumber(height)
+	if header == nil {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	checkpoint := &CheckPoint{Height: height, Root: header.Root.String()}
+
+	utxos, err := p.broadcaster.ListUnspent(p.address)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := buildCheckPointTx(checkpoint, utxos, p.key, p.address, p.feeRate)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := p.broadcaster.BroadcastTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	published := &rawdb.PublishedCheckPoint{
+		Height: height,
+		Root:   header.Root,
+		TxID:   txid.String(),
+		Time:   uint64(time.Now().Unix()),
+	}
+	rawdb.WritePublishedCheckPoint(p.db, published)
+	p.last = height
+	log.Info("Published the checkpoint", "height", height, "root", header.Root, "txid", txid)
+	return published, nil
+}
+
+// buildCheckPointTx builds and signs the transaction carrying the checkpoint, the OP_RETURN
+// output comes first and the change goes back to the sender as the verifiers expect
+func buildCheckPointTx(checkpoint *CheckPoint, utxos []*btcapi.UnspentOutput, key *btcec.PrivateKey,
+	address btcutil.Address, feeRate int64) (*wire.MsgTx, error) {
+	payload, err := checkpoint.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	nullData, err := txscript.NullDataScript(payload)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// spend the largest outputs first to keep the transaction small
+	sorted := make([]*btcapi.UnspentOutput, 0, len(utxos))
+	for _, utxo := range utxos {
+		if bytes.Equal(utxo.Output.PkScript, pkScript) {
+			sorted = append(sorted, utxo)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Output.Value > sorted[j].Output.Value
+	})
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxOut(wire.NewTxOut(0, nullData))
+	tx.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	var (
+		total   int64
+		fee     int64
+		fetcher = txscript.NewMultiPrevOutFetcher(nil)
+	)
+	for _, utxo := range sorted {
+		tx.AddTxIn(wire.NewTxIn(utxo.Outpoint, nil, nil))
+		fetcher.AddPrevOut(*utxo.Outpoint, utxo.Output)
+		total += utxo.Output.Value
+
+		fee = estimateTaprootVSize(tx) * feeRate
+		if total >= fee+taprootDust {
+			break
+		}
+	}
+	if total < fee+taprootDust {
+		return nil, errInsufficientFunds
+	}
+	tx.TxOut[1].Value = total - fee
+
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, in := range tx.TxIn {
+		prevOut := fetcher.FetchPrevOutput(in.PreviousOutPoint)
+		witness, err := txscript.TaprootWitnessSignature(tx, sigHashes, i, prevOut.Value, prevOut.PkScript, txscript.SigHashDefault, key)
+		if err != nil {
+			return nil, err
+		}
+		in.Witness = witness
+	}
+	return tx, nil
+}
+
+// estimateTaprootVSize returns the virtual size of the transaction once its key path
+// taproot inputs are signed
+func estimateTaprootVSize(tx *wire.MsgTx) int64 {
+	signed := tx.Copy()
+	for _, in := range signed.TxIn {
+		in.Witness = wire.TxWitness{make([]byte, schnorr.SignatureSize)}
+	}
+	weight := signed.SerializeSizeStripped()*3 + signed.SerializeSize()
+	return int64((weight + 3) / 4)
+}
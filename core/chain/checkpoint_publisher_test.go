@@ -0,0 +1,176 @@
+package chain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/tools/utils/btcapi"
+)
+
+type testCheckPointChain struct {
+	current *types.Block
+	headers map[uint64]*types.Header
+	feed    event.Feed
+}
+
+func (c *testCheckPointChain) CurrentBlock() *types.Block { return c.current }
+
+func (c *testCheckPointChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.headers[number]
+}
+
+func (c *testCheckPointChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+type testBroadcaster struct {
+	utxos []*btcapi.UnspentOutput
+	txs   []*wire.MsgTx
+	err   error
+}
+
+func (b *testBroadcaster) LatestCheckPoint() (*CheckPoint, error) { return nil, errNoCheckPoint }
+
+func (b *testBroadcaster) ListUnspent(btcutil.Address) ([]*btcapi.UnspentOutput, error) {
+	return b.utxos, nil
+}
+
+func (b *testBroadcaster) BroadcastTx(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	b.txs = append(b.txs, tx)
+	hash := tx.TxHash()
+	return &hash, nil
+}
+
+func testUnspent(t *testing.T, address btcutil.Address, index byte, value int64) *btcapi.UnspentOutput {
+	pkScript, err := txscript.PayToAddrScript(address)
+	assert.NoError(t, err)
+	return &btcapi.UnspentOutput{
+		Outpoint: wire.NewOutPoint(&chainhash.Hash{index}, 0),
+		Output:   wire.NewTxOut(value, pkScript),
+	}
+}
+
+func verifyTxSignatures(t *testing.T, tx *wire.MsgTx, utxos []*btcapi.UnspentOutput) {
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, utxo := range utxos {
+		fetcher.AddPrevOut(*utxo.Outpoint, utxo.Output)
+	}
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, in := range tx.TxIn {
+		prevOut := fetcher.FetchPrevOutput(in.PreviousOutPoint)
+		vm, err := txscript.NewEngine(prevOut.PkScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, prevOut.Value, fetcher)
+		assert.NoError(t, err)
+		assert.NoError(t, vm.Execute())
+	}
+}
+
+func TestBuildCheckPointTx(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	address, err := taprootAddress(key, &chaincfg.TestNet3Params)
+	assert.NoError(t, err)
+	other, _ := btcec.NewPrivateKey()
+	otherAddress, _ := taprootAddress(other, &chaincfg.TestNet3Params)
+
+	checkpoint := &CheckPoint{Height: 400000, Root: common.Hash{0x12, 0x34}.Hex()}
+	utxos := []*btcapi.UnspentOutput{
+		testUnspent(t, address, 1, 5000),
+		testUnspent(t, otherAddress, 2, 100000),
+		testUnspent(t, address, 3, 6000),
+	}
+
+	tx, err := buildCheckPointTx(checkpoint, utxos, key, address, 2)
+	assert.NoError(t, err)
+	// the largest output of the sender covers the fee
+	assert.Equal(t, 1, len(tx.TxIn))
+	assert.Equal(t, *utxos[2].Outpoint, tx.TxIn[0].PreviousOutPoint)
+	assert.Equal(t, 2, len(tx.TxOut))
+	assert.Equal(t, utxos[2].Output.PkScript, tx.TxOut[1].PkScript)
+
+	fee := 6000 - tx.TxOut[1].Value
+	vsize := (int64(tx.SerializeSizeStripped())*3 + int64(tx.SerializeSize()) + 3) / 4
+	assert.Equal(t, vsize*2, fee)
+	verifyTxSignatures(t, tx, utxos)
+
+	parsed, err := checkPointFromTx(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, checkpoint, parsed)
+
+	// both outputs of the sender are needed
+	tx, err = buildCheckPointTx(checkpoint, utxos, key, address, 40)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxIn))
+	verifyTxSignatures(t, tx, utxos)
+
+	_, err = buildCheckPointTx(checkpoint, utxos, key, address, 100)
+	assert.Equal(t, errInsufficientFunds, err)
+}
+
+func TestCheckpointPublisher(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	network := &chaincfg.TestNet3Params
+	address, _ := taprootAddress(key, network)
+
+	bc := &testCheckPointChain{headers: make(map[uint64]*types.Header)}
+	for _, number := range []uint64{100, 200, 300} {
+		bc.headers[number] = &types.Header{Number: new(big.Int).SetUint64(number), Root: common.Hash{byte(number / 100)}}
+	}
+	broadcaster := &testBroadcaster{utxos: []*btcapi.UnspentOutput{testUnspent(t, address, 1, 100000)}}
+	db := rawdb.NewMemoryDatabase()
+
+	_, err := newCheckpointPublisher(bc, db, broadcaster, key, network, DefaultCheckPointSender, 100, 2)
+	assert.Error(t, err)
+	p, err := newCheckpointPublisher(bc, db, broadcaster, key, network, address.EncodeAddress(), 100, 2)
+	assert.NoError(t, err)
+
+	p.update(99)
+	assert.Equal(t, 0, len(broadcaster.txs))
+	p.update(150)
+	p.update(199)
+	assert.Equal(t, 1, len(broadcaster.txs))
+
+	// a failed publication is retried later
+	broadcaster.err = errors.New("broadcast failed")
+	p.update(200)
+	broadcaster.err = nil
+	p.update(201)
+	assert.Equal(t, 1, len(broadcaster.txs))
+	p.lastRetry = p.lastRetry.Add(-publishRetryInterval)
+	p.update(350)
+	assert.Equal(t, 2, len(broadcaster.txs))
+
+	published := rawdb.ReadPublishedCheckPoints(db)
+	assert.Equal(t, 2, len(published))
+	assert.Equal(t, uint64(100), published[0].Height)
+	assert.Equal(t, common.Hash{1}, published[0].Root)
+	assert.Equal(t, broadcaster.txs[0].TxHash().String(), published[0].TxID)
+	assert.Equal(t, uint64(300), published[1].Height)
+	assert.Equal(t, published[1], rawdb.ReadPublishedCheckPoint(db, 300))
+
+	cp, err := checkPointFromTx(broadcaster.txs[1])
+	assert.NoError(t, err)
+	assert.Equal(t, &CheckPoint{Height: 300, Root: common.Hash{3}.Hex()}, cp)
+
+	// the history survives restarts
+	p, err = newCheckpointPublisher(bc, db, broadcaster, key, network, address.EncodeAddress(), 100, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), p.last)
+	p.update(399)
+	assert.Equal(t, 2, len(broadcaster.txs))
+}
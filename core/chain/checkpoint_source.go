@@ -0,0 +1,435 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mapprotocol/atlas/tools/utils/btcapi"
+	"github.com/mapprotocol/atlas/tools/utils/mempool"
+)
+
+const (
+	CheckPointSourceMempool  = "mempool"
+	CheckPointSourceBitcoind = "bitcoind"
+	CheckPointSourceFile     = "file"
+
+	CheckPointNetworkMainnet = "mainnet"
+	CheckPointNetworkTestnet = "testnet"
+
+	// CheckPointPolicyWarn logs a disagreement with the anchored checkpoint and keeps syncing
+	CheckPointPolicyWarn = "warn"
+	// CheckPointPolicyRefuse starts the node without syncing with peers
+	CheckPointPolicyRefuse = "refuse"
+	// CheckPointPolicyHalt stops the node from starting
+	CheckPointPolicyHalt = "halt"
+)
+
+var errNoCheckPoint = errors.New("no checkpoint found")
+
+// CheckPointConfig selects where the checkpoints anchored on bitcoin are read from, and what
+// the node does when the local chain disagrees with them
+type CheckPointConfig struct {
+	Source   string `toml:",omitempty"`
+	Network  string `toml:",omitempty"`
+	Sender   string `toml:",omitempty"`
+	Bitcoind string `toml:",omitempty"` // bitcoind JSON-RPC URL, with the credentials as user info
+	File     string `toml:",omitempty"`
+	Policy   string `toml:",omitempty"`
+
+	// Publisher settings, only the operator anchoring the checkpoints enables them
+	Publish  bool   `toml:",omitempty"`
+	Interval uint64 `toml:",omitempty"` // blocks between two checkpoints
+	Key      string `toml:",omitempty"` // file holding the hex private key of the sender
+	FeeRate  int64  `toml:",omitempty"` // sat/vB
+}
+
+var DefaultCheckPointConfig = CheckPointConfig{
+	Source:   CheckPointSourceMempool,
+	Network:  CheckPointNetworkTestnet,
+	Sender:   DefaultCheckPointSender,
+	Policy:   CheckPointPolicyHalt,
+	Interval: uint64(checkPointLength),
+	FeeRate:  2,
+}
+
+func (c *CheckPointConfig) NetParams() (*chaincfg.Params, error) {
+	switch c.Network {
+	case CheckPointNetworkMainnet:
+		return &chaincfg.MainNetParams, nil
+	case CheckPointNetworkTestnet:
+		return &chaincfg.TestNet3Params, nil
+	}
+	return nil, fmt.Errorf("unsupported checkpoint network: %s", c.Network)
+}
+
+func (c *CheckPointConfig) Validate() error {
+	if _, err := c.NetParams(); err != nil {
+		return err
+	}
+	switch c.Policy {
+	case CheckPointPolicyWarn, CheckPointPolicyRefuse, CheckPointPolicyHalt:
+	default:
+		return fmt.Errorf("unsupported checkpoint policy: %s", c.Policy)
+	}
+	switch c.Source {
+	case CheckPointSourceMempool:
+	case CheckPointSourceBitcoind:
+		if c.Bitcoind == "" {
+			return errors.New("the bitcoind checkpoint source needs a JSON-RPC URL")
+		}
+	case CheckPointSourceFile:
+		if c.File == "" {
+			return errors.New("the file checkpoint source needs a file")
+		}
+	default:
+		return fmt.Errorf("unsupported checkpoint source: %s", c.Source)
+	}
+	if c.Source != CheckPointSourceFile && c.Sender == "" {
+		return errors.New("checkpoint sender address is empty")
+	}
+	if c.Publish {
+		if c.Source == CheckPointSourceFile {
+			return errors.New("the file checkpoint source can't publish checkpoints")
+		}
+		if c.Key == "" {
+			return errors.New("publishing checkpoints needs the key of the sender")
+		}
+		if c.Interval == 0 || c.FeeRate <= 0 {
+			return errors.New("invalid checkpoint interval or fee rate")
+		}
+	}
+	return nil
+}
+
+// CheckpointSource returns the latest checkpoint of the atlas chain anchored on bitcoin
+type CheckpointSource interface {
+	LatestCheckPoint() (*CheckPoint, error)
+}
+
+// CheckpointBroadcaster is a checkpoint source that the checkpoint publisher can fund and
+// broadcast its transactions through
+type CheckpointBroadcaster interface {
+	CheckpointSource
+	ListUnspent(address btcutil.Address) ([]*btcapi.UnspentOutput, error)
+	BroadcastTx(tx *wire.MsgTx) (*chainhash.Hash, error)
+}
+
+func NewCheckpointSource(config *CheckPointConfig) (CheckpointSource, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	network, _ := config.NetParams()
+	switch config.Source {
+	case CheckPointSourceBitcoind:
+		return NewBitcoindCheckpointSource(config.Bitcoind, config.Sender, network), nil
+	case CheckPointSourceFile:
+		return NewFileCheckpointSource(config.File), nil
+	}
+	return NewMempoolCheckpointSource(config.Sender, network), nil
+}
+
+// latestCheckPoint returns the checkpoint with the highest height
+func latestCheckPoint(checkpoints []*CheckPoint) (*CheckPoint, error) {
+	var latest *CheckPoint
+	for _, cp := range checkpoints {
+		if latest == nil || cp.Height > latest.Height {
+			latest = cp
+		}
+	}
+	if latest == nil {
+		return nil, errNoCheckPoint
+	}
+	return latest, nil
+}
+
+// checkPointFromTx returns the checkpoint carried by the OP_RETURN output of the transaction
+func checkPointFromTx(tx *wire.MsgTx) (*CheckPoint, error) {
+	for _, out := range tx.TxOut {
+		if IsCheckPointData(out.PkScript) {
+			return checkPointFromScript(out.PkScript)
+		}
+	}
+	return nil, errNoCheckPoint
+}
+
+// MempoolCheckpointSource reads the checkpoints sent by the sender from mempool.space
+type MempoolCheckpointSource struct {
+	sender string
+	client *mempool.MempoolClient
+}
+
+func NewMempoolCheckpointSource(sender string, network *chaincfg.Params) *MempoolCheckpointSource {
+	return &MempoolCheckpointSource{sender: sender, client: mempool.NewClient(network)}
+}
+
+func (s *MempoolCheckpointSource) LatestCheckPoint() (*CheckPoint, error) {
+	simTxs, err := s.client.GetTxsFromAddress(s.sender)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []*CheckPoint
+	for _, tx := range simTxs {
+		if s.sender != tx.Sender || len(tx.OutPuts) != 2 {
+			log.Info("fetch the latest checkpoint, invalid tx", "txid", tx.Txid)
+			continue
+		}
+		script, err := hex.DecodeString(tx.OutPuts[0].Scriptpubkey)
+		if err != nil {
+			log.Error("decode the Scriptpubkey failed", "err", err, "txid", tx.Txid.String())
+			continue
+		}
+		cp, err := checkPointFromScript(script)
+		if err != nil {
+			log.Error("not a OP_RETURN tx", "txhash", tx.Txid, "error", err)
+			continue
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return latestCheckPoint(checkpoints)
+}
+
+func (s *MempoolCheckpointSource) ListUnspent(address btcutil.Address) ([]*btcapi.UnspentOutput, error) {
+	return s.client.ListUnspent(address)
+}
+
+func (s *MempoolCheckpointSource) BroadcastTx(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	return s.client.BroadcastTx(tx)
+}
+
+// BitcoindCheckpointSource reads the checkpoints sent by the sender from a bitcoind node, the
+// node must run with txindex so that the inputs of the checkpoint transactions can be resolved
+type BitcoindCheckpointSource struct {
+	url     string
+	sender  string
+	network *chaincfg.Params
+}
+
+func NewBitcoindCheckpointSource(url, sender string, network *chaincfg.Params) *BitcoindCheckpointSource {
+	return &BitcoindCheckpointSource{url: url, sender: sender, network: network}
+}
+
+type bitcoindRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type bitcoindResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *BitcoindCheckpointSource) call(result interface{}, method string, params ...interface{}) error {
+	body, err := json.Marshal(&bitcoindRequest{JSONRPC: "1.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var ret bitcoindResponse
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return fmt.Errorf("bitcoind %s: %s", method, resp.Status)
+	}
+	if ret.Error != nil {
+		return fmt.Errorf("bitcoind %s: %s (%d)", method, ret.Error.Message, ret.Error.Code)
+	}
+	return json.Unmarshal(ret.Result, result)
+}
+
+func (s *BitcoindCheckpointSource) getRawTransaction(txid string) (*wire.MsgTx, error) {
+	var raw string
+	if err := s.call(&raw, "getrawtransaction", txid); err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// sentBySender returns whether the first input of the transaction spends an output of the sender
+func (s *BitcoindCheckpointSource) sentBySender(tx *wire.MsgTx) (bool, error) {
+	if len(tx.TxIn) == 0 {
+		return false, nil
+	}
+	prevOut := tx.TxIn[0].PreviousOutPoint
+	if prevOut.Hash == (chainhash.Hash{}) {
+		return false, nil
+	}
+	prev, err := s.getRawTransaction(prevOut.Hash.String())
+	if err != nil {
+		return false, err
+	}
+	if int(prevOut.Index) >= len(prev.TxOut) {
+		return false, nil
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(prev.TxOut[prevOut.Index].PkScript, s.network)
+	if err != nil {
+		return false, nil
+	}
+	for _, addr := range addrs {
+		if addr.EncodeAddress() == s.sender {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type bitcoindUnspent struct {
+	Txid         string  `json:"txid"`
+	Vout         uint32  `json:"vout"`
+	ScriptPubKey string  `json:"scriptPubKey"`
+	Amount       float64 `json:"amount"`
+}
+
+func (s *BitcoindCheckpointSource) scanUnspent(address string) ([]*bitcoindUnspent, error) {
+	var scan struct {
+		Success  bool               `json:"success"`
+		Unspents []*bitcoindUnspent `json:"unspents"`
+	}
+	if err := s.call(&scan, "scantxoutset", "start", []string{fmt.Sprintf("addr(%s)", address)}); err != nil {
+		return nil, err
+	}
+	if !scan.Success {
+		return nil, errors.New("bitcoind scantxoutset failed")
+	}
+	return scan.Unspents, nil
+}
+
+// LatestCheckPoint scans the unspent outputs of the sender, the change of every checkpoint
+// transaction goes back to the sender
+func (s *BitcoindCheckpointSource) LatestCheckPoint() (*CheckPoint, error) {
+	unspents, err := s.scanUnspent(s.sender)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		checkpoints []*CheckPoint
+		seen        = make(map[string]bool)
+	)
+	for _, unspent := range unspents {
+		if seen[unspent.Txid] {
+			continue
+		}
+		seen[unspent.Txid] = true
+
+		tx, err := s.getRawTransaction(unspent.Txid)
+		if err != nil {
+			return nil, err
+		}
+		cp, err := checkPointFromTx(tx)
+		if err != nil {
+			continue
+		}
+		ok, err := s.sentBySender(tx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			log.Info("fetch the latest checkpoint, not sent by the sender", "txid", unspent.Txid)
+			continue
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return latestCheckPoint(checkpoints)
+}
+
+func (s *BitcoindCheckpointSource) ListUnspent(address btcutil.Address) ([]*btcapi.UnspentOutput, error) {
+	unspents, err := s.scanUnspent(address.EncodeAddress())
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]*btcapi.UnspentOutput, 0, len(unspents))
+	for _, unspent := range unspents {
+		hash, err := chainhash.NewHashFromStr(unspent.Txid)
+		if err != nil {
+			return nil, err
+		}
+		script, err := hex.DecodeString(unspent.ScriptPubKey)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := btcutil.NewAmount(unspent.Amount)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, &btcapi.UnspentOutput{
+			Outpoint: wire.NewOutPoint(hash, unspent.Vout),
+			Output:   wire.NewTxOut(int64(amount), script),
+		})
+	}
+	return outputs, nil
+}
+
+func (s *BitcoindCheckpointSource) BroadcastTx(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	var txid string
+	if err := s.call(&txid, "sendrawtransaction", hex.EncodeToString(buf.Bytes())); err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(txid)
+}
+
+// FileCheckpointSource reads checkpoints from a JSON file holding a checkpoint or a list of
+// checkpoints, for nodes that can't reach bitcoin and for tests
+type FileCheckpointSource struct {
+	path string
+}
+
+func NewFileCheckpointSource(path string) *FileCheckpointSource {
+	return &FileCheckpointSource{path: path}
+}
+
+func (s *FileCheckpointSource) LatestCheckPoint() (*CheckPoint, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var checkpoints []*CheckPoint
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &checkpoints)
+	} else {
+		var cp CheckPoint
+		err = json.Unmarshal(data, &cp)
+		checkpoints = append(checkpoints, &cp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", s.path, err)
+	}
+	return latestCheckPoint(checkpoints)
+}
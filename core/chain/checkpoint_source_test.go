@@ -0,0 +1,192 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+func TestFileCheckpointSource(t *testing.T) {
+	dir := t.TempDir()
+	single := filepath.Join(dir, "single.json")
+	list := filepath.Join(dir, "list.json")
+	empty := filepath.Join(dir, "empty.json")
+	assert.NoError(t, ioutil.WriteFile(single, []byte(`{"height": 200000, "root": "0x01"}`), 0644))
+	assert.NoError(t, ioutil.WriteFile(list, []byte(`[{"height": 200000, "root": "0x01"}, {"height": 600000, "root": "0x03"}, {"height": 400000, "root": "0x02"}]`), 0644))
+	assert.NoError(t, ioutil.WriteFile(empty, []byte(`[]`), 0644))
+
+	cp, err := NewFileCheckpointSource(single).LatestCheckPoint()
+	assert.NoError(t, err)
+	assert.Equal(t, &CheckPoint{Height: 200000, Root: "0x01"}, cp)
+
+	cp, err = NewFileCheckpointSource(list).LatestCheckPoint()
+	assert.NoError(t, err)
+	assert.Equal(t, &CheckPoint{Height: 600000, Root: "0x03"}, cp)
+
+	_, err = NewFileCheckpointSource(empty).LatestCheckPoint()
+	assert.Equal(t, errNoCheckPoint, err)
+
+	_, err = NewFileCheckpointSource(filepath.Join(dir, "missing.json")).LatestCheckPoint()
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestVerifyCheckPoint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "checkpoints.json")
+	root := common.HexToHash("0x01")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"height": 200000, "root": "`+root.Hex()+`"}`), 0644))
+
+	chain := &testCheckPointChain{
+		current: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(200001)}),
+		headers: map[uint64]*types.Header{200000: {Number: big.NewInt(200000), Root: root}},
+	}
+	cp, err := VerifyCheckPoint(NewFileCheckpointSource(file), chain)
+	assert.NoError(t, err)
+	assert.Equal(t, &CheckPoint{Height: 200000, Root: root.Hex()}, cp)
+
+	chain.headers[200000].Root = common.HexToHash("0x02")
+	_, err = VerifyCheckPoint(NewFileCheckpointSource(file), chain)
+	assert.True(t, errors.Is(err, ErrCheckPointMismatch))
+
+	// a source that can't be read is not a mismatch
+	_, err = VerifyCheckPoint(NewFileCheckpointSource(filepath.Join(dir, "missing.json")), chain)
+	assert.True(t, errors.Is(err, ErrCheckPointUnavailable))
+	assert.False(t, errors.Is(err, ErrCheckPointMismatch))
+}
+
+func TestCheckPointConfig(t *testing.T) {
+	config := DefaultCheckPointConfig
+	assert.NoError(t, config.Validate())
+	source, err := NewCheckpointSource(&config)
+	assert.NoError(t, err)
+	assert.IsType(t, &MempoolCheckpointSource{}, source)
+
+	config.Source = CheckPointSourceBitcoind
+	assert.Error(t, config.Validate())
+	config.Bitcoind = "http://127.0.0.1:18332"
+	source, err = NewCheckpointSource(&config)
+	assert.NoError(t, err)
+	assert.IsType(t, &BitcoindCheckpointSource{}, source)
+
+	config.Network = "regtest"
+	assert.Error(t, config.Validate())
+	config.Network = CheckPointNetworkMainnet
+	config.Policy = "ignore"
+	assert.Error(t, config.Validate())
+}
+
+func checkPointScript(height uint64, root common.Hash) []byte {
+	data := make([]byte, 40)
+	binary.LittleEndian.PutUint64(data[:8], height)
+	copy(data[8:], root[:])
+	script, _ := txscript.NullDataScript(data)
+	return script
+}
+
+func serializeTx(t *testing.T, tx *wire.MsgTx) string {
+	var buf bytes.Buffer
+	assert.NoError(t, tx.Serialize(&buf))
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// newBitcoindMock serves scantxoutset and getrawtransaction for the given transactions
+func newBitcoindMock(t *testing.T, unspents []*wire.MsgTx, txs ...*wire.MsgTx) *httptest.Server {
+	raws := make(map[string]string)
+	for _, tx := range append(unspents, txs...) {
+		raws[tx.TxHash().String()] = serializeTx(t, tx)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req bitcoindRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{}
+		switch req.Method {
+		case "scantxoutset":
+			var items []map[string]interface{}
+			for _, tx := range unspents {
+				items = append(items, map[string]interface{}{"txid": tx.TxHash().String(), "vout": 1})
+			}
+			result = map[string]interface{}{"success": true, "unspents": items}
+		case "getrawtransaction":
+			raw, ok := raws[req.Params[0].(string)]
+			if !ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{"result": nil, "error": map[string]interface{}{"code": -5, "message": "No such mempool or blockchain transaction"}})
+				return
+			}
+			result = raw
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": result, "error": nil})
+	}))
+}
+
+func TestBitcoindCheckpointSource(t *testing.T) {
+	network := &chaincfg.TestNet3Params
+	senderKey, _ := btcec.NewPrivateKey()
+	otherKey, _ := btcec.NewPrivateKey()
+	sender, err := makeTpAddress(senderKey, network)
+	assert.NoError(t, err)
+	other, err := makeTpAddress(otherKey, network)
+	assert.NoError(t, err)
+	senderScript, _ := txscript.PayToAddrScript(sender)
+	otherScript, _ := txscript.PayToAddrScript(other)
+
+	funding := wire.NewMsgTx(wire.TxVersion)
+	funding.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	funding.AddTxOut(wire.NewTxOut(100000, senderScript))
+	funding.AddTxOut(wire.NewTxOut(100000, otherScript))
+
+	anchor := func(index uint32, height uint64, root common.Hash) *wire.MsgTx {
+		prev := funding.TxHash()
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prev, index), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(0, checkPointScript(height, root)))
+		tx.AddTxOut(wire.NewTxOut(90000, senderScript))
+		return tx
+	}
+	first := anchor(0, 200000, common.Hash{0xaa})
+	second := anchor(0, 400000, common.Hash{0xbb})
+	// sent by another address to the sender, must be ignored
+	forged := anchor(1, 600000, common.Hash{0xcc})
+	plain := wire.NewMsgTx(wire.TxVersion)
+	plain.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 0), nil, nil))
+	plain.AddTxOut(wire.NewTxOut(1000, senderScript))
+
+	server := newBitcoindMock(t, []*wire.MsgTx{first, forged, plain, second}, funding)
+	defer server.Close()
+
+	cp, err := NewBitcoindCheckpointSource(server.URL, sender.EncodeAddress(), network).LatestCheckPoint()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(400000), cp.Height)
+	assert.Equal(t, common.Hash{0xbb}.Hex(), cp.Root)
+
+	// no checkpoint anchored by the sender
+	server2 := newBitcoindMock(t, []*wire.MsgTx{forged, plain}, funding)
+	defer server2.Close()
+	_, err = NewBitcoindCheckpointSource(server2.URL, sender.EncodeAddress(), network).LatestCheckPoint()
+	assert.Equal(t, errNoCheckPoint, err)
+
+	// the funding transaction is unknown to the node
+	server3 := newBitcoindMock(t, []*wire.MsgTx{first})
+	defer server3.Close()
+	_, err = NewBitcoindCheckpointSource(server3.URL, sender.EncodeAddress(), network).LatestCheckPoint()
+	assert.Error(t, err)
+}
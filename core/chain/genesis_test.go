@@ -205,6 +205,34 @@ func TestGenesisHashes(t *testing.T) {
 		}
 	}
 }
+func TestGenesisRelayerRewardRate(t *testing.T) {
+	config := *params2.IstanbulTestChainConfig
+	istanbul := *config.Istanbul
+	config.Istanbul = &istanbul
+
+	config.Istanbul.RelayerRewardRate = params2.MaxRelayerRewardRate
+	if _, err := (&Genesis{Config: &config}).Commit(rawdb.NewMemoryDatabase()); err != nil {
+		t.Fatalf("failed to commit genesis with the whole rewards to relayers: %v", err)
+	}
+	config.Istanbul.RelayerRewardRate = params2.MaxRelayerRewardRate + 1
+	if _, err := (&Genesis{Config: &config}).Commit(rawdb.NewMemoryDatabase()); err == nil {
+		t.Fatal("committed genesis with more than the validator rewards to relayers")
+	}
+}
+
+func TestGenesisForkOrder(t *testing.T) {
+	config := *params2.TestChainConfig
+	config.BLS12377Block = big.NewInt(10)
+	config.SlashingBlock = big.NewInt(10)
+	if _, err := (&Genesis{Config: &config}).Commit(rawdb.NewMemoryDatabase()); err != nil {
+		t.Fatalf("failed to commit genesis with the slashing precompiles after the bls12377 ones: %v", err)
+	}
+	config.SlashingBlock = big.NewInt(5)
+	if _, err := (&Genesis{Config: &config}).Commit(rawdb.NewMemoryDatabase()); err == nil {
+		t.Fatal("committed genesis with the slashing precompiles before the bls12377 ones")
+	}
+}
+
 func generateAddr() common.Address {
 	priv, _ := crypto.GenerateKey()
 	privHex := hex.EncodeToString(crypto.FromECDSA(priv))
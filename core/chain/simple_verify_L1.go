@@ -5,18 +5,24 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/mapprotocol/atlas/tools/utils/mempool"
 	"strings"
 )
 
 var (
-	// for test
-	l1Address        = "tb1pkepxd60wx4z33qdgz5vad5dvtus6syv3m5m6xc3kthdfar9jmmvq3a8mp7"
-	checkPointLength = 200000
-	testnet          = true
+	// DefaultCheckPointSender is the bitcoin testnet address that anchors the checkpoints
+	DefaultCheckPointSender = "tb1pkepxd60wx4z33qdgz5vad5dvtus6syv3m5m6xc3kthdfar9jmmvq3a8mp7"
+	checkPointLength        = 200000
+)
+
+var (
+	// ErrCheckPointUnavailable is returned when the checkpoint source can't provide the latest
+	// checkpoint, the local chain is not verified then
+	ErrCheckPointUnavailable = errors.New("checkpoint unavailable")
+	// ErrCheckPointMismatch is returned when the local chain disagrees with the latest checkpoint
+	ErrCheckPointMismatch = errors.New("checkpoint mismatch")
 )
 
 type CheckPoint struct {
@@ -33,6 +39,20 @@ func FromBytes(data []byte) (*CheckPoint, error) {
 	v.Root = hexutil.Encode(data[8:])
 	return v, nil
 }
+
+// Bytes returns the 40 bytes payload of the OP_RETURN output, the reverse of FromBytes
+func (c *CheckPoint) Bytes() ([]byte, error) {
+	root, err := hexutil.Decode(c.Root)
+	if err != nil {
+		return nil, err
+	}
+	if len(root) != common.HashLength {
+		return nil, errors.New("invalid root length in checkpoint")
+	}
+	data := make([]byte, 8, 40)
+	binary.LittleEndian.PutUint64(data, c.Height)
+	return append(data, root...), nil
+}
 func (c *CheckPoint) String() string {
 	return fmt.Sprintf("root=%s, height:%v", c.Root, c.Height)
 }
@@ -53,7 +73,7 @@ func IsCheckPointData(script []byte) bool {
 	return false
 }
 
-func getCheckPointByHeight(height uint64, bc *BlockChain) (*CheckPoint, error) {
+func getCheckPointByHeight(height uint64, bc checkPointChain) (*CheckPoint, error) {
 	head := bc.GetHeaderByNumber(height)
 	if head != nil {
 		return &CheckPoint{
@@ -63,7 +83,7 @@ func getCheckPointByHeight(height uint64, bc *BlockChain) (*CheckPoint, error) {
 	}
 	return nil, errors.New("not found the head")
 }
-func verifyBlockWithCheckPoint(ck *CheckPoint, bc *BlockChain) error {
+func verifyBlockWithCheckPoint(ck *CheckPoint, bc checkPointChain) error {
 	ck0, err := getCheckPointByHeight(ck.Height, bc)
 	if err != nil {
 		return err
@@ -71,44 +91,7 @@ func verifyBlockWithCheckPoint(ck *CheckPoint, bc *BlockChain) error {
 	if ck0.Equal(ck) {
 		return nil
 	}
-	return errors.New("verify checkpoint failed" + ck.String() + ck0.String())
-}
-func fetchLatestCheckPoint(sender string, network *chaincfg.Params) (*CheckPoint, error) {
-	client := mempool.NewClient(network)
-	cCheckPoint := &CheckPoint{}
-	simTxs, err := client.GetTxsFromAddress(sender)
-	if err != nil {
-		return nil, err
-	}
-	// check latest checkpoint match with the config checkpoint
-	for i := range simTxs {
-		tx := simTxs[len(simTxs)-i-1]
-		if sender == tx.Sender && len(tx.OutPuts) == 2 {
-			//str := tx.OutPuts[0].Scriptpubkey_asm
-			script, err := hex.DecodeString(tx.OutPuts[0].Scriptpubkey)
-			if err != nil {
-				log.Error("decode the Scriptpubkey failed", "err", err, "txid", tx.Txid.String())
-				continue
-			}
-			if !IsCheckPointData(script) {
-				fmt.Println(tx.Txid.String(), "is a op_return tx")
-			}
-			cc, err := checkPointFromScript(script)
-			if err != nil {
-				log.Error("not a OP_RETURN tx", "txhash", tx.Txid, "error", err)
-				continue
-			}
-			if cCheckPoint != nil {
-				if cc.Height < cCheckPoint.Height {
-					continue
-				}
-			}
-			cCheckPoint = cc
-		} else {
-			log.Info("fetch the latest checkpoint, invalid tx", "txid", tx.Txid)
-		}
-	}
-	return cCheckPoint, nil
+	return fmt.Errorf("%w: anchored %v, local %v", ErrCheckPointMismatch, ck, ck0)
 }
 func checkPointFromScript(script []byte) (*CheckPoint, error) {
 	if len(script) == 42 {
@@ -131,38 +114,28 @@ func checkPointFromAsm(str string) (*CheckPoint, error) {
 	return FromBytes(b0)
 }
 
-func VerifyCheckPoint(testnet bool, bc *BlockChain) error {
-	netParams := &chaincfg.MainNetParams
-	if testnet {
-		netParams = &chaincfg.TestNet3Params
-	}
-	sender := l1Address
-	//sender, err := btcutil.DecodeAddress(l1Address, netParams)
-	//if err != nil {
-	//	log.Error("check point verify failed [DecodeAddress]", "error", err)
-	//	// make it pass
-	//	return nil
-	//}
-	checkpoint, err := fetchLatestCheckPoint(sender, netParams)
+// VerifyCheckPoint checks that the local chain has the state root of the latest checkpoint
+// anchored on bitcoin, and returns the checkpoint. Only a disagreement of the local chain is
+// reported as ErrCheckPointMismatch.
+func VerifyCheckPoint(source CheckpointSource, bc checkPointChain) (*CheckPoint, error) {
+	checkpoint, err := source.LatestCheckPoint()
 	if err != nil {
-		log.Error("check point verify failed [fetchLatestCheckPoint]", "error", err)
-		// make it pass
-		return nil
+		return nil, fmt.Errorf("%w: %v", ErrCheckPointUnavailable, err)
 	}
 	cur := bc.CurrentBlock()
 	if cur == nil {
 		log.Info("current block is nil....,not verify")
-		return nil
+		return checkpoint, nil
 	}
 	curHeight := cur.Number().Uint64()
 	if curHeight < checkpoint.Height {
 		log.Info("current height is low than checkpoint, not verify")
-		return nil
+		return checkpoint, nil
 	}
 	err = verifyBlockWithCheckPoint(checkpoint, bc)
 	if err != nil {
 		log.Error("check point verify failed [verifyBlockWithCheckPoint]", "error", err)
-		return err
+		return checkpoint, err
 	}
-	return nil
+	return checkpoint, nil
 }
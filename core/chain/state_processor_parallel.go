@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/abstract"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/metrics"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	parallelMergedMeter     = metrics.NewRegisteredMeter("chain/parallel/merged", nil)
+	parallelReexecutedMeter = metrics.NewRegisteredMeter("chain/parallel/reexecuted", nil)
+)
+
+// speculativeTx is a block transaction executed on its own copy of the state
+// the block transactions start from.
+type speculativeTx struct {
+	tx     *types.Transaction
+	msg    types.Message
+	msgErr error
+
+	statedb *state.StateDB
+	rwSet   *state.ReadWriteSet
+	result  *ExecutionResult
+	err     error
+
+	done chan struct{} // closed once the speculative execution is over
+}
+
+// execute applies the transaction to its state copy, recording what it reads
+// and writes. The block gas pool is only checked when merging.
+func (spec *speculativeTx) execute(blockContext vm.BlockContext, config *params.ChainConfig, cfg vm.Config, signer types.Signer) {
+	if spec.msg, spec.msgErr = spec.tx.AsMessage(signer, blockContext.BaseFee); spec.msgErr != nil {
+		return
+	}
+	spec.statedb.StartTracking()
+	evm := vm.NewEVM(blockContext, NewEVMTxContext(spec.msg), spec.statedb, config, cfg)
+	spec.result, spec.err = ApplyMessage(evm, spec.msg, new(core.GasPool).AddGas(spec.msg.Gas()))
+	spec.rwSet = spec.statedb.StopTracking()
+}
+
+// mergeable reports whether the speculative execution succeeded, fits in the
+// block gas pool and did not read any state written by the transactions merged
+// before it.
+func (spec *speculativeTx) mergeable(gp *core.GasPool, written map[state.StateKey]struct{}) bool {
+	if spec.msgErr != nil || spec.err != nil || spec.rwSet == nil || !spec.rwSet.Complete() {
+		return false
+	}
+	return gp.Gas() >= spec.msg.Gas() && !spec.rwSet.ConflictsWith(written)
+}
+
+// useParallelExecution reports whether the transactions are executed in
+// parallel. Tracing and preimage recording need the sequential execution.
+func useParallelExecution(cfg vm.Config, txs types.Transactions) bool {
+	return cfg.ParallelExecution && !cfg.Debug && !cfg.EnablePreimageRecording && len(txs) > 1
+}
+
+// applyTransactionsParallel applies the transactions to the state like the
+// sequential loop of StateProcessor.Process, producing the same receipts and
+// state.
+//
+// Every transaction is first executed speculatively on a copy of the initial
+// state. The results are then merged in order: a transaction that failed, or
+// read state written by a transaction merged before it, is executed again on
+// the merged state instead.
+func applyTransactionsParallel(config *params.ChainConfig, bc abstract.ChainContext, author *common.Address, gp *core.GasPool, statedb *state.StateDB, header *types.Header, txs types.Transactions, usedGas *uint64, cfg vm.Config) (types.Receipts, []*types.Log, error) {
+	var (
+		signer    = types.MakeSigner(config, header.Number)
+		blockHash = header.Hash()
+		specs     = make([]*speculativeTx, len(txs))
+		jobs      = make(chan *speculativeTx)
+		quit      = make(chan struct{})
+		workers   = cfg.ParallelWorkers
+	)
+	defer close(quit)
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	for i, tx := range txs {
+		specs[i] = &speculativeTx{tx: tx, done: make(chan struct{})}
+	}
+	// Copy the initial state before the merges modify it, the copies of the
+	// transactions are then made while the previous ones execute
+	base := statedb.Copy()
+	go func() {
+		defer close(jobs)
+		for i, spec := range specs {
+			spec.statedb = base.Copy()
+			spec.statedb.Prepare(spec.tx.Hash(), i)
+			select {
+			case jobs <- spec:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		go func() {
+			blockContext := NewEVMBlockContext(header, bc, author)
+			for spec := range jobs {
+				select {
+				case <-quit:
+				default:
+					spec.execute(blockContext, config, cfg, signer)
+				}
+				close(spec.done)
+			}
+		}()
+	}
+
+	var (
+		receipts types.Receipts
+		allLogs  []*types.Log
+		written  = make(map[state.StateKey]struct{})
+		vmenv    = vm.NewEVM(NewEVMBlockContext(header, bc, author), vm.TxContext{}, statedb, config, cfg)
+	)
+	for i, spec := range specs {
+		<-spec.done
+
+		statedb.Prepare(spec.tx.Hash(), i)
+		var (
+			receipt *types.Receipt
+			rwSet   *state.ReadWriteSet
+		)
+		if spec.mergeable(gp, written) {
+			statedb.MergeWrites(spec.statedb, spec.rwSet)
+			for _, log := range spec.statedb.GetLogs(spec.tx.Hash()) {
+				cpy := *log
+				statedb.AddLog(&cpy)
+			}
+			// Same gas accounting as buying the gas and refunding the leftover
+			if err := gp.SubGas(spec.result.UsedGas); err != nil {
+				return nil, nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, spec.tx.Hash().Hex(), err)
+			}
+			receipt = finaliseTransaction(spec.msg, config, statedb, header.Number, blockHash, spec.tx, usedGas, spec.result)
+			rwSet = spec.rwSet
+			parallelMergedMeter.Mark(1)
+		} else {
+			if spec.msgErr != nil {
+				return nil, nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, spec.tx.Hash().Hex(), spec.msgErr)
+			}
+			var err error
+			statedb.StartTracking()
+			receipt, err = applyTransaction(spec.msg, config, bc, author, gp, statedb, header.Number, blockHash, spec.tx, usedGas, vmenv)
+			rwSet = statedb.StopTracking()
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, spec.tx.Hash().Hex(), err)
+			}
+			parallelReexecutedMeter.Mark(1)
+		}
+		for key := range rwSet.Writes() {
+			written[key] = struct{}{}
+		}
+		spec.statedb = nil
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+	return receipts, allLogs, nil
+}
@@ -0,0 +1,250 @@
+package chain
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
+
+	"github.com/mapprotocol/atlas/core"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/params"
+)
+
+var (
+	// counterCode increments slot 0 and emits an empty log
+	counterCode = common.FromHex("600054600101600055600060006000a000")
+	// callerCounterCode increments the slot of the caller and emits a log with the caller as topic
+	callerCounterCode = common.FromHex("33546001013355336000600060a100")
+	// coinbaseReaderCode stores the coinbase balance in slot 0
+	coinbaseReaderCode = common.FromHex("41316000550000")
+	// hashLoopCode hashes 1000 times and stores the result in the slot of the caller
+	hashLoopCode = common.FromHex("6103e85b6020600020600052600190038060035750600051335500")
+	// revertingCode calls the empty account, creates an empty contract and reverts
+	revertingCode = common.FromHex("6000600060006000600061c0e05af150600060006000f05060006000fd")
+	// revertedCallerCode calls the reverting contract and stores 1 in slot 0
+	revertedCallerCode = common.FromHex("6000600060006000600061c0055af150600160005500")
+
+	counterAddr        = common.HexToAddress("0xc001")
+	callerCounterAddr  = common.HexToAddress("0xc002")
+	coinbaseReaderAddr = common.HexToAddress("0xc003")
+	hashLoopAddr       = common.HexToAddress("0xc004")
+	revertingAddr      = common.HexToAddress("0xc005")
+	revertedCallerAddr = common.HexToAddress("0xc006")
+	emptyAddr          = common.HexToAddress("0xc0e0")
+	parallelCoinbase   = common.HexToAddress("0xc0ffee")
+)
+
+// parallelTestEnv is a state with funded accounts and the test contracts.
+type parallelTestEnv struct {
+	config  *params.ChainConfig
+	signer  types.Signer
+	header  *types.Header
+	statedb *state.StateDB
+	keys    []*ecdsa.PrivateKey
+	nonces  []uint64
+}
+
+func newParallelTestEnv(t testing.TB, accounts int) *parallelTestEnv {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	env := &parallelTestEnv{
+		config:  params.TestChainConfig,
+		header:  &types.Header{Number: big.NewInt(1), GasLimit: 100000000, Time: 1, BaseFee: big.NewInt(1)},
+		statedb: statedb,
+		nonces:  make([]uint64, accounts),
+	}
+	env.signer = types.MakeSigner(env.config, env.header.Number)
+	for i := 0; i < accounts; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("could not generate key: %v", err)
+		}
+		env.keys = append(env.keys, key)
+		statedb.SetBalance(crypto.PubkeyToAddress(key.PublicKey), new(big.Int).Mul(big.NewInt(ethparams.Ether), big.NewInt(1000)))
+	}
+	statedb.SetCode(counterAddr, counterCode)
+	statedb.SetCode(callerCounterAddr, callerCounterCode)
+	statedb.SetCode(coinbaseReaderAddr, coinbaseReaderCode)
+	statedb.SetCode(hashLoopAddr, hashLoopCode)
+
+	// Start from a committed state like a block does
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatalf("could not commit state: %v", err)
+	}
+	env.statedb, _ = state.New(root, statedb.Database(), nil)
+	return env
+}
+
+func (env *parallelTestEnv) tx(t testing.TB, from int, to *common.Address, value int64, gas uint64, data []byte) *types.Transaction {
+	txdata := &types.LegacyTx{Nonce: env.nonces[from], To: to, Value: big.NewInt(value), Gas: gas, GasPrice: big.NewInt(1), Data: data}
+	tx, err := types.SignNewTx(env.keys[from], env.signer, txdata)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	env.nonces[from]++
+	return tx
+}
+
+// apply applies the transactions on a copy of the state, in parallel or in order.
+func (env *parallelTestEnv) apply(txs types.Transactions, parallel bool) (types.Receipts, *state.StateDB, uint64, error) {
+	var (
+		statedb = env.statedb.Copy()
+		gp      = new(core.GasPool).AddGas(env.header.GasLimit)
+		usedGas uint64
+		cfg     = vm.Config{ParallelExecution: parallel, ParallelWorkers: 4}
+	)
+	if parallel {
+		receipts, _, err := applyTransactionsParallel(env.config, nil, &parallelCoinbase, gp, statedb, env.header, txs, &usedGas, cfg)
+		return receipts, statedb, usedGas, err
+	}
+	var receipts types.Receipts
+	for i, tx := range txs {
+		statedb.Prepare(tx.Hash(), i)
+		receipt, err := ApplyTransaction(env.config, nil, &parallelCoinbase, gp, statedb, env.header, tx, &usedGas, cfg)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, statedb, usedGas, nil
+}
+
+// TestParallelExecutionMatchesSequential tests that the parallel execution of
+// independent and conflicting transactions produces the same receipts and
+// state as the sequential one.
+func TestParallelExecutionMatchesSequential(t *testing.T) {
+	env := newParallelTestEnv(t, 8)
+
+	var txs types.Transactions
+	for round := 0; round < 3; round++ {
+		for i := range env.keys {
+			fresh := common.BigToAddress(big.NewInt(int64(0x1000 + round*len(env.keys) + i)))
+			switch i % 4 {
+			case 0:
+				txs = append(txs, env.tx(t, i, &fresh, 1000, 21000, nil))
+			case 1:
+				txs = append(txs, env.tx(t, i, &counterAddr, 0, 100000, nil))
+			case 2:
+				txs = append(txs, env.tx(t, i, &callerCounterAddr, 0, 100000, nil))
+			case 3:
+				txs = append(txs, env.tx(t, i, &coinbaseReaderAddr, 0, 100000, nil))
+			}
+		}
+	}
+	// A contract creation, a computation and a transaction running out of gas
+	txs = append(txs, env.tx(t, 0, nil, 0, 100000, nil))
+	txs = append(txs, env.tx(t, 2, &hashLoopAddr, 0, 200000, nil))
+	txs = append(txs, env.tx(t, 1, &counterAddr, 0, 21100, nil))
+
+	want, wantState, wantGas, err := env.apply(txs, false)
+	if err != nil {
+		t.Fatalf("sequential execution failed: %v", err)
+	}
+	have, haveState, haveGas, err := env.apply(txs, true)
+	if err != nil {
+		t.Fatalf("parallel execution failed: %v", err)
+	}
+	if haveGas != wantGas {
+		t.Errorf("used gas mismatch: have %d, want %d", haveGas, wantGas)
+	}
+	if len(have) != len(want) {
+		t.Fatalf("receipt count mismatch: have %d, want %d", len(have), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(have[i], want[i]) {
+			t.Errorf("receipt %d mismatch:\nhave %+v\nwant %+v", i, have[i], want[i])
+		}
+	}
+	if have, want := haveState.IntermediateRoot(true), wantState.IntermediateRoot(true); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// TestParallelExecutionRevertedCall tests that the touches and creations of a
+// reverted inner call are not merged, so that an empty account only touched by
+// the reverted call is not deleted.
+func TestParallelExecutionRevertedCall(t *testing.T) {
+	env := newParallelTestEnv(t, 2)
+
+	// Keep an empty account in the state, like a genesis allocation may
+	env.statedb.SetCode(revertingAddr, revertingCode)
+	env.statedb.SetCode(revertedCallerAddr, revertedCallerCode)
+	env.statedb.CreateAccount(emptyAddr)
+	root, err := env.statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("could not commit state: %v", err)
+	}
+	env.statedb, _ = state.New(root, env.statedb.Database(), nil)
+
+	fresh := common.BigToAddress(big.NewInt(0x1000))
+	txs := types.Transactions{
+		env.tx(t, 0, &revertedCallerAddr, 0, 200000, nil),
+		env.tx(t, 1, &fresh, 1000, 21000, nil),
+	}
+	_, wantState, _, err := env.apply(txs, false)
+	if err != nil {
+		t.Fatalf("sequential execution failed: %v", err)
+	}
+	_, haveState, _, err := env.apply(txs, true)
+	if err != nil {
+		t.Fatalf("parallel execution failed: %v", err)
+	}
+	if !wantState.Exist(emptyAddr) {
+		t.Fatal("empty account touched by a reverted call deleted by the sequential execution")
+	}
+	if wantState.GetState(revertedCallerAddr, common.Hash{}) != common.BigToHash(common.Big1) {
+		t.Fatal("calling transaction did not succeed")
+	}
+	if have, want := haveState.IntermediateRoot(true), wantState.IntermediateRoot(true); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// TestParallelExecutionErrors tests that an invalid transaction fails the
+// parallel execution like the sequential one.
+func TestParallelExecutionErrors(t *testing.T) {
+	env := newParallelTestEnv(t, 2)
+
+	txs := types.Transactions{env.tx(t, 0, &counterAddr, 0, 100000, nil)}
+	env.nonces[1] = 5
+	txs = append(txs, env.tx(t, 1, &counterAddr, 0, 100000, nil))
+
+	_, _, _, want := env.apply(txs, false)
+	_, _, _, have := env.apply(txs, true)
+	if want == nil || have == nil || have.Error() != want.Error() {
+		t.Errorf("error mismatch: have %v, want %v", have, want)
+	}
+}
+
+func benchmarkApplyTransactions(b *testing.B, parallel bool, conflicting bool) {
+	env := newParallelTestEnv(b, 200)
+
+	var txs types.Transactions
+	for i := range env.keys {
+		if conflicting {
+			txs = append(txs, env.tx(b, i, &counterAddr, 0, 100000, nil))
+		} else {
+			txs = append(txs, env.tx(b, i, &hashLoopAddr, 0, 200000, nil))
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := env.apply(txs, parallel); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApplyIndependentSequential(b *testing.B) { benchmarkApplyTransactions(b, false, false) }
+func BenchmarkApplyIndependentParallel(b *testing.B)   { benchmarkApplyTransactions(b, true, false) }
+func BenchmarkApplyConflictingSequential(b *testing.B) { benchmarkApplyTransactions(b, false, true) }
+func BenchmarkApplyConflictingParallel(b *testing.B)   { benchmarkApplyTransactions(b, true, true) }
@@ -24,11 +24,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/params"
+	ethparams "github.com/ethereum/go-ethereum/params"
 
+	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/mapprotocol/atlas/core/vm"
+	"github.com/mapprotocol/atlas/core/vm/vmcontext"
+	"github.com/mapprotocol/atlas/params"
 )
 
 var emptyCodeHash = crypto.Keccak256Hash(nil)
@@ -64,6 +68,13 @@ type StateTransition struct {
 	data       []byte
 	state      types.StateDB
 	evm        *vm.EVM
+
+	// feeCurrency is the currency the gas fees are paid in, nil for the native token
+	feeCurrency *common.Address
+	// baseFee is the base fee of the block in the fee currency
+	baseFee *big.Int
+	// feeCurrencyGas is the intrinsic gas of the fee currency debit and credit calls
+	feeCurrencyGas uint64
 }
 
 // Message represents a message sent to a contract.
@@ -81,6 +92,7 @@ type Message interface {
 	IsFake() bool
 	Data() []byte
 	AccessList() types.AccessList
+	FeeCurrency() *common.Address
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -123,9 +135,9 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if isContractCreation && isHomestead {
-		gas = params.TxGasContractCreation
+		gas = ethparams.TxGasContractCreation
 	} else {
-		gas = params.TxGas
+		gas = ethparams.TxGas
 	}
 	// Bump the required gas by the amount of transactional data
 	if len(data) > 0 {
@@ -137,9 +149,9 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 			}
 		}
 		// Make sure we don't exceed uint64 for all data combinations
-		nonZeroGas := params.TxDataNonZeroGasFrontier
+		nonZeroGas := ethparams.TxDataNonZeroGasFrontier
 		if isEIP2028 {
-			nonZeroGas = params.TxDataNonZeroGasEIP2028
+			nonZeroGas = ethparams.TxDataNonZeroGasEIP2028
 		}
 		if (math.MaxUint64-gas)/nonZeroGas < nz {
 			return 0, core.ErrGasUintOverflow
@@ -147,14 +159,14 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 		gas += nz * nonZeroGas
 
 		z := uint64(len(data)) - nz
-		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
+		if (math.MaxUint64-gas)/ethparams.TxDataZeroGas < z {
 			return 0, core.ErrGasUintOverflow
 		}
-		gas += z * params.TxDataZeroGas
+		gas += z * ethparams.TxDataZeroGas
 	}
 	if accessList != nil {
-		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
-		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+		gas += uint64(len(accessList)) * ethparams.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * ethparams.TxAccessListStorageKeyGas
 	}
 	return gas, nil
 }
@@ -162,15 +174,17 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *core.GasPool) *StateTransition {
 	return &StateTransition{
-		gp:        gp,
-		evm:       evm,
-		msg:       msg,
-		gasPrice:  msg.GasPrice(),
-		gasFeeCap: msg.GasFeeCap(),
-		gasTipCap: msg.GasTipCap(),
-		value:     msg.Value(),
-		data:      msg.Data(),
-		state:     evm.StateDB,
+		gp:          gp,
+		evm:         evm,
+		msg:         msg,
+		gasPrice:    msg.GasPrice(),
+		gasFeeCap:   msg.GasFeeCap(),
+		gasTipCap:   msg.GasTipCap(),
+		value:       msg.Value(),
+		data:        msg.Data(),
+		state:       evm.StateDB,
+		feeCurrency: msg.FeeCurrency(),
+		baseFee:     evm.Context.BaseFee,
 	}
 }
 
@@ -193,6 +207,20 @@ func (st *StateTransition) to() common.Address {
 	return *st.msg.To()
 }
 
+// isDoubleSignEvidence returns whether the message is a double sign evidence submitted
+// by the block proposer, which is free of the base fee since the Slashing fork.
+func (st *StateTransition) isDoubleSignEvidence() bool {
+	return st.evm.ChainConfig().IsSlashing(st.evm.Context.BlockNumber) &&
+		st.to() == params.DoubleSignSlashingAddress && st.msg.From() == st.evm.Context.Coinbase &&
+		st.gasFeeCap != nil && st.gasFeeCap.Sign() == 0 && st.gasTipCap != nil && st.gasTipCap.Sign() == 0
+}
+
+// runFeeCurrencyCall calls the fee currency contracts from the VM address on the state of the
+// transaction, the calls are not traced
+func (st *StateTransition) runFeeCurrencyCall(call func(vmRunner vm.EVMRunner) error) error {
+	return call(vmcontext.NewEVMRunnerFromEVM(st.evm))
+}
+
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).SetUint64(st.msg.Gas())
 	mgval = mgval.Mul(mgval, st.gasPrice)
@@ -200,9 +228,24 @@ func (st *StateTransition) buyGas() error {
 	if st.gasFeeCap != nil {
 		balanceCheck = new(big.Int).SetUint64(st.msg.Gas())
 		balanceCheck = balanceCheck.Mul(balanceCheck, st.gasFeeCap)
-		balanceCheck.Add(balanceCheck, st.value)
+		// the value is always paid in the native token
+		if st.feeCurrency == nil {
+			balanceCheck.Add(balanceCheck, st.value)
+		}
 	}
-	if have, want := st.state.GetBalance(st.msg.From()), balanceCheck; have.Cmp(want) < 0 {
+	if st.feeCurrency != nil {
+		var have *big.Int
+		err := st.runFeeCurrencyCall(func(vmRunner vm.EVMRunner) (err error) {
+			have, err = currency.GetBalanceOf(vmRunner, st.msg.From(), *st.feeCurrency)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if want := balanceCheck; have.Cmp(want) < 0 {
+			return fmt.Errorf("%w: address %v have %v want %v in fee currency %v", core.ErrInsufficientFunds, st.msg.From().Hex(), have, want, st.feeCurrency.Hex())
+		}
+	} else if have, want := st.state.GetBalance(st.msg.From()), balanceCheck; have.Cmp(want) < 0 {
 		return fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, st.msg.From().Hex(), have, want)
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -211,10 +254,39 @@ func (st *StateTransition) buyGas() error {
 	st.gas += st.msg.Gas()
 
 	st.initialGas = st.msg.Gas()
+	if st.feeCurrency != nil {
+		return st.runFeeCurrencyCall(func(vmRunner vm.EVMRunner) error {
+			return currency.DebitGasFees(vmRunner, *st.feeCurrency, st.msg.From(), mgval)
+		})
+	}
 	st.state.SubBalance(st.msg.From(), mgval)
 	return nil
 }
 
+// preCheckFeeCurrency checks the fee currency is whitelisted and converts the base fee of the
+// block to it, along with the effective gas price.
+func (st *StateTransition) preCheckFeeCurrency() error {
+	if !st.evm.ChainConfig().IsFeeCurrency(st.evm.Context.BlockNumber) {
+		return fmt.Errorf("%w: address %v, fee currency %v", core.ErrTxTypeNotSupported, st.msg.From().Hex(), st.feeCurrency.Hex())
+	}
+	return st.runFeeCurrencyCall(func(vmRunner vm.EVMRunner) error {
+		if !currency.IsWhitelisted(vmRunner, st.feeCurrency) {
+			return fmt.Errorf("%w: address %v, fee currency %v", core.ErrNonWhitelistedFeeCurrency, st.msg.From().Hex(), st.feeCurrency.Hex())
+		}
+		st.feeCurrencyGas = blockchain_parameters.GetIntrinsicGasForAlternativeFeeCurrencyOrDefault(vmRunner)
+		if st.baseFee == nil {
+			return nil
+		}
+		rate, err := currency.GetExchangeRate(vmRunner, st.feeCurrency)
+		if err != nil {
+			return err
+		}
+		st.baseFee = rate.FromBase(st.baseFee)
+		st.gasPrice = cmath.BigMin(new(big.Int).Add(st.gasTipCap, st.baseFee), st.gasFeeCap)
+		return nil
+	})
+}
+
 func (st *StateTransition) preCheck() error {
 	// Only check transactions that are not fake
 	if !st.msg.IsFake() {
@@ -233,8 +305,13 @@ func (st *StateTransition) preCheck() error {
 				st.msg.From().Hex(), codeHash)
 		}
 	}
+	if st.feeCurrency != nil {
+		if err := st.preCheckFeeCurrency(); err != nil {
+			return err
+		}
+	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
-	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
+	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) && !st.isDoubleSignEvidence() {
 		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
 		if !st.evm.Config.NoBaseFee || st.gasFeeCap.BitLen() > 0 || st.gasTipCap.BitLen() > 0 {
 			if l := st.gasFeeCap.BitLen(); l > 256 {
@@ -252,9 +329,9 @@ func (st *StateTransition) preCheck() error {
 			// This will panic if baseFee is nil, but basefee presence is verified
 			// as part of header validation.
 
-			if st.gasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 {
+			if st.gasFeeCap.Cmp(st.baseFee) < 0 {
 				return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", core.ErrFeeCapTooLow,
-					st.msg.From().Hex(), st.gasFeeCap, st.evm.Context.BaseFee)
+					st.msg.From().Hex(), st.gasFeeCap, st.baseFee)
 			}
 		}
 	}
@@ -301,6 +378,12 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	if st.feeCurrency != nil {
+		if math.MaxUint64-gas < st.feeCurrencyGas {
+			return nil, core.ErrGasUintOverflow
+		}
+		gas += st.feeCurrencyGas
+	}
 	if st.gas < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, st.gas, gas)
 	}
@@ -329,17 +412,33 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 
 	if !london {
 		// Before EIP-3529: refunds were capped to gasUsed / 2
-		st.refundGas(params.RefundQuotient)
+		st.refundGas(ethparams.RefundQuotient)
 	} else {
 		// After EIP-3529: refunds are capped to gasUsed / 5
-		st.refundGas(params.RefundQuotientEIP3529)
+		st.refundGas(ethparams.RefundQuotientEIP3529)
 	}
 	effectiveTip := st.gasPrice
-	if london {
-		effectiveTip = cmath.BigMin(st.gasTipCap, new(big.Int).Sub(st.gasFeeCap, st.evm.Context.BaseFee))
+	if st.isDoubleSignEvidence() {
+		effectiveTip = common.Big0
+	} else if london {
+		effectiveTip = cmath.BigMin(st.gasTipCap, new(big.Int).Sub(st.gasFeeCap, st.baseFee))
+	}
+	tipTxFee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip)
+	if st.feeCurrency != nil {
+		// refund the remaining gas and pay the tip in the fee currency, burning the base fee
+		refund := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
+		baseTxFee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+		baseTxFee.Sub(baseTxFee, tipTxFee)
+		err := st.runFeeCurrencyCall(func(vmRunner vm.EVMRunner) error {
+			return currency.CreditGasFees(vmRunner, *st.feeCurrency, msg.From(), st.evm.Context.Coinbase, refund, tipTxFee, baseTxFee)
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// burn all tips
+		st.state.AddBalance(st.evm.Context.Coinbase, tipTxFee)
 	}
-	// burn all tips
-	st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -356,9 +455,12 @@ func (st *StateTransition) refundGas(refundQuotient uint64) {
 	}
 	st.gas += refund
 
-	// Return ETH for remaining gas, exchanged at the original rate.
-	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(st.msg.From(), remaining)
+	// Return ETH for remaining gas, exchanged at the original rate. The
+	// fee currency is credited along with the tip.
+	if st.feeCurrency == nil {
+		remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
+		st.state.AddBalance(st.msg.From(), remaining)
+	}
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
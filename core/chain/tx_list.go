@@ -254,8 +254,9 @@ type txList struct {
 	strict bool         // Whether nonces are strictly continuous or not
 	txs    *txSortedMap // Heap indexed sorted hash map of the transactions
 
-	costcap *big.Int // Price of the highest costing transaction (reset only if exceeds balance)
-	gascap  uint64   // Gas limit of the highest spending transaction (reset only if exceeds block limit)
+	costcap *big.Int                    // Price of the highest costing transaction (reset only if exceeds balance)
+	feecaps map[common.Address]*big.Int // Fee of the highest costing transaction of each fee currency (reset only if exceeds balance)
+	gascap  uint64                      // Gas limit of the highest spending transaction (reset only if exceeds block limit)
 }
 
 // newTxList create a new transaction list for maintaining nonce-indexable fast,
@@ -265,9 +266,28 @@ func newTxList(strict bool) *txList {
 		strict:  strict,
 		txs:     newTxSortedMap(),
 		costcap: new(big.Int),
+		feecaps: make(map[common.Address]*big.Int),
 	}
 }
 
+// nativeCost returns the cost of the transaction in the native token, the gas fees
+// of a fee currency transaction are paid in its currency.
+func nativeCost(tx *types.Transaction) *big.Int {
+	if tx.FeeCurrency() != nil {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
+// FeeCurrencies returns the fee currencies of the transactions in the list.
+func (l *txList) FeeCurrencies() []common.Address {
+	currencies := make([]common.Address, 0, len(l.feecaps))
+	for feeCurrency := range l.feecaps {
+		currencies = append(currencies, feeCurrency)
+	}
+	return currencies
+}
+
 // Overlaps returns whether the transaction specified has the same nonce as one
 // already contained within the list.
 func (l *txList) Overlaps(tx *types.Transaction) bool {
@@ -305,9 +325,14 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	}
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
-	if cost := tx.Cost(); l.costcap.Cmp(cost) < 0 {
+	if cost := nativeCost(tx); l.costcap.Cmp(cost) < 0 {
 		l.costcap = cost
 	}
+	if feeCurrency := tx.FeeCurrency(); feeCurrency != nil {
+		if fee := tx.Fee(); l.feecaps[*feeCurrency] == nil || l.feecaps[*feeCurrency].Cmp(fee) < 0 {
+			l.feecaps[*feeCurrency] = fee
+		}
+	}
 	if gas := tx.Gas(); l.gascap < gas {
 		l.gascap = gas
 	}
@@ -324,23 +349,46 @@ func (l *txList) Forward(threshold uint64) types.Transactions {
 // Filter removes all transactions from the list with a cost or gas limit higher
 // than the provided thresholds. Every removed transaction is returned for any
 // post-removal maintenance. Strict-mode invalidated transactions are also
-// returned.
+// returned. The fees of the fee currency transactions are checked against the
+// feeLimits of their currencies.
 //
-// This method uses the cached costcap and gascap to quickly decide if there's even
-// a point in calculating all the costs or if the balance covers all. If the threshold
+// This method uses the cached costcap, feecaps and gascap to quickly decide if there's
+// even a point in calculating all the costs or if the balance covers all. If the threshold
 // is lower than the costgas cap, the caps will be reset to a new high after removing
 // the newly invalidated transactions.
-func (l *txList) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions, types.Transactions) {
+func (l *txList) Filter(costLimit *big.Int, feeLimits map[common.Address]*big.Int, gasLimit uint64) (types.Transactions, types.Transactions) {
+	feeLimit := func(feeCurrency common.Address) *big.Int {
+		if limit := feeLimits[feeCurrency]; limit != nil {
+			return limit
+		}
+		return common.Big0
+	}
 	// If all transactions are below the threshold, short circuit
-	if l.costcap.Cmp(costLimit) <= 0 && l.gascap <= gasLimit {
+	withinFeeLimits := true
+	for feeCurrency, feecap := range l.feecaps {
+		if feecap.Cmp(feeLimit(feeCurrency)) > 0 {
+			withinFeeLimits = false
+			break
+		}
+	}
+	if l.costcap.Cmp(costLimit) <= 0 && withinFeeLimits && l.gascap <= gasLimit {
 		return nil, nil
 	}
 	l.costcap = new(big.Int).Set(costLimit) // Lower the caps to the thresholds
+	for feeCurrency, feecap := range l.feecaps {
+		if limit := feeLimit(feeCurrency); feecap.Cmp(limit) > 0 {
+			l.feecaps[feeCurrency] = new(big.Int).Set(limit)
+		}
+	}
 	l.gascap = gasLimit
 
 	// Filter out all the transactions above the account's funds
 	removed := l.txs.Filter(func(tx *types.Transaction) bool {
-		return tx.Gas() > gasLimit || tx.Cost().Cmp(costLimit) > 0
+		if tx.Gas() > gasLimit || nativeCost(tx).Cmp(costLimit) > 0 {
+			return true
+		}
+		feeCurrency := tx.FeeCurrency()
+		return feeCurrency != nil && tx.Fee().Cmp(feeLimit(*feeCurrency)) > 0
 	})
 
 	if len(removed) == 0 {
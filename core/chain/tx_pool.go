@@ -33,6 +33,7 @@ import (
 
 	"github.com/mapprotocol/atlas/consensus/misc"
 	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
@@ -251,6 +252,8 @@ type TxPool struct {
 	eip2718  bool // Fork indicator whether we are using EIP-2718 type transactions.
 	eip1559  bool // Fork indicator whether we are using EIP-1559 type transactions.
 
+	feeCurrency bool // Fork indicator whether we are accepting fee currency transactions.
+
 	currentState *state.StateDB // Current state in the blockchain head
 	// todo ibft compare
 	currentVMRunner vm.EVMRunner // Current EVMRunner
@@ -605,6 +608,14 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
 		return core.ErrTxTypeNotSupported
 	}
+	// Reject fee currency transactions until the fee currency fork activates.
+	if !pool.feeCurrency && tx.Type() == types.FeeCurrencyTxType {
+		return core.ErrTxTypeNotSupported
+	}
+	// Accept only the fee currencies of the whitelist
+	if feeCurrency := tx.FeeCurrency(); feeCurrency != nil && !pool.ctx().IsWhitelisted(*feeCurrency) {
+		return core.ErrNonWhitelistedFeeCurrency
+	}
 	// Reject transactions over defined size to prevent DOS attacks
 	if uint64(tx.Size()) > txMaxSize {
 		return ErrOversizedData
@@ -639,7 +650,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	//if !local && tx.Type() == types.LegacyTxType && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 	//	return ErrUnderpriced
 	//}
-	if tx.GasFeeCapIntCmp(pool.gasPrice) < 0 {
+	if pool.ctx().CmpValues(tx.GasFeeCap(), tx.FeeCurrency(), pool.gasPrice, nil) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -663,6 +674,13 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err != nil {
 		return err
 	}
+	// Paying the fees in a fee currency costs the debit and credit calls
+	if tx.FeeCurrency() != nil {
+		if math.MaxUint64-intrGas < pool.ctx().gasForAlternativeCurrency {
+			return core.ErrGasUintOverflow
+		}
+		intrGas += pool.ctx().gasForAlternativeCurrency
+	}
 	if tx.Gas() < intrGas {
 		return core.ErrIntrinsicGas
 	}
@@ -678,23 +696,19 @@ func ValidateTransactorBalanceCoversTx(tx *types.Transaction, from common.Addres
 			"value", tx.Value(), "fee currency", tx.FeeCurrency(), "balance", currentState.GetBalance(from))
 		return errors.New("insufficient funds for gas * price + value + gatewayFee")
 	} else if tx.FeeCurrency() != nil {
-		//feeCurrencyBalance, err := currency.GetBalanceOf(currentVMRunner, from, *tx.FeeCurrency())
-		//
-		//if err != nil {
-		//	log.Debug("validateTx error in getting fee currency balance", "feeCurrency", tx.FeeCurrency(), "error", err)
-		//	return err
-		//}
-
-		// This is required to match the logic in canPayFee() state_transition.go
-		//   - Prior to E hardfork: we require the balance to be strictly greater than the fee,
-		//     which means we reject the transaction if balance <= fee
-		//   - After E hardfork: we require the balance to be greater than or equal to the fee,
-		//     which means we reject the transaction if balance < fee
-		//fee := tx.Fee()
-		//if (eHardfork && feeCurrencyBalance.Cmp(fee) < 0) || (!eHardfork && feeCurrencyBalance.Cmp(fee) <= 0) {
-		//	log.Debug("validateTx insufficient fee currency", "feeCurrency", tx.FeeCurrency(), "feeCurrencyBalance", feeCurrencyBalance)
-		//	return errors.New("insufficient funds for gas * price + value + gatewayFee")
-		//}
+		feeCurrencyBalance, err := currency.GetBalanceOf(currentVMRunner, from, *tx.FeeCurrency())
+
+		if err != nil {
+			log.Debug("validateTx error in getting fee currency balance", "feeCurrency", tx.FeeCurrency(), "error", err)
+			return err
+		}
+
+		// This is required to match the logic in buyGas() state_transition.go,
+		// the transaction is rejected if balance < fee
+		if feeCurrencyBalance.Cmp(tx.Fee()) < 0 {
+			log.Debug("validateTx insufficient fee currency", "feeCurrency", tx.FeeCurrency(), "feeCurrencyBalance", feeCurrencyBalance)
+			return errors.New("insufficient funds for gas * price + value + gatewayFee")
+		}
 
 		if currentState.GetBalance(from).Cmp(tx.Value()) < 0 {
 			log.Debug("validateTx insufficient funds", "balance", currentState.GetBalance(from).String())
@@ -1369,7 +1383,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// atomic store of the new txPoolContext
 	newCtx := txPoolContext{
 		NewBlockContext(pool.currentVMRunner),
-		//currency.NewManager(pool.currentVMRunner),
+		currency.NewManager(pool.currentVMRunner),
 	}
 	pool.currentCtx.Store(newCtx)
 
@@ -1383,6 +1397,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
 	pool.eip2718 = pool.chainconfig.IsBerlin(next)
 	pool.eip1559 = pool.chainconfig.IsLondon(next)
+	pool.feeCurrency = pool.chainconfig.IsFeeCurrency(next)
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1406,20 +1421,11 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 
-		// todo ibft cancel
 		// Get balances in each currency
-		//balances := make(map[common.Address]*big.Int)
-		//allCurrencies := list.FeeCurrencies()
-		//for _, feeCurrency := range allCurrencies {
-		//	feeCurrencyBalance, _ := currency.GetBalanceOf(pool.currentVMRunner, addr, feeCurrency)
-		//	balances[feeCurrency] = feeCurrencyBalance
-		//}
+		balances := pool.feeCurrencyBalances(addr, list)
 
 		// Drop all transactions that are too costly (low balance or out of gas)
-		// todo ibft upgrade???
-		// todo ibft cancel
-		//drops, _ := list.Filter(pool.currentState.GetBalance(addr), balances, pool.currentMaxGas)
-		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, _ := list.Filter(pool.currentState.GetBalance(addr), balances, pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
@@ -1616,19 +1622,11 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 
-		// todo ibft cancel
-		//// Get balances in each currency
-		//balances := make(map[common.Address]*big.Int)
-		//allCurrencies := list.FeeCurrencies()
-		//for _, feeCurrency := range allCurrencies {
-		//	feeCurrencyBalance, _ := currency.GetBalanceOf(pool.currentVMRunner, addr, feeCurrency)
-		//	balances[feeCurrency] = feeCurrencyBalance
-		//}
+		// Get balances in each currency
+		balances := pool.feeCurrencyBalances(addr, list)
 
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
-		//drops, invalids := list.Filter(pool.currentState.GetBalance(addr), balances, pool.currentMaxGas)
-		// todo ibft cancel
-		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
+		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), balances, pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
@@ -1933,10 +1931,26 @@ func numSlots(tx *types.Transaction) int {
 	return int((tx.Size() + txSlotSize - 1) / txSlotSize)
 }
 
+// feeCurrencyBalances returns the balances of the account in the fee currencies of the
+// transactions in the list, a balance failing to load is left out.
+func (pool *TxPool) feeCurrencyBalances(addr common.Address, list *txList) map[common.Address]*big.Int {
+	balances := make(map[common.Address]*big.Int)
+	for _, feeCurrency := range list.FeeCurrencies() {
+		feeCurrencyBalance, err := currency.GetBalanceOf(pool.currentVMRunner, addr, feeCurrency)
+		if err != nil {
+			log.Debug("Failed to get fee currency balance", "addr", addr, "feeCurrency", feeCurrency, "err", err)
+			continue
+		}
+		balances[feeCurrency] = feeCurrencyBalance
+	}
+	return balances
+}
+
 // BlockContext represents contextual information about the blockchain state
 // for a given block
 type BlockContext struct {
 	gasForAlternativeCurrency uint64
+	whitelistedCurrencies     map[common.Address]struct{}
 }
 
 // NewBlockContext creates a block context for a given block (represented by the
@@ -1944,13 +1958,30 @@ type BlockContext struct {
 // state MUST be pointing to header's stateRoot
 func NewBlockContext(vmRunner vm.EVMRunner) BlockContext {
 	gasForAlternativeCurrency := blockchain_parameters.GetIntrinsicGasForAlternativeFeeCurrencyOrDefault(vmRunner)
+
+	whitelistedCurrencies := make(map[common.Address]struct{})
+	whitelist, err := currency.GetWhitelist(vmRunner)
+	if err != nil {
+		log.Debug("Failed to get the fee currency whitelist", "err", err)
+	}
+	for _, feeCurrency := range whitelist {
+		whitelistedCurrencies[feeCurrency] = struct{}{}
+	}
 	return BlockContext{
 		gasForAlternativeCurrency: gasForAlternativeCurrency,
+		whitelistedCurrencies:     whitelistedCurrencies,
 	}
 }
 
+// IsWhitelisted returns whether the currency is accepted to pay the gas fees
+func (bc BlockContext) IsWhitelisted(feeCurrency common.Address) bool {
+	_, ok := bc.whitelistedCurrencies[feeCurrency]
+	return ok
+}
+
 type txPoolContext struct {
 	BlockContext
+	*currency.Manager
 }
 
 func (pool *TxPool) ctx() *txPoolContext {
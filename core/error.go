@@ -99,6 +99,10 @@ var (
 
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
+
+	// ErrNonWhitelistedFeeCurrency is returned if the currency a transaction pays
+	// its gas fees in is not in the fee currency whitelist.
+	ErrNonWhitelistedFeeCurrency = errors.New("fee currency not whitelisted")
 )
 
 var (
@@ -1045,11 +1045,8 @@ func (m *Mmr) GenerateProof(proofHeight, EndHeight uint64) *ProofInfo {
 	// })
 
 	mmrClone := m.Copy()
-	lRemove := int64(EndHeight - mmrClone.leafNum)
-	if lRemove > 0 {
-		for i := 0; i < int(lRemove); i++ {
-			mmrClone.pop()
-		}
+	for mmrClone.leafNum > EndHeight {
+		mmrClone.pop()
 	}
 
 	info := mmrClone.genProof(big.NewInt(0), []uint64{proofHeight})
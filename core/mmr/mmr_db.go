@@ -0,0 +1,212 @@
+// Copyright 2021 MAP Protocol Authors.
+// This file is part of MAP Protocol.
+
+// MAP Protocol is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// MAP Protocol is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with MAP Protocol.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The nodes of a Mmr are laid out in post order, the nodes of a complete subtree
+// keep their position once the subtree is complete. Only the nodes bagging the
+// peaks move when leaves are pushed, so a Mmr kept in a database only stores the
+// complete subtrees and recomputes the bagging nodes, which also gives the Mmr of
+// every smaller leaf number from the same nodes.
+
+var (
+	errEmptyMmr    = errors.New("empty mmr")
+	errMissingNode = errors.New("missing mmr node")
+)
+
+// NodeReader retrieves the node at the given position of a Mmr, nil if missing
+type NodeReader func(pos uint64) (*Node, error)
+
+// NodeWriter stores the node at the given position of a Mmr
+type NodeWriter func(pos uint64, n *Node) error
+
+// EncodeRLP serializes the node into the Ethereum RLP format.
+func (n *Node) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, []interface{}{n.value, n.difficulty, n.timeCost})
+}
+
+// DecodeRLP implements rlp.Decoder, and loads the node fields from a RLP stream.
+func (n *Node) DecodeRLP(s *rlp.Stream) error {
+	var node struct {
+		Value      common.Hash
+		Difficulty *big.Int
+		TimeCost   uint64
+	}
+	if err := s.Decode(&node); err != nil {
+		return err
+	}
+	n.value, n.difficulty, n.timeCost = node.Value, node.Difficulty, node.TimeCost
+	return nil
+}
+
+func readNode(read NodeReader, pos uint64) (*Node, error) {
+	n, err := read(pos)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, fmt.Errorf("%w at position %d", errMissingNode, pos)
+	}
+	return n, nil
+}
+
+// subtreeRoot returns the root of the subtree holding leafNum leaves whose first node
+// is at position offset
+func subtreeRoot(read NodeReader, offset, leafNum uint64) (*Node, error) {
+	if IsPowerOfTwo(leafNum) {
+		return readNode(read, offset+leafToNodeNumber(leafNum)-1)
+	}
+	left := getLeftLeafNumber(leafNum)
+	l, err := subtreeRoot(read, offset, left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := subtreeRoot(read, offset+leafToNodeNumber(left), leafNum-left)
+	if err != nil {
+		return nil, err
+	}
+	return merge(l, r), nil
+}
+
+// LeafPosition returns the position of the leaf with the given index
+func LeafPosition(index uint64) uint64 {
+	return GetNodeFromLeaf(index)
+}
+
+// RootFromReader returns the root node of the Mmr of the first leafNum leaves
+func RootFromReader(read NodeReader, leafNum uint64) (*Node, error) {
+	if leafNum == 0 {
+		return nil, errEmptyMmr
+	}
+	return subtreeRoot(read, 0, leafNum)
+}
+
+// AppendLeaf writes the leaf following the first leafNum leaves, together with the
+// roots of the complete subtrees it closes
+func AppendLeaf(read NodeReader, write NodeWriter, leafNum uint64, leaf *Node) error {
+	pos := GetNodeFromLeaf(leafNum)
+	if err := write(pos, leaf); err != nil {
+		return err
+	}
+	node := leaf
+	for height := 0; leafNum&(1<<uint(height)) != 0; height++ {
+		sibling, err := readNode(read, pos-siblingOffset(height))
+		if err != nil {
+			return err
+		}
+		node = merge(sibling, node)
+		pos++
+		if err := write(pos, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateProofFromReader builds the proof of the leaf at proofHeight against the Mmr of
+// the first leafNum leaves, the proof has the same layout as the one of GenerateProof
+func GenerateProofFromReader(read NodeReader, proofHeight, leafNum uint64) (*ProofInfo, error) {
+	if proofHeight >= leafNum {
+		return nil, fmt.Errorf("leaf %d out of the mmr of %d leaves", proofHeight, leafNum)
+	}
+	root, err := RootFromReader(read, leafNum)
+	if err != nil {
+		return nil, err
+	}
+	elems, err := proofFromReader(read, nil, 0, 0, leafNum, proofHeight)
+	if err != nil {
+		return nil, err
+	}
+	elems = append(elems, &ProofElem{
+		Cat:     0,
+		Right:   false,
+		LeafNum: leafNum,
+		Res: &ProofRes{
+			H:  root.GetHash(),
+			TD: root.getDifficulty(),
+		},
+	})
+	return &ProofInfo{
+		RootHash:       root.GetHash(),
+		RootDifficulty: root.getDifficulty(),
+		LeafNumber:     leafNum,
+		Elems:          elems,
+		Checked:        []uint64{proofHeight},
+	}, nil
+}
+
+func proofFromReader(read NodeReader, proofs []*ProofElem, offset, first, leafNum, proofHeight uint64) ([]*ProofElem, error) {
+	if leafNum == 1 {
+		leaf, err := readNode(read, offset)
+		if err != nil {
+			return nil, err
+		}
+		return append(proofs, &ProofElem{
+			Cat:     2,
+			Right:   false,
+			LeafNum: 0,
+			Res: &ProofRes{
+				H:  leaf.GetHash(),
+				TD: leaf.getDifficulty(),
+			},
+		}), nil
+	}
+	left := getLeftLeafNumber(leafNum)
+	rightOffset := offset + leafToNodeNumber(left)
+	if proofHeight < first+left {
+		proofs, err := proofFromReader(read, proofs, offset, first, left, proofHeight)
+		if err != nil {
+			return nil, err
+		}
+		sibling, err := subtreeRoot(read, rightOffset, leafNum-left)
+		if err != nil {
+			return nil, err
+		}
+		return append(proofs, &ProofElem{
+			Cat:     1,
+			Right:   true,
+			LeafNum: 0,
+			Res: &ProofRes{
+				H:  sibling.GetHash(),
+				TD: sibling.getDifficulty(),
+			},
+		}), nil
+	}
+	sibling, err := subtreeRoot(read, offset, left)
+	if err != nil {
+		return nil, err
+	}
+	proofs = append(proofs, &ProofElem{
+		Cat:     1,
+		Right:   false,
+		LeafNum: 0,
+		Res: &ProofRes{
+			H:  sibling.GetHash(),
+			TD: sibling.getDifficulty(),
+		},
+	})
+	return proofFromReader(read, proofs, rightOffset, first+left, leafNum-left, proofHeight)
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type testNodeDB map[uint64]*Node
+
+func (db testNodeDB) read(pos uint64) (*Node, error) {
+	return db[pos], nil
+}
+
+func (db testNodeDB) write(pos uint64, n *Node) error {
+	db[pos] = n
+	return nil
+}
+
+func TestMmrFromReader(t *testing.T) {
+	var (
+		count = 37
+		mmr   = NewMMR()
+		db    = make(testNodeDB)
+	)
+	for i := 0; i < count; i++ {
+		leaf := NewNode(BytesToHash(IntToBytes(i)), big.NewInt(1), nil, nil, 0)
+		mmr.Push(NewNode(leaf.GetHash(), leaf.getDifficulty(), nil, nil, 0))
+		if err := AppendLeaf(db.read, db.write, uint64(i), leaf); err != nil {
+			t.Fatal(err)
+		}
+		root, err := RootFromReader(db.read, uint64(i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root.GetHash() != mmr.GetRoot2() {
+			t.Fatalf("root mismatch at %d leaves", i+1)
+		}
+	}
+	if len(db) != int(GetNodeFromLeaf(uint64(count))) {
+		t.Fatalf("stored %d nodes, want %d", len(db), GetNodeFromLeaf(uint64(count)))
+	}
+
+	for _, tip := range []uint64{1, 2, 5, 16, 23, uint64(count)} {
+		for _, height := range []uint64{0, tip / 2, tip - 1} {
+			proof, err := GenerateProofFromReader(db.read, height, tip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := mmr.GenerateProof(height, tip); !reflect.DeepEqual(proof.Elems, want.Elems) || proof.RootHash != want.RootHash {
+				t.Fatalf("proof of %d against %d leaves mismatch", height, tip)
+			}
+			blocks, _ := VerifyRequiredBlocks2(proof)
+			if !proof.VerifyProof2(blocks) {
+				t.Fatalf("proof of %d against %d leaves not verified", height, tip)
+			}
+		}
+	}
+	if _, err := GenerateProofFromReader(db.read, 5, 5); err == nil {
+		t.Fatal("proof of a leaf out of the mmr generated")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
@@ -133,12 +134,15 @@ func (o *ChainAdapter) setLeatestHeader(confirm *types.Header, leatest []*types.
 // to make sure the mmr_root in the header
 func (o *ChainAdapter) checkMmrRootForFirst(root common.Hash) error {
 	if len(o.Latest) > 0 {
-		// l := o.Latest[len(o.Latest)-1]
-		// rHash := l.MmrRoot
-		// if !bytes.Equal(root[:], rHash[:]) {
-		// 	fmt.Println("mmr root not match for first proof in header:", hex.EncodeToString(root[:]), "root in proof:", hex.EncodeToString(rHash[:]))
-		// 	return errors.New("genesis not match")
-		// }
+		l := o.Latest[len(o.Latest)-1]
+		if l.MmrRoot == nil {
+			return errors.New("no mmr root in header")
+		}
+		rHash := *l.MmrRoot
+		if !bytes.Equal(root[:], rHash[:]) {
+			log.Warn("mmr root not match for first proof", "header", rHash, "proof", root)
+			return errors.New("mmr root not match for first proof")
+		}
 		return nil
 	}
 	return errors.New("not get the first proof")
@@ -716,11 +716,9 @@ func deriveLogFields(receipts []*receiptLogs, hash common.Hash, number uint64, t
 	// Handle block finalization receipt (only IBFT)
 	if len(txs)+1 == len(receipts) {
 		j := len(txs)
+		types.SetSystemLogFields(receipts[j].Logs, hash, j)
 		for k := 0; k < len(receipts[j].Logs); k++ {
 			receipts[j].Logs[k].BlockNumber = number
-			receipts[j].Logs[k].BlockHash = hash
-			receipts[j].Logs[k].TxHash = hash
-			receipts[j].Logs[k].TxIndex = uint(j)
 			receipts[j].Logs[k].Index = logIndex
 			logIndex++
 		}
@@ -0,0 +1,62 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// PublishedCheckPoint is a checkpoint this node anchored on bitcoin.
+type PublishedCheckPoint struct {
+	Height uint64      `json:"height"`
+	Root   common.Hash `json:"root"`
+	TxID   string      `json:"txid"` // bitcoin transaction id
+	Time   uint64      `json:"time"` // unix time of the broadcast
+}
+
+// ReadPublishedCheckPoint retrieves the checkpoint published for the given block.
+func ReadPublishedCheckPoint(db ethdb.KeyValueReader, number uint64) *PublishedCheckPoint {
+	data, _ := db.Get(checkPointKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	cp := new(PublishedCheckPoint)
+	if err := rlp.DecodeBytes(data, cp); err != nil {
+		log.Error("Invalid published checkpoint RLP", "number", number, "err", err)
+		return nil
+	}
+	return cp
+}
+
+// ReadPublishedCheckPoints retrieves all the published checkpoints, in ascending
+// block order.
+func ReadPublishedCheckPoints(db ethdb.Iteratee) []*PublishedCheckPoint {
+	it := db.NewIterator(checkPointPrefix, nil)
+	defer it.Release()
+
+	var cps []*PublishedCheckPoint
+	for it.Next() {
+		if len(it.Key()) != len(checkPointPrefix)+8 {
+			continue
+		}
+		cp := new(PublishedCheckPoint)
+		if err := rlp.DecodeBytes(it.Value(), cp); err != nil {
+			log.Error("Invalid published checkpoint RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		cps = append(cps, cp)
+	}
+	return cps
+}
+
+// WritePublishedCheckPoint stores a checkpoint published on bitcoin.
+func WritePublishedCheckPoint(db ethdb.KeyValueWriter, cp *PublishedCheckPoint) {
+	data, err := rlp.EncodeToBytes(cp)
+	if err != nil {
+		log.Crit("Failed to RLP encode published checkpoint", "err", err)
+	}
+	if err := db.Put(checkPointKey(cp.Height), data); err != nil {
+		log.Crit("Failed to store published checkpoint", "err", err)
+	}
+}
@@ -0,0 +1,54 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	mmr "github.com/mapprotocol/atlas/core/mmr"
+)
+
+// ReadMmrLeafNumber retrieves the number of canonical block hashes pushed to the
+// merkle mountain range.
+func ReadMmrLeafNumber(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(mmrLeafNumberKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteMmrLeafNumber stores the number of canonical block hashes pushed to the
+// merkle mountain range.
+func WriteMmrLeafNumber(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(mmrLeafNumberKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the mmr leaf number", "err", err)
+	}
+}
+
+// ReadMmrNode retrieves the merkle mountain range node at the given position.
+func ReadMmrNode(db ethdb.KeyValueReader, pos uint64) *mmr.Node {
+	data, _ := db.Get(mmrNodeKey(pos))
+	if len(data) == 0 {
+		return nil
+	}
+	node := new(mmr.Node)
+	if err := rlp.DecodeBytes(data, node); err != nil {
+		log.Error("Invalid mmr node RLP", "pos", pos, "err", err)
+		return nil
+	}
+	return node
+}
+
+// WriteMmrNode stores the merkle mountain range node at the given position.
+func WriteMmrNode(db ethdb.KeyValueWriter, pos uint64, node *mmr.Node) {
+	data, err := rlp.EncodeToBytes(node)
+	if err != nil {
+		log.Crit("Failed to RLP encode mmr node", "err", err)
+	}
+	if err := db.Put(mmrNodeKey(pos), data); err != nil {
+		log.Crit("Failed to store mmr node", "err", err)
+	}
+}
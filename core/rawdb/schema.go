@@ -96,6 +96,12 @@ var (
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
+	checkPointPrefix = []byte("checkpoint-published-") // checkPointPrefix + num (uint64 big endian) -> published checkpoint
+
+	// mmrLeafNumberKey tracks the number of canonical block hashes in the merkle mountain range
+	mmrLeafNumberKey = []byte("MmrLeafNumber")
+	mmrNodePrefix    = []byte("mmr-node-") // mmrNodePrefix + pos (uint64 big endian) -> mmr node
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -234,6 +240,16 @@ func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
 
+// checkPointKey = checkPointPrefix + num (uint64 big endian)
+func checkPointKey(number uint64) []byte {
+	return append(checkPointPrefix, encodeBlockNumber(number)...)
+}
+
+// mmrNodeKey = mmrNodePrefix + pos (uint64 big endian)
+func mmrNodeKey(pos uint64) []byte {
+	return append(mmrNodePrefix, encodeBlockNumber(pos)...)
+}
+
 //--------------- mark ---------
 //type ChainType uint64
 //
@@ -0,0 +1,203 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateKeyKind is the kind of account data a StateKey refers to.
+type StateKeyKind uint8
+
+const (
+	AccountKey    StateKeyKind = iota // existence of the account
+	BalanceKey                        // balance of the account
+	NonceKey                          // nonce of the account
+	CodeKey                           // code of the account
+	StorageKey                        // storage slot of the account
+	POWStorageKey                     // POW storage slot of the account
+)
+
+// StateKey identifies a piece of account data accessed by a transaction.
+type StateKey struct {
+	Address common.Address
+	Kind    StateKeyKind
+	Slot    common.Hash
+}
+
+// ReadWriteSet records the state read and written by a transaction, so that
+// transactions executed speculatively on copies of the same state can be
+// checked for conflicts and merged back in order.
+//
+// Balances only added to or subtracted from are merged as deltas and are not
+// reads, so that paying the block coinbase does not make every transaction of
+// a block conflict with the previous ones. Any other write is also a read, as
+// its value is only valid on the state it was computed on.
+type ReadWriteSet struct {
+	reads  map[StateKey]struct{}
+	writes map[StateKey]struct{}
+
+	balances    map[common.Address]*big.Int // balances before the first change of the touched accounts
+	setBalances map[common.Address]struct{} // accounts whose balance was overwritten
+	created     []accountCreation           // accounts created with CreateAccount
+	dirty       map[common.Address]struct{} // touched accounts still changed once the reverted calls are undone
+	incomplete  bool                        // set when the state was accessed in a way that is not tracked
+}
+
+// accountCreation is an account created with CreateAccount and the journal entry
+// of the creation, which is no longer in the journal if the creation was reverted.
+type accountCreation struct {
+	addr  common.Address
+	index int
+	entry journalEntry
+}
+
+func newReadWriteSet() *ReadWriteSet {
+	return &ReadWriteSet{
+		reads:       make(map[StateKey]struct{}),
+		writes:      make(map[StateKey]struct{}),
+		balances:    make(map[common.Address]*big.Int),
+		setBalances: make(map[common.Address]struct{}),
+	}
+}
+
+// Writes returns the keys written by the transaction.
+func (rw *ReadWriteSet) Writes() map[StateKey]struct{} {
+	return rw.writes
+}
+
+// Complete reports whether all the state accessed by the transaction was tracked.
+// An incomplete set cannot be checked for conflicts nor merged.
+func (rw *ReadWriteSet) Complete() bool {
+	return !rw.incomplete
+}
+
+// ConflictsWith reports whether the transaction read any of the written keys.
+func (rw *ReadWriteSet) ConflictsWith(written map[StateKey]struct{}) bool {
+	for key := range rw.reads {
+		if _, ok := written[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// read records a read of the account data, which also depends on the
+// existence of the account.
+func (rw *ReadWriteSet) read(addr common.Address, kind StateKeyKind, slot common.Hash) {
+	rw.reads[StateKey{Address: addr, Kind: AccountKey}] = struct{}{}
+	rw.reads[StateKey{Address: addr, Kind: kind, Slot: slot}] = struct{}{}
+}
+
+// write records a write of the account data, touching the account first.
+func (rw *ReadWriteSet) write(s *StateDB, addr common.Address, kind StateKeyKind, slot common.Hash) {
+	rw.touch(s, addr)
+	rw.writes[StateKey{Address: addr, Kind: kind, Slot: slot}] = struct{}{}
+}
+
+// touch remembers the balance of the account before its first change. Touching
+// a missing or empty account may create or delete it.
+func (rw *ReadWriteSet) touch(s *StateDB, addr common.Address) {
+	if _, ok := rw.balances[addr]; ok {
+		return
+	}
+	balance := new(big.Int)
+	if obj := s.getStateObject(addr); obj != nil {
+		balance.Set(obj.Balance())
+		if obj.empty() {
+			rw.writes[StateKey{Address: addr, Kind: AccountKey}] = struct{}{}
+		}
+	} else {
+		rw.writes[StateKey{Address: addr, Kind: AccountKey}] = struct{}{}
+	}
+	rw.balances[addr] = balance
+	rw.writes[StateKey{Address: addr, Kind: BalanceKey}] = struct{}{}
+}
+
+// create records the creation of the account by the last journal entry.
+func (rw *ReadWriteSet) create(s *StateDB, addr common.Address) {
+	index := s.journal.length() - 1
+	rw.created = append(rw.created, accountCreation{addr: addr, index: index, entry: s.journal.entries[index]})
+}
+
+// StartTracking starts recording the state read and written through the
+// StateDB into a new read/write set.
+func (s *StateDB) StartTracking() {
+	s.rwSet = newReadWriteSet()
+}
+
+// StopTracking stops recording and returns the recorded read/write set. The
+// touched accounts left empty are recorded as written, as they are deleted
+// when the state is finalised. The touches and creations undone by reverted
+// calls are dropped from the changes merged, but are kept as writes.
+func (s *StateDB) StopTracking() *ReadWriteSet {
+	rw := s.rwSet
+	s.rwSet = nil
+	if rw == nil {
+		return nil
+	}
+	rw.dirty = make(map[common.Address]struct{})
+	for addr := range rw.balances {
+		if s.Empty(addr) {
+			rw.writes[StateKey{Address: addr, Kind: AccountKey}] = struct{}{}
+		}
+		if _, ok := s.journal.dirties[addr]; ok {
+			rw.dirty[addr] = struct{}{}
+		}
+	}
+	created := rw.created[:0]
+	for _, c := range rw.created {
+		if c.index < s.journal.length() && s.journal.entries[c.index] == c.entry {
+			created = append(created, c)
+		}
+	}
+	rw.created = created
+	return rw
+}
+
+// MergeWrites applies the writes recorded in rw by a transaction executed on
+// src, a copy of an earlier version of s. The transaction must not conflict with
+// the writes merged into s since the copy was made.
+func (s *StateDB) MergeWrites(src *StateDB, rw *ReadWriteSet) {
+	for _, c := range rw.created {
+		s.CreateAccount(c.addr)
+	}
+	for addr, origin := range rw.balances {
+		// The accounts only touched by reverted calls are left untouched
+		if _, ok := rw.dirty[addr]; !ok {
+			continue
+		}
+		balance := src.GetBalance(addr)
+		if _, ok := rw.setBalances[addr]; ok {
+			s.SetBalance(addr, balance)
+			continue
+		}
+		// Adding a zero delta still touches the account like the transaction did
+		delta := new(big.Int).Sub(balance, origin)
+		if delta.Sign() >= 0 {
+			s.AddBalance(addr, delta)
+		} else {
+			s.SubBalance(addr, delta.Neg(delta))
+		}
+	}
+	for key := range rw.writes {
+		if _, ok := rw.dirty[key.Address]; !ok {
+			continue
+		}
+		switch key.Kind {
+		case NonceKey:
+			s.SetNonce(key.Address, src.GetNonce(key.Address))
+		case CodeKey:
+			s.SetCode(key.Address, src.GetCode(key.Address))
+		case StorageKey:
+			s.SetState(key.Address, key.Slot, src.GetState(key.Address, key.Slot))
+		case POWStorageKey:
+			s.SetPOWState(key.Address, key.Slot, src.GetPOWState(key.Address, key.Slot))
+		}
+	}
+	for addr := range rw.balances {
+		if src.HasSelfDestructed(addr) {
+			s.SelfDestruct(addr)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/core/rawdb"
+)
+
+// TestMergeWrites tests that transactions executed on copies of the same state
+// are merged like if they had been executed in order, and that the conflicting
+// ones are detected.
+func TestMergeWrites(t *testing.T) {
+	var (
+		coinbase = common.HexToAddress("c0")
+		sender   = common.HexToAddress("aa")
+		receiver = common.HexToAddress("bb")
+		contract = common.HexToAddress("cc")
+		slot     = common.HexToHash("01")
+		other    = common.HexToHash("02")
+	)
+	base, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	base.SetBalance(sender, big.NewInt(100))
+	base.SetBalance(coinbase, big.NewInt(1))
+	base.SetState(contract, slot, common.HexToHash("01"))
+	base.SetNonce(contract, 1)
+	base.IntermediateRoot(true)
+
+	// The first transaction updates the contract and pays the coinbase
+	first := base.Copy()
+	first.StartTracking()
+	first.SetState(contract, slot, common.HexToHash("02"))
+	first.AddBalance(coinbase, big.NewInt(5))
+	firstSet := first.StopTracking()
+
+	// The second one transfers to a new account, pays the coinbase and
+	// reverts a write of the contract storage
+	second := base.Copy()
+	second.StartTracking()
+	if second.GetBalance(sender).Cmp(big.NewInt(10)) >= 0 {
+		second.SubBalance(sender, big.NewInt(10))
+		second.AddBalance(receiver, big.NewInt(10))
+	}
+	snapshot := second.Snapshot()
+	second.SetState(contract, other, common.HexToHash("09"))
+	second.RevertToSnapshot(snapshot)
+	second.AddBalance(coinbase, big.NewInt(7))
+	secondSet := second.StopTracking()
+
+	// The third one reads the contract storage written by the first one
+	third := base.Copy()
+	third.StartTracking()
+	third.GetState(contract, slot)
+	thirdSet := third.StopTracking()
+
+	written := make(map[StateKey]struct{})
+	for key := range firstSet.Writes() {
+		written[key] = struct{}{}
+	}
+	if firstSet.ConflictsWith(make(map[StateKey]struct{})) {
+		t.Fatal("first transaction conflicts with an empty write set")
+	}
+	if secondSet.ConflictsWith(written) {
+		t.Fatal("paying the coinbase should not conflict")
+	}
+	if !thirdSet.ConflictsWith(written) {
+		t.Fatal("reading a written slot should conflict")
+	}
+	// Writing a slot, even if reverted, depends on its previous value
+	if _, ok := secondSet.reads[StateKey{Address: contract, Kind: StorageKey, Slot: other}]; !ok {
+		t.Fatal("written slot not recorded as read")
+	}
+
+	merged := base.Copy()
+	merged.MergeWrites(first, firstSet)
+	merged.MergeWrites(second, secondSet)
+	merged.Finalise(true)
+
+	if balance := merged.GetBalance(coinbase); balance.Cmp(big.NewInt(13)) != 0 {
+		t.Errorf("coinbase balance mismatch: have %v, want %v", balance, 13)
+	}
+	if balance := merged.GetBalance(sender); balance.Cmp(big.NewInt(90)) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want %v", balance, 90)
+	}
+	if balance := merged.GetBalance(receiver); balance.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("receiver balance mismatch: have %v, want %v", balance, 10)
+	}
+	if value := merged.GetState(contract, slot); value != common.HexToHash("02") {
+		t.Errorf("slot mismatch: have %x, want %x", value, common.HexToHash("02"))
+	}
+	if value := merged.GetState(contract, other); value != (common.Hash{}) {
+		t.Errorf("reverted slot merged: have %x", value)
+	}
+
+	// Executing the transactions in order gives the same state
+	sequential := base.Copy()
+	sequential.SetState(contract, slot, common.HexToHash("02"))
+	sequential.AddBalance(coinbase, big.NewInt(5))
+	sequential.SubBalance(sender, big.NewInt(10))
+	sequential.AddBalance(receiver, big.NewInt(10))
+	sequential.AddBalance(coinbase, big.NewInt(7))
+	if have, want := merged.IntermediateRoot(true), sequential.IntermediateRoot(true); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// TestMergeWritesTouchedEmpty tests that touching an empty account is merged,
+// so that it gets deleted like in the speculative execution.
+func TestMergeWritesTouchedEmpty(t *testing.T) {
+	empty := common.HexToAddress("ee")
+
+	base, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	base.GetOrNewStateObject(empty)
+	base.IntermediateRoot(false)
+	if !base.Exist(empty) {
+		t.Fatal("empty account not created")
+	}
+	spec := base.Copy()
+	spec.StartTracking()
+	spec.AddBalance(empty, new(big.Int))
+	rwSet := spec.StopTracking()
+
+	if _, ok := rwSet.Writes()[StateKey{Address: empty, Kind: AccountKey}]; !ok {
+		t.Fatal("touched empty account not recorded as written")
+	}
+	merged := base.Copy()
+	merged.MergeWrites(spec, rwSet)
+	merged.Finalise(true)
+	if merged.Exist(empty) {
+		t.Error("touched empty account not deleted")
+	}
+}
@@ -102,6 +102,9 @@ type StateDB struct {
 	// Per-transaction access list
 	accessList *accessList
 
+	// Per-transaction read/write set, recorded between StartTracking and StopTracking
+	rwSet *ReadWriteSet
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -253,18 +256,27 @@ func (s *StateDB) SubRefund(gas uint64) {
 // Exist reports whether the given account address exists in the state.
 // Notably this also returns true for selfDestructed accounts.
 func (s *StateDB) Exist(addr common.Address) bool {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+	}
 	return s.getStateObject(addr) != nil
 }
 
 // Empty returns whether the state object is either non-existent
 // or empty according to the EIP161 specification (balance = nonce = code = 0)
 func (s *StateDB) Empty(addr common.Address) bool {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+	}
 	so := s.getStateObject(addr)
 	return so == nil || so.empty()
 }
 
 // GetBalance retrieves the balance from the given address or 0 if object not found
 func (s *StateDB) GetBalance(addr common.Address) *big.Int {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, BalanceKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Balance()
@@ -273,6 +285,9 @@ func (s *StateDB) GetBalance(addr common.Address) *big.Int {
 }
 
 func (s *StateDB) GetNonce(addr common.Address) uint64 {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, NonceKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Nonce()
@@ -287,6 +302,9 @@ func (s *StateDB) TxIndex() int {
 }
 
 func (s *StateDB) GetCode(addr common.Address) []byte {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, CodeKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Code(s.db)
@@ -295,6 +313,9 @@ func (s *StateDB) GetCode(addr common.Address) []byte {
 }
 
 func (s *StateDB) GetCodeSize(addr common.Address) int {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, CodeKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.CodeSize(s.db)
@@ -303,6 +324,9 @@ func (s *StateDB) GetCodeSize(addr common.Address) int {
 }
 
 func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, CodeKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
 		return common.Hash{}
@@ -312,6 +336,9 @@ func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
 
 // GetState retrieves a value from the given account's storage trie.
 func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, StorageKey, hash)
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetState(s.db, hash)
@@ -320,6 +347,10 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
 }
 
 func (self *StateDB) GetPOWState(a common.Address, b common.Hash) []byte {
+	if self.rwSet != nil {
+		// The account created if missing is empty, it is deleted again when the state is finalised
+		self.rwSet.read(a, POWStorageKey, b)
+	}
 	stateObject := self.GetOrNewStateObject(a)
 	if stateObject != nil {
 		return stateObject.GetPOWState(self.db, b)
@@ -352,6 +383,9 @@ func (s *StateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte,
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, StorageKey, hash)
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetCommittedState(s.db, hash)
@@ -377,6 +411,9 @@ func (s *StateDB) StorageTrie(addr common.Address) Trie {
 }
 
 func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.selfDestructed
@@ -390,6 +427,9 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *big.Int) {
+	if s.rwSet != nil {
+		s.rwSet.write(s, addr, BalanceKey, common.Hash{})
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -398,6 +438,9 @@ func (s *StateDB) AddBalance(addr common.Address, amount *big.Int) {
 
 // SubBalance subtracts amount from the account associated with addr.
 func (s *StateDB) SubBalance(addr common.Address, amount *big.Int) {
+	if s.rwSet != nil {
+		s.rwSet.write(s, addr, BalanceKey, common.Hash{})
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SubBalance(amount)
@@ -405,6 +448,11 @@ func (s *StateDB) SubBalance(addr common.Address, amount *big.Int) {
 }
 
 func (s *StateDB) SetBalance(addr common.Address, amount *big.Int) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, BalanceKey, common.Hash{})
+		s.rwSet.write(s, addr, BalanceKey, common.Hash{})
+		s.rwSet.setBalances[addr] = struct{}{}
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetBalance(amount)
@@ -412,6 +460,10 @@ func (s *StateDB) SetBalance(addr common.Address, amount *big.Int) {
 }
 
 func (s *StateDB) SetNonce(addr common.Address, nonce uint64) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, NonceKey, common.Hash{})
+		s.rwSet.write(s, addr, NonceKey, common.Hash{})
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetNonce(nonce)
@@ -419,6 +471,10 @@ func (s *StateDB) SetNonce(addr common.Address, nonce uint64) {
 }
 
 func (s *StateDB) SetCode(addr common.Address, code []byte) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, CodeKey, common.Hash{})
+		s.rwSet.write(s, addr, CodeKey, common.Hash{})
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetCode(crypto.Keccak256Hash(code), code)
@@ -426,6 +482,10 @@ func (s *StateDB) SetCode(addr common.Address, code []byte) {
 }
 
 func (s *StateDB) SetState(addr common.Address, key, value common.Hash) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, StorageKey, key)
+		s.rwSet.write(s, addr, StorageKey, key)
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetState(s.db, key, value)
@@ -433,6 +493,10 @@ func (s *StateDB) SetState(addr common.Address, key, value common.Hash) {
 }
 
 func (self *StateDB) SetPOWState(addr common.Address, key common.Hash, value []byte) {
+	if self.rwSet != nil {
+		self.rwSet.read(addr, POWStorageKey, key)
+		self.rwSet.write(self, addr, POWStorageKey, key)
+	}
 	stateObject := self.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetPOWState(self.db, key, value)
@@ -442,6 +506,9 @@ func (self *StateDB) SetPOWState(addr common.Address, key common.Hash, value []b
 // SetStorage replaces the entire storage for the specified account with given
 // storage. This function should only be used for debugging.
 func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	if s.rwSet != nil {
+		s.rwSet.incomplete = true
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetStorage(storage)
@@ -454,10 +521,19 @@ func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common
 // The account's state object is still available until the state is committed,
 // getStateObject will return a non-nil account after SelfDestruct.
 func (s *StateDB) SelfDestruct(addr common.Address) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
 		return
 	}
+	if s.rwSet != nil {
+		s.rwSet.read(addr, BalanceKey, common.Hash{})
+		s.rwSet.write(s, addr, AccountKey, common.Hash{})
+		s.rwSet.write(s, addr, BalanceKey, common.Hash{})
+		s.rwSet.setBalances[addr] = struct{}{}
+	}
 	s.journal.append(selfDestructChange{
 		account:     &addr,
 		prev:        stateObject.selfDestructed,
@@ -468,6 +544,9 @@ func (s *StateDB) SelfDestruct(addr common.Address) {
 }
 
 func (s *StateDB) Selfdestruct6780(addr common.Address) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
 		return
@@ -640,13 +719,23 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 //
 // Carrying over the balance ensures that Ether doesn't disappear.
 func (s *StateDB) CreateAccount(addr common.Address) {
+	if s.rwSet != nil {
+		s.rwSet.read(addr, AccountKey, common.Hash{})
+		s.rwSet.write(s, addr, AccountKey, common.Hash{})
+	}
 	newObj, prev := s.createObject(addr)
+	if s.rwSet != nil {
+		s.rwSet.create(s, addr)
+	}
 	if prev != nil {
 		newObj.setBalance(prev.data.Balance)
 	}
 }
 
 func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value common.Hash) bool) error {
+	if db.rwSet != nil {
+		db.rwSet.incomplete = true
+	}
 	so := db.getStateObject(addr)
 	if so == nil {
 		return nil
@@ -677,6 +766,9 @@ func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value common
 
 // ForEachPOWStorage is callback function. cb return true indicating like to continue, return false indicating stop
 func (self *StateDB) ForEachPOWStorage(addr common.Address, cb func(key common.Hash, value []byte) bool) {
+	if self.rwSet != nil {
+		self.rwSet.incomplete = true
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return
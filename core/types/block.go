@@ -88,6 +88,10 @@ type Header struct {
 
 	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
 	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
+
+	// MmrRoot is the root of the Merkle Mountain Range of all the ancestor block
+	// hashes, it was added by the mmr fork and is ignored in earlier headers.
+	MmrRoot *common.Hash `json:"mmrRoot" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -238,6 +242,10 @@ func CopyHeader(h *Header) *Header {
 	if h.BaseFee != nil {
 		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
 	}
+	if h.MmrRoot != nil {
+		root := *h.MmrRoot
+		cpy.MmrRoot = &root
+	}
 	if len(h.Extra) > 0 {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeCurrencyTx is a dynamic fee transaction paying its gas fees in a whitelisted ERC20
+// fee currency, the fee cap and the tip cap are denominated in that currency.
+type FeeCurrencyTx struct {
+	ChainID     *big.Int
+	Nonce       uint64
+	GasTipCap   *big.Int
+	GasFeeCap   *big.Int
+	Gas         uint64
+	FeeCurrency common.Address
+	To          *common.Address `rlp:"nil"` // nil means contract creation
+	Value       *big.Int
+	Data        []byte
+	AccessList  AccessList
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *FeeCurrencyTx) copy() TxData {
+	cpy := &FeeCurrencyTx{
+		Nonce:       tx.Nonce,
+		To:          copyAddressPtr(tx.To),
+		Data:        common.CopyBytes(tx.Data),
+		Gas:         tx.Gas,
+		FeeCurrency: tx.FeeCurrency,
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *FeeCurrencyTx) txType() byte                 { return FeeCurrencyTxType }
+func (tx *FeeCurrencyTx) chainID() *big.Int            { return tx.ChainID }
+func (tx *FeeCurrencyTx) accessList() AccessList       { return tx.AccessList }
+func (tx *FeeCurrencyTx) data() []byte                 { return tx.Data }
+func (tx *FeeCurrencyTx) gas() uint64                  { return tx.Gas }
+func (tx *FeeCurrencyTx) gasFeeCap() *big.Int          { return tx.GasFeeCap }
+func (tx *FeeCurrencyTx) gasTipCap() *big.Int          { return tx.GasTipCap }
+func (tx *FeeCurrencyTx) gasPrice() *big.Int           { return tx.GasFeeCap }
+func (tx *FeeCurrencyTx) value() *big.Int              { return tx.Value }
+func (tx *FeeCurrencyTx) nonce() uint64                { return tx.Nonce }
+func (tx *FeeCurrencyTx) to() *common.Address          { return tx.To }
+func (tx *FeeCurrencyTx) feeCurrency() *common.Address { return &tx.FeeCurrency }
+
+func (tx *FeeCurrencyTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *FeeCurrencyTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
@@ -18,11 +18,13 @@ package types
 
 import (
 	"bytes"
+	"fmt"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/crypto/sha3"
@@ -114,6 +116,45 @@ func DeriveSha(list DerivableList, hasher TrieHasher) common.Hash {
 	return hasher.Hash()
 }
 
+// proofList collects the encoded trie nodes written by a merkle proof
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// DeriveProof builds the merkle proof of the index'th item of the list in the trie
+// hashed by DeriveSha. It returns the root of the trie, the key of the item and the
+// encoded trie nodes on the path from the root to the item.
+func DeriveProof(list DerivableList, index int) (common.Hash, []byte, [][]byte, error) {
+	if index < 0 || index >= list.Len() {
+		return common.Hash{}, nil, nil, fmt.Errorf("index %d out of range [0, %d)", index, list.Len())
+	}
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		return common.Hash{}, nil, nil, err
+	}
+	valueBuf := encodeBufferPool.Get().(*bytes.Buffer)
+	defer encodeBufferPool.Put(valueBuf)
+
+	var indexBuf []byte
+	for i := 0; i < list.Len(); i++ {
+		indexBuf = rlp.AppendUint64(indexBuf[:0], uint64(i))
+		tr.Update(indexBuf, encodeForDerive(list, i, valueBuf))
+	}
+	key := rlp.AppendUint64(nil, uint64(index))
+	var proof proofList
+	if err := tr.Prove(key, 0, &proof); err != nil {
+		return common.Hash{}, nil, nil, err
+	}
+	return tr.Hash(), key, proof, nil
+}
+
 // Deprecated: for test use only
 func DeriveTire(rs types.Receipts, tr *trie.Trie) *trie.Trie {
 	valueBuf := encodeBufferPool.Get().(*bytes.Buffer)
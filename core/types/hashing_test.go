@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/mapprotocol/atlas/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -142,6 +143,44 @@ func TestDerivableList(t *testing.T) {
 	}
 }
 
+func TestDeriveProof(t *testing.T) {
+	txs, err := genTxs(300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{1, 2, 0x7f, 0x80, 300} {
+		list := txs[:n]
+		root := types.DeriveSha(list, trie.NewStackTrie(nil))
+		for _, index := range []int{0, 1, 0x7f, 0x80, n - 1} {
+			if index >= n {
+				continue
+			}
+			got, key, proof, err := types.DeriveProof(list, index)
+			if err != nil {
+				t.Fatalf("%d txs, index %d: %v", n, index, err)
+			}
+			if got != root {
+				t.Fatalf("%d txs: proof root %x, want %x", n, got, root)
+			}
+			db := memorydb.New()
+			for _, node := range proof {
+				db.Put(crypto.Keccak256(node), node)
+			}
+			value, err := trie.VerifyProof(root, key, db)
+			if err != nil {
+				t.Fatalf("%d txs, index %d: invalid proof: %v", n, index, err)
+			}
+			want, _ := list[index].MarshalBinary()
+			if !bytes.Equal(value, want) {
+				t.Fatalf("%d txs, index %d: proved %x, want %x", n, index, value, want)
+			}
+		}
+	}
+	if _, _, _, err := types.DeriveProof(txs[:1], 1); err == nil {
+		t.Fatal("proof of an index out of range succeeded")
+	}
+}
+
 func genTxs(num uint64) (types.Transactions, error) {
 	key, err := crypto.HexToECDSA("deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
 	if err != nil {
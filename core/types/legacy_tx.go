@@ -91,17 +91,18 @@ func (tx *LegacyTx) copy() TxData {
 }
 
 // accessors for innerTx.
-func (tx *LegacyTx) txType() byte           { return LegacyTxType }
-func (tx *LegacyTx) chainID() *big.Int      { return deriveChainId(tx.V) }
-func (tx *LegacyTx) accessList() AccessList { return nil }
-func (tx *LegacyTx) data() []byte           { return tx.Data }
-func (tx *LegacyTx) gas() uint64            { return tx.Gas }
-func (tx *LegacyTx) gasPrice() *big.Int     { return tx.GasPrice }
-func (tx *LegacyTx) gasTipCap() *big.Int    { return tx.GasPrice }
-func (tx *LegacyTx) gasFeeCap() *big.Int    { return tx.GasPrice }
-func (tx *LegacyTx) value() *big.Int        { return tx.Value }
-func (tx *LegacyTx) nonce() uint64          { return tx.Nonce }
-func (tx *LegacyTx) to() *common.Address    { return tx.To }
+func (tx *LegacyTx) txType() byte                 { return LegacyTxType }
+func (tx *LegacyTx) chainID() *big.Int            { return deriveChainId(tx.V) }
+func (tx *LegacyTx) accessList() AccessList       { return nil }
+func (tx *LegacyTx) data() []byte                 { return tx.Data }
+func (tx *LegacyTx) gas() uint64                  { return tx.Gas }
+func (tx *LegacyTx) gasPrice() *big.Int           { return tx.GasPrice }
+func (tx *LegacyTx) gasTipCap() *big.Int          { return tx.GasPrice }
+func (tx *LegacyTx) gasFeeCap() *big.Int          { return tx.GasPrice }
+func (tx *LegacyTx) value() *big.Int              { return tx.Value }
+func (tx *LegacyTx) nonce() uint64                { return tx.Nonce }
+func (tx *LegacyTx) to() *common.Address          { return tx.To }
+func (tx *LegacyTx) feeCurrency() *common.Address { return nil }
 
 func (tx *LegacyTx) rawSignatureValues() (v, r, s *big.Int) {
 	return tx.V, tx.R, tx.S
@@ -53,6 +53,11 @@ type Log struct {
 	// The Removed field is true if this log was reverted due to a chain reorganisation.
 	// You must pay attention to this field if you receive logs through a filter query.
 	Removed bool `json:"removed"`
+
+	// The System field is true if this log was emitted by the core contract calls made
+	// during the block processing, outside of transactions. System logs belong to the
+	// system receipt of the block and have no transaction hash.
+	System bool `json:"system,omitempty"`
 }
 
 type logMarshaling struct {
@@ -193,7 +193,7 @@ func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
 			return errEmptyTypedReceipt
 		}
 		r.Type = b[0]
-		if r.Type == AccessListTxType || r.Type == DynamicFeeTxType {
+		if r.Type == AccessListTxType || r.Type == DynamicFeeTxType || r.Type == FeeCurrencyTxType {
 			var dec receiptRLP
 			if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
 				return err
@@ -229,7 +229,7 @@ func (r *Receipt) decodeTyped(b []byte) error {
 		return errEmptyTypedReceipt
 	}
 	switch b[0] {
-	case DynamicFeeTxType, AccessListTxType:
+	case DynamicFeeTxType, AccessListTxType, FeeCurrencyTxType:
 		var data receiptRLP
 		err := rlp.DecodeBytes(b[1:], &data)
 		if err != nil {
@@ -398,6 +398,9 @@ func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
 	case DynamicFeeTxType:
 		w.WriteByte(DynamicFeeTxType)
 		rlp.Encode(w, data)
+	case FeeCurrencyTxType:
+		w.WriteByte(FeeCurrencyTxType)
+		rlp.Encode(w, data)
 	default:
 		// For unsupported types, write nothing. Since this is for
 		// DeriveSha, the error will be caught matching the derived hash
@@ -452,11 +455,9 @@ func (rs Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, nu
 	// Handle block finalization receipt (only IBFT)
 	if len(txs)+1 == len(rs) {
 		j := len(txs)
+		rs[j].deriveSystemFields(hash, number, j)
 		for k := 0; k < len(rs[j].Logs); k++ {
 			rs[j].Logs[k].BlockNumber = number
-			rs[j].Logs[k].BlockHash = hash
-			rs[j].Logs[k].TxHash = hash
-			rs[j].Logs[k].TxIndex = uint(j)
 			rs[j].Logs[k].Index = logIndex
 			logIndex++
 		}
@@ -338,6 +338,80 @@ func TestDeriveFields(t *testing.T) {
 	}
 }
 
+// TestDeriveFieldsSystemReceipt tests that the system receipt following the
+// transaction receipts of a block is derived without any transaction fields,
+// and that it is hashed like the former block receipt.
+func TestDeriveFieldsSystemReceipt(t *testing.T) {
+	to := common.HexToAddress("0x2")
+	txs := Transactions{
+		NewTx(&LegacyTx{To: &to, Nonce: 1, Value: big.NewInt(1), Gas: 1, GasPrice: big.NewInt(1)}),
+	}
+	number := big.NewInt(1)
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+
+	systemLogs := []*Log{
+		{Address: common.BytesToAddress([]byte{0x22})},
+		{Address: common.BytesToAddress([]byte{0x02, 0x22})},
+	}
+	system := NewSystemReceipt(systemLogs, hash, len(txs))
+
+	// The consensus encoding is the one of a successful legacy receipt using no gas
+	legacy := NewReceipt(nil, false, 0)
+	legacy.Logs = systemLogs
+	legacy.Bloom = CreateBloom(Receipts{legacy})
+	have, _ := system.MarshalBinary()
+	want, _ := legacy.MarshalBinary()
+	if !bytes.Equal(have, want) {
+		t.Fatalf("system receipt encoding mismatch: have %x, want %x", have, want)
+	}
+
+	receipts := Receipts{
+		&Receipt{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 1, Logs: []*Log{{Address: to}}},
+		system,
+	}
+	clearComputedFieldsOnReceipts(t, receipts)
+	if err := receipts.DeriveFields(params.TestChainConfig, hash, number.Uint64(), txs); err != nil {
+		t.Fatalf("DeriveFields(...) = %v, want <nil>", err)
+	}
+	if receipts.SystemReceipt(len(txs)) != system {
+		t.Fatal("system receipt not found")
+	}
+	if have := receipts.TransactionReceipts(len(txs)); len(have) != len(txs) {
+		t.Fatalf("transaction receipt count mismatch: have %d, want %d", len(have), len(txs))
+	}
+	if receipts[0].Logs[0].System {
+		t.Error("transaction log marked as system log")
+	}
+	if system.TxHash != (common.Hash{}) {
+		t.Errorf("system receipt TxHash = %s, want empty", system.TxHash.String())
+	}
+	if system.BlockHash != hash {
+		t.Errorf("system receipt BlockHash = %s, want %s", system.BlockHash.String(), hash.String())
+	}
+	if system.BlockNumber.Cmp(number) != 0 {
+		t.Errorf("system receipt BlockNumber = %s, want %s", system.BlockNumber.String(), number.String())
+	}
+	for j, log := range system.Logs {
+		if !log.System {
+			t.Errorf("system receipt Logs[%d] not marked as system log", j)
+		}
+		if log.TxHash != (common.Hash{}) {
+			t.Errorf("system receipt Logs[%d].TxHash = %s, want empty", j, log.TxHash.String())
+		}
+		if log.TxIndex != uint(len(txs)) {
+			t.Errorf("system receipt Logs[%d].TxIndex = %d, want %d", j, log.TxIndex, len(txs))
+		}
+		if log.Index != uint(j+1) {
+			t.Errorf("system receipt Logs[%d].Index = %d, want %d", j, log.Index, j+1)
+		}
+	}
+
+	// Blocks without system logs have no system receipt
+	if receipts[:1].SystemReceipt(len(txs)) != nil {
+		t.Error("system receipt found in transaction receipts")
+	}
+}
+
 // TestTypedReceiptEncodingDecoding reproduces a flaw that existed in the receipt
 // rlp decoder, which failed due to a shadowing error.
 func TestTypedReceiptEncodingDecoding(t *testing.T) {
@@ -502,4 +576,5 @@ func clearComputedFieldsOnLog(t *testing.T, log *Log) {
 	log.TxHash = common.Hash{}
 	log.TxIndex = math.MaxUint32
 	log.Index = math.MaxUint32
+	log.System = false
 }
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NewSystemReceipt creates the system receipt of a block, holding the logs emitted by
+// the core contract calls made during the block processing outside of transactions.
+// It follows the receipts of the txCount transactions of the block and is hashed with
+// them like a successful legacy receipt that used no gas.
+func NewSystemReceipt(logs []*Log, blockHash common.Hash, txCount int) *Receipt {
+	receipt := NewReceipt(nil, false, 0)
+	receipt.Logs = logs
+	receipt.Bloom = CreateBloom(Receipts{receipt})
+	receipt.BlockHash = blockHash
+	receipt.TransactionIndex = uint(txCount)
+	SetSystemLogFields(logs, blockHash, txCount)
+	return receipt
+}
+
+// SetSystemLogFields sets the derived fields of the logs of the system receipt of a
+// block with txCount transactions, apart from their block number and index.
+func SetSystemLogFields(logs []*Log, blockHash common.Hash, txCount int) {
+	for _, log := range logs {
+		log.BlockHash = blockHash
+		log.TxHash = common.Hash{}
+		log.TxIndex = uint(txCount)
+		log.System = true
+	}
+}
+
+// SystemReceipt returns the system receipt of a block with txCount transactions, or
+// nil if the block has none.
+func (rs Receipts) SystemReceipt(txCount int) *Receipt {
+	if len(rs) == txCount+1 {
+		return rs[txCount]
+	}
+	return nil
+}
+
+// TransactionReceipts returns the receipts of a block with txCount transactions
+// without its system receipt.
+func (rs Receipts) TransactionReceipts(txCount int) Receipts {
+	if len(rs) > txCount {
+		return rs[:txCount]
+	}
+	return rs
+}
+
+// deriveSystemFields fills the system receipt with its computed fields.
+func (r *Receipt) deriveSystemFields(hash common.Hash, number uint64, txCount int) {
+	r.TxHash = common.Hash{}
+	r.BlockHash = hash
+	r.BlockNumber = new(big.Int).SetUint64(number)
+	r.TransactionIndex = uint(txCount)
+	SetSystemLogFields(r.Logs, hash, txCount)
+}
@@ -46,6 +46,7 @@ const (
 	LegacyTxType = iota
 	AccessListTxType
 	DynamicFeeTxType
+	FeeCurrencyTxType = 0x7b
 )
 
 // Transaction is an Ethereum transaction.
@@ -68,7 +69,7 @@ func NewTx(inner TxData) *Transaction {
 
 // TxData is the underlying data of a transaction.
 //
-// This is implemented by DynamicFeeTx, LegacyTx and AccessListTx.
+// This is implemented by DynamicFeeTx, LegacyTx, AccessListTx and FeeCurrencyTx.
 type TxData interface {
 	txType() byte // returns the type ID
 	copy() TxData // creates a deep copy and initializes all fields
@@ -83,6 +84,7 @@ type TxData interface {
 	value() *big.Int
 	nonce() uint64
 	to() *common.Address
+	feeCurrency() *common.Address
 
 	rawSignatureValues() (v, r, s *big.Int)
 	setSignatureValues(chainID, v, r, s *big.Int)
@@ -187,6 +189,10 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		var inner DynamicFeeTx
 		err := rlp.DecodeBytes(b[1:], &inner)
 		return &inner, err
+	case FeeCurrencyTxType:
+		var inner FeeCurrencyTx
+		err := rlp.DecodeBytes(b[1:], &inner)
+		return &inner, err
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -462,7 +468,21 @@ type TxWithMinerFee struct {
 // miner gasTipCap if a base fee is provided.
 // Returns error in case of a negative effective miner gasTipCap.
 func NewTxWithMinerFee(tx *Transaction, baseFee *big.Int) (*TxWithMinerFee, error) {
-	minerFee, err := tx.EffectiveGasTip(baseFee)
+	return newTxWithMinerFee(tx, baseFeeMinerFee(baseFee))
+}
+
+// MinerFeeFunc returns the effective miner gasTipCap of a transaction in the native token.
+type MinerFeeFunc func(tx *Transaction) (*big.Int, error)
+
+// baseFeeMinerFee returns the effective miner gasTipCap given the base fee.
+func baseFeeMinerFee(baseFee *big.Int) MinerFeeFunc {
+	return func(tx *Transaction) (*big.Int, error) {
+		return tx.EffectiveGasTip(baseFee)
+	}
+}
+
+func newTxWithMinerFee(tx *Transaction, minerFeeFn MinerFeeFunc) (*TxWithMinerFee, error) {
+	minerFee, err := minerFeeFn(tx)
 	if err != nil {
 		return nil, err
 	}
@@ -504,10 +524,10 @@ func (s *TxByPriceAndTime) Pop() interface{} {
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type TransactionsByPriceAndNonce struct {
-	txs     map[common.Address]Transactions // Per account nonce-sorted list of transactions
-	heads   TxByPriceAndTime                // Next transaction for each unique account (price heap)
-	signer  Signer                          // Signer for the set of transactions
-	baseFee *big.Int                        // Current base fee
+	txs      map[common.Address]Transactions // Per account nonce-sorted list of transactions
+	heads    TxByPriceAndTime                // Next transaction for each unique account (price heap)
+	signer   Signer                          // Signer for the set of transactions
+	minerFee MinerFeeFunc                    // Effective miner gasTipCap of the transactions
 }
 
 // NewTransactionsByPriceAndNonce creates a transaction set that can retrieve
@@ -516,11 +536,21 @@ type TransactionsByPriceAndNonce struct {
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
 func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions, baseFee *big.Int) *TransactionsByPriceAndNonce {
+	return NewTransactionsByPriceAndNonceWithMinerFee(signer, txs, baseFeeMinerFee(baseFee))
+}
+
+// NewTransactionsByPriceAndNonceWithMinerFee creates a transaction set sorted by the
+// miner gasTipCap given by minerFee, which converts the tips of the fee currency
+// transactions to the native token.
+//
+// Note, the input map is reowned so the caller should not interact any more with
+// if after providing it to the constructor.
+func NewTransactionsByPriceAndNonceWithMinerFee(signer Signer, txs map[common.Address]Transactions, minerFee MinerFeeFunc) *TransactionsByPriceAndNonce {
 	// Initialize a price and received time based heap with the head transactions
 	heads := make(TxByPriceAndTime, 0, len(txs))
 	for from, accTxs := range txs {
 		acc, err := Sender(signer, accTxs[0])
-		wrapped, err := NewTxWithMinerFee(accTxs[0], baseFee)
+		wrapped, err := newTxWithMinerFee(accTxs[0], minerFee)
 		// Remove transaction if sender doesn't match from, or if wrapping fails.
 		if acc != from || err != nil {
 			delete(txs, from)
@@ -532,10 +562,10 @@ func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transa
 	heap.Init(&heads)
 	// Assemble and return the transaction set
 	return &TransactionsByPriceAndNonce{
-		txs:     txs,
-		heads:   heads,
-		signer:  signer,
-		baseFee: baseFee,
+		txs:      txs,
+		heads:    heads,
+		signer:   signer,
+		minerFee: minerFee,
 	}
 }
 
@@ -551,7 +581,7 @@ func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
 func (t *TransactionsByPriceAndNonce) Shift() {
 	acc, _ := Sender(t.signer, t.heads[0].tx)
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
-		if wrapped, err := NewTxWithMinerFee(txs[0], t.baseFee); err == nil {
+		if wrapped, err := newTxWithMinerFee(txs[0], t.minerFee); err == nil {
 			t.heads[0], t.txs[acc] = wrapped, txs[1:]
 			heap.Fix(&t.heads, 0)
 			return
@@ -571,51 +601,55 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 //
 // NOTE: In a future PR this will be removed.
 type Message struct {
-	to         *common.Address
-	from       common.Address
-	nonce      uint64
-	amount     *big.Int
-	gasLimit   uint64
-	gasPrice   *big.Int
-	gasFeeCap  *big.Int
-	gasTipCap  *big.Int
-	data       []byte
-	accessList AccessList
-	isFake     bool
-}
-
-func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice, gasFeeCap, gasTipCap *big.Int, data []byte, accessList AccessList, isFake bool) Message {
+	to          *common.Address
+	from        common.Address
+	nonce       uint64
+	amount      *big.Int
+	gasLimit    uint64
+	gasPrice    *big.Int
+	gasFeeCap   *big.Int
+	gasTipCap   *big.Int
+	data        []byte
+	accessList  AccessList
+	feeCurrency *common.Address
+	isFake      bool
+}
+
+func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice, gasFeeCap, gasTipCap *big.Int, feeCurrency *common.Address, data []byte, accessList AccessList, isFake bool) Message {
 	return Message{
-		from:       from,
-		to:         to,
-		nonce:      nonce,
-		amount:     amount,
-		gasLimit:   gasLimit,
-		gasPrice:   gasPrice,
-		gasFeeCap:  gasFeeCap,
-		gasTipCap:  gasTipCap,
-		data:       data,
-		accessList: accessList,
-		isFake:     isFake,
+		from:        from,
+		to:          to,
+		nonce:       nonce,
+		amount:      amount,
+		gasLimit:    gasLimit,
+		gasPrice:    gasPrice,
+		gasFeeCap:   gasFeeCap,
+		gasTipCap:   gasTipCap,
+		feeCurrency: feeCurrency,
+		data:        data,
+		accessList:  accessList,
+		isFake:      isFake,
 	}
 }
 
 // AsMessage returns the transaction as a core.Message.
 func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	msg := Message{
-		nonce:      tx.Nonce(),
-		gasLimit:   tx.Gas(),
-		gasPrice:   new(big.Int).Set(tx.GasPrice()),
-		gasFeeCap:  new(big.Int).Set(tx.GasFeeCap()),
-		gasTipCap:  new(big.Int).Set(tx.GasTipCap()),
-		to:         tx.To(),
-		amount:     tx.Value(),
-		data:       tx.Data(),
-		accessList: tx.AccessList(),
-		isFake:     false,
-	}
-	// If baseFee provided, set gasPrice to effectiveGasPrice.
-	if baseFee != nil {
+		nonce:       tx.Nonce(),
+		gasLimit:    tx.Gas(),
+		gasPrice:    new(big.Int).Set(tx.GasPrice()),
+		gasFeeCap:   new(big.Int).Set(tx.GasFeeCap()),
+		gasTipCap:   new(big.Int).Set(tx.GasTipCap()),
+		to:          tx.To(),
+		amount:      tx.Value(),
+		data:        tx.Data(),
+		accessList:  tx.AccessList(),
+		feeCurrency: tx.FeeCurrency(),
+		isFake:      false,
+	}
+	// If baseFee provided, set gasPrice to effectiveGasPrice. The base fee of a
+	// fee currency transaction is converted by the state transition.
+	if baseFee != nil && msg.feeCurrency == nil {
 		msg.gasPrice = math.BigMin(msg.gasPrice.Add(msg.gasTipCap, baseFee), msg.gasFeeCap)
 	}
 	var err error
@@ -623,26 +657,36 @@ func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	return msg, err
 }
 
-func (m Message) From() common.Address               { return m.from }
-func (m Message) To() *common.Address                { return m.to }
-func (m Message) GasPrice() *big.Int                 { return m.gasPrice }
-func (m Message) GasFeeCap() *big.Int                { return m.gasFeeCap }
-func (m Message) GasTipCap() *big.Int                { return m.gasTipCap }
-func (m Message) Value() *big.Int                    { return m.amount }
-func (m Message) Gas() uint64                        { return m.gasLimit }
-func (m Message) Nonce() uint64                      { return m.nonce }
-func (m Message) Data() []byte                       { return m.data }
-func (m Message) AccessList() AccessList             { return m.accessList }
-func (m Message) IsFake() bool                       { return m.isFake }
-func (tx *Transaction) FeeCurrency() *common.Address { return nil }
+func (m Message) From() common.Address         { return m.from }
+func (m Message) To() *common.Address          { return m.to }
+func (m Message) GasPrice() *big.Int           { return m.gasPrice }
+func (m Message) GasFeeCap() *big.Int          { return m.gasFeeCap }
+func (m Message) GasTipCap() *big.Int          { return m.gasTipCap }
+func (m Message) Value() *big.Int              { return m.amount }
+func (m Message) Gas() uint64                  { return m.gasLimit }
+func (m Message) Nonce() uint64                { return m.nonce }
+func (m Message) Data() []byte                 { return m.data }
+func (m Message) AccessList() AccessList       { return m.accessList }
+func (m Message) IsFake() bool                 { return m.isFake }
+func (m Message) FeeCurrency() *common.Address { return m.feeCurrency }
+
+// FeeCurrency returns the currency the gas fees are paid in, nil for the native token.
+func (tx *Transaction) FeeCurrency() *common.Address {
+	return copyAddressPtr(tx.inner.feeCurrency())
+}
+
+// Fee returns the maximum gas fees of the transaction, in its fee currency.
 func (tx *Transaction) Fee() *big.Int {
 	return Fee(tx.GasPrice(), tx.Gas(), nil)
 }
 
 // Fee calculates the transaction fee (gasLimit * gasPrice + gatewayFee)
 func Fee(gasPrice *big.Int, gasLimit uint64, gatewayFee *big.Int) *big.Int {
-	gasFee := new(big.Int).Mul(gasPrice, big.NewInt(int64(gasLimit)))
-	return gasFee.Add(gasFee, gatewayFee)
+	gasFee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	if gatewayFee != nil {
+		gasFee.Add(gasFee, gatewayFee)
+	}
+	return gasFee
 }
 
 // copyAddressPtr copies an address.
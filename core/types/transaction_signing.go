@@ -41,6 +41,8 @@ type sigCache struct {
 func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
 	var signer Signer
 	switch {
+	case config.IsFeeCurrency(blockNumber):
+		signer = NewFeeCurrencySigner(config.ChainID)
 	case config.IsLondon(blockNumber):
 		signer = NewLondonSigner(config.ChainID)
 	case config.IsBerlin(blockNumber):
@@ -60,6 +62,9 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config *params.ChainConfig) Signer {
 	if config.ChainID != nil {
+		if config.FeeCurrencyBlock != nil {
+			return NewFeeCurrencySigner(config.ChainID)
+		}
 		if config.LondonBlock != nil {
 			return NewLondonSigner(config.ChainID)
 		}
@@ -84,7 +89,7 @@ func LatestSignerForChainID(chainID *big.Int) Signer {
 	if chainID == nil {
 		return HomesteadSigner{}
 	}
-	return NewLondonSigner(chainID)
+	return NewFeeCurrencySigner(chainID)
 }
 
 // SignTx signs the transaction using the given signer and private key.
@@ -167,6 +172,75 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+type feeCurrencySigner struct{ londonSigner }
+
+// NewFeeCurrencySigner returns a signer that accepts
+// - fee currency transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewFeeCurrencySigner(chainId *big.Int) Signer {
+	return feeCurrencySigner{londonSigner{eip2930Signer{NewEIP155Signer(chainId)}}}
+}
+
+func (s feeCurrencySigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != FeeCurrencyTxType {
+		return s.londonSigner.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Fee currency txs use 0 and 1 as their recovery id like the
+	// DynamicFee txs, add 27 to become equivalent to unprotected
+	// Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, ErrInvalidChainId
+	}
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+func (s feeCurrencySigner) Equal(s2 Signer) bool {
+	x, ok := s2.(feeCurrencySigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0
+}
+
+func (s feeCurrencySigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*FeeCurrencyTx)
+	if !ok {
+		return s.londonSigner.SignatureValues(tx, sig)
+	}
+	// Check that chain ID of tx matches the signer. We also accept ID zero here,
+	// because it indicates that the chain ID was not specified in the tx.
+	if txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(s.chainId) != 0 {
+		return nil, nil, nil, ErrInvalidChainId
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s feeCurrencySigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != FeeCurrencyTxType {
+		return s.londonSigner.Hash(tx)
+	}
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.inner.feeCurrency(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+		})
+}
+
 type londonSigner struct{ eip2930Signer }
 
 // NewLondonSigner returns a signer that accepts
@@ -488,6 +488,67 @@ func TestTransactionCoding(t *testing.T) {
 	}
 }
 
+// TestFeeCurrencyTransactionCoding tests that fee currency transactions keep their
+// currency and sender through rlp and JSON.
+func TestFeeCurrencyTransactionCoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var (
+		signer      = NewFeeCurrencySigner(common.Big1)
+		sender      = crypto.PubkeyToAddress(key.PublicKey)
+		recipient   = common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87")
+		feeCurrency = common.HexToAddress("0x0000000000000000000000000000000000000765")
+	)
+	tx, err := SignNewTx(key, signer, &FeeCurrencyTx{
+		ChainID:     big.NewInt(1),
+		Nonce:       1,
+		To:          &recipient,
+		Gas:         123457,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   big.NewInt(10),
+		FeeCurrency: feeCurrency,
+		Data:        []byte("abcdef"),
+	})
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != FeeCurrencyTxType {
+		t.Fatalf("wrong transaction type: have %d, want %d", tx.Type(), FeeCurrencyTxType)
+	}
+	for _, coding := range []func(*Transaction) (*Transaction, error){encodeDecodeBinary, encodeDecodeJSON} {
+		parsedTx, err := coding(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := assertEqual(parsedTx, tx); err != nil {
+			t.Fatal(err)
+		}
+		if have := parsedTx.FeeCurrency(); have == nil || *have != feeCurrency {
+			t.Fatalf("wrong fee currency: have %v, want %v", have, feeCurrency)
+		}
+		from, err := Sender(signer, parsedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from != sender {
+			t.Fatalf("wrong sender: have %x, want %x", from, sender)
+		}
+	}
+	// the fee currency is part of the signed hash
+	other := NewTx(&FeeCurrencyTx{
+		ChainID:     big.NewInt(1),
+		Nonce:       1,
+		To:          &recipient,
+		Gas:         123457,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   big.NewInt(10),
+		FeeCurrency: recipient,
+		Data:        []byte("abcdef"),
+	})
+	if signer.Hash(tx) == signer.Hash(other) {
+		t.Fatal("signing hash does not cover the fee currency")
+	}
+}
+
 func encodeDecodeJSON(tx *Transaction) (*Transaction, error) {
 	data, err := json.Marshal(tx)
 	if err != nil {
@@ -0,0 +1,183 @@
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+
+	"golang.org/x/crypto/sha3"
+
+	params2 "github.com/mapprotocol/atlas/params"
+)
+
+var (
+	errCip20UnsupportedHash = errors.New("unsupported cip20 hash function")
+	errBlake2sInvalidConfig = errors.New("invalid blake2s config")
+)
+
+// cip20Hash is a hash function of the CIP-20 precompile, selected by the first input byte
+type cip20Hash interface {
+	RequiredGas(input []byte) uint64
+	Run(input []byte) ([]byte, error)
+}
+
+// cip20Hashes are the hash functions of the CIP-20 precompile keyed by their selector
+var cip20Hashes = map[byte]cip20Hash{
+	0x00: &cip20Sha3{base: params2.Sha3_256BaseGas, perWord: params2.Sha3_256PerWordGas, new: sha3.New256},
+	0x01: &cip20Sha3{base: params2.Sha3_512BaseGas, perWord: params2.Sha3_512PerWordGas, new: sha3.New512},
+	0x02: &cip20Sha3{base: params2.Keccak512BaseGas, perWord: params2.Keccak512PerWordGas, new: sha3.NewLegacyKeccak512},
+	0x10: &cip20Blake2s{},
+}
+
+// cip20HashFunctions implements the CIP-20 precompile, the first input byte selects
+// the hash function applied to the rest of the input.
+type cip20HashFunctions struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *cip20HashFunctions) RequiredGas(input []byte) uint64 {
+	if len(input) == 0 {
+		return params2.InvalidCip20Gas
+	}
+	if h, ok := cip20Hashes[input[0]]; ok {
+		return h.RequiredGas(input[1:])
+	}
+	return params2.InvalidCip20Gas
+}
+
+func (c *cip20HashFunctions) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, ErrInputLength
+	}
+	if h, ok := cip20Hashes[input[0]]; ok {
+		return h.Run(input[1:])
+	}
+	return nil, errCip20UnsupportedHash
+}
+
+func wordGas(input []byte, base, perWord uint64) uint64 {
+	return base + uint64(len(input)+31)/32*perWord
+}
+
+// cip20Sha3 implements the sha3 and keccak variants of CIP-20
+type cip20Sha3 struct {
+	base, perWord uint64
+	new           func() hash.Hash
+}
+
+func (c *cip20Sha3) RequiredGas(input []byte) uint64 {
+	return wordGas(input, c.base, c.perWord)
+}
+
+func (c *cip20Sha3) Run(input []byte) ([]byte, error) {
+	h := c.new()
+	h.Write(input)
+	return h.Sum(nil), nil
+}
+
+// cip20Blake2s implements the configurable BLAKE2s variant of CIP-20. The input is the
+// 32 bytes BLAKE2s parameter block (digest size, key length, fanout, depth, leaf length,
+// node offset, node depth, inner length, salt and personalization) followed by the preimage.
+// Keyed hashing isn't supported.
+type cip20Blake2s struct{}
+
+func (c *cip20Blake2s) RequiredGas(input []byte) uint64 {
+	return wordGas(input, params2.Blake2sBaseGas, params2.Blake2sPerWordGas)
+}
+
+func (c *cip20Blake2s) Run(input []byte) ([]byte, error) {
+	if len(input) < 32 {
+		return nil, ErrInputLength
+	}
+	config := input[:32]
+	if size := config[0]; size == 0 || size > 32 || config[1] != 0 {
+		return nil, errBlake2sInvalidConfig
+	}
+	return blake2sHash(config, input[32:]), nil
+}
+
+var blake2sIV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var blake2sSigma = [10][16]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// blake2sHash hashes data with the 32 bytes parameter block of RFC 7693, the golang.org/x/crypto
+// implementation only supports the default parameters.
+func blake2sHash(config []byte, data []byte) []byte {
+	var h [8]uint32
+	for i := range h {
+		h[i] = blake2sIV[i] ^ binary.LittleEndian.Uint32(config[4*i:])
+	}
+	var (
+		block   [64]byte
+		counter uint64
+	)
+	for {
+		n := copy(block[:], data)
+		data = data[n:]
+		for i := n; i < len(block); i++ {
+			block[i] = 0
+		}
+		counter += uint64(n)
+		last := len(data) == 0
+		blake2sCompress(&h, &block, counter, last)
+		if last {
+			break
+		}
+	}
+	out := make([]byte, 32)
+	for i, v := range h {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+	return out[:config[0]]
+}
+
+func blake2sCompress(h *[8]uint32, block *[64]byte, counter uint64, last bool) {
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	var v [16]uint32
+	copy(v[:8], h[:])
+	copy(v[8:], blake2sIV[:])
+	v[12] ^= uint32(counter)
+	v[13] ^= uint32(counter >> 32)
+	if last {
+		v[14] = ^v[14]
+	}
+	g := func(a, b, c, d int, x, y uint32) {
+		v[a] = v[a] + v[b] + x
+		v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+		v[c] = v[c] + v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+		v[a] = v[a] + v[b] + y
+		v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+		v[c] = v[c] + v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+	}
+	for _, s := range blake2sSigma {
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
@@ -32,6 +32,10 @@ import (
 	params2 "github.com/mapprotocol/atlas/params"
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -201,6 +205,122 @@ var PrecompiledContractsBerlin = map[common.Address]PrecompiledContract{
 	eth2VerifyUpdateAddress: &eth2VerifyLightClient{},
 }
 
+// PrecompiledContractsBLS12377 contains the set of pre-compiled contracts used
+// since the BLS12-377 fork: the Berlin set plus the EIP-2539 BLS12-377 curve
+// operations, the CIP-20 hash functions, the CIP-26 validator BLS key lookup and
+// the eth2 light client store.
+var PrecompiledContractsBLS12377 = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{eip2565: true},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+	params.HeaderStoreAddress:        &store{},
+	params.TxVerifyAddress:           &verify{},
+	///////////////////////////////
+	// bls Precompiled Contracts
+	common.BytesToAddress([]byte{10}): &bls12381G1Add{},
+	common.BytesToAddress([]byte{11}): &bls12381G1Mul{},
+	common.BytesToAddress([]byte{12}): &bls12381G1MultiExp{},
+	common.BytesToAddress([]byte{13}): &bls12381G2Add{},
+	common.BytesToAddress([]byte{14}): &bls12381G2Mul{},
+	common.BytesToAddress([]byte{15}): &bls12381G2MultiExp{},
+	common.BytesToAddress([]byte{16}): &bls12381Pairing{},
+	common.BytesToAddress([]byte{17}): &bls12381MapG1{},
+	common.BytesToAddress([]byte{18}): &bls12381MapG2{},
+	///////////////////////////////
+	// Atlas Precompiled Contracts
+	transferAddress:              &transfer{},
+	fractionMulExpAddress:        &fractionMulExp{},
+	proofOfPossessionAddress:     &proofOfPossession{},
+	getValidatorAddress:          &getValidator{},
+	numberValidatorsAddress:      &numberValidators{},
+	epochSizeAddress:             &epochSize{},
+	blockNumberFromHeaderAddress: &blockNumberFromHeader{},
+	hashHeaderAddress:            &hashHeader{},
+	getParentSealBitmapAddress:   &getParentSealBitmap{},
+	getVerifiedSealBitmapAddress: &getVerifiedSealBitmap{},
+	// New in Donut hard fork
+	ed25519Address: &ed25519Verify{},
+	// New in BLS12-377 hard fork
+	b12_377G1AddAddress:      &bls12377G1Add{},
+	b12_377G1MulAddress:      &bls12377G1Mul{},
+	b12_377G1MultiExpAddress: &bls12377G1MultiExp{},
+	b12_377G2AddAddress:      &bls12377G2Add{},
+	b12_377G2MulAddress:      &bls12377G2Mul{},
+	b12_377G2MultiExpAddress: &bls12377G2MultiExp{},
+	b12_377PairingAddress:    &bls12377Pairing{},
+	cip20Address:             &cip20HashFunctions{},
+	cip26Address:             &getValidatorBLS{},
+
+	eth2VerifyUpdateAddress: &eth2VerifyLightClient{},
+	// The eth2 light client store is enabled with the BLS12-377 hard fork
+	params.Eth2LightClientAddress: &eth2LightClient{},
+}
+
+// PrecompiledContractsSlashing contains the set of pre-compiled contracts used
+// since the Slashing fork: the BLS12-377 set plus the double sign slashing and the
+// downtime slashing configuration.
+var PrecompiledContractsSlashing = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{eip2565: true},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+	params.HeaderStoreAddress:        &store{},
+	params.TxVerifyAddress:           &verify{},
+	///////////////////////////////
+	// bls Precompiled Contracts
+	common.BytesToAddress([]byte{10}): &bls12381G1Add{},
+	common.BytesToAddress([]byte{11}): &bls12381G1Mul{},
+	common.BytesToAddress([]byte{12}): &bls12381G1MultiExp{},
+	common.BytesToAddress([]byte{13}): &bls12381G2Add{},
+	common.BytesToAddress([]byte{14}): &bls12381G2Mul{},
+	common.BytesToAddress([]byte{15}): &bls12381G2MultiExp{},
+	common.BytesToAddress([]byte{16}): &bls12381Pairing{},
+	common.BytesToAddress([]byte{17}): &bls12381MapG1{},
+	common.BytesToAddress([]byte{18}): &bls12381MapG2{},
+	///////////////////////////////
+	// Atlas Precompiled Contracts
+	transferAddress:              &transfer{},
+	fractionMulExpAddress:        &fractionMulExp{},
+	proofOfPossessionAddress:     &proofOfPossession{},
+	getValidatorAddress:          &getValidator{},
+	numberValidatorsAddress:      &numberValidators{},
+	epochSizeAddress:             &epochSize{},
+	blockNumberFromHeaderAddress: &blockNumberFromHeader{},
+	hashHeaderAddress:            &hashHeader{},
+	getParentSealBitmapAddress:   &getParentSealBitmap{},
+	getVerifiedSealBitmapAddress: &getVerifiedSealBitmap{},
+	// New in Donut hard fork
+	ed25519Address: &ed25519Verify{},
+	// New in BLS12-377 hard fork
+	b12_377G1AddAddress:      &bls12377G1Add{},
+	b12_377G1MulAddress:      &bls12377G1Mul{},
+	b12_377G1MultiExpAddress: &bls12377G1MultiExp{},
+	b12_377G2AddAddress:      &bls12377G2Add{},
+	b12_377G2MulAddress:      &bls12377G2Mul{},
+	b12_377G2MultiExpAddress: &bls12377G2MultiExp{},
+	b12_377PairingAddress:    &bls12377Pairing{},
+	cip20Address:             &cip20HashFunctions{},
+	cip26Address:             &getValidatorBLS{},
+
+	eth2VerifyUpdateAddress: &eth2VerifyLightClient{},
+	// The eth2 light client store is enabled with the BLS12-377 hard fork
+	params.Eth2LightClientAddress: &eth2LightClient{},
+	// New in Slashing hard fork
+	params.DoubleSignSlashingAddress: &doubleSignSlashing{},
+	params.DowntimeSlashingAddress:   &downtimeSlashing{},
+}
+
 // PrecompiledContractsBLS contains the set of pre-compiled Ethereum
 // contracts specified in EIP-2537. These are exported for testing purposes.
 var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
@@ -234,6 +354,8 @@ var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
 }
 
 var (
+	PrecompiledAddressesSlashing  []common.Address
+	PrecompiledAddressesBLS12377  []common.Address
 	PrecompiledAddressesBerlin    []common.Address
 	PrecompiledAddressesIstanbul  []common.Address
 	PrecompiledAddressesByzantium []common.Address
@@ -253,11 +375,21 @@ func init() {
 	for k := range PrecompiledContractsBerlin {
 		PrecompiledAddressesBerlin = append(PrecompiledAddressesBerlin, k)
 	}
+	for k := range PrecompiledContractsBLS12377 {
+		PrecompiledAddressesBLS12377 = append(PrecompiledAddressesBLS12377, k)
+	}
+	for k := range PrecompiledContractsSlashing {
+		PrecompiledAddressesSlashing = append(PrecompiledAddressesSlashing, k)
+	}
 }
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
 	switch {
+	case rules.IsSlashing:
+		return PrecompiledAddressesSlashing
+	case rules.IsBLS12377:
+		return PrecompiledAddressesBLS12377
 	case rules.IsBerlin:
 		return PrecompiledAddressesBerlin
 	case rules.IsIstanbul:
@@ -1172,6 +1304,404 @@ func (c *bls12381MapG2) Run(evm *EVM, contract *Contract, input []byte) ([]byte,
 	return g.EncodePoint(r), nil
 }
 
+var (
+	errBLS12377InvalidInputLength          = errors.New("invalid input length")
+	errBLS12377InvalidFieldElementTopBytes = errors.New("invalid field element top bytes")
+	errBLS12377FieldElementNotCanonical    = errors.New("must be less than modulus")
+	errBLS12377PointNotOnCurve             = errors.New("point is not on curve")
+	errBLS12377G1PointSubgroup             = errors.New("g1 point is not on correct subgroup")
+	errBLS12377G2PointSubgroup             = errors.New("g2 point is not on correct subgroup")
+)
+
+// bls12377G1Add implements EIP-2539 G1Add precompile.
+type bls12377G1Add struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1Add) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G1AddGas
+}
+
+func (c *bls12377G1Add) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G1Add precompile.
+	// > G1 addition call expects `256` bytes as an input that is interpreted as byte concatenation of two G1 points (`128` bytes each).
+	// > Output is an encoding of addition operation result - single G1 point (`128` bytes).
+	if len(input) != 256 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	var err error
+	var p0, p1 *bls12377.G1Affine
+
+	// Decode G1 point p_0
+	if p0, err = decodeBLS12377PointG1(input[:128]); err != nil {
+		return nil, err
+	}
+	// Decode G1 point p_1
+	if p1, err = decodeBLS12377PointG1(input[128:]); err != nil {
+		return nil, err
+	}
+
+	// Compute r = p_0 + p_1
+	r := new(bls12377.G1Affine)
+	r.Add(p0, p1)
+
+	// Encode the G1 point result into 128 bytes
+	return encodeBLS12377PointG1(r), nil
+}
+
+// bls12377G1Mul implements EIP-2539 G1Mul precompile.
+type bls12377G1Mul struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1Mul) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G1MulGas
+}
+
+func (c *bls12377G1Mul) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G1Mul precompile.
+	// > G1 multiplication call expects `160` bytes as an input that is interpreted as byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
+	// > Output is an encoding of multiplication operation result - single G1 point (`128` bytes).
+	if len(input) != 160 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	var err error
+	var p0 *bls12377.G1Affine
+
+	// Decode G1 point
+	if p0, err = decodeBLS12377PointG1(input[:128]); err != nil {
+		return nil, err
+	}
+	// The scalar multiplication relies on the endomorphism of the subgroup
+	if !p0.IsInSubGroup() {
+		return nil, errBLS12377G1PointSubgroup
+	}
+	// Decode scalar value
+	e := new(big.Int).SetBytes(input[128:])
+
+	// Compute r = e * p_0
+	r := new(bls12377.G1Affine)
+	r.ScalarMultiplication(p0, e)
+
+	// Encode the G1 point into 128 bytes
+	return encodeBLS12377PointG1(r), nil
+}
+
+// bls12377MultiExpGas returns the gas of a multi exponentiation of k pairs with the
+// EIP-2539 discount.
+func bls12377MultiExpGas(k int, mulGas uint64) uint64 {
+	if k == 0 {
+		// Return 0 gas for small input length
+		return 0
+	}
+	// Lookup discount value for point, scalar value pair length
+	var discount uint64
+	if dLen := len(params2.Bls12377MultiExpDiscountTable); k < dLen {
+		discount = params2.Bls12377MultiExpDiscountTable[k-1]
+	} else {
+		discount = params2.Bls12377MultiExpDiscountTable[dLen-1]
+	}
+	// Calculate gas and return the result
+	return (uint64(k) * mulGas * discount) / 1000
+}
+
+// bls12377G1MultiExp implements EIP-2539 G1MultiExp precompile.
+type bls12377G1MultiExp struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G1MultiExp) RequiredGas(input []byte) uint64 {
+	return bls12377MultiExpGas(len(input)/160, params2.Bls12377G1MulGas)
+}
+
+func (c *bls12377G1MultiExp) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G1MultiExp precompile.
+	// G1 multiplication call expects `160*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G1 point (`128` bytes) and encoding of a scalar value (`32` bytes).
+	// Output is an encoding of multiexponentiation operation result - single G1 point (`128` bytes).
+	k := len(input) / 160
+	if len(input) == 0 || len(input)%160 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	points := make([]bls12377.G1Affine, k)
+	scalars := make([]fr.Element, k)
+
+	// Decode point scalar pairs
+	for i := 0; i < k; i++ {
+		off := 160 * i
+		t0, t1, t2 := off, off+128, off+160
+		// Decode G1 point
+		p, err := decodeBLS12377PointG1(input[t0:t1])
+		if err != nil {
+			return nil, err
+		}
+		// The multi exponentiation relies on the endomorphism of the subgroup
+		if !p.IsInSubGroup() {
+			return nil, errBLS12377G1PointSubgroup
+		}
+		points[i] = *p
+		// Decode scalar value
+		scalars[i].SetBigInt(new(big.Int).SetBytes(input[t1:t2]))
+	}
+
+	// Compute r = e_0 * p_0 + e_1 * p_1 + ... + e_(k-1) * p_(k-1)
+	r := new(bls12377.G1Affine)
+	if _, err := r.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+
+	// Encode the G1 point to 128 bytes
+	return encodeBLS12377PointG1(r), nil
+}
+
+// bls12377G2Add implements EIP-2539 G2Add precompile.
+type bls12377G2Add struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2Add) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G2AddGas
+}
+
+func (c *bls12377G2Add) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G2Add precompile.
+	// > G2 addition call expects `512` bytes as an input that is interpreted as byte concatenation of two G2 points (`256` bytes each).
+	// > Output is an encoding of addition operation result - single G2 point (`256` bytes).
+	if len(input) != 512 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	var err error
+	var p0, p1 *bls12377.G2Affine
+
+	// Decode G2 point p_0
+	if p0, err = decodeBLS12377PointG2(input[:256]); err != nil {
+		return nil, err
+	}
+	// Decode G2 point p_1
+	if p1, err = decodeBLS12377PointG2(input[256:]); err != nil {
+		return nil, err
+	}
+
+	// Compute r = p_0 + p_1
+	r := new(bls12377.G2Affine)
+	r.Add(p0, p1)
+
+	// Encode the G2 point into 256 bytes
+	return encodeBLS12377PointG2(r), nil
+}
+
+// bls12377G2Mul implements EIP-2539 G2Mul precompile.
+type bls12377G2Mul struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2Mul) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377G2MulGas
+}
+
+func (c *bls12377G2Mul) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G2MUL precompile logic.
+	// > G2 multiplication call expects `288` bytes as an input that is interpreted as byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
+	// > Output is an encoding of multiplication operation result - single G2 point (`256` bytes).
+	if len(input) != 288 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	var err error
+	var p0 *bls12377.G2Affine
+
+	// Decode G2 point
+	if p0, err = decodeBLS12377PointG2(input[:256]); err != nil {
+		return nil, err
+	}
+	// The scalar multiplication relies on the endomorphism of the subgroup
+	if !p0.IsInSubGroup() {
+		return nil, errBLS12377G2PointSubgroup
+	}
+	// Decode scalar value
+	e := new(big.Int).SetBytes(input[256:])
+
+	// Compute r = e * p_0
+	r := new(bls12377.G2Affine)
+	r.ScalarMultiplication(p0, e)
+
+	// Encode the G2 point into 256 bytes
+	return encodeBLS12377PointG2(r), nil
+}
+
+// bls12377G2MultiExp implements EIP-2539 G2MultiExp precompile.
+type bls12377G2MultiExp struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377G2MultiExp) RequiredGas(input []byte) uint64 {
+	return bls12377MultiExpGas(len(input)/288, params2.Bls12377G2MulGas)
+}
+
+func (c *bls12377G2MultiExp) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 G2MultiExp precompile logic
+	// > G2 multiplication call expects `288*k` bytes as an input that is interpreted as byte concatenation of `k` slices each of them being a byte concatenation of encoding of G2 point (`256` bytes) and encoding of a scalar value (`32` bytes).
+	// > Output is an encoding of multiexponentiation operation result - single G2 point (`256` bytes).
+	k := len(input) / 288
+	if len(input) == 0 || len(input)%288 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	points := make([]bls12377.G2Affine, k)
+	scalars := make([]fr.Element, k)
+
+	// Decode point scalar pairs
+	for i := 0; i < k; i++ {
+		off := 288 * i
+		t0, t1, t2 := off, off+256, off+288
+		// Decode G2 point
+		p, err := decodeBLS12377PointG2(input[t0:t1])
+		if err != nil {
+			return nil, err
+		}
+		// The multi exponentiation relies on the endomorphism of the subgroup
+		if !p.IsInSubGroup() {
+			return nil, errBLS12377G2PointSubgroup
+		}
+		points[i] = *p
+		// Decode scalar value
+		scalars[i].SetBigInt(new(big.Int).SetBytes(input[t1:t2]))
+	}
+
+	// Compute r = e_0 * p_0 + e_1 * p_1 + ... + e_(k-1) * p_(k-1)
+	r := new(bls12377.G2Affine)
+	if _, err := r.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+
+	// Encode the G2 point to 256 bytes.
+	return encodeBLS12377PointG2(r), nil
+}
+
+// bls12377Pairing implements EIP-2539 Pairing precompile.
+type bls12377Pairing struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bls12377Pairing) RequiredGas(input []byte) uint64 {
+	return params2.Bls12377PairingBaseGas + uint64(len(input)/384)*params2.Bls12377PairingPerPairGas
+}
+
+func (c *bls12377Pairing) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	// Implements EIP-2539 Pairing precompile logic.
+	// > Pairing call expects `384*k` bytes as an inputs that is interpreted as byte concatenation of `k` slices. Each slice has the following structure:
+	// > - `128` bytes of G1 point encoding
+	// > - `256` bytes of G2 point encoding
+	// > Output is a `32` bytes where last single byte is `0x01` if pairing result is equal to multiplicative identity in a pairing target field and `0x00` otherwise
+	// > (which is equivalent of Big Endian encoding of Solidity values `uint256(1)` and `uin256(0)` respectively).
+	k := len(input) / 384
+	if len(input) == 0 || len(input)%384 != 0 {
+		return nil, errBLS12377InvalidInputLength
+	}
+	g1s := make([]bls12377.G1Affine, k)
+	g2s := make([]bls12377.G2Affine, k)
+
+	// Decode pairs
+	for i := 0; i < k; i++ {
+		off := 384 * i
+		t0, t1, t2 := off, off+128, off+384
+
+		// Decode G1 point
+		p1, err := decodeBLS12377PointG1(input[t0:t1])
+		if err != nil {
+			return nil, err
+		}
+		// Decode G2 point
+		p2, err := decodeBLS12377PointG2(input[t1:t2])
+		if err != nil {
+			return nil, err
+		}
+
+		// 'point is on curve' check already done,
+		// Here we need to apply subgroup checks.
+		if !p1.IsInSubGroup() {
+			return nil, errBLS12377G1PointSubgroup
+		}
+		if !p2.IsInSubGroup() {
+			return nil, errBLS12377G2PointSubgroup
+		}
+		g1s[i], g2s[i] = *p1, *p2
+	}
+	// Prepare 32 byte output
+	out := make([]byte, 32)
+
+	// Compute pairing and set the result
+	ok, err := bls12377.PairingCheck(g1s, g2s)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		out[31] = 1
+	}
+	return out, nil
+}
+
+// decodeBLS12377FieldElement decodes a 64 bytes EIP-2539 field element, which is
+// a 48 bytes big endian integer below the modulus left padded with zeroes.
+func decodeBLS12377FieldElement(in []byte) (fp.Element, error) {
+	var e fp.Element
+	for i := 0; i < 16; i++ {
+		if in[i] != 0 {
+			return e, errBLS12377InvalidFieldElementTopBytes
+		}
+	}
+	if err := e.SetBytesCanonical(in[16:64]); err != nil {
+		return e, errBLS12377FieldElementNotCanonical
+	}
+	return e, nil
+}
+
+// decodeBLS12377PointG1 decodes a 128 bytes EIP-2539 G1 point, with all zeroes
+// encoding the point at infinity.
+func decodeBLS12377PointG1(in []byte) (*bls12377.G1Affine, error) {
+	x, err := decodeBLS12377FieldElement(in[:64])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBLS12377FieldElement(in[64:128])
+	if err != nil {
+		return nil, err
+	}
+	p := &bls12377.G1Affine{X: x, Y: y}
+	if !p.IsOnCurve() {
+		return nil, errBLS12377PointNotOnCurve
+	}
+	return p, nil
+}
+
+// decodeBLS12377PointG2 decodes a 256 bytes EIP-2539 G2 point, with all zeroes
+// encoding the point at infinity.
+func decodeBLS12377PointG2(in []byte) (*bls12377.G2Affine, error) {
+	var coords [4]fp.Element
+	for i := range coords {
+		c, err := decodeBLS12377FieldElement(in[64*i : 64*(i+1)])
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = c
+	}
+	p := new(bls12377.G2Affine)
+	p.X.A0, p.X.A1, p.Y.A0, p.Y.A1 = coords[0], coords[1], coords[2], coords[3]
+	if !p.IsOnCurve() {
+		return nil, errBLS12377PointNotOnCurve
+	}
+	return p, nil
+}
+
+// encodeBLS12377PointG1 returns the 128 bytes EIP-2539 encoding of a G1 point.
+func encodeBLS12377PointG1(p *bls12377.G1Affine) []byte {
+	out := make([]byte, 128)
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	copy(out[16:64], x[:])
+	copy(out[80:128], y[:])
+	return out
+}
+
+// encodeBLS12377PointG2 returns the 256 bytes EIP-2539 encoding of a G2 point.
+func encodeBLS12377PointG2(p *bls12377.G2Affine) []byte {
+	out := make([]byte, 256)
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		b := e.Bytes()
+		copy(out[64*i+16:64*(i+1)], b[:])
+	}
+	return out
+}
+
 const gasPerByte = 68
 
 type store struct{}
@@ -1740,5 +2270,27 @@ func (c *eth2VerifyLightClient) RequiredGas(input []byte) uint64 {
 }
 
 func (c *eth2VerifyLightClient) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
-	return nil, eth2.VerifyLightClientUpdate(input)
+	return nil, eth2.VerifyLightClientUpdate(evm.ChainConfig(), evm.Context.BlockNumber, input)
+}
+
+type eth2LightClient struct{}
+
+func (c *eth2LightClient) RequiredGas(input []byte) uint64 {
+	var (
+		baseGas uint64 = 21000
+	)
+
+	method, err := abiEth2LightClient.MethodById(input)
+	if err != nil {
+		return baseGas
+	}
+
+	if gas, ok := Eth2LightClientGas[method.Name]; ok {
+		return gas
+	}
+	return baseGas
+}
+
+func (c *eth2LightClient) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	return RunEth2LightClient(evm, contract, input)
 }
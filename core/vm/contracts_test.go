@@ -26,6 +26,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/sha3"
 
 	"github.com/mapprotocol/atlas/core/rawdb"
 	"github.com/mapprotocol/atlas/core/state"
@@ -96,6 +98,16 @@ var allPrecompiles = map[common.Address]PrecompiledContract{
 	b12_381MapFpToG1Address:  &bls12381MapG1{},
 	b12_381MapFp2ToG2Address: &bls12381MapG2{},
 	cip26Address:             &getValidatorBLS{},
+
+	// New in BLS12-377 hard fork
+	b12_377G1AddAddress:      &bls12377G1Add{},
+	b12_377G1MulAddress:      &bls12377G1Mul{},
+	b12_377G1MultiExpAddress: &bls12377G1MultiExp{},
+	b12_377G2AddAddress:      &bls12377G2Add{},
+	b12_377G2MulAddress:      &bls12377G2Mul{},
+	b12_377G2MultiExpAddress: &bls12377G2MultiExp{},
+	b12_377PairingAddress:    &bls12377Pairing{},
+	cip20Address:             &cip20HashFunctions{},
 }
 
 func testJSON(name, addr string, t *testing.T) {
@@ -508,3 +520,69 @@ func BenchmarkPrecompiledBLS12381G2MultiExpWorstCase(b *testing.B) {
 	}
 	benchmarkPrecompiled("0f", testcase, b)
 }
+
+func TestPrecompiledBLS12377G1Add(t *testing.T)      { testJson("bls12377G1Add", "e9", t) }
+func TestPrecompiledBLS12377G1Mul(t *testing.T)      { testJson("bls12377G1Mul", "e8", t) }
+func TestPrecompiledBLS12377G1MultiExp(t *testing.T) { testJson("bls12377G1MultiExp", "e7", t) }
+func TestPrecompiledBLS12377G2Add(t *testing.T)      { testJson("bls12377G2Add", "e6", t) }
+func TestPrecompiledBLS12377G2Mul(t *testing.T)      { testJson("bls12377G2Mul", "e5", t) }
+func TestPrecompiledBLS12377G2MultiExp(t *testing.T) { testJson("bls12377G2MultiExp", "e4", t) }
+func TestPrecompiledBLS12377Pairing(t *testing.T)    { testJson("bls12377Pairing", "e3", t) }
+
+func TestPrecompiledBLS12377G1AddFail(t *testing.T)      { testJsonFail("bls12377G1Add", "e9", t) }
+func TestPrecompiledBLS12377G1MulFail(t *testing.T)      { testJsonFail("bls12377G1Mul", "e8", t) }
+func TestPrecompiledBLS12377G1MultiExpFail(t *testing.T) { testJsonFail("bls12377G1MultiExp", "e7", t) }
+func TestPrecompiledBLS12377G2AddFail(t *testing.T)      { testJsonFail("bls12377G2Add", "e6", t) }
+func TestPrecompiledBLS12377G2MulFail(t *testing.T)      { testJsonFail("bls12377G2Mul", "e5", t) }
+func TestPrecompiledBLS12377G2MultiExpFail(t *testing.T) { testJsonFail("bls12377G2MultiExp", "e4", t) }
+func TestPrecompiledBLS12377PairingFail(t *testing.T)    { testJsonFail("bls12377Pairing", "e3", t) }
+
+// TestEth2LightClientFork tests that the eth2 light client store is only a
+// precompile since the BLS12-377 fork.
+func TestEth2LightClientFork(t *testing.T) {
+	for name, precompiles := range map[string]map[common.Address]PrecompiledContract{
+		"homestead": PrecompiledContractsHomestead,
+		"byzantium": PrecompiledContractsByzantium,
+		"istanbul":  PrecompiledContractsIstanbul,
+		"berlin":    PrecompiledContractsBerlin,
+		"bls12377":  PrecompiledContractsBLS12377,
+		"slashing":  PrecompiledContractsSlashing,
+	} {
+		_, active := precompiles[params.Eth2LightClientAddress]
+		if want := name == "bls12377" || name == "slashing"; active != want {
+			t.Errorf("%s: eth2 light client active = %v, want %v", name, active, want)
+		}
+	}
+}
+
+func TestPrecompiledCip20(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	sum := func(h interface{ Sum([]byte) []byte }) []byte { return h.Sum(nil) }
+	sha3256, sha3512, keccak512 := sha3.New256(), sha3.New512(), sha3.NewLegacyKeccak512()
+	sha3256.Write(data)
+	sha3512.Write(data)
+	keccak512.Write(data)
+	blake := blake2s.Sum256(data)
+	// digest size 32, no key, fanout 1 and depth 1 are the default parameters
+	config := make([]byte, 32)
+	config[0], config[2], config[3] = 32, 1, 1
+	short := append([]byte{16}, config[1:]...)
+	tests := []precompiledTest{
+		{Name: "sha3_256", Input: common.Bytes2Hex(append([]byte{0x00}, data...)), Expected: common.Bytes2Hex(sum(sha3256))},
+		{Name: "sha3_512", Input: common.Bytes2Hex(append([]byte{0x01}, data...)), Expected: common.Bytes2Hex(sum(sha3512))},
+		{Name: "keccak512", Input: common.Bytes2Hex(append([]byte{0x02}, data...)), Expected: common.Bytes2Hex(sum(keccak512))},
+		{Name: "blake2s", Input: common.Bytes2Hex(append(append([]byte{0x10}, config...), data...)), Expected: common.Bytes2Hex(blake[:])},
+		{Name: "blake2s_empty", Input: common.Bytes2Hex(append([]byte{0x10}, config...)), Expected: "69217a3079908094e11121d042354a7c1f55b6482ca1a51e1b250dfd1ed0eef9"},
+		{Name: "blake2s_short_config", Input: common.Bytes2Hex(append([]byte{0x10}, config[1:]...)), Expected: ErrInputLength.Error(), ErrorExpected: true},
+		{Name: "blake2s_keyed", Input: common.Bytes2Hex(append([]byte{0x10, 32, 32}, config[2:]...)), Expected: errBlake2sInvalidConfig.Error(), ErrorExpected: true},
+		{Name: "unsupported", Input: "03", Expected: errCip20UnsupportedHash.Error(), ErrorExpected: true},
+	}
+	for _, test := range tests {
+		testPrecompiled(cip20Address.Hex(), test, t)
+	}
+	// the digest size changes the output length and the initial state
+	res, err := allPrecompiles[cip20Address].Run(nil, nil, append(append([]byte{0x10}, short...), data...))
+	if err != nil || len(res) != 16 || bytes.Equal(res, blake[:16]) {
+		t.Fatalf("unexpected 16 bytes blake2s digest %x, err %v", res, err)
+	}
+}
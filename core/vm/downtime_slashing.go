@@ -0,0 +1,274 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/accounts/abi"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	SetDowntimeSlashing = "setDowntimeSlashing"
+	DowntimeSlashing    = "downtimeSlashing"
+	JailedUntil         = "jailedUntil"
+
+	EventOfDowntimeSlashed = "ValidatorDowntimeSlashed"
+
+	// maxSlashFraction is the basis points of the whole locked gold of a validator
+	maxSlashFraction = 10000
+)
+
+var errInvalidSlashFraction = errors.New("slash fraction exceeds 10000 basis points")
+
+// DowntimeSlashing contract ABI
+var (
+	abiDowntimeSlashing, _ = abi.JSON(strings.NewReader(params.DowntimeSlashingABIJSON))
+)
+
+var DowntimeSlashingGas = map[string]uint64{
+	SetDowntimeSlashing: 21000,
+	DowntimeSlashing:    0,
+	JailedUntil:         0,
+}
+
+// DowntimeSlashingConfig is the governance set configuration of the downtime slashing, the
+// slashing is disabled while MaxMissedBlocks is zero
+type DowntimeSlashingConfig struct {
+	// MaxMissedBlocks is the number of consecutive monitored blocks a validator may miss in an epoch
+	MaxMissedBlocks uint64
+	// SlashFraction is the share of the locked gold slashed in basis points
+	SlashFraction uint64
+	// JailEpochs is the number of epochs a slashed validator is not elected for
+	JailEpochs uint64
+}
+
+// JailedValidator is a validator account excluded from the validator sets up to epoch Until
+type JailedValidator struct {
+	Account common.Address `json:"account"`
+	Until   uint64         `json:"until"`
+}
+
+var (
+	downtimeSlashingConfigKey = crypto.Keccak256Hash([]byte("downtimeSlashingConfig"))
+	jailedValidatorsKey       = crypto.Keccak256Hash([]byte("jailedValidators"))
+)
+
+// GetDowntimeSlashingConfig returns the downtime slashing configuration
+func GetDowntimeSlashingConfig(db types.StateDB) (*DowntimeSlashingConfig, error) {
+	config := new(DowntimeSlashingConfig)
+	data := db.GetPOWState(params.DowntimeSlashingAddress, downtimeSlashingConfigKey)
+	if len(data) == 0 {
+		return config, nil
+	}
+	if err := rlp.DecodeBytes(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func setDowntimeSlashingConfig(db types.StateDB, config *DowntimeSlashingConfig) error {
+	data, err := rlp.EncodeToBytes(config)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(params.DowntimeSlashingAddress, downtimeSlashingConfigKey, data)
+	return nil
+}
+
+// GetJailedValidators returns the jailed validator accounts
+func GetJailedValidators(db types.StateDB) ([]*JailedValidator, error) {
+	var jailed []*JailedValidator
+	data := db.GetPOWState(params.DowntimeSlashingAddress, jailedValidatorsKey)
+	if len(data) == 0 {
+		return jailed, nil
+	}
+	if err := rlp.DecodeBytes(data, &jailed); err != nil {
+		return nil, err
+	}
+	return jailed, nil
+}
+
+func setJailedValidators(db types.StateDB, jailed []*JailedValidator) error {
+	if len(jailed) == 0 {
+		db.SetPOWState(params.DowntimeSlashingAddress, jailedValidatorsKey, nil)
+		return nil
+	}
+	data, err := rlp.EncodeToBytes(jailed)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(params.DowntimeSlashingAddress, jailedValidatorsKey, data)
+	return nil
+}
+
+// JailValidator excludes the validator account from the validator sets up to epoch until
+func JailValidator(db types.StateDB, account common.Address, until uint64) error {
+	jailed, err := GetJailedValidators(db)
+	if err != nil {
+		return err
+	}
+	for _, v := range jailed {
+		if v.Account == account {
+			if v.Until < until {
+				v.Until = until
+			}
+			return setJailedValidators(db, jailed)
+		}
+	}
+	return setJailedValidators(db, append(jailed, &JailedValidator{Account: account, Until: until}))
+}
+
+// ReleaseJailedValidators releases the validators jailed up to the given epoch
+func ReleaseJailedValidators(db types.StateDB, epoch uint64) error {
+	jailed, err := GetJailedValidators(db)
+	if err != nil {
+		return err
+	}
+	remaining := make([]*JailedValidator, 0, len(jailed))
+	for _, v := range jailed {
+		if v.Until > epoch {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) == len(jailed) {
+		return nil
+	}
+	return setJailedValidators(db, remaining)
+}
+
+// AddDowntimeSlashedLog emits the event of a validator slashed for downtime at the end of the epoch
+func AddDowntimeSlashedLog(db types.StateDB, number uint64, account, signer common.Address, epoch, missedBlocks uint64, penalty *big.Int, until uint64) error {
+	event := abiDowntimeSlashing.Events[EventOfDowntimeSlashed]
+	data, err := event.Inputs.NonIndexed().Pack(new(big.Int).SetUint64(epoch), new(big.Int).SetUint64(missedBlocks),
+		penalty, new(big.Int).SetUint64(until))
+	if err != nil {
+		return err
+	}
+	db.AddLog(&types.Log{
+		Address:     params.DowntimeSlashingAddress,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(account.Bytes()), common.BytesToHash(signer.Bytes())},
+		Data:        data,
+		BlockNumber: number,
+	})
+	return nil
+}
+
+type downtimeSlashing struct{}
+
+func (c *downtimeSlashing) RequiredGas(input []byte) uint64 {
+	var (
+		baseGas uint64 = 21000
+	)
+
+	method, err := abiDowntimeSlashing.MethodById(input)
+	if err != nil {
+		return baseGas
+	}
+	if gas, ok := DowntimeSlashingGas[method.Name]; ok {
+		return gas
+	}
+	return baseGas
+}
+
+func (c *downtimeSlashing) Run(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	method, err := abiDowntimeSlashing.MethodById(input)
+	if err != nil {
+		log.Error("get downtime slashing ABI method failed", "error", err)
+		return nil, err
+	}
+
+	data := input[4:]
+	switch method.Name {
+	case SetDowntimeSlashing:
+		ret, err = setDowntimeSlashing(evm, contract, data)
+	case DowntimeSlashing:
+		ret, err = downtimeSlashingConfig(evm)
+	case JailedUntil:
+		ret, err = jailedUntil(evm, data)
+	default:
+		log.Warn("run downtime slashing contract failed, invalid method name", "method.name", method.Name)
+		return ret, errors.New("invalid method name")
+	}
+
+	if err != nil {
+		log.Error("run downtime slashing contract failed", "method.name", method.Name, "error", err)
+	}
+	return ret, err
+}
+
+func setDowntimeSlashing(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	if !bytes.Equal(contract.CallerAddress.Bytes(), adminHash[12:]) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		MaxMissedBlocks *big.Int
+		SlashFraction   *big.Int
+		JailEpochs      *big.Int
+	}{}
+	method := abiDowntimeSlashing.Methods[SetDowntimeSlashing]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.MaxMissedBlocks.IsUint64() || !args.JailEpochs.IsUint64() {
+		return nil, errors.New("value out of range")
+	}
+	if !args.SlashFraction.IsUint64() || args.SlashFraction.Uint64() > maxSlashFraction {
+		return nil, errInvalidSlashFraction
+	}
+	return nil, setDowntimeSlashingConfig(evm.StateDB, &DowntimeSlashingConfig{
+		MaxMissedBlocks: args.MaxMissedBlocks.Uint64(),
+		SlashFraction:   args.SlashFraction.Uint64(),
+		JailEpochs:      args.JailEpochs.Uint64(),
+	})
+}
+
+func downtimeSlashingConfig(evm *EVM) (ret []byte, err error) {
+	config, err := GetDowntimeSlashingConfig(evm.StateDB)
+	if err != nil {
+		return nil, err
+	}
+	method := abiDowntimeSlashing.Methods[DowntimeSlashing]
+	return method.Outputs.Pack(new(big.Int).SetUint64(config.MaxMissedBlocks),
+		new(big.Int).SetUint64(config.SlashFraction), new(big.Int).SetUint64(config.JailEpochs))
+}
+
+func jailedUntil(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		Account common.Address
+	}{}
+	method := abiDowntimeSlashing.Methods[JailedUntil]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	jailed, err := GetJailedValidators(evm.StateDB)
+	if err != nil {
+		return nil, err
+	}
+	until := new(big.Int)
+	for _, v := range jailed {
+		if v.Account == args.Account {
+			until.SetUint64(v.Until)
+			break
+		}
+	}
+	return method.Outputs.Pack(until)
+}
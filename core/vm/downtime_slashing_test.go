@@ -0,0 +1,108 @@
+package vm
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func runDowntimeSlashing(evm *EVM, caller common.Address, method string, args ...interface{}) ([]byte, error) {
+	input, err := abiDowntimeSlashing.Pack(method, args...)
+	if err != nil {
+		panic(err)
+	}
+	contract := NewContract(AccountRef(caller), AccountRef(params.DowntimeSlashingAddress), new(big.Int), 0)
+	return (&downtimeSlashing{}).Run(evm, contract, input)
+}
+
+func TestDowntimeSlashingConfig(t *testing.T) {
+	var (
+		evm   = newRelayerTestEVM(15)
+		owner = common.HexToAddress("0x01")
+		other = common.HexToAddress("0x02")
+	)
+	evm.StateDB.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, owner.Hash())
+
+	if config, err := GetDowntimeSlashingConfig(evm.StateDB); err != nil || config.MaxMissedBlocks != 0 {
+		t.Fatalf("downtime slashing enabled by default: %v, err = %v", config, err)
+	}
+	if _, err := runDowntimeSlashing(evm, other, SetDowntimeSlashing, big.NewInt(100), big.NewInt(500), big.NewInt(2)); err == nil {
+		t.Fatal("downtime slashing set by a non owner")
+	}
+	if _, err := runDowntimeSlashing(evm, owner, SetDowntimeSlashing, big.NewInt(100), big.NewInt(10001), big.NewInt(2)); err != errInvalidSlashFraction {
+		t.Fatalf("slash fraction above the locked gold accepted, err = %v", err)
+	}
+	if _, err := runDowntimeSlashing(evm, owner, SetDowntimeSlashing, big.NewInt(100), big.NewInt(500), big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := runDowntimeSlashing(evm, other, DowntimeSlashing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := abiDowntimeSlashing.Methods[DowntimeSlashing].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{big.NewInt(100), big.NewInt(500), big.NewInt(2)}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("downtime slashing config %v, want %v", out, want)
+	}
+}
+
+func TestJailedValidators(t *testing.T) {
+	var (
+		evm = newRelayerTestEVM(15)
+		v1  = common.HexToAddress("0x11")
+		v2  = common.HexToAddress("0x12")
+	)
+	jailedUntil := func(account common.Address) uint64 {
+		ret, err := runDowntimeSlashing(evm, v1, JailedUntil, account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := abiDowntimeSlashing.Methods[JailedUntil].Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out[0].(*big.Int).Uint64()
+	}
+
+	if err := JailValidator(evm.StateDB, v1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := JailValidator(evm.StateDB, v2, 5); err != nil {
+		t.Fatal(err)
+	}
+	// jailing again only extends the jail
+	if err := JailValidator(evm.StateDB, v2, 4); err != nil {
+		t.Fatal(err)
+	}
+	if until := jailedUntil(v2); until != 5 {
+		t.Fatalf("v2 jailed until %d, want 5", until)
+	}
+
+	if err := ReleaseJailedValidators(evm.StateDB, 3); err != nil {
+		t.Fatal(err)
+	}
+	jailed, err := GetJailedValidators(evm.StateDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*JailedValidator{{Account: v2, Until: 5}}; !reflect.DeepEqual(jailed, want) {
+		t.Fatalf("jailed validators %v, want %v", jailed, want)
+	}
+	if until := jailedUntil(v1); until != 0 {
+		t.Fatalf("released v1 jailed until %d", until)
+	}
+
+	if err := ReleaseJailedValidators(evm.StateDB, 5); err != nil {
+		t.Fatal(err)
+	}
+	if jailed, err := GetJailedValidators(evm.StateDB); err != nil || len(jailed) != 0 {
+		t.Fatalf("jailed validators after release: %v, err = %v", jailed, err)
+	}
+}
@@ -0,0 +1,229 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/atlas/chains/eth2"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	Eth2Initialize             = "initialize"
+	Eth2Update                 = "updateLightClient"
+	Eth2HeaderHeight           = "headerHeight"
+	Eth2FinalizedExecutionHash = "finalizedExecutionHash"
+	Eth2VerifyReceiptProof     = "verifyReceiptProof"
+	EventOfEth2Update          = "UpdateLightClient"
+)
+
+// Eth2LightClient contract ABI
+var (
+	abiEth2LightClient, _ = abi.JSON(strings.NewReader(params.Eth2LightClientABIJSON))
+)
+
+// Eth2LightClientGas defines all method gas
+var Eth2LightClientGas = map[string]uint64{
+	Eth2Initialize:             42000,
+	Eth2Update:                 params.VerifyEth2UpdateGas,
+	Eth2HeaderHeight:           0,
+	Eth2FinalizedExecutionHash: 0,
+	Eth2VerifyReceiptProof:     42000,
+}
+
+// RunEth2LightClient execute atlas eth2 light client contract
+func RunEth2LightClient(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	method, err := abiEth2LightClient.MethodById(input)
+	if err != nil {
+		log.Error("get eth2 light client ABI method failed", "error", err)
+		return nil, err
+	}
+
+	data := input[4:]
+	switch method.Name {
+	case Eth2Initialize:
+		ret, err = eth2Initialize(evm, contract, data)
+	case Eth2Update:
+		ret, err = eth2UpdateLightClient(evm, contract, data)
+	case Eth2HeaderHeight:
+		ret, err = eth2HeaderHeight(evm, data)
+	case Eth2FinalizedExecutionHash:
+		ret, err = eth2FinalizedExecutionHash(evm, data)
+	case Eth2VerifyReceiptProof:
+		ret, err = eth2VerifyReceiptProof(evm, data)
+	default:
+		log.Warn("run eth2 light client contract failed, invalid method name", "method.name", method.Name)
+		return ret, errors.New("invalid method name")
+	}
+
+	if err != nil {
+		log.Error("run eth2 light client contract failed", "method.name", method.Name, "error", err)
+	} else {
+		log.Info("run eth2 light client contract succeed", "method.name", method.Name)
+	}
+
+	return ret, err
+}
+
+func eth2Initialize(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	if !bytes.Equal(contract.CallerAddress.Bytes(), adminHash[12:]) {
+		return nil, errors.New("forbidden")
+	}
+
+	var bootstrap []byte
+	method := abiEth2LightClient.Methods[Eth2Initialize]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&bootstrap, unpack); err != nil {
+		return nil, err
+	}
+
+	if _, err := eth2.InitLightClientStore(evm.StateDB, evm.ChainConfig(), evm.Context.BlockNumber, bootstrap); err != nil {
+		log.Error("failed to initialize eth2 light client", "error", err)
+		return nil, err
+	}
+	return nil, nil
+}
+
+func eth2UpdateLightClient(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainId *big.Int
+		Update  []byte
+	}{}
+	method := abiEth2LightClient.Methods[Eth2Update]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.ChainId.IsUint64() {
+		return nil, errors.New("invalid chain id")
+	}
+
+	store, err := eth2.ApplyLightClientUpdate(evm.StateDB, args.ChainId.Uint64(), args.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	// make event
+	event := abiEth2LightClient.Events[EventOfEth2Update]
+	logData, err := event.Inputs.Pack()
+	topics := []common.Hash{
+		event.ID,
+		contract.CallerAddress.Hash(),
+		common.BigToHash(new(big.Int).SetUint64(store.FinalizedHeader.Slot)),
+	}
+	addLog(evm, contract, topics, logData)
+	return nil, nil
+}
+
+func eth2HeaderHeight(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainId *big.Int
+	}{}
+	method := abiEth2LightClient.Methods[Eth2HeaderHeight]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.ChainId.IsUint64() {
+		return nil, errors.New("invalid chain id")
+	}
+
+	store, err := eth2.LoadLightClientStore(evm.StateDB, args.ChainId.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(
+		new(big.Int).SetUint64(store.FinalizedExecution.BlockNumber),
+		new(big.Int).SetUint64(store.FinalizedHeader.Slot),
+	)
+}
+
+func eth2FinalizedExecutionHash(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainId *big.Int
+		Slot    *big.Int
+	}{}
+	method := abiEth2LightClient.Methods[Eth2FinalizedExecutionHash]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.ChainId.IsUint64() {
+		return nil, errors.New("invalid chain id")
+	}
+	if !args.Slot.IsUint64() {
+		return nil, errors.New("invalid slot")
+	}
+
+	store, err := eth2.LoadLightClientStore(evm.StateDB, args.ChainId.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	execution, err := store.GetExecution(evm.StateDB, args.Slot.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(execution.BlockHash)
+}
+
+func eth2VerifyReceiptProof(evm *EVM, input []byte) (ret []byte, err error) {
+	var (
+		success = true
+		message = ""
+		logs    []byte
+		args    = struct {
+			ChainId      *big.Int
+			ReceiptProof []byte
+		}{}
+	)
+
+	method := abiEth2LightClient.Methods[Eth2VerifyReceiptProof]
+	defer func() {
+		var packErr error
+
+		if err != nil {
+			success, message, logs = false, err.Error(), []byte{}
+		}
+		// see verifyProofData, a failed verification is reported in the outputs
+		ret, packErr = method.Outputs.Pack(success, message, logs)
+		if packErr != nil {
+			log.Error("verify receipt proof outputs pack failed", "error", packErr.Error())
+		}
+	}()
+
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err = method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if !args.ChainId.IsUint64() {
+		return nil, errors.New("invalid chain id")
+	}
+
+	store, err := eth2.LoadLightClientStore(evm.StateDB, args.ChainId.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	logs, err = store.VerifyReceiptProof(evm.StateDB, args.ReceiptProof)
+	return nil, err
+}
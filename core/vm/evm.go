@@ -61,6 +61,10 @@ type (
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	var precompiles map[common.Address]PrecompiledContract
 	switch {
+	case evm.chainRules.IsSlashing:
+		precompiles = PrecompiledContractsSlashing
+	case evm.chainRules.IsBLS12377:
+		precompiles = PrecompiledContractsBLS12377
 	case evm.chainRules.IsBerlin:
 		precompiles = PrecompiledContractsBerlin
 	case evm.chainRules.IsIstanbul:
@@ -3,6 +3,7 @@ package vm
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -23,8 +24,13 @@ const (
 	SetRelayer    = "setRelayer"
 	GetRelayer    = "getRelayer"
 	EventOfUpdate = "UpdateBlockHeader"
+
+	SetHeaderRetention   = "setHeaderRetention"
+	EarliestStoredNumber = "earliestStoredNumber"
 )
 
+var errRetentionNotSupported = errors.New("header retention is not supported by the chain")
+
 // HeaderStore contract ABI
 var (
 	abiHeaderStore, _ = abi.JSON(strings.NewReader(params.HeaderStoreABIJSON))
@@ -32,9 +38,32 @@ var (
 
 // SyncGas defines all method gas
 var SyncGas = map[string]uint64{
-	CurNbrAndHash: 42000,
-	SetRelayer:    2100,
-	GetRelayer:    0,
+	CurNbrAndHash:        42000,
+	SetRelayer:           2100,
+	GetRelayer:           0,
+	RegisterRelayer:      21000,
+	DeregisterRelayer:    21000,
+	StakeRelayer:         21000,
+	GetRelayers:          0,
+	IsRelayer:            0,
+	ClaimRelayerReward:   21000,
+	SetHeaderRetention:   21000,
+	EarliestStoredNumber: 0,
+}
+
+// headerStoreForks holds the fork activating each method added after genesis, the method
+// is invalid before it
+var headerStoreForks = map[string]func(*params.ChainConfig, *big.Int) bool{
+	RegisterRelayer:   (*params.ChainConfig).IsRelayerRegistry,
+	DeregisterRelayer: (*params.ChainConfig).IsRelayerRegistry,
+	StakeRelayer:      (*params.ChainConfig).IsRelayerRegistry,
+	GetRelayers:       (*params.ChainConfig).IsRelayerRegistry,
+	IsRelayer:         (*params.ChainConfig).IsRelayerRegistry,
+
+	ClaimRelayerReward: (*params.ChainConfig).IsRelayerReward,
+
+	SetHeaderRetention:   (*params.ChainConfig).IsHeaderRetention,
+	EarliestStoredNumber: (*params.ChainConfig).IsHeaderRetention,
 }
 
 // RunHeaderStore execute atlas header store contract
@@ -44,6 +73,10 @@ func RunHeaderStore(evm *EVM, contract *Contract, input []byte) (ret []byte, err
 		log.Error("get header store ABI method failed", "error", err)
 		return nil, err
 	}
+	if isFork, ok := headerStoreForks[method.Name]; ok && !isFork(evm.chainConfig, evm.Context.BlockNumber) {
+		log.Warn("run header store contract failed, invalid method name", "method.name", method.Name)
+		return nil, errors.New("invalid method name")
+	}
 
 	data := input[4:]
 	switch method.Name {
@@ -59,6 +92,22 @@ func RunHeaderStore(evm *EVM, contract *Contract, input []byte) (ret []byte, err
 		ret, err = setRelayer(evm, contract, data)
 	case GetRelayer:
 		ret, err = getRelayer(evm)
+	case RegisterRelayer:
+		ret, err = registerRelayer(evm, contract, data)
+	case DeregisterRelayer:
+		ret, err = deregisterRelayer(evm, contract, data)
+	case StakeRelayer:
+		ret, err = stakeRelayer(evm, contract, data)
+	case GetRelayers:
+		ret, err = getRelayers(evm, data)
+	case IsRelayer:
+		ret, err = isRelayer(evm, data)
+	case ClaimRelayerReward:
+		ret, err = claimRelayerReward(evm, contract)
+	case SetHeaderRetention:
+		ret, err = setHeaderRetention(evm, contract, data)
+	case EarliestStoredNumber:
+		ret, err = earliestStoredNumber(evm, data)
 	default:
 		log.Warn("run header store contract failed, invalid method name", "method.name", method.Name)
 		return ret, errors.New("invalid method name")
@@ -82,10 +131,6 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 		Headers []byte
 	}{}
 
-	if err := validateRelayer(evm, contract.CallerAddress); err != nil {
-		return nil, err
-	}
-
 	method := abiHeaderStore.Methods[Save]
 	unpack, err := method.Inputs.Unpack(input)
 	if err != nil {
@@ -108,16 +153,19 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 	// check if it is a supported chain
 	fromChain := chains.ChainType(args.From.Uint64())
 	toChain := chains.ChainType(args.To.Uint64())
-	if !(chains.IsSupportedChain(fromChain) && chains.IsSupportedChain(toChain)) {
+	if !(chains.IsSupportedChain(fromChain, evm.chainConfig, evm.Context.BlockNumber) && chains.IsSupportedChain(toChain, evm.chainConfig, evm.Context.BlockNumber)) {
 		return nil, ErrNotSupportChain
 	}
+	if err := validateRelayer(evm, fromChain, contract.CallerAddress); err != nil {
+		return nil, err
+	}
 
-	group, err := chains.ChainType2ChainGroup(fromChain)
+	group, err := chains.ChainType2ChainGroup(fromChain, evm.chainConfig, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	chain, err := interfaces.ChainFactory(group)
+	chain, err := interfaces.ChainFactory(group, fromChain)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +179,22 @@ func updateBlockHeader(evm *EVM, contract *Contract, input []byte) (ret []byte,
 		log.Error("failed to write headers", "error", err)
 		return nil, err
 	}
+	if err := recordSubmit(evm, fromChain); err != nil {
+		return nil, err
+	}
+	if evm.chainConfig.IsRelayerReward(evm.Context.BlockNumber) {
+		var canonical, side uint64
+		for _, n := range nums {
+			if hash, err := chain.GetHashByNumber(evm.StateDB, n.Number); err == nil && hash == n.Hash {
+				canonical++
+			} else {
+				side++
+			}
+		}
+		if err := recordRelayerWork(evm, contract.CallerAddress, canonical, side); err != nil {
+			return nil, err
+		}
+	}
 
 	// make event
 	event := abiHeaderStore.Events[EventOfUpdate]
@@ -179,11 +243,11 @@ func reset(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
 		return nil, errors.New("current chainID does not match the from parameter")
 	}
 
-	group, err := chains.ChainType2ChainGroup(from)
+	group, err := chains.ChainType2ChainGroup(from, evm.chainConfig, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
-	hs, err := interfaces.HeaderStoreFactory(group)
+	hs, err := interfaces.HeaderStoreFactory(group, from)
 	if err != nil {
 		return nil, err
 	}
@@ -207,11 +271,12 @@ func currentNumberAndHash(evm *EVM, contract *Contract, input []byte) (ret []byt
 		return nil, err
 	}
 
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(args.ChainID.Uint64()))
+	chain := chains.ChainType(args.ChainID.Uint64())
+	group, err := chains.ChainType2ChainGroup(chain, evm.chainConfig, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
-	hs, err := interfaces.HeaderStoreFactory(group)
+	hs, err := interfaces.HeaderStoreFactory(group, chain)
 	if err != nil {
 		return nil, err
 	}
@@ -249,12 +314,73 @@ func getRelayer(evm *EVM) (ret []byte, err error) {
 	return method.Outputs.Pack(common.BytesToAddress(relayerBytes))
 }
 
-func validateRelayer(evm *EVM, caller common.Address) error {
-	adminAddrBytes := evm.StateDB.GetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]))
-	if !bytes.Equal(caller.Bytes(), adminAddrBytes) {
-		return errors.New("invalid relayer")
+// retentionHeaderStore returns the header store of the chain if it supports header retention
+func retentionHeaderStore(evm *EVM, chain chains.ChainType) (interfaces.IRetention, error) {
+	group, err := chains.ChainType2ChainGroup(chain, evm.chainConfig, evm.Context.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	hs, err := interfaces.HeaderStoreFactory(group, chain)
+	if err != nil {
+		return nil, err
+	}
+	rs, ok := hs.(interfaces.IRetention)
+	if !ok {
+		return nil, errRetentionNotSupported
+	}
+	return rs, nil
+}
+
+func setHeaderRetention(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	if !bytes.Equal(contract.CallerAddress.Bytes(), adminHash[12:]) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		ChainType *big.Int
+		Retention *big.Int
+	}{}
+	method := abiHeaderStore.Methods[SetHeaderRetention]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	chain := chains.ChainType(args.ChainType.Uint64())
+	rs, err := retentionHeaderStore(evm, chain)
+	if err != nil {
+		return nil, err
+	}
+	if !args.Retention.IsUint64() || args.Retention.Uint64() < rs.MinRetention() || args.Retention.Uint64() > rs.MaxRetention() {
+		return nil, fmt.Errorf("retention must be between %d and %d", rs.MinRetention(), rs.MaxRetention())
+	}
+	return nil, rs.SetRetention(evm.StateDB, args.Retention.Uint64())
+}
+
+func earliestStoredNumber(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+	}{}
+	method := abiHeaderStore.Methods[EarliestStoredNumber]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	rs, err := retentionHeaderStore(evm, chains.ChainType(args.ChainType.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	number, err := rs.EarliestStoredNumber(evm.StateDB)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return method.Outputs.Pack(new(big.Int).SetUint64(number))
 }
 
 func addLog(evm *EVM, contract *Contract, topics []common.Hash, data []byte) {
@@ -2,8 +2,13 @@ package vm
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/params"
 )
 
 func headerStorePack(method string, args ...interface{}) []byte {
@@ -77,3 +82,65 @@ func TestCurrentNumberAndHash(t *testing.T) {
 		})
 	}
 }
+
+func TestSetHeaderRetention(t *testing.T) {
+	var (
+		evm   = newRelayerTestEVM(1)
+		owner = common.HexToAddress("0x01")
+		eth   = big.NewInt(int64(chains.ChainTypeETHTest))
+	)
+	evm.StateDB.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, owner.Hash())
+
+	config := *evm.chainConfig
+	config.HeaderRetentionBlock = big.NewInt(2)
+	evm.chainConfig = &config
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, eth, big.NewInt(2048)); err == nil || err.Error() != "invalid method name" {
+		t.Fatalf("retention set before the fork, err = %v", err)
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), EarliestStoredNumber, eth); err == nil || err.Error() != "invalid method name" {
+		t.Fatalf("earliest stored number read before the fork, err = %v", err)
+	}
+	config.HeaderRetentionBlock = big.NewInt(1)
+
+	if _, err := runHeaderStore(evm, common.HexToAddress("0x02"), new(big.Int), SetHeaderRetention, eth, big.NewInt(2048)); err == nil {
+		t.Fatal("retention set by a non owner")
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, eth, big.NewInt(chains.MinHeaderRetention-1)); err == nil {
+		t.Fatal("retention below the minimum accepted")
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, eth, big.NewInt(2048)); err != nil {
+		t.Fatalf("failed to set retention: %v", err)
+	}
+	// each chain has its own retention, even the ethereum chains sharing a header store
+	if got := chains.GetHeaderRetention(evm.StateDB, chains.ChainTypeETHTest); got != 2048 {
+		t.Fatalf("retention %d, want 2048", got)
+	}
+	if got := chains.GetHeaderRetention(evm.StateDB, chains.ChainTypeETH); got != 0 {
+		t.Fatalf("retention of another chain %d, want 0", got)
+	}
+
+	bsc := big.NewInt(int64(chains.ChainTypeBSC))
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, bsc, big.NewInt(2048)); err != chains.ErrNotSupportChain {
+		t.Fatalf("retention set for a chain before its fork, err = %v", err)
+	}
+	config.BSCBlock = big.NewInt(0)
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, bsc, big.NewInt(2048)); err != nil {
+		t.Fatalf("failed to set retention: %v", err)
+	}
+	if got := chains.GetHeaderRetention(evm.StateDB, chains.ChainTypeBSC); got != 2048 {
+		t.Fatalf("retention %d, want 2048", got)
+	}
+
+	// bitcoin headers need the ancestors of a whole retarget period
+	btc := big.NewInt(int64(chains.ChainTypeBTC))
+	config.BTCBlock = big.NewInt(0)
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, btc, big.NewInt(2048)); err == nil {
+		t.Fatal("retention below the retarget period accepted")
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), SetHeaderRetention, btc, big.NewInt(4096)); err != nil {
+		t.Fatalf("failed to set retention: %v", err)
+	}
+	if got := chains.GetHeaderRetention(evm.StateDB, chains.ChainTypeBTC); got != 4096 {
+		t.Fatalf("retention %d, want 4096", got)
+	}
+}
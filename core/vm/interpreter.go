@@ -32,6 +32,8 @@ type Config struct {
 	NoRecursion             bool   // Disables call, callcode, delegate call and create
 	NoBaseFee               bool   // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	EnablePreimageRecording bool   // Enables recording of SHA3/keccak preimages
+	ParallelExecution       bool   // Executes the block transactions speculatively in parallel
+	ParallelWorkers         int    // Number of parallel executors, defaults to the number of CPUs
 
 	JumpTable [256]*operation // EVM instruction table, automatically populated if unset
 
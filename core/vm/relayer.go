@@ -0,0 +1,278 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const (
+	RegisterRelayer   = "registerRelayer"
+	DeregisterRelayer = "deregisterRelayer"
+	StakeRelayer      = "stakeRelayer"
+	GetRelayers       = "getRelayers"
+	IsRelayer         = "isRelayer"
+)
+
+var (
+	errRelayerRegistered    = errors.New("relayer already registered")
+	errRelayerNotRegistered = errors.New("relayer not registered")
+	errRelayerSetFull       = errors.New("relayer set is full")
+	errRelayerStakeTooLow   = errors.New("relayer stake is lower than the minimum")
+	errInvalidRelayer       = errors.New("invalid relayer")
+)
+
+type relayerInfo struct {
+	Address common.Address
+	// Stake is the amount locked in the header store by stakeRelayer, relayers
+	// registered by the registry owner have no stake
+	Stake *big.Int
+}
+
+// relayerSet is the set of relayers allowed to submit headers of a source chain
+type relayerSet struct {
+	Relayers []relayerInfo
+	// LastSubmit is the atlas block number of the last accepted header submission
+	LastSubmit uint64
+}
+
+func (s *relayerSet) indexOf(addr common.Address) int {
+	for i, r := range s.Relayers {
+		if r.Address == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// active returns the relayer expected to submit the headers in the epoch of the given block
+func (s *relayerSet) active(number, epochSize uint64) common.Address {
+	epoch := number
+	if epochSize != 0 {
+		epoch = number / epochSize
+	}
+	return s.Relayers[epoch%uint64(len(s.Relayers))].Address
+}
+
+func relayerSetKey(chain chains.ChainType) common.Hash {
+	return crypto.Keccak256Hash([]byte("relayers"), new(big.Int).SetUint64(uint64(chain)).Bytes())
+}
+
+func loadRelayerSet(evm *EVM, chain chains.ChainType) (*relayerSet, error) {
+	set := new(relayerSet)
+	data := evm.StateDB.GetPOWState(params.NewRelayerAddress, relayerSetKey(chain))
+	if len(data) == 0 {
+		return set, nil
+	}
+	if err := rlp.DecodeBytes(data, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func storeRelayerSet(evm *EVM, chain chains.ChainType, set *relayerSet) error {
+	data, err := rlp.EncodeToBytes(set)
+	if err != nil {
+		return err
+	}
+	evm.StateDB.SetPOWState(params.NewRelayerAddress, relayerSetKey(chain), data)
+	return nil
+}
+
+func isRegistryOwner(evm *EVM, caller common.Address) bool {
+	adminHash := evm.StateDB.GetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation)
+	return bytes.Equal(caller.Bytes(), adminHash[12:])
+}
+
+func addRelayer(evm *EVM, chain chains.ChainType, relayer common.Address, stake *big.Int) error {
+	if !chains.IsSupportedChain(chain, evm.chainConfig, evm.Context.BlockNumber) {
+		return ErrNotSupportChain
+	}
+	set, err := loadRelayerSet(evm, chain)
+	if err != nil {
+		return err
+	}
+	if set.indexOf(relayer) >= 0 {
+		return errRelayerRegistered
+	}
+	if len(set.Relayers) >= params.MaxRelayersPerChain {
+		return errRelayerSetFull
+	}
+	set.Relayers = append(set.Relayers, relayerInfo{Address: relayer, Stake: stake})
+	return storeRelayerSet(evm, chain, set)
+}
+
+func registerRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	if !isRegistryOwner(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[RegisterRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	return nil, addRelayer(evm, chains.ChainType(args.ChainType.Uint64()), args.Relayer, new(big.Int))
+}
+
+// stakeRelayer registers the caller as a relayer of the chain, the call value is locked
+// in the header store until the relayer is deregistered
+func stakeRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+	}{}
+	method := abiHeaderStore.Methods[StakeRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if contract.Value().Cmp(params.MinRelayerStake) < 0 {
+		return nil, errRelayerStakeTooLow
+	}
+	stake := new(big.Int).Set(contract.Value())
+	return nil, addRelayer(evm, chains.ChainType(args.ChainType.Uint64()), contract.CallerAddress, stake)
+}
+
+// deregisterRelayer removes a relayer by the registry owner or by the relayer itself,
+// the stake is returned to the relayer
+func deregisterRelayer(evm *EVM, contract *Contract, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[DeregisterRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	if contract.CallerAddress != args.Relayer && !isRegistryOwner(evm, contract.CallerAddress) {
+		return nil, errors.New("forbidden")
+	}
+
+	chain := chains.ChainType(args.ChainType.Uint64())
+	set, err := loadRelayerSet(evm, chain)
+	if err != nil {
+		return nil, err
+	}
+	i := set.indexOf(args.Relayer)
+	if i < 0 {
+		return nil, errRelayerNotRegistered
+	}
+	stake := set.Relayers[i].Stake
+	set.Relayers = append(set.Relayers[:i], set.Relayers[i+1:]...)
+	if err := storeRelayerSet(evm, chain, set); err != nil {
+		return nil, err
+	}
+	if stake != nil && stake.Sign() > 0 {
+		evm.Context.Transfer(evm, params.HeaderStoreAddress, args.Relayer, stake)
+	}
+	return nil, nil
+}
+
+func getRelayers(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+	}{}
+	method := abiHeaderStore.Methods[GetRelayers]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	set, err := loadRelayerSet(evm, chains.ChainType(args.ChainType.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	relayers := make([]common.Address, 0, len(set.Relayers))
+	for _, r := range set.Relayers {
+		relayers = append(relayers, r.Address)
+	}
+	return method.Outputs.Pack(relayers)
+}
+
+func isRelayer(evm *EVM, input []byte) (ret []byte, err error) {
+	args := struct {
+		ChainType *big.Int
+		Relayer   common.Address
+	}{}
+	method := abiHeaderStore.Methods[IsRelayer]
+	unpack, err := method.Inputs.Unpack(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := method.Inputs.Copy(&args, unpack); err != nil {
+		return nil, err
+	}
+	set, err := loadRelayerSet(evm, chains.ChainType(args.ChainType.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(set.indexOf(args.Relayer) >= 0)
+}
+
+// validateRelayer checks that the caller may submit headers of the chain. The relayers
+// of a chain take turns per epoch, if the active relayer hasn't submitted for
+// RelayerGraceBlocks blocks any registered relayer may submit. Chains without
+// registered relayers, and all the chains before the relayer registry fork, only accept
+// the relayer set by setRelayer.
+func validateRelayer(evm *EVM, chain chains.ChainType, caller common.Address) error {
+	set := new(relayerSet)
+	if evm.chainConfig.IsRelayerRegistry(evm.Context.BlockNumber) {
+		var err error
+		if set, err = loadRelayerSet(evm, chain); err != nil {
+			return err
+		}
+	}
+	if len(set.Relayers) == 0 {
+		adminAddrBytes := evm.StateDB.GetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]))
+		if !bytes.Equal(caller.Bytes(), adminAddrBytes) {
+			return errInvalidRelayer
+		}
+		return nil
+	}
+	if set.indexOf(caller) < 0 {
+		return errInvalidRelayer
+	}
+	number := evm.Context.BlockNumber.Uint64()
+	if caller == set.active(number, evm.Context.EpochSize) || number-set.LastSubmit > params.RelayerGraceBlocks {
+		return nil
+	}
+	return errInvalidRelayer
+}
+
+// recordSubmit marks the block of the last accepted submission of the chain
+func recordSubmit(evm *EVM, chain chains.ChainType) error {
+	if !evm.chainConfig.IsRelayerRegistry(evm.Context.BlockNumber) {
+		return nil
+	}
+	set, err := loadRelayerSet(evm, chain)
+	if err != nil {
+		return err
+	}
+	if len(set.Relayers) == 0 {
+		return nil
+	}
+	set.LastSubmit = evm.Context.BlockNumber.Uint64()
+	return storeRelayerSet(evm, chain, set)
+}
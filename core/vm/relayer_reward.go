@@ -0,0 +1,175 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+const ClaimRelayerReward = "claimRelayerReward"
+
+var errNoRelayerReward = errors.New("no pending relayer reward")
+
+// RelayerWork counts the headers a relayer got accepted by the header store in an epoch
+type RelayerWork struct {
+	Relayer common.Address
+	// Canonical is the number of headers on the canonical chain of the header store
+	// right after their insertion, the other accepted headers are counted as Side
+	Canonical uint64
+	Side      uint64
+}
+
+// Weight returns the useful work of the relayer the epoch rewards are shared by
+func (w *RelayerWork) Weight() uint64 {
+	return w.Canonical*params.RelayerCanonicalHeaderWeight + w.Side*params.RelayerSideHeaderWeight
+}
+
+// RelayerReward is the reward account of a relayer
+type RelayerReward struct {
+	Pending *big.Int
+	Claimed *big.Int
+}
+
+func relayerWorkKey(epoch uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("relayerWork"), new(big.Int).SetUint64(epoch).Bytes())
+}
+
+func relayerRewardKey(relayer common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("relayerReward"), relayer.Bytes())
+}
+
+// GetRelayerWork returns the work of all relayers in the epoch
+func GetRelayerWork(db types.StateDB, epoch uint64) ([]*RelayerWork, error) {
+	var work []*RelayerWork
+	data := db.GetPOWState(params.NewRelayerAddress, relayerWorkKey(epoch))
+	if len(data) == 0 {
+		return work, nil
+	}
+	if err := rlp.DecodeBytes(data, &work); err != nil {
+		return nil, err
+	}
+	return work, nil
+}
+
+func setRelayerWork(db types.StateDB, epoch uint64, work []*RelayerWork) error {
+	data, err := rlp.EncodeToBytes(work)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(params.NewRelayerAddress, relayerWorkKey(epoch), data)
+	return nil
+}
+
+// GetRelayerReward returns the pending and claimed rewards of the relayer
+func GetRelayerReward(db types.StateDB, relayer common.Address) (*RelayerReward, error) {
+	reward := &RelayerReward{Pending: new(big.Int), Claimed: new(big.Int)}
+	data := db.GetPOWState(params.NewRelayerAddress, relayerRewardKey(relayer))
+	if len(data) == 0 {
+		return reward, nil
+	}
+	if err := rlp.DecodeBytes(data, reward); err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+func setRelayerReward(db types.StateDB, relayer common.Address, reward *RelayerReward) error {
+	data, err := rlp.EncodeToBytes(reward)
+	if err != nil {
+		return err
+	}
+	db.SetPOWState(params.NewRelayerAddress, relayerRewardKey(relayer), data)
+	return nil
+}
+
+// recordRelayerWork adds the headers inserted by the relayer to the work of the current epoch
+func recordRelayerWork(evm *EVM, relayer common.Address, canonical, side uint64) error {
+	if evm.Context.EpochSize == 0 {
+		return nil
+	}
+	epoch := istanbul.GetEpochNumber(evm.Context.BlockNumber.Uint64(), evm.Context.EpochSize)
+	work, err := GetRelayerWork(evm.StateDB, epoch)
+	if err != nil {
+		return err
+	}
+	var w *RelayerWork
+	for _, v := range work {
+		if v.Relayer == relayer {
+			w = v
+			break
+		}
+	}
+	if w == nil {
+		w = &RelayerWork{Relayer: relayer}
+		work = append(work, w)
+	}
+	w.Canonical += canonical
+	w.Side += side
+	return setRelayerWork(evm.StateDB, epoch, work)
+}
+
+// DistributeRelayerRewards credits the relayers with a share of total proportional to their
+// work in the epoch and clears the epoch work. The credited rewards must be minted to the
+// header store by the caller, the returned amount is the sum of the credited rewards.
+func DistributeRelayerRewards(db types.StateDB, epoch uint64, total *big.Int) (*big.Int, error) {
+	work, err := GetRelayerWork(db, epoch)
+	if err != nil {
+		return nil, err
+	}
+	totalWeight := new(big.Int)
+	for _, w := range work {
+		totalWeight.Add(totalWeight, new(big.Int).SetUint64(w.Weight()))
+	}
+	distributed := new(big.Int)
+	if totalWeight.Sign() == 0 {
+		return distributed, nil
+	}
+	for _, w := range work {
+		amount := new(big.Int).SetUint64(w.Weight())
+		amount.Mul(amount, total).Div(amount, totalWeight)
+		if amount.Sign() == 0 {
+			continue
+		}
+		reward, err := GetRelayerReward(db, w.Relayer)
+		if err != nil {
+			return nil, err
+		}
+		reward.Pending.Add(reward.Pending, amount)
+		if err := setRelayerReward(db, w.Relayer, reward); err != nil {
+			return nil, err
+		}
+		distributed.Add(distributed, amount)
+		log.Debug("credit relayer reward", "relayer", w.Relayer, "epoch", epoch, "amount", amount)
+	}
+	db.SetPOWState(params.NewRelayerAddress, relayerWorkKey(epoch), nil)
+	return distributed, nil
+}
+
+// claimRelayerReward transfers the pending reward of the caller from the header store
+func claimRelayerReward(evm *EVM, contract *Contract) (ret []byte, err error) {
+	reward, err := GetRelayerReward(evm.StateDB, contract.CallerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if reward.Pending.Sign() == 0 {
+		return nil, errNoRelayerReward
+	}
+	amount := reward.Pending
+	if !evm.Context.CanTransfer(evm.StateDB, params.HeaderStoreAddress, amount) {
+		return nil, ErrInsufficientBalance
+	}
+	reward.Claimed.Add(reward.Claimed, amount)
+	reward.Pending = new(big.Int)
+	if err := setRelayerReward(evm.StateDB, contract.CallerAddress, reward); err != nil {
+		return nil, err
+	}
+	evm.Context.Transfer(evm, params.HeaderStoreAddress, contract.CallerAddress, amount)
+	return abiHeaderStore.Methods[ClaimRelayerReward].Outputs.Pack(amount)
+}
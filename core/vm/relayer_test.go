@@ -0,0 +1,230 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/atlas/chains"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func newRelayerTestEVM(number uint64) *EVM {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	ctx := BlockContext{
+		BlockNumber: new(big.Int).SetUint64(number),
+		EpochSize:   10,
+		Transfer: func(evm *EVM, from, to common.Address, amount *big.Int) {
+			evm.StateDB.SubBalance(from, amount)
+			evm.StateDB.AddBalance(to, amount)
+		},
+	}
+	config := *params.TestChainConfig
+	config.RelayerRegistryBlock = big.NewInt(0)
+	config.RelayerRewardBlock = big.NewInt(0)
+	return NewEVM(ctx, TxContext{}, statedb, &config, Config{})
+}
+
+func runHeaderStore(evm *EVM, caller common.Address, value *big.Int, method string, args ...interface{}) ([]byte, error) {
+	input, err := abiHeaderStore.Pack(method, args...)
+	if err != nil {
+		panic(err)
+	}
+	contract := NewContract(AccountRef(caller), AccountRef(params.HeaderStoreAddress), value, 0)
+	return RunHeaderStore(evm, contract, input)
+}
+
+func TestRelayerRegistry(t *testing.T) {
+	var (
+		evm    = newRelayerTestEVM(15)
+		owner  = common.HexToAddress("0x01")
+		r1     = common.HexToAddress("0x11")
+		r2     = common.HexToAddress("0x12")
+		staker = common.HexToAddress("0x13")
+		chain  = big.NewInt(int64(chains.ChainTypeETH))
+	)
+	evm.StateDB.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, owner.Hash())
+
+	if _, err := runHeaderStore(evm, r1, new(big.Int), RegisterRelayer, chain, r1); err == nil {
+		t.Fatal("relayer registered by a non owner")
+	}
+	for _, r := range []common.Address{r1, r2} {
+		if _, err := runHeaderStore(evm, owner, new(big.Int), RegisterRelayer, chain, r); err != nil {
+			t.Fatalf("failed to register relayer: %v", err)
+		}
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), RegisterRelayer, chain, r1); err != errRelayerRegistered {
+		t.Fatalf("duplicate relayer registered, err = %v", err)
+	}
+
+	low := new(big.Int).Sub(params.MinRelayerStake, big.NewInt(1))
+	if _, err := runHeaderStore(evm, staker, low, StakeRelayer, chain); err != errRelayerStakeTooLow {
+		t.Fatalf("relayer registered with a low stake, err = %v", err)
+	}
+	// the call value is transferred to the header store before running the precompile
+	evm.StateDB.AddBalance(params.HeaderStoreAddress, params.MinRelayerStake)
+	if _, err := runHeaderStore(evm, staker, params.MinRelayerStake, StakeRelayer, chain); err != nil {
+		t.Fatalf("failed to stake relayer: %v", err)
+	}
+
+	ret, err := runHeaderStore(evm, staker, new(big.Int), GetRelayers, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := abiHeaderStore.Methods[GetRelayers].Outputs.Unpack(ret)
+	if relayers := out[0].([]common.Address); len(relayers) != 3 || relayers[0] != r1 || relayers[2] != staker {
+		t.Fatalf("relayers mismatch: %v", relayers)
+	}
+
+	// the second epoch belongs to the second relayer
+	if err := validateRelayer(evm, chains.ChainTypeETH, r2); err != nil {
+		t.Fatalf("active relayer rejected: %v", err)
+	}
+	if err := recordSubmit(evm, chains.ChainTypeETH); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRelayer(evm, chains.ChainTypeETH, r1); err != errInvalidRelayer {
+		t.Fatalf("inactive relayer accepted, err = %v", err)
+	}
+	evm.Context.BlockNumber = new(big.Int).SetUint64(15 + params.RelayerGraceBlocks + 1)
+	if err := validateRelayer(evm, chains.ChainTypeETH, r1); err != nil {
+		t.Fatalf("registered relayer rejected after the grace window: %v", err)
+	}
+	if err := validateRelayer(evm, chains.ChainTypeETH, owner); err != errInvalidRelayer {
+		t.Fatalf("unregistered relayer accepted, err = %v", err)
+	}
+
+	if _, err := runHeaderStore(evm, r1, new(big.Int), DeregisterRelayer, chain, staker); err == nil {
+		t.Fatal("relayer deregistered by another relayer")
+	}
+	if _, err := runHeaderStore(evm, staker, new(big.Int), DeregisterRelayer, chain, staker); err != nil {
+		t.Fatalf("failed to deregister relayer: %v", err)
+	}
+	if evm.StateDB.GetBalance(staker).Cmp(params.MinRelayerStake) != 0 {
+		t.Fatal("stake not returned")
+	}
+	ret, err = runHeaderStore(evm, owner, new(big.Int), IsRelayer, chain, staker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, _ = abiHeaderStore.Methods[IsRelayer].Outputs.Unpack(ret); out[0].(bool) {
+		t.Fatal("deregistered relayer is still registered")
+	}
+}
+
+func TestLegacyRelayer(t *testing.T) {
+	evm := newRelayerTestEVM(1)
+	relayer := common.HexToAddress("0x11")
+	evm.StateDB.SetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]), relayer.Bytes())
+
+	if err := validateRelayer(evm, chains.ChainTypeETH, relayer); err != nil {
+		t.Fatalf("legacy relayer rejected: %v", err)
+	}
+	if err := validateRelayer(evm, chains.ChainTypeETH, common.HexToAddress("0x12")); err != errInvalidRelayer {
+		t.Fatalf("unknown relayer accepted, err = %v", err)
+	}
+}
+
+func TestRelayerRegistryFork(t *testing.T) {
+	var (
+		evm     = newRelayerTestEVM(9)
+		owner   = common.HexToAddress("0x01")
+		relayer = common.HexToAddress("0x11")
+		chain   = big.NewInt(int64(chains.ChainTypeETH))
+	)
+	evm.chainConfig.RelayerRegistryBlock = big.NewInt(10)
+	evm.StateDB.SetState(params.RegistryProxyAddress, params.ProxyOwnerStorageLocation, owner.Hash())
+	evm.StateDB.SetPOWState(params.NewRelayerAddress, common.BytesToHash(params.NewRelayerAddress[:]), owner.Bytes())
+
+	for _, method := range []string{RegisterRelayer, DeregisterRelayer, IsRelayer} {
+		if _, err := runHeaderStore(evm, owner, new(big.Int), method, chain, relayer); err == nil || err.Error() != "invalid method name" {
+			t.Fatalf("%s before the fork, err = %v", method, err)
+		}
+	}
+	for _, method := range []string{StakeRelayer, GetRelayers} {
+		if _, err := runHeaderStore(evm, owner, new(big.Int), method, chain); err == nil || err.Error() != "invalid method name" {
+			t.Fatalf("%s before the fork, err = %v", method, err)
+		}
+	}
+	// a relayer set is neither used nor updated before the fork
+	if err := storeRelayerSet(evm, chains.ChainTypeETH, &relayerSet{Relayers: []relayerInfo{{Address: relayer, Stake: new(big.Int)}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRelayer(evm, chains.ChainTypeETH, relayer); err != errInvalidRelayer {
+		t.Fatalf("registered relayer accepted before the fork, err = %v", err)
+	}
+	if err := validateRelayer(evm, chains.ChainTypeETH, owner); err != nil {
+		t.Fatalf("legacy relayer rejected before the fork: %v", err)
+	}
+	if err := recordSubmit(evm, chains.ChainTypeETH); err != nil {
+		t.Fatal(err)
+	}
+	if set, _ := loadRelayerSet(evm, chains.ChainTypeETH); set.LastSubmit != 0 {
+		t.Fatalf("submission recorded before the fork at %d", set.LastSubmit)
+	}
+
+	evm.Context.BlockNumber = big.NewInt(10)
+	if err := validateRelayer(evm, chains.ChainTypeETH, relayer); err != nil {
+		t.Fatalf("registered relayer rejected after the fork: %v", err)
+	}
+	if _, err := runHeaderStore(evm, owner, new(big.Int), GetRelayers, chain); err != nil {
+		t.Fatalf("failed to get relayers after the fork: %v", err)
+	}
+}
+
+func TestRelayerRewards(t *testing.T) {
+	var (
+		evm = newRelayerTestEVM(15)
+		r1  = common.HexToAddress("0x11")
+		r2  = common.HexToAddress("0x12")
+	)
+	evm.Context.CanTransfer = func(db types.StateDB, addr common.Address, amount *big.Int) bool {
+		return db.GetBalance(addr).Cmp(amount) >= 0
+	}
+	if err := recordRelayerWork(evm, r1, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordRelayerWork(evm, r2, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordRelayerWork(evm, r1, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	// r1 weighs 2 * 4 + 2 and r2 weighs 2
+	distributed, err := DistributeRelayerRewards(evm.StateDB, 2, big.NewInt(1200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distributed.Cmp(big.NewInt(1200)) != 0 {
+		t.Fatalf("distributed %v, want 1200", distributed)
+	}
+	for relayer, want := range map[common.Address]int64{r1: 1000, r2: 200} {
+		reward, _ := GetRelayerReward(evm.StateDB, relayer)
+		if reward.Pending.Cmp(big.NewInt(want)) != 0 {
+			t.Fatalf("pending reward of %v is %v, want %v", relayer, reward.Pending, want)
+		}
+	}
+	if work, _ := GetRelayerWork(evm.StateDB, 2); len(work) != 0 {
+		t.Fatal("epoch work not cleared")
+	}
+
+	evm.StateDB.AddBalance(params.HeaderStoreAddress, distributed)
+	evm.chainConfig.RelayerRewardBlock = big.NewInt(16)
+	if _, err := runHeaderStore(evm, r1, new(big.Int), ClaimRelayerReward); err == nil || err.Error() != "invalid method name" {
+		t.Fatalf("reward claimed before the fork, err = %v", err)
+	}
+	evm.chainConfig.RelayerRewardBlock = big.NewInt(15)
+	if _, err := runHeaderStore(evm, r1, new(big.Int), ClaimRelayerReward); err != nil {
+		t.Fatalf("failed to claim reward: %v", err)
+	}
+	reward, _ := GetRelayerReward(evm.StateDB, r1)
+	if reward.Pending.Sign() != 0 || reward.Claimed.Cmp(big.NewInt(1000)) != 0 || evm.StateDB.GetBalance(r1).Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("claim mismatch: pending %v, claimed %v", reward.Pending, reward.Claimed)
+	}
+	if _, err := runHeaderStore(evm, r1, new(big.Int), ClaimRelayerReward); err != errNoRelayerReward {
+		t.Fatalf("claimed twice, err = %v", err)
+	}
+}
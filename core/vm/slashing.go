@@ -0,0 +1,140 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/accounts/abi"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/contracts/abis"
+	"github.com/mapprotocol/atlas/core/types"
+	"github.com/mapprotocol/atlas/params"
+)
+
+// The double sign slashing precompile takes the RLP encoded double sign evidence of the
+// istanbul core, verifies it against the validator set of the block it was signed for
+// and slashes the validator account once per round. The precompile calls the Validators
+// and LockedGold contracts itself, so governance must register its address as a slasher
+// of both contracts.
+
+var (
+	errDoubleSignSlashed  = errors.New("double sign already slashed")
+	errNotEvidenceSigner  = errors.New("evidence signer is not a validator of the block")
+	errInvalidEvidenceSeq = errors.New("invalid evidence sequence")
+
+	// DoubleSignSlashedEvent is the topic of the log emitted for a slashed double sign,
+	// the signer and its account are the next topics and the data holds the sequence and
+	// the round
+	DoubleSignSlashedEvent = crypto.Keccak256Hash([]byte("DoubleSignSlashed(address,address,uint256,uint256)"))
+)
+
+func doubleSignKey(signer common.Address, view *istanbul.View) common.Hash {
+	seq, round := common.BigToHash(view.Sequence), common.BigToHash(view.Round)
+	return crypto.Keccak256Hash([]byte("doubleSign"), signer.Bytes(), seq.Bytes(), round.Bytes())
+}
+
+// IsDoubleSignSlashed returns whether the double sign of the evidence was slashed already
+func IsDoubleSignSlashed(db types.StateDB, evidence *istanbul.DoubleSignEvidence) bool {
+	return len(db.GetPOWState(params.DoubleSignSlashingAddress, doubleSignKey(evidence.Signer(), evidence.View()))) != 0
+}
+
+type doubleSignSlashing struct{}
+
+func (c *doubleSignSlashing) RequiredGas(input []byte) uint64 {
+	return params.DoubleSignSlashingGas
+}
+
+func (c *doubleSignSlashing) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	var evidence istanbul.DoubleSignEvidence
+	if err := rlp.DecodeBytes(input, &evidence); err != nil {
+		return nil, err
+	}
+	if err := evidence.Verify(); err != nil {
+		return nil, err
+	}
+	view := evidence.View()
+	if view.Sequence.Sign() <= 0 || view.Sequence.Cmp(evm.Context.BlockNumber) > 0 {
+		return nil, errInvalidEvidenceSeq
+	}
+	if IsDoubleSignSlashed(evm.StateDB, &evidence) {
+		return nil, errDoubleSignSlashed
+	}
+
+	// The validator set is resolved from the ancestor of the executing block, a side
+	// chain block of the same number may have elected another set
+	signer := evidence.Signer()
+	parent := new(big.Int).Sub(view.Sequence, common.Big1)
+	var validator istanbul.Validator
+	for _, v := range evm.Context.GetValidators(parent, evm.Context.GetHash(parent.Uint64())) {
+		if v.Address() == signer {
+			validator = v
+			break
+		}
+	}
+	if validator == nil {
+		return nil, errNotEvidenceSigner
+	}
+	if err := evidence.VerifyCommittedSeals(validator.BLSPublicKey(), evm.chainConfig.BN256ForkBlock, view.Sequence); err != nil {
+		return nil, err
+	}
+	evm.StateDB.SetPOWState(params.DoubleSignSlashingAddress, doubleSignKey(signer, view), []byte{1})
+
+	account, err := signerToAccount(evm, contract, signer)
+	if err != nil {
+		return nil, err
+	}
+	if err := callRegistered(evm, contract, params.ValidatorsRegistryId, abis.Validators, "halveSlashingMultiplier", account); err != nil {
+		return nil, err
+	}
+	if err := callRegistered(evm, contract, params.LockedGoldRegistryId, abis.LockedGold, "slash",
+		account, params.DoubleSignPenalty, evm.Origin, params.DoubleSignReward,
+		[]common.Address{}, []common.Address{}, []*big.Int{}); err != nil {
+		return nil, err
+	}
+
+	topics := []common.Hash{DoubleSignSlashedEvent, common.BytesToHash(signer.Bytes()), common.BytesToHash(account.Bytes())}
+	data := append(common.LeftPadBytes(view.Sequence.Bytes(), 32), common.LeftPadBytes(view.Round.Bytes(), 32)...)
+	addLog(evm, contract, topics, data)
+	return nil, nil
+}
+
+// signerToAccount returns the validator account authorizing the signer
+func signerToAccount(evm *EVM, contract *Contract, signer common.Address) (common.Address, error) {
+	address, err := evm.Context.GetRegisteredAddress(evm, params.AccountsId)
+	if err != nil {
+		return common.Address{}, err
+	}
+	input, err := abis.Accounts.Pack("signerToAccount", signer)
+	if err != nil {
+		return common.Address{}, err
+	}
+	ret, _, err := evm.StaticCall(contract, address, input, params.MaxGasForGetRegisteredValidators)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("signerToAccount: %w", err)
+	}
+	var account common.Address
+	if err := abis.Accounts.UnpackIntoInterface(&account, "signerToAccount", ret); err != nil {
+		return common.Address{}, err
+	}
+	return account, nil
+}
+
+// callRegistered calls the method of a registered contract on behalf of the precompile
+func callRegistered(evm *EVM, contract *Contract, registryId common.Hash, contractAbi *abi.ABI, method string, args ...interface{}) error {
+	address, err := evm.Context.GetRegisteredAddress(evm, registryId)
+	if err != nil {
+		return err
+	}
+	input, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	if _, _, err := evm.Call(contract, address, input, params.MaxGasForSlashing, common.Big0); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/core/rawdb"
+	"github.com/mapprotocol/atlas/core/state"
+	"github.com/mapprotocol/atlas/params"
+)
+
+func TestDoubleSignSlashingPrecompile(t *testing.T) {
+	if _, ok := PrecompiledContractsBLS12377[params.DoubleSignSlashingAddress]; ok {
+		t.Fatal("double sign slashing active before the Slashing fork")
+	}
+	p, ok := PrecompiledContractsSlashing[params.DoubleSignSlashingAddress]
+	if !ok {
+		t.Fatal("double sign slashing not active since the Slashing fork")
+	}
+	if gas := p.RequiredGas(nil); gas != params.DoubleSignSlashingGas {
+		t.Fatalf("required gas %d, want %d", gas, params.DoubleSignSlashingGas)
+	}
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	prepare := func(digest common.Hash) *istanbul.Message {
+		view := &istanbul.View{Sequence: big.NewInt(10), Round: common.Big0}
+		msg := istanbul.NewPrepareMessage(&istanbul.Subject{View: view, Digest: digest}, addr)
+		if err := msg.Sign(func(data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), key)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	input, err := rlp.EncodeToBytes(&istanbul.DoubleSignEvidence{First: prepare(common.HexToHash("01")), Second: prepare(common.HexToHash("01"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The evidence is rejected before touching the state
+	if _, err := p.Run(nil, nil, input); err != istanbul.ErrEvidenceSameDigest {
+		t.Fatalf("evidence without a double sign: err = %v", err)
+	}
+	if _, err := p.Run(nil, nil, []byte{1, 2, 3}); err == nil {
+		t.Fatal("malformed evidence accepted")
+	}
+
+	// The validator set is looked up on the ancestor of the executing block
+	input, err = rlp.EncodeToBytes(&istanbul.DoubleSignEvidence{First: prepare(common.HexToHash("01")), Second: prepare(common.HexToHash("02"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	var (
+		number *big.Int
+		hash   common.Hash
+	)
+	context := BlockContext{
+		BlockNumber: big.NewInt(12),
+		GetHash: func(n uint64) common.Hash {
+			return common.BigToHash(new(big.Int).SetUint64(n + 1000))
+		},
+		GetValidators: func(blockNumber *big.Int, headerHash common.Hash) []istanbul.Validator {
+			number, hash = blockNumber, headerHash
+			return nil
+		},
+	}
+	evm := NewEVM(context, TxContext{}, statedb, params.TestChainConfig, Config{})
+	if _, err := p.Run(evm, nil, input); err != errNotEvidenceSigner {
+		t.Fatalf("evidence of a non validator: err = %v", err)
+	}
+	if number.Uint64() != 9 || hash != context.GetHash(9) {
+		t.Errorf("validator set lookup mismatch: have %v %x, want 9 %x", number, hash, context.GetHash(9))
+	}
+}
+
+func TestDoubleSignKey(t *testing.T) {
+	signer := common.HexToAddress("0x01")
+	first := doubleSignKey(signer, &istanbul.View{Sequence: big.NewInt(0x0102), Round: common.Big0})
+	second := doubleSignKey(signer, &istanbul.View{Sequence: common.Big1, Round: common.Big2})
+	if first == second {
+		t.Fatal("double sign keys of different views collide")
+	}
+}
@@ -121,15 +121,16 @@ func verifyProofData(evm *EVM, contract *Contract, input []byte) (ret []byte, er
 	//if bytes.Equal(args.Coin.Bytes(), common.Address{}.Bytes()) {
 	//	return nil, errors.New("coin address is empty")
 	//}
-	if !chains.IsSupportedChain(chains.ChainType(args.SrcChain.Uint64())) {
+	srcChain := chains.ChainType(args.SrcChain.Uint64())
+	if !chains.IsSupportedChain(srcChain, evm.chainConfig, evm.Context.BlockNumber) {
 		return nil, ErrNotSupportChain
 	}
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(args.SrcChain.Uint64()))
+	group, err := chains.ChainType2VerifyGroup(srcChain, evm.chainConfig, evm.Context.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	v, err := interfaces.VerifyFactory(group)
+	v, err := interfaces.VerifyFactory(group, srcChain)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +203,7 @@ func TestReceiptsRootAndProof(t *testing.T) {
 		router = common.HexToAddress("0xd6199276959b95a68c1ee30e8569f5fe060903a6")
 	)
 
-	group, err := chains.ChainType2ChainGroup(chains.ChainType(srcChain.Uint64()))
+	group, err := chains.ChainType2ChainGroup(chains.ChainType(srcChain.Uint64()), params.TestChainConfig, big.NewInt(1))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -211,7 +211,7 @@ func TestReceiptsRootAndProof(t *testing.T) {
 	//set := flag.NewFlagSet("test", 0)
 	//chainsdb.NewStoreDb(cli.NewContext(nil, set, nil), 10, 2)
 
-	v, err := interfaces.VerifyFactory(group)
+	v, err := interfaces.VerifyFactory(group, chains.ChainType(srcChain.Uint64()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -222,6 +222,61 @@ func TestReceiptsRootAndProof(t *testing.T) {
 	}
 }
 
+// TestVerifyGroupFork tests that Ethereum mainnet receipts are only verified by the
+// eth2 light client since the eth2 verify fork, while its header store is kept, and that the
+// chains added by a fork are only supported from its block.
+func TestVerifyGroupFork(t *testing.T) {
+	config := &params.ChainConfig{
+		Eth2VerifyBlock:   big.NewInt(100),
+		Eth2TestnetsBlock: big.NewInt(50),
+		BSCBlock:          big.NewInt(60),
+		BTCBlock:          big.NewInt(70),
+	}
+	tests := []struct {
+		chain chains.ChainType
+		num   int64
+		want  chains.ChainGroup
+	}{
+		{chains.ChainTypeETH, 99, chains.ChainGroupETH},
+		{chains.ChainTypeETH, 100, chains.ChainGroupETH2},
+		{chains.ChainTypeETHTest, 100, chains.ChainGroupETH},
+		{chains.ChainTypeETHSepolia, 50, chains.ChainGroupETH2},
+		{chains.ChainTypeBSC, 60, chains.ChainGroupBSC},
+		{chains.ChainTypeBTCTest, 70, chains.ChainGroupBTC},
+	}
+	for _, tt := range tests {
+		group, err := chains.ChainType2VerifyGroup(tt.chain, config, big.NewInt(tt.num))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group != tt.want {
+			t.Errorf("chain %d at block %d: group mismatch: have %d, want %d", tt.chain, tt.num, group, tt.want)
+		}
+	}
+	if group, _ := chains.ChainType2ChainGroup(chains.ChainTypeETH, config, big.NewInt(100)); group != chains.ChainGroupETH {
+		t.Errorf("mainnet header store group mismatch: have %d, want %d", group, chains.ChainGroupETH)
+	}
+	// the chains are not supported before their fork
+	for _, tt := range []struct {
+		chain chains.ChainType
+		num   int64
+	}{
+		{chains.ChainTypeETHHolesky, 49},
+		{chains.ChainTypeBSCTest, 59},
+		{chains.ChainTypeBTC, 69},
+	} {
+		if _, err := chains.ChainType2VerifyGroup(tt.chain, config, big.NewInt(tt.num)); err != chains.ErrNotSupportChain {
+			t.Errorf("chain %d before the fork: error mismatch: have %v, want %v", tt.chain, err, chains.ErrNotSupportChain)
+		}
+	}
+	if chains.IsSupportedChain(chains.ChainTypeETHSepolia, config, big.NewInt(49)) {
+		t.Error("sepolia is supported before the fork")
+	}
+	if !chains.IsSupportedChain(chains.ChainTypeETH, config, big.NewInt(0)) {
+		t.Error("mainnet is not supported")
+	}
+}
+
 func PackInput(abi abi.ABI, abiMethod string, params ...interface{}) []byte {
 	input, err := abi.Pack(abiMethod, params...)
 	if err != nil {
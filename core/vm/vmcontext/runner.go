@@ -41,6 +41,23 @@ func NewEVMRunner(chain evmRunnerContext, header *types.Header, state types.Stat
 	}
 }
 
+// NewEVMRunnerFromEVM creates an EVMRunner in the block context of the given evm and on its state,
+// for the internal calls made while executing a transaction. The calls are not traced.
+func NewEVMRunnerFromEVM(evm *vm.EVM) vm.EVMRunner {
+	return &evmRunner{
+		state: evm.StateDB,
+		newEVM: func(from common.Address) *vm.EVM {
+			context := evm.Context
+			context.Origin = from
+			context.GasPrice = common.Big0
+			config := evm.Config
+			config.Debug = false
+			config.Tracer = nil
+			return vm.NewEVM(context, vm.TxContext{}, evm.StateDB, evm.ChainConfig(), config)
+		},
+	}
+}
+
 type evmRunner struct {
 	newEVM func(from common.Address) *vm.EVM
 	state  types.StateDB
@@ -12,6 +12,7 @@ require (
 	github.com/buraksezer/consistent v0.9.0
 	github.com/cespare/cp v1.1.1
 	github.com/cespare/xxhash/v2 v2.1.2
+	github.com/consensys/gnark-crypto v0.10.0
 	github.com/davecgh/go-spew v1.1.1
 	github.com/deckarep/golang-set v1.7.1
 	github.com/dop251/goja v0.0.0-20211011172007-d99e4b8cbf48
@@ -56,7 +57,7 @@ require (
 	github.com/tyler-smith/go-bip39 v1.0.2
 	golang.org/x/crypto v0.1.0
 	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4
-	golang.org/x/sys v0.1.0
+	golang.org/x/sys v0.2.0
 	golang.org/x/term v0.1.0
 	golang.org/x/text v0.4.0
 	google.golang.org/protobuf v1.26.0
@@ -13,8 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mapprotocol/atlas/consensus"
 	"github.com/mapprotocol/atlas/contracts/blockchain_parameters"
+	"github.com/mapprotocol/atlas/contracts/currency"
 	"github.com/mapprotocol/atlas/contracts/random"
 	"github.com/mapprotocol/atlas/core"
 	"github.com/mapprotocol/atlas/core/chain"
@@ -22,8 +24,12 @@ import (
 	"github.com/mapprotocol/atlas/core/rawdb"
 	"github.com/mapprotocol/atlas/core/state"
 	"github.com/mapprotocol/atlas/core/types"
+	params2 "github.com/mapprotocol/atlas/params"
 )
 
+// doubleSignEvidenceGas is the gas limit of the double sign evidence transactions
+const doubleSignEvidenceGas = 2 * params2.MaxGasForSlashing
+
 // blockState is the collection of modified state that is used to assemble a block
 type blockState struct {
 	signer types.Signer
@@ -78,6 +84,13 @@ func prepareBlock(w *worker) (*blockState, error) {
 			header.GasLimit = chain.CalcGasLimit(parentGasLimit, w.config.GasCeil)
 		}
 	}
+	if w.chainConfig.IsMmr(header.Number) {
+		root, err := w.chain.MmrRoot(parent.Header())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the mmr root: %w", err)
+		}
+		header.MmrRoot = &root
+	}
 
 	txFeeRecipient := w.txFeeRecipient
 	if w.txFeeRecipient != w.validator {
@@ -165,6 +178,51 @@ func prepareBlock(w *worker) (*blockState, error) {
 	return b, nil
 }
 
+// applyDoubleSignEvidence submits the pending double sign evidence of the istanbul core
+// to the slashing precompile in transactions of the validator, ahead of the pool transactions.
+func (b *blockState) applyDoubleSignEvidence(w *worker) {
+	if !w.chainConfig.IsSlashing(b.header.Number) {
+		return
+	}
+	istanbul, ok := w.engine.(consensus.Istanbul)
+	if !ok || istanbul.ValidatorAddress() != b.header.Coinbase {
+		return
+	}
+	for _, evidence := range istanbul.PendingDoubleSignEvidence() {
+		hash := evidence.Hash()
+		if vm.IsDoubleSignSlashed(b.state, evidence) {
+			istanbul.DiscardDoubleSignEvidence(hash)
+			continue
+		}
+		data, err := rlp.EncodeToBytes(evidence)
+		if err != nil {
+			log.Error("Failed to encode double sign evidence", "hash", hash, "err", err)
+			continue
+		}
+		tx, err := istanbul.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    b.state.GetNonce(b.header.Coinbase),
+			To:       &params2.DoubleSignSlashingAddress,
+			Gas:      doubleSignEvidenceGas,
+			GasPrice: common.Big0,
+			Data:     data,
+		}), b.signer)
+		if err != nil {
+			log.Error("Failed to sign double sign evidence", "hash", hash, "err", err)
+			return
+		}
+		b.state.Prepare(tx.Hash(), b.tcount)
+		if _, err := b.commitTransaction(w, tx, b.txFeeRecipient); err != nil {
+			log.Debug("Failed to apply double sign evidence", "hash", hash, "err", err)
+			continue
+		}
+		b.tcount++
+		if receipt := b.receipts[len(b.receipts)-1]; receipt.Status == types.ReceiptStatusFailed {
+			log.Warn("Discarding invalid double sign evidence", "hash", hash, "signer", evidence.Signer())
+			istanbul.DiscardDoubleSignEvidence(hash)
+		}
+	}
+}
+
 // selectAndApplyTransactions selects and applies transactions to the in flight block state.
 func (b *blockState) selectAndApplyTransactions(ctx context.Context, w *worker) error {
 	// Fill the block with all available pending transactions.
@@ -189,15 +247,15 @@ func (b *blockState) selectAndApplyTransactions(ctx context.Context, w *worker)
 		}
 	}
 
-	//txComparator := createTxCmp(w.chain, b.header, b.state)
+	minerFee := createMinerFee(w.chain, b.header, b.state)
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(b.signer, localTxs, b.header.BaseFee)
+		txs := types.NewTransactionsByPriceAndNonceWithMinerFee(b.signer, localTxs, minerFee)
 		if err := b.commitTransactions(ctx, w, txs, b.txFeeRecipient); err != nil {
 			return fmt.Errorf("failed to commit local transactions: %w", err)
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(b.signer, remoteTxs, b.header.BaseFee)
+		txs := types.NewTransactionsByPriceAndNonceWithMinerFee(b.signer, remoteTxs, minerFee)
 		if err := b.commitTransactions(ctx, w, txs, b.txFeeRecipient); err != nil {
 			return fmt.Errorf("failed to commit remote transactions: %w", err)
 		}
@@ -357,15 +415,31 @@ func (b *blockState) finalizeAndAssemble(w *worker) (*types.Block, error) {
 	return block, nil
 }
 
-// createTxCmp creates a Transaction comparator
-//func createTxCmp(chain *chain.BlockChain, header *types.Header, state *state.StateDB) func(tx1 *types.Transaction, tx2 *types.Transaction) int {
-//	vmRunner := chain.NewEVMRunner(header, state)
-//	currencyManager := currency.NewManager(vmRunner)
-//
-//	return func(tx1 *types.Transaction, tx2 *types.Transaction) int {
-//		return currencyManager.CmpValues(tx1.GasPrice(), tx1.FeeCurrency(), tx2.GasPrice(), tx2.FeeCurrency())
-//	}
-//}
+// createMinerFee creates the effective miner tip function of the transactions, the tips of
+// the fee currency transactions are converted to the native token
+func createMinerFee(chain *chain.BlockChain, header *types.Header, state *state.StateDB) types.MinerFeeFunc {
+	vmRunner := chain.NewEVMRunner(header, state)
+	currencyManager := currency.NewManager(vmRunner)
+
+	return func(tx *types.Transaction) (*big.Int, error) {
+		if tx.FeeCurrency() == nil {
+			return tx.EffectiveGasTip(header.BaseFee)
+		}
+		feeCurrency, err := currencyManager.GetCurrency(tx.FeeCurrency())
+		if err != nil {
+			return nil, err
+		}
+		var baseFee *big.Int
+		if header.BaseFee != nil {
+			baseFee = feeCurrency.FromBase(header.BaseFee)
+		}
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			return nil, err
+		}
+		return feeCurrency.ToBase(tip), nil
+	}
+}
 
 // totalFees computes total consumed fees in ETH. Block transactions and receipts have to have the same order.
 func totalFees(block *types.Block, receipts []*types.Receipt) *big.Float {
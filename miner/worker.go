@@ -343,6 +343,9 @@ func (w *worker) constructAndSubmitNewBlock(ctx context.Context) {
 		return
 	}
 
+	if w.isRunning() {
+		b.applyDoubleSignEvidence(w)
+	}
 	err = b.selectAndApplyTransactions(ctx, w)
 	if err != nil {
 		log.Error("Failed to apply transactions to the block", "err", err)
@@ -15,6 +15,14 @@ contract HeaderStore {
     function currentNumberAndHash(uint256 chainID) public returns (uint256 number, bytes memory hash) {}
     function setRelayer(address relayer) public {}
     function getRelayer() public returns (address relayer) {}
+    function registerRelayer(uint256 chainType, address relayer) public {}
+    function deregisterRelayer(uint256 chainType, address relayer) public {}
+    function stakeRelayer(uint256 chainType) public payable {}
+    function getRelayers(uint256 chainType) public view returns (address[] memory relayers) {}
+    function isRelayer(uint256 chainType, address relayer) public view returns (bool registered) {}
+    function claimRelayerReward() public returns (uint256 amount) {}
+    function setHeaderRetention(uint256 chainType, uint256 retention) public {}
+    function earliestStoredNumber(uint256 chainType) public view returns (uint256 number) {}
     function reset(uint256 from, uint256 td, bytes memory header) public {}
     function verifyProofData(bytes memory receiptProof) public returns(bool success, string memory message, bytes memory logs) {}
 }
@@ -39,6 +47,19 @@ const HeaderStoreABIJSON = `[
 	   "name": "UpdateBlockHeader",
 	   "type": "event"
 	},
+	{
+	   "inputs": [],
+	   "name": "claimRelayerReward",
+	   "outputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "amount",
+			 "type": "uint256"
+		  }
+	   ],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -63,6 +84,43 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "deregisterRelayer",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "earliestStoredNumber",
+	   "outputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "number",
+			 "type": "uint256"
+		  }
+	   ],
+	   "stateMutability": "view",
+	   "type": "function"
+	},
 	{
 	   "inputs": [],
 	   "name": "getRelayer",
@@ -76,6 +134,67 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "getRelayers",
+	   "outputs": [
+		  {
+			 "internalType": "address[]",
+			 "name": "relayers",
+			 "type": "address[]"
+		  }
+	   ],
+	   "stateMutability": "view",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "isRelayer",
+	   "outputs": [
+		  {
+			 "internalType": "bool",
+			 "name": "registered",
+			 "type": "bool"
+		  }
+	   ],
+	   "stateMutability": "view",
+	   "type": "function"
+	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "address",
+			 "name": "relayer",
+			 "type": "address"
+		  }
+	   ],
+	   "name": "registerRelayer",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -99,6 +218,24 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  },
+		  {
+			 "internalType": "uint256",
+			 "name": "retention",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "setHeaderRetention",
+	   "outputs": [],
+	   "stateMutability": "nonpayable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -112,6 +249,19 @@ const HeaderStoreABIJSON = `[
 	   "stateMutability": "nonpayable",
 	   "type": "function"
 	},
+	{
+	   "inputs": [
+		  {
+			 "internalType": "uint256",
+			 "name": "chainType",
+			 "type": "uint256"
+		  }
+	   ],
+	   "name": "stakeRelayer",
+	   "outputs": [],
+	   "stateMutability": "payable",
+	   "type": "function"
+	},
 	{
 	   "inputs": [
 		  {
@@ -209,3 +359,288 @@ const TxVerifyABIJSON = `[
 		"type": "function"
 	}
 ]`
+
+// Eth2LightClientABIJSON  eth2 light client store abi json
+/*
+
+bootstrap: abi.encode(BeaconBlockHeader finalizedHeader, SyncCommittee curSyncCommittee,
+	SyncCommittee nextSyncCommittee, uint64 chainID, uint256 blockNumber, bytes32 blockHash, bytes32 receiptsRoot)
+update: abi.encode(LightClientUpdate update)
+
+every beacon network has its own store selected by the execution layer chain id, the
+bootstrap initializes the store of its chain id
+
+type ReceiptProof struct {
+	Slot    uint64
+	Receipt *ethtypes.Receipt
+	Prove   light.NodeList
+	TxIndex uint
+}
+
+contract Eth2LightClient {
+    event UpdateLightClient(address indexed account, uint256 indexed slot);
+    function initialize(bytes memory bootstrap) public {}
+    function updateLightClient(uint256 chainId, bytes memory update) public {}
+    function headerHeight(uint256 chainId) public returns (uint256 height, uint256 slot) {}
+    function finalizedExecutionHash(uint256 chainId, uint256 slot) public returns (bytes32 hash) {}
+    function verifyReceiptProof(uint256 chainId, bytes memory receiptProof) public returns(bool success, string memory message, bytes memory logs) {}
+}
+*/
+const Eth2LightClientABIJSON = `[
+	{
+		"anonymous": false,
+		"inputs": [
+			{
+				"indexed": true,
+				"internalType": "address",
+				"name": "account",
+				"type": "address"
+			},
+			{
+				"indexed": true,
+				"internalType": "uint256",
+				"name": "slot",
+				"type": "uint256"
+			}
+		],
+		"name": "UpdateLightClient",
+		"type": "event"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "bytes",
+				"name": "bootstrap",
+				"type": "bytes"
+			}
+		],
+		"name": "initialize",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "chainId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "bytes",
+				"name": "update",
+				"type": "bytes"
+			}
+		],
+		"name": "updateLightClient",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "chainId",
+				"type": "uint256"
+			}
+		],
+		"name": "headerHeight",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "height",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "slot",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "chainId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "slot",
+				"type": "uint256"
+			}
+		],
+		"name": "finalizedExecutionHash",
+		"outputs": [
+			{
+				"internalType": "bytes32",
+				"name": "hash",
+				"type": "bytes32"
+			}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "chainId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "bytes",
+				"name": "receiptProof",
+				"type": "bytes"
+			}
+		],
+		"name": "verifyReceiptProof",
+		"outputs": [
+			{
+				"internalType": "bool",
+				"name": "success",
+				"type": "bool"
+			},
+			{
+				"internalType": "string",
+				"name": "message",
+				"type": "string"
+			},
+			{
+				"internalType": "bytes",
+				"name": "logs",
+				"type": "bytes"
+			}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+// DowntimeSlashingABIJSON  downtime slashing abi json
+/*
+
+slashFraction is the share of the locked gold slashed from a downtime validator in basis points,
+a validator is jailed for jailEpochs epochs after the epoch it was slashed in.
+
+contract DowntimeSlashing {
+    event ValidatorDowntimeSlashed(address indexed account, address indexed signer, uint256 epoch, uint256 missedBlocks, uint256 penalty, uint256 jailedUntil);
+    function setDowntimeSlashing(uint256 maxMissedBlocks, uint256 slashFraction, uint256 jailEpochs) public {}
+    function downtimeSlashing() public view returns (uint256 maxMissedBlocks, uint256 slashFraction, uint256 jailEpochs) {}
+    function jailedUntil(address account) public view returns (uint256 epoch) {}
+}
+*/
+const DowntimeSlashingABIJSON = `[
+	{
+		"anonymous": false,
+		"inputs": [
+			{
+				"indexed": true,
+				"internalType": "address",
+				"name": "account",
+				"type": "address"
+			},
+			{
+				"indexed": true,
+				"internalType": "address",
+				"name": "signer",
+				"type": "address"
+			},
+			{
+				"indexed": false,
+				"internalType": "uint256",
+				"name": "epoch",
+				"type": "uint256"
+			},
+			{
+				"indexed": false,
+				"internalType": "uint256",
+				"name": "missedBlocks",
+				"type": "uint256"
+			},
+			{
+				"indexed": false,
+				"internalType": "uint256",
+				"name": "penalty",
+				"type": "uint256"
+			},
+			{
+				"indexed": false,
+				"internalType": "uint256",
+				"name": "jailedUntil",
+				"type": "uint256"
+			}
+		],
+		"name": "ValidatorDowntimeSlashed",
+		"type": "event"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "maxMissedBlocks",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "slashFraction",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "jailEpochs",
+				"type": "uint256"
+			}
+		],
+		"name": "setDowntimeSlashing",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "downtimeSlashing",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "maxMissedBlocks",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "slashFraction",
+				"type": "uint256"
+			},
+			{
+				"internalType": "uint256",
+				"name": "jailEpochs",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "address",
+				"name": "account",
+				"type": "address"
+			}
+		],
+		"name": "jailedUntil",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "epoch",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
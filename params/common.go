@@ -21,6 +21,38 @@ var (
 	NewRelayerAddress  = common.BytesToAddress([]byte("relayerAddress"))
 	HeaderStoreAddress = common.BytesToAddress([]byte("headerstoreAddress"))
 	TxVerifyAddress    = common.BytesToAddress([]byte("txVerifyAddress"))
+
+	Eth2LightClientAddress = common.BytesToAddress([]byte("eth2LightClientAddress"))
+
+	DoubleSignSlashingAddress = common.BytesToAddress([]byte("doubleSignSlashing"))
+	DowntimeSlashingAddress   = common.BytesToAddress([]byte("downtimeSlashing"))
+)
+
+var (
+	// MinRelayerStake is the minimum value locked by stakeRelayer
+	MinRelayerStake = new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e18))
+
+	// DoubleSignPenalty is the locked gold slashed from a validator account signing two
+	// different blocks in the same consensus round, DoubleSignReward of it goes to the
+	// reporter of the evidence
+	DoubleSignPenalty = new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e18))
+	DoubleSignReward  = new(big.Int).Mul(big.NewInt(10000), big.NewInt(1e18))
+)
+
+const (
+	// MaxRelayersPerChain bounds the relayer set of a source chain
+	MaxRelayersPerChain = 64
+	// RelayerGraceBlocks is the number of blocks without header submission after
+	// which any registered relayer may submit instead of the active one
+	RelayerGraceBlocks uint64 = 100
+
+	// RelayerCanonicalHeaderWeight and RelayerSideHeaderWeight weigh the headers accepted
+	// from a relayer when sharing the relayer epoch rewards
+	RelayerCanonicalHeaderWeight uint64 = 4
+	RelayerSideHeaderWeight      uint64 = 1
+
+	// MaxRelayerRewardRate is the whole of the validator epoch rewards in basis points
+	MaxRelayerRewardRate uint64 = 10000
 )
 
 const (
@@ -67,6 +99,7 @@ var (
 	GovernanceRegistryId           = makeRegistryId("Governance")
 	LockedGoldRegistryId           = makeRegistryId("LockedGold")
 	RandomRegistryId               = makeRegistryId("Random")
+	SortedOraclesRegistryId        = makeRegistryId("SortedOracles")
 
 	//TransferWhitelistRegistryId    = makeRegistryId("TransferWhitelist")
 	ValidatorsRegistryId = makeRegistryId("Validators")
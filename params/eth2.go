@@ -0,0 +1,27 @@
+package params
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BeaconNetworkConfig describes a beacon chain whose light client updates can be verified
+// by the eth2 light client precompile. Fork epochs left unset are treated as not scheduled.
+//
+// The verification result of the precompile depends on these values, so beacon networks
+// can only be declared in the chain config.
+type BeaconNetworkConfig struct {
+	Name                         string        `json:"name,omitempty"`
+	ChainID                      uint64        `json:"chainId"`                 // execution layer chain id the network is selected by
+	GenesisValidatorsRoot        common.Hash   `json:"genesisValidatorsRoot"`   // genesis validators root mixed into the signing domain
+	SlotsPerEpoch                uint64        `json:"slotsPerEpoch,omitempty"` // 0 = mainnet preset
+	EpochsPerSyncCommitteePeriod uint64        `json:"epochsPerSyncCommitteePeriod,omitempty"`
+	BellatrixForkVersion         hexutil.Bytes `json:"bellatrixForkVersion"`
+	BellatrixForkEpoch           *uint64       `json:"bellatrixForkEpoch,omitempty"`
+	CapellaForkVersion           hexutil.Bytes `json:"capellaForkVersion,omitempty"`
+	CapellaForkEpoch             *uint64       `json:"capellaForkEpoch,omitempty"`
+	DenebForkVersion             hexutil.Bytes `json:"denebForkVersion,omitempty"`
+	DenebForkEpoch               *uint64       `json:"denebForkEpoch,omitempty"`
+	ElectraForkVersion           hexutil.Bytes `json:"electraForkVersion,omitempty"`
+	ElectraForkEpoch             *uint64       `json:"electraForkEpoch,omitempty"`
+}
@@ -49,6 +49,8 @@ const (
 	MaxGasForIsReserveLow                          uint64 = 1 * million
 	MaxGasForGetCommunityPartnerSettingPartner     uint64 = 100 * thousand
 	MaxGasForGetMgrMaintainerAddress               uint64 = 100 * thousand
+	MaxGasForSlashing                              uint64 = 10 * million
+	MaxGasForGetAccountTotalLockedGold             uint64 = 1 * million
 
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	CallValueTransferGas uint64 = 9000  // Paid for CALL when the value transfer is non-zero.
@@ -85,6 +87,7 @@ const (
 	// May take a bit more time with 100 validators, need to bench that
 	GetVerifiedSealBitmapGas uint64 = 350000           // Cost of verifying the seal on a given RLP encoded header.
 	Ed25519VerifyGas         uint64 = 1500             // Gas needed for and Ed25519 signature verification
+	DoubleSignSlashingGas    uint64 = 400000           // Cost of verifying double sign evidence and slashing the signer.
 	Sha2_512BaseGas          uint64 = Sha256BaseGas    // Base price for a Sha2-512 operation
 	Sha2_512PerWordGas       uint64 = Sha256PerWordGas // Per-word price for a Sha2-512 operation
 
@@ -121,3 +124,7 @@ const (
 	MaxCodeSize        = 49152              // Maximum bytecode to permit for a contract
 	MaxGasLimit uint64 = 0x7fffffffffffffff // Maximum the gas limit (2^63-1).
 )
+
+// Gas discount table for BLS12-377 G1 and G2 multi exponentiation operations, as
+// specified by EIP-2539.
+var Bls12377MultiExpDiscountTable = [128]uint64{1200, 888, 764, 641, 594, 547, 500, 453, 438, 423, 408, 394, 379, 364, 349, 334, 330, 326, 322, 318, 314, 310, 306, 302, 298, 294, 289, 285, 281, 277, 273, 269, 268, 266, 265, 263, 262, 260, 259, 257, 256, 254, 253, 251, 250, 248, 247, 245, 244, 242, 241, 239, 238, 236, 235, 233, 232, 231, 229, 228, 226, 225, 223, 222, 221, 220, 219, 219, 218, 217, 216, 216, 215, 214, 213, 213, 212, 211, 211, 210, 209, 208, 208, 207, 206, 205, 205, 204, 203, 202, 202, 201, 200, 199, 199, 198, 197, 196, 196, 195, 194, 193, 193, 192, 191, 191, 190, 189, 188, 188, 187, 186, 185, 185, 184, 183, 182, 182, 181, 180, 179, 179, 178, 177, 176, 176, 175, 174}
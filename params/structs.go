@@ -103,6 +103,32 @@ type ChainConfig struct {
 	DeregisterBlock   *big.Int `json:"deregisterblock,omitempty"`
 	CalcBaseBlock     *big.Int `json:"calcbaseblock,omitempty"`
 	MAIBlock          *big.Int `json:"maiBlock,omitempty"` // MAI switch block (nil = no fork, 0 = already on shanghai)
+	// BLS12377Block activates the BLS12-377, CIP-20 and CIP-26 precompiles (nil = no fork, 0 = already activated)
+	BLS12377Block *big.Int `json:"bls12377Block,omitempty"`
+	// RelayerRegistryBlock activates the per-chain relayer registry of the header store (nil = no fork, 0 = already activated)
+	RelayerRegistryBlock *big.Int `json:"relayerRegistryBlock,omitempty"`
+	// RelayerRewardBlock activates the header submission accounting and the relayer epoch rewards (nil = no fork, 0 = already activated)
+	RelayerRewardBlock *big.Int `json:"relayerRewardBlock,omitempty"`
+	// HeaderRetentionBlock activates the per chain retention of the headers kept by the header stores (nil = no fork, 0 = already activated)
+	HeaderRetentionBlock *big.Int `json:"headerRetentionBlock,omitempty"`
+	// MmrBlock activates the commitment of the Merkle Mountain Range of the ancestor block hashes in the header (nil = no fork, 0 = already activated)
+	MmrBlock *big.Int `json:"mmrBlock,omitempty"`
+	// SlashingBlock activates the double sign and downtime slashing with their precompiles and the free evidence system transactions (nil = no fork, 0 = already activated)
+	SlashingBlock *big.Int `json:"slashingBlock,omitempty"`
+	// FeeCurrencyBlock activates the transactions paying gas in whitelisted ERC20 fee currencies (nil = no fork, 0 = already activated)
+	FeeCurrencyBlock *big.Int `json:"feeCurrencyBlock,omitempty"`
+	// Eth2VerifyBlock activates the verification of the Ethereum mainnet receipts against the execution payloads finalized by the eth2 light client (nil = no fork, 0 = already activated)
+	Eth2VerifyBlock *big.Int `json:"eth2VerifyBlock,omitempty"`
+	// Eth2TestnetsBlock makes the built-in Sepolia and Holesky beacon networks available to the eth2 light client precompiles (nil = no fork, 0 = already activated)
+	Eth2TestnetsBlock *big.Int `json:"eth2TestnetsBlock,omitempty"`
+	// BSCBlock activates the header stores and the receipt verification of BNB Smart Chain and its testnet (nil = no fork, 0 = already activated)
+	BSCBlock *big.Int `json:"bscBlock,omitempty"`
+	// BTCBlock activates the header stores and the transaction verification of bitcoin and its testnet (nil = no fork, 0 = already activated)
+	BTCBlock *big.Int `json:"btcBlock,omitempty"`
+
+	// Eth2Networks registers additional beacon networks for the eth2 light client precompile
+	Eth2Networks []*BeaconNetworkConfig `json:"eth2Networks,omitempty"`
+
 	// This does not belong here but passing it to every function is not possible since that breaks
 	// some implemented interfaces and introduces churn across the geth codebase.
 	FullHeaderChainAvailable bool // False for lightest Sync mode, true otherwise
@@ -142,6 +168,10 @@ type IstanbulConfig struct {
 	// have timeouts of this + additional time that increases with round
 	// number.
 	RequestTimeout uint64 `json:"requesttimeout,omitempty"`
+
+	// RelayerRewardRate is the share of the validator epoch rewards paid to the relayers
+	// after the RelayerRewardBlock, in basis points
+	RelayerRewardRate uint64 `json:"relayerRewardRate,omitempty"`
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -158,7 +188,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v BN256Fork: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Berlin: %v, London: %v, Reward: %v, Deregister: %v, Calc: %v, MAI: %v, Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v BN256Fork: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Berlin: %v, London: %v, Reward: %v, Deregister: %v, Calc: %v, MAI: %v, BLS12377: %v, RelayerRegistry: %v, RelayerReward: %v, HeaderRetention: %v, Mmr: %v, Slashing: %v, FeeCurrency: %v, Eth2Verify: %v, Eth2Testnets: %v, BSC: %v, BTC: %v, Engine: %v}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.DAOForkBlock,
@@ -178,6 +208,17 @@ func (c *ChainConfig) String() string {
 		c.DeregisterBlock,
 		c.CalcBaseBlock,
 		c.MAIBlock,
+		c.BLS12377Block,
+		c.RelayerRegistryBlock,
+		c.RelayerRewardBlock,
+		c.HeaderRetentionBlock,
+		c.MmrBlock,
+		c.SlashingBlock,
+		c.FeeCurrencyBlock,
+		c.Eth2VerifyBlock,
+		c.Eth2TestnetsBlock,
+		c.BSCBlock,
+		c.BTCBlock,
 		engine,
 	)
 }
@@ -267,6 +308,61 @@ func (c *ChainConfig) IsMAI(num *big.Int) bool {
 	return isForked(c.MAIBlock, num)
 }
 
+// IsBLS12377 returns whether num is either equal to the BLS12-377 precompiles fork block or greater.
+func (c *ChainConfig) IsBLS12377(num *big.Int) bool {
+	return isForked(c.BLS12377Block, num)
+}
+
+// IsRelayerRegistry returns whether num is either equal to the relayer registry fork block or greater.
+func (c *ChainConfig) IsRelayerRegistry(num *big.Int) bool {
+	return isForked(c.RelayerRegistryBlock, num)
+}
+
+// IsRelayerReward returns whether num is either equal to the relayer reward fork block or greater.
+func (c *ChainConfig) IsRelayerReward(num *big.Int) bool {
+	return isForked(c.RelayerRewardBlock, num)
+}
+
+// IsHeaderRetention returns whether num is either equal to the header retention fork block or greater.
+func (c *ChainConfig) IsHeaderRetention(num *big.Int) bool {
+	return isForked(c.HeaderRetentionBlock, num)
+}
+
+// IsMmr returns whether num is either equal to the mmr fork block or greater.
+func (c *ChainConfig) IsMmr(num *big.Int) bool {
+	return isForked(c.MmrBlock, num)
+}
+
+// IsSlashing returns whether num is either equal to the slashing fork block or greater.
+func (c *ChainConfig) IsSlashing(num *big.Int) bool {
+	return isForked(c.SlashingBlock, num)
+}
+
+// IsFeeCurrency returns whether num is either equal to the fee currency fork block or greater.
+func (c *ChainConfig) IsFeeCurrency(num *big.Int) bool {
+	return isForked(c.FeeCurrencyBlock, num)
+}
+
+// IsEth2Verify returns whether num is either equal to the eth2 verify fork block or greater.
+func (c *ChainConfig) IsEth2Verify(num *big.Int) bool {
+	return isForked(c.Eth2VerifyBlock, num)
+}
+
+// IsEth2Testnets returns whether num is either equal to the eth2 testnets fork block or greater.
+func (c *ChainConfig) IsEth2Testnets(num *big.Int) bool {
+	return isForked(c.Eth2TestnetsBlock, num)
+}
+
+// IsBSC returns whether num is either equal to the bsc fork block or greater.
+func (c *ChainConfig) IsBSC(num *big.Int) bool {
+	return isForked(c.BSCBlock, num)
+}
+
+// IsBTC returns whether num is either equal to the btc fork block or greater.
+func (c *ChainConfig) IsBTC(num *big.Int) bool {
+	return isForked(c.BTCBlock, num)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -286,8 +382,12 @@ func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *Confi
 }
 
 // CheckConfigForkOrder checks that we don't "skip" any forks, geth isn't pluggable enough
-// to guarantee that forks can be implemented in a different order than on official networks
+// to guarantee that forks can be implemented in a different order than on official networks.
+// It also rejects a relayer reward rate above the validator epoch rewards.
 func (c *ChainConfig) CheckConfigForkOrder() error {
+	if c.Istanbul != nil && c.Istanbul.RelayerRewardRate > MaxRelayerRewardRate {
+		return fmt.Errorf("invalid relayer reward rate %d, above %d basis points", c.Istanbul.RelayerRewardRate, MaxRelayerRewardRate)
+	}
 	type fork struct {
 		name     string
 		block    *big.Int
@@ -307,6 +407,17 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		{name: "muirGlacierBlock", block: c.MuirGlacierBlock, optional: true},
 		{name: "berlinBlock", block: c.BerlinBlock},
 		{name: "londonBlock", block: c.LondonBlock},
+		{name: "bls12377Block", block: c.BLS12377Block, optional: true},
+		{name: "relayerRegistryBlock", block: c.RelayerRegistryBlock, optional: true},
+		{name: "relayerRewardBlock", block: c.RelayerRewardBlock, optional: true},
+		{name: "headerRetentionBlock", block: c.HeaderRetentionBlock, optional: true},
+		{name: "mmrBlock", block: c.MmrBlock, optional: true},
+		{name: "slashingBlock", block: c.SlashingBlock, optional: true},
+		{name: "feeCurrencyBlock", block: c.FeeCurrencyBlock, optional: true},
+		{name: "eth2VerifyBlock", block: c.Eth2VerifyBlock, optional: true},
+		{name: "eth2TestnetsBlock", block: c.Eth2TestnetsBlock, optional: true},
+		{name: "bscBlock", block: c.BSCBlock, optional: true},
+		{name: "btcBlock", block: c.BTCBlock, optional: true},
 	} {
 		if lastFork.name != "" {
 			// Next one must be higher number
@@ -379,6 +490,39 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.BLS12377Block, newcfg.BLS12377Block, head) {
+		return newCompatError("BLS12-377 fork block", c.BLS12377Block, newcfg.BLS12377Block)
+	}
+	if isForkIncompatible(c.RelayerRegistryBlock, newcfg.RelayerRegistryBlock, head) {
+		return newCompatError("relayer registry fork block", c.RelayerRegistryBlock, newcfg.RelayerRegistryBlock)
+	}
+	if isForkIncompatible(c.RelayerRewardBlock, newcfg.RelayerRewardBlock, head) {
+		return newCompatError("relayer reward fork block", c.RelayerRewardBlock, newcfg.RelayerRewardBlock)
+	}
+	if isForkIncompatible(c.HeaderRetentionBlock, newcfg.HeaderRetentionBlock, head) {
+		return newCompatError("header retention fork block", c.HeaderRetentionBlock, newcfg.HeaderRetentionBlock)
+	}
+	if isForkIncompatible(c.MmrBlock, newcfg.MmrBlock, head) {
+		return newCompatError("mmr fork block", c.MmrBlock, newcfg.MmrBlock)
+	}
+	if isForkIncompatible(c.SlashingBlock, newcfg.SlashingBlock, head) {
+		return newCompatError("slashing fork block", c.SlashingBlock, newcfg.SlashingBlock)
+	}
+	if isForkIncompatible(c.FeeCurrencyBlock, newcfg.FeeCurrencyBlock, head) {
+		return newCompatError("fee currency fork block", c.FeeCurrencyBlock, newcfg.FeeCurrencyBlock)
+	}
+	if isForkIncompatible(c.Eth2VerifyBlock, newcfg.Eth2VerifyBlock, head) {
+		return newCompatError("eth2 verify fork block", c.Eth2VerifyBlock, newcfg.Eth2VerifyBlock)
+	}
+	if isForkIncompatible(c.Eth2TestnetsBlock, newcfg.Eth2TestnetsBlock, head) {
+		return newCompatError("eth2 testnets fork block", c.Eth2TestnetsBlock, newcfg.Eth2TestnetsBlock)
+	}
+	if isForkIncompatible(c.BSCBlock, newcfg.BSCBlock, head) {
+		return newCompatError("bsc fork block", c.BSCBlock, newcfg.BSCBlock)
+	}
+	if isForkIncompatible(c.BTCBlock, newcfg.BTCBlock, head) {
+		return newCompatError("btc fork block", c.BTCBlock, newcfg.BTCBlock)
+	}
 	return nil
 }
 
@@ -447,7 +591,7 @@ type Rules struct {
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon, IsCatalyst                          bool
-	IsMAI                                                   bool
+	IsMAI, IsBLS12377, IsSlashing, IsFeeCurrency            bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -470,6 +614,9 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsLondon:         c.IsLondon(num),
 		IsCatalyst:       c.IsCatalyst(num),
 		IsMAI:            c.IsMAI(num),
+		IsBLS12377:       c.IsBLS12377(num),
+		IsSlashing:       c.IsSlashing(num),
+		IsFeeCurrency:    c.IsFeeCurrency(num),
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/mapprotocol/atlas/tools/utils/btcapi"
 )
@@ -83,6 +84,10 @@ func (c *MempoolClient) ListUnspent(address btcutil.Address) ([]*btcapi.UnspentO
 		return nil, err
 	}
 
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
 	unspentOutputs := make([]*btcapi.UnspentOutput, 0)
 	for _, utxo := range utxos {
 		txHash, err := chainhash.NewHashFromStr(utxo.Txid)
@@ -91,7 +96,7 @@ func (c *MempoolClient) ListUnspent(address btcutil.Address) ([]*btcapi.UnspentO
 		}
 		unspentOutputs = append(unspentOutputs, &btcapi.UnspentOutput{
 			Outpoint: wire.NewOutPoint(txHash, uint32(utxo.Vout)),
-			Output:   wire.NewTxOut(utxo.Value, address.ScriptAddress()),
+			Output:   wire.NewTxOut(utxo.Value, pkScript),
 		})
 	}
 	return unspentOutputs, nil